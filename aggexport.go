@@ -0,0 +1,252 @@
+package appdash
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+func init() {
+	RegisterEvent(AggregateEvent{})
+}
+
+// An AggregateEvent is the on-wire form of the aggregates of the spans of
+// one key (see MetricsOnlyCollector.Key) that started in one minute, as
+// streamed by an AggregateExporter to a central MetricsOnlyCollector. It
+// is sent as the only event of a span of its own, over any Collector (e.g.
+// a RemoteCollector), and is merged into the central aggregates rather
+// than stored as a span.
+type AggregateEvent struct {
+	Key     string                   `trace:"Aggregate.Key"`
+	Start   time.Time                `trace:"Aggregate.Start"`   // the start of the minute
+	Count   int64                    `trace:"Aggregate.Count"`   // number of spans
+	Errors  int64                    `trace:"Aggregate.Errors"`  // number of failed spans
+	Sum     float64                  `trace:"Aggregate.Sum"`     // sum of durations, in seconds
+	SumSq   float64                  `trace:"Aggregate.SumSq"`   // sum of squared durations, in seconds
+	Min     time.Duration            `trace:"Aggregate.Min"`     // shortest duration
+	Max     time.Duration            `trace:"Aggregate.Max"`     // longest duration
+	Slowest map[string]time.Duration `trace:"Aggregate.Slowest"` // trace ID -> duration of the slowest spans
+	Latency string                   `trace:"Aggregate.Latency"` // LatencySketch of the durations, binary-encoded
+}
+
+// Schema returns the constant "aggregate".
+func (AggregateEvent) Schema() string { return "aggregate" }
+
+// event returns the AggregateEvent for the aggregates in b of the spans of
+// key that started in minute (in Unix minutes).
+func (b *metricsBucket) event(key string, minute int64) (AggregateEvent, error) {
+	latency, err := b.latency.MarshalBinary()
+	if err != nil {
+		return AggregateEvent{}, err
+	}
+	e := AggregateEvent{
+		Key:     key,
+		Start:   time.Unix(minute*60, 0).UTC(),
+		Count:   b.count,
+		Errors:  b.errors,
+		Sum:     b.sum,
+		SumSq:   b.sumSq,
+		Min:     b.min,
+		Max:     b.max,
+		Slowest: make(map[string]time.Duration, len(b.slowest)),
+		Latency: string(latency),
+	}
+	for _, s := range b.slowest {
+		e.Slowest[s.trace.String()] = s.duration
+	}
+	return e, nil
+}
+
+// bucket returns the aggregates that e describes.
+func (e *AggregateEvent) bucket() (*metricsBucket, error) {
+	if e.Count <= 0 {
+		return nil, errors.New("aggregate event has no spans")
+	}
+	b := &metricsBucket{
+		count:  e.Count,
+		errors: e.Errors,
+		sum:    e.Sum,
+		sumSq:  e.SumSq,
+		min:    e.Min,
+		max:    e.Max,
+	}
+	if err := b.latency.UnmarshalBinary([]byte(e.Latency)); err != nil {
+		return nil, err
+	}
+	for id, d := range e.Slowest {
+		trace, err := ParseID(id)
+		if err != nil {
+			return nil, err
+		}
+		b.slowest = addSlowest(b.slowest, slowSpan{trace: trace, duration: d})
+	}
+	return b, nil
+}
+
+// collectAggregate merges the aggregates of an AggregateEvent in anns
+// into mc's.
+func (mc *MetricsOnlyCollector) collectAggregate(anns Annotations) error {
+	var e AggregateEvent
+	if err := UnmarshalEvent(anns, &e); err != nil {
+		return err
+	}
+	b, err := e.bucket()
+	if err != nil {
+		return fmt.Errorf("MetricsOnlyCollector: invalid aggregate for %q: %s", e.Key, err)
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.pruneNoLock()
+	if mc.buckets == nil {
+		mc.buckets = map[string]map[int64]*metricsBucket{}
+	}
+	if mc.buckets[e.Key] == nil {
+		mc.buckets[e.Key] = map[int64]*metricsBucket{}
+	}
+	minute := e.Start.Unix() / 60
+	if prev := mc.buckets[e.Key][minute]; prev != nil {
+		prev.merge(b)
+	} else {
+		mc.buckets[e.Key][minute] = b
+	}
+	return nil
+}
+
+// takeBuckets removes and returns all of mc's aggregates.
+func (mc *MetricsOnlyCollector) takeBuckets() map[string]map[int64]*metricsBucket {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	buckets := mc.buckets
+	mc.buckets = nil
+	return buckets
+}
+
+// An AggregateExporter is a Collector that aggregates the spans collected
+// into it, as a MetricsOnlyCollector does, and periodically streams the
+// aggregates to a central collector instead of the spans themselves. The
+// central collector, typically a MetricsOnlyCollector behind a
+// CollectorServer, merges the aggregates of every instance into
+// fleet-wide metrics, including latency percentiles (see LatencySketch).
+//
+// Each export sends one AggregateEvent per key and minute for the spans
+// collected since the previous export, so the volume of data sent depends
+// on the number of keys rather than on the number of spans.
+type AggregateExporter struct {
+	// Metrics aggregates the spans collected between exports. Its Key,
+	// IsError and Clock fields determine how spans are aggregated.
+	Metrics *MetricsOnlyCollector
+
+	// Collector is the central collector that aggregates are sent to,
+	// such as a RemoteCollector.
+	Collector Collector
+
+	// Interval is the interval at which aggregates are exported
+	// automatically, in a separate goroutine, once the first span is
+	// collected. If zero, aggregates are only exported by calling Export.
+	Interval time.Duration
+
+	// Log, if non-nil, is used to log errors from automatic exports.
+	Log *log.Logger
+
+	mu       sync.Mutex
+	started  bool
+	stopped  bool
+	stopChan chan struct{}
+}
+
+// NewAggregateExporter is shorthand for:
+//
+// 	c := &AggregateExporter{
+// 		Metrics:   &MetricsOnlyCollector{},
+// 		Collector: c,
+// 		Interval:  10 * time.Second,
+// 		Log:       log.New(os.Stderr, "appdash: ", log.LstdFlags),
+// 	}
+//
+func NewAggregateExporter(c Collector) *AggregateExporter {
+	return &AggregateExporter{
+		Metrics:   &MetricsOnlyCollector{},
+		Collector: c,
+		Interval:  10 * time.Second,
+		Log:       log.New(os.Stderr, "appdash: ", log.LstdFlags),
+	}
+}
+
+// Collect implements the Collector interface by aggregating the span until
+// the next export.
+func (ae *AggregateExporter) Collect(span SpanID, anns ...Annotation) error {
+	ae.mu.Lock()
+	if ae.stopped {
+		ae.mu.Unlock()
+		return errors.New("AggregateExporter is stopped")
+	}
+	if !ae.started && ae.Interval > 0 {
+		ae.start()
+	}
+	ae.mu.Unlock()
+	return ae.Metrics.Collect(span, anns...)
+}
+
+// Export immediately sends the aggregates of the spans collected since the
+// last export to the central collector. Aggregates that could not be sent
+// are dropped.
+func (ae *AggregateExporter) Export() error {
+	var errs []error
+	for key, buckets := range ae.Metrics.takeBuckets() {
+		for minute, b := range buckets {
+			e, err := b.event(key, minute)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			as, err := MarshalEvent(e)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := ae.Collector.Collect(NewRootSpanID(), as...); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	} else if len(errs) > 1 {
+		return fmt.Errorf("AggregateExporter: multiple errors: %v", errs)
+	}
+	return nil
+}
+
+func (ae *AggregateExporter) start() {
+	ae.stopChan = make(chan struct{})
+	ae.started = true
+	go func() {
+		for {
+			select {
+			case <-time.After(ae.Interval):
+				if err := ae.Export(); err != nil && ae.Log != nil {
+					ae.Log.Printf("AggregateExporter: %s", err)
+				}
+			case <-ae.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops automatic exports and exports the remaining aggregates. After
+// stopping, calls to Collect will fail.
+func (ae *AggregateExporter) Stop() error {
+	ae.mu.Lock()
+	if ae.started && !ae.stopped {
+		close(ae.stopChan)
+	}
+	ae.stopped = true
+	ae.mu.Unlock()
+	return ae.Export()
+}
@@ -0,0 +1,87 @@
+package appdash
+
+import (
+	"net"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestAggregateExporter(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 12, 0, 0, 0, time.UTC)}
+	central := &MetricsOnlyCollector{Clock: clock}
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go NewServer(l, central).Start()
+
+	// Two instances serve the same route with different latencies, and
+	// stream their aggregates to the central collector.
+	var all []time.Duration
+	var failed int64
+	for i, latency := range []func(n int) time.Duration{
+		func(n int) time.Duration { return time.Duration(n%100+1) * time.Millisecond },     // 1-100ms
+		func(n int) time.Duration { return time.Duration(n%50+1) * 10 * time.Millisecond }, // 10-500ms
+	} {
+		rc := NewRemoteCollector(l.Addr().String())
+		ae := NewAggregateExporter(rc)
+		ae.Interval = 0
+		ae.Metrics.Clock = clock
+		for n := 0; n < 1000; n++ {
+			start := clock.Now().Add(-time.Duration(n%3) * time.Minute)
+			d := latency(n)
+			status := "200"
+			if n%100 == 0 {
+				status = "500"
+				failed++
+			}
+			as, err := MarshalEvent(Timespan{S: start, E: start.Add(d)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			as = append(as, Annotation{Key: "Server.Route", Value: []byte("/users")}, Annotation{Key: "Server.Response.StatusCode", Value: []byte(status)})
+			if err := ae.Collect(SpanID{Trace: ID(i*1000 + n + 1), Span: 1}, as...); err != nil {
+				t.Fatal(err)
+			}
+			all = append(all, d)
+		}
+		if err := ae.Stop(); err != nil {
+			t.Fatal(err)
+		}
+		if err := rc.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := ae.Metrics.Aggregate(-time.Hour, 0); len(got) != 0 {
+			t.Errorf("instance %d: got aggregates %v after export, want none", i, got)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	var r *AggregatedResult
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		results, err := central.Aggregate(-time.Hour, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) == 1 && results[0].Samples == int64(len(all)) {
+			r = results[0]
+			break
+		}
+	}
+	if r == nil {
+		t.Fatal("central collector did not receive the aggregates of both instances")
+	}
+	if r.RootSpanName != "/users" || r.Errors != failed || r.Min != all[0] || r.Max != all[len(all)-1] {
+		t.Errorf("got fleet-wide aggregate %+v, want %d errors, min %v and max %v", r, failed, all[0], all[len(all)-1])
+	}
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		want := exactQuantile(all, q)
+		if got := r.Latency.Quantile(q); !withinAccuracy(got, want) {
+			t.Errorf("q=%v: got fleet-wide %v, want %v", q, got, want)
+		}
+	}
+	if len(r.Slowest) != metricsSlowest || r.Slowest[0] <= 1000 {
+		t.Errorf("got slowest traces %v, want %d from the second instance", r.Slowest, metricsSlowest)
+	}
+}
@@ -0,0 +1,147 @@
+package appdash
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// An AnnotationType describes how the values of an annotation key should be
+// compared. Annotation values are always stored as strings; the type only
+// affects comparison (e.g. when sorting or range-filtering spans).
+type AnnotationType int
+
+const (
+	// StringAnnotation values compare lexically. It is the type of every key
+	// that has no registered type.
+	StringAnnotation AnnotationType = iota
+
+	// NumberAnnotation values are decimal numbers (as written by
+	// MarshalEvent for integer, float and time.Duration fields) and compare
+	// numerically, so that "20" < "100".
+	NumberAnnotation
+
+	// TimeAnnotation values are RFC3339 timestamps (as written by
+	// MarshalEvent for time.Time fields) and compare chronologically.
+	TimeAnnotation
+)
+
+func (t AnnotationType) String() string {
+	switch t {
+	case NumberAnnotation:
+		return "number"
+	case TimeAnnotation:
+		return "time"
+	default:
+		return "string"
+	}
+}
+
+var (
+	annotationTypes   = map[string]AnnotationType{} // annotation key -> type
+	annotationTypesMu sync.RWMutex
+)
+
+// RegisterAnnotationType registers the type of values stored under the given
+// annotation key. Types of the fields of events passed to RegisterEvent are
+// registered automatically; this is only needed for annotations that are
+// not produced by MarshalEvent, or to override the type derived from a field.
+func RegisterAnnotationType(key string, t AnnotationType) {
+	annotationTypesMu.Lock()
+	annotationTypes[key] = t
+	annotationTypesMu.Unlock()
+}
+
+// AnnotationTypeOf returns the registered type of the given annotation key,
+// or StringAnnotation if none is registered.
+func AnnotationTypeOf(key string) AnnotationType {
+	annotationTypesMu.RLock()
+	t := annotationTypes[key]
+	annotationTypesMu.RUnlock()
+	return t
+}
+
+// AnnotationTypes returns a copy of all registered non-string annotation
+// types, keyed by annotation key.
+func AnnotationTypes() map[string]AnnotationType {
+	annotationTypesMu.RLock()
+	defer annotationTypesMu.RUnlock()
+	m := make(map[string]AnnotationType, len(annotationTypes))
+	for k, t := range annotationTypes {
+		if t != StringAnnotation {
+			m[k] = t
+		}
+	}
+	return m
+}
+
+// CompareAnnotationValues compares two values of the given annotation key
+// according to its registered type. The result is -1 if a < b, 0 if a == b
+// and +1 if a > b. If either value cannot be parsed as the key's type, the
+// values are compared lexically.
+func CompareAnnotationValues(key string, a, b []byte) int {
+	switch AnnotationTypeOf(key) {
+	case NumberAnnotation:
+		x, errA := strconv.ParseFloat(string(a), 64)
+		y, errB := strconv.ParseFloat(string(b), 64)
+		if errA == nil && errB == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	case TimeAnnotation:
+		x, errA := time.Parse(time.RFC3339Nano, string(a))
+		y, errB := time.Parse(time.RFC3339Nano, string(b))
+		if errA == nil && errB == nil {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			}
+			return 0
+		}
+	}
+	return bytes.Compare(a, b)
+}
+
+// registerFieldTypes registers the annotation types of the fields of t, as
+// they would be flattened by flattenValue under the given prefix. Maps and
+// slices are skipped because their keys are only known at marshal time.
+func registerFieldTypes(prefix string, t reflect.Type) {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		RegisterAnnotationType(prefix, TimeAnnotation)
+		return
+	case reflect.TypeOf(time.Duration(0)):
+		RegisterAnnotationType(prefix, NumberAnnotation)
+		return
+	}
+	if t.Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()) {
+		return // flattened using String
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		registerFieldTypes(prefix, t.Elem())
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		RegisterAnnotationType(prefix, NumberAnnotation)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			fld := t.Field(i)
+			if fld.PkgPath != "" {
+				continue
+			}
+			registerFieldTypes(nest(prefix, fieldName(fld)), fld.Type)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package appdash
+
+import (
+	"testing"
+	"time"
+)
+
+type typedEvent struct {
+	Code     int           `trace:"Typed.Code"`
+	Ratio    float64       `trace:"Typed.Ratio"`
+	Elapsed  time.Duration `trace:"Typed.Elapsed"`
+	At       time.Time     `trace:"Typed.At"`
+	Name     string        `trace:"Typed.Name"`
+	Span     SpanID        `trace:"Typed.Span"`
+	Nested   struct{ N uint8 }
+	Tags     map[string]int
+	internal int
+}
+
+func (typedEvent) Schema() string { return "typed" }
+
+func init() { RegisterEvent(typedEvent{}) }
+
+func TestRegisterEvent_annotationTypes(t *testing.T) {
+	want := map[string]AnnotationType{
+		"Typed.Code":    NumberAnnotation,
+		"Typed.Ratio":   NumberAnnotation,
+		"Typed.Elapsed": NumberAnnotation,
+		"Typed.At":      TimeAnnotation,
+		"Typed.Name":    StringAnnotation,
+		"Typed.Span":    StringAnnotation,
+		"Nested.N":      NumberAnnotation,
+		"Tags":          StringAnnotation,
+		"internal":      StringAnnotation,
+		"Unknown.Key":   StringAnnotation,
+	}
+	for key, typ := range want {
+		if got := AnnotationTypeOf(key); got != typ {
+			t.Errorf("%s: got type %v, want %v", key, got, typ)
+		}
+	}
+	if got := AnnotationTypes()["Typed.Code"]; got != NumberAnnotation {
+		t.Errorf("AnnotationTypes: got %v for Typed.Code, want %v", got, NumberAnnotation)
+	}
+}
+
+func TestCompareAnnotationValues(t *testing.T) {
+	RegisterAnnotationType("test.num", NumberAnnotation)
+	RegisterAnnotationType("test.time", TimeAnnotation)
+
+	tests := []struct {
+		key  string
+		a, b string
+		want int
+	}{
+		{"test.num", "20", "100", -1},
+		{"test.num", "100", "20", 1},
+		{"test.num", "1.5", "1.50", 0},
+		{"test.num", "abc", "100", 1}, // unparseable, lexical
+		{"test.time", "2016-01-01T00:00:00Z", "2015-12-31T23:00:00-02:00", -1},
+		{"test.time", "2016-01-01T00:00:00Z", "2016-01-01T01:00:00+01:00", 0},
+		{"test.unregistered", "20", "100", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareAnnotationValues(tt.key, []byte(tt.a), []byte(tt.b)); got != tt.want {
+			t.Errorf("%s: compare %q, %q: got %d, want %d", tt.key, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package appdash
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultMaxAnnotations is the number of annotations per collection that
+// an AnnotationLimitCollector allows when its Max is zero. It is well above
+// what any ordinary span records, so that only pathological events (such as
+// an enormous, deeply nested struct) reach it.
+const DefaultMaxAnnotations = 10000
+
+// AnnotationsTruncatedKey is the reserved annotation key that
+// AnnotationLimitCollector records on a truncated collection. Its value is
+// the number of annotations that were dropped.
+const AnnotationsTruncatedKey = "_annotationsTruncated"
+
+// An OverflowPolicy determines what an AnnotationLimitCollector does with
+// the collections that have too many annotations.
+type OverflowPolicy int
+
+const (
+	// TruncateOverflow drops the annotations beyond the limit, and passes
+	// on the rest along with an AnnotationsTruncatedKey annotation.
+	TruncateOverflow OverflowPolicy = iota
+
+	// RejectOverflow rejects the whole collection, returning an error.
+	RejectOverflow
+)
+
+// An AnnotationLimitCollector is a Collector that caps the number of
+// annotations passed on in a single collection, so that one pathological
+// event cannot overwhelm the wire protocol or the store downstream.
+// Collections over the cap are truncated or rejected according to its
+// Policy, and counted (see Truncated and Rejected).
+//
+// When truncating, the schema annotations of events are kept, so that the
+// events whose fields survive are still recognized, and the other
+// annotations are kept in order up to the cap.
+type AnnotationLimitCollector struct {
+	// Collector is the underlying collector that spans are sent to.
+	Collector
+
+	// Max is the greatest number of annotations passed on per collection,
+	// including the AnnotationsTruncatedKey marker of a truncated one. If
+	// zero, DefaultMaxAnnotations is used.
+	Max int
+
+	// Policy determines what happens to collections over Max.
+	Policy OverflowPolicy
+
+	truncated, rejected int64 // accessed atomically
+}
+
+// NewAnnotationLimitCollector is shorthand for:
+//
+// 	c := &AnnotationLimitCollector{
+// 		Collector: c,
+// 		Max:       max,
+// 		Policy:    policy,
+// 	}
+//
+func NewAnnotationLimitCollector(c Collector, max int, policy OverflowPolicy) *AnnotationLimitCollector {
+	return &AnnotationLimitCollector{Collector: c, Max: max, Policy: policy}
+}
+
+// Collect implements the Collector interface.
+func (lc *AnnotationLimitCollector) Collect(span SpanID, anns ...Annotation) error {
+	max := lc.max()
+	if len(anns) <= max {
+		return lc.Collector.Collect(span, anns...)
+	}
+	if lc.Policy == RejectOverflow {
+		atomic.AddInt64(&lc.rejected, 1)
+		return fmt.Errorf("AnnotationLimitCollector: span %v has %d annotations (max %d)", span, len(anns), max)
+	}
+	atomic.AddInt64(&lc.truncated, 1)
+
+	// Make room for the marker and the schema annotations, then keep the
+	// others in order.
+	room := max - 1
+	for _, a := range anns {
+		if strings.HasPrefix(a.Key, schemaPrefix) {
+			room--
+		}
+	}
+	kept := make([]Annotation, 0, max)
+	for _, a := range anns {
+		if strings.HasPrefix(a.Key, schemaPrefix) {
+			if len(kept) < max-1 {
+				kept = append(kept, a)
+			}
+		} else if room > 0 {
+			kept = append(kept, a)
+			room--
+		}
+	}
+	kept = append(kept, Annotation{Key: AnnotationsTruncatedKey, Value: []byte(strconv.Itoa(len(anns) - len(kept)))})
+	return lc.Collector.Collect(span, kept...)
+}
+
+func (lc *AnnotationLimitCollector) max() int {
+	if lc.Max <= 0 {
+		return DefaultMaxAnnotations
+	}
+	return lc.Max
+}
+
+// Truncated returns the number of collections truncated under the
+// TruncateOverflow policy.
+func (lc *AnnotationLimitCollector) Truncated() int64 {
+	return atomic.LoadInt64(&lc.truncated)
+}
+
+// Rejected returns the number of collections rejected under the
+// RejectOverflow policy.
+func (lc *AnnotationLimitCollector) Rejected() int64 {
+	return atomic.LoadInt64(&lc.rejected)
+}
@@ -0,0 +1,76 @@
+package appdash
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestAnnotationLimitCollector(t *testing.T) {
+	var got Annotations
+	c := collectorFunc(func(span SpanID, as ...Annotation) error {
+		got = as
+		return nil
+	})
+	many := func(n int) Annotations {
+		as := make(Annotations, n)
+		for i := range as {
+			as[i] = Annotation{Key: "Big.Field" + strconv.Itoa(i), Value: []byte("v")}
+		}
+		return as
+	}
+
+	// Under the cap, collections pass unchanged.
+	lc := NewAnnotationLimitCollector(c, 5, TruncateOverflow)
+	for _, n := range []int{0, 4, 5} {
+		if err := lc.Collect(SpanID{1, 2, 0}, many(n)...); err != nil {
+			t.Fatal(err)
+		}
+		if want := many(n); len(got) != n || n > 0 && !reflect.DeepEqual(got, want) {
+			t.Errorf("%d annotations: got %v, want them unchanged", n, got)
+		}
+	}
+	if n := lc.Truncated(); n != 0 {
+		t.Errorf("got %d truncated collections, want 0", n)
+	}
+
+	// Over it, they are truncated with a marker, keeping schemas.
+	over := append(many(8), Annotation{Key: schemaPrefix + "big"})
+	if err := lc.Collect(SpanID{1, 2, 0}, over...); err != nil {
+		t.Fatal(err)
+	}
+	want := Annotations{over[0], over[1], over[2], over[8], {Key: AnnotationsTruncatedKey, Value: []byte("5")}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got truncated annotations %v, want %v", got, want)
+	}
+	if n := lc.Truncated(); n != 1 {
+		t.Errorf("got %d truncated collections, want 1", n)
+	}
+
+	// Or rejected.
+	got = nil
+	lc = NewAnnotationLimitCollector(c, 5, RejectOverflow)
+	if err := lc.Collect(SpanID{1, 2, 0}, over...); err == nil {
+		t.Error("got no error for a rejected collection")
+	}
+	if got != nil {
+		t.Errorf("rejected collection was passed on as %v", got)
+	}
+	if n := lc.Rejected(); n != 1 {
+		t.Errorf("got %d rejected collections, want 1", n)
+	}
+}
+
+func TestAnnotationLimitCollector_defaultMax(t *testing.T) {
+	var got int
+	lc := &AnnotationLimitCollector{Collector: collectorFunc(func(span SpanID, as ...Annotation) error {
+		got = len(as)
+		return nil
+	})}
+	if err := lc.Collect(SpanID{1, 2, 0}, make([]Annotation, DefaultMaxAnnotations+1)...); err != nil {
+		t.Fatal(err)
+	}
+	if got != DefaultMaxAnnotations || lc.Truncated() != 1 {
+		t.Errorf("got %d annotations and %d truncations, want %d and 1", got, lc.Truncated(), DefaultMaxAnnotations)
+	}
+}
@@ -0,0 +1,85 @@
+// Package appdashtest provides utilities for testing instrumentation that
+// records appdash spans.
+//
+// Recorded annotations contain details, such as timestamps and generated
+// span IDs, that vary between runs. Canonicalize and AnnotationsEqual
+// compare annotations in a way that is insensitive to their order, the time
+// zone of timestamps and any keys the test chooses to ignore:
+//
+// 	got, _ := appdash.MarshalEvent(recorded)
+// 	want, _ := appdash.MarshalEvent(httptrace.ServerEvent{...})
+// 	if !appdashtest.AnnotationsEqual(got, want, "Server.Request.Headers") {
+// 		t.Errorf("got %v, want %v", got, want)
+// 	}
+//
+// StoreTest checks that a Store implementation behaves as the appdash.Store
+// interface documents.
+package appdashtest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// Canonicalize returns a copy of as in canonical form: annotations are
+// sorted by key (keeping the relative order of annotations with the same
+// key), values of keys registered as appdash.TimeAnnotation are converted
+// to UTC, and annotations whose keys are in ignoreKeys are removed. An
+// ignored key also ignores all keys nested below it, so that e.g.
+// "Server.Request.Headers" ignores every request header.
+func Canonicalize(as appdash.Annotations, ignoreKeys ...string) appdash.Annotations {
+	c := make(appdash.Annotations, 0, len(as))
+	for _, a := range as {
+		if ignored(a.Key, ignoreKeys) {
+			continue
+		}
+		if appdash.AnnotationTypeOf(a.Key) == appdash.TimeAnnotation {
+			if t, err := time.Parse(time.RFC3339Nano, string(a.Value)); err == nil {
+				a.Value = []byte(t.UTC().Format(time.RFC3339Nano))
+			}
+		}
+		c = append(c, a)
+	}
+	sort.Stable(byKey(c))
+	return c
+}
+
+// CanonicalSpan returns a copy of s with its annotations canonicalized (see
+// Canonicalize).
+func CanonicalSpan(s appdash.Span, ignoreKeys ...string) appdash.Span {
+	s.Annotations = Canonicalize(s.Annotations, ignoreKeys...)
+	return s
+}
+
+// AnnotationsEqual reports whether a and b are equal once canonicalized
+// (see Canonicalize) with the given keys ignored.
+func AnnotationsEqual(a, b appdash.Annotations, ignoreKeys ...string) bool {
+	ca, cb := Canonicalize(a, ignoreKeys...), Canonicalize(b, ignoreKeys...)
+	if len(ca) != len(cb) {
+		return false
+	}
+	for i := range ca {
+		if ca[i].Key != cb[i].Key || string(ca[i].Value) != string(cb[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func ignored(key string, ignoreKeys []string) bool {
+	for _, k := range ignoreKeys {
+		if key == k || strings.HasPrefix(key, k+".") {
+			return true
+		}
+	}
+	return false
+}
+
+type byKey appdash.Annotations
+
+func (v byKey) Len() int           { return len(v) }
+func (v byKey) Less(i, j int) bool { return v[i].Key < v[j].Key }
+func (v byKey) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
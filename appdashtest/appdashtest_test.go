@@ -0,0 +1,65 @@
+package appdashtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+type timeEvent struct {
+	Name string    `trace:"Test.Name"`
+	At   time.Time `trace:"Test.At"`
+}
+
+func (timeEvent) Schema() string { return "appdashtestTime" }
+
+func init() { appdash.RegisterEvent(timeEvent{}) }
+
+func TestCanonicalize(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	as := appdash.Annotations{
+		{Key: "Test.Name", Value: []byte("b")},
+		{Key: "Test.At", Value: []byte(time.Date(2000, 1, 1, 7, 0, 0, 0, est).Format(time.RFC3339Nano))},
+		{Key: "Headers.X-Id", Value: []byte("123")},
+		{Key: "Test.Name", Value: []byte("a")},
+	}
+	want := appdash.Annotations{
+		{Key: "Test.At", Value: []byte("2000-01-01T12:00:00Z")},
+		{Key: "Test.Name", Value: []byte("b")},
+		{Key: "Test.Name", Value: []byte("a")},
+	}
+	if got := Canonicalize(as, "Headers"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if as[0].Key != "Test.Name" {
+		t.Error("Canonicalize modified its input")
+	}
+}
+
+func TestAnnotationsEqual(t *testing.T) {
+	at := time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+	a, err := appdash.MarshalEvent(timeEvent{Name: "x", At: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := appdash.MarshalEvent(timeEvent{Name: "x", At: at.In(time.FixedZone("CET", 60*60))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !AnnotationsEqual(a, b) {
+		t.Errorf("got %v != %v, want them equal in different time zones", a, b)
+	}
+
+	c, err := appdash.MarshalEvent(timeEvent{Name: "y", At: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if AnnotationsEqual(a, c) {
+		t.Errorf("got %v == %v, want them unequal", a, c)
+	}
+	if !AnnotationsEqual(a, c, "Test.Name") {
+		t.Errorf("got %v != %v, want them equal ignoring Test.Name", a, c)
+	}
+}
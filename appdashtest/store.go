@@ -0,0 +1,277 @@
+package appdashtest
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// StoreTest runs a suite of conformance tests against a Store
+// implementation, checking that it behaves as the appdash.Store interface
+// documents. newStore is called to create a new, empty store for each test.
+// If the store also implements appdash.Queryer or appdash.DeleteStore,
+// those interfaces are tested as well.
+//
+// A store's own tests typically call it as:
+//
+// 	func TestMyStore(t *testing.T) {
+// 		appdashtest.StoreTest(t, func() appdash.Store { return NewMyStore() })
+// 	}
+func StoreTest(t *testing.T, newStore func() appdash.Store) {
+	tests := []struct {
+		name string
+		test func(*testing.T, appdash.Store)
+	}{
+		{"CollectThenRetrieve", testStoreCollectThenRetrieve},
+		{"AnnotationOrder", testStoreAnnotationOrder},
+		{"ChildBeforeParent", testStoreChildBeforeParent},
+		{"EmptySpan", testStoreEmptySpan},
+		{"UnknownTrace", testStoreUnknownTrace},
+		{"ConcurrentCollect", testStoreConcurrentCollect},
+		{"Queryer", testStoreQueryer},
+		{"Delete", testStoreDelete},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) { test.test(t, newStore()) })
+	}
+}
+
+// mustCollect collects the span, failing the test on error.
+func mustCollect(t *testing.T, s appdash.Store, span appdash.SpanID, as ...appdash.Annotation) {
+	t.Helper()
+	if err := s.Collect(span, as...); err != nil {
+		t.Fatalf("Collect(%v): %s", span, err)
+	}
+}
+
+// mustTrace retrieves the trace, failing the test on error.
+func mustTrace(t *testing.T, s appdash.Store, id appdash.ID) *appdash.Trace {
+	t.Helper()
+	trace, err := s.Trace(id)
+	if err != nil {
+		t.Fatalf("Trace(%v): %s", id, err)
+	}
+	return trace
+}
+
+func ann(k, v string) appdash.Annotation { return appdash.Annotation{Key: k, Value: []byte(v)} }
+
+// Spans collected with their annotations come back as a tree.
+func testStoreCollectThenRetrieve(t *testing.T, s appdash.Store) {
+	root := appdash.SpanID{Trace: 1, Span: 10}
+	child := appdash.SpanID{Trace: 1, Span: 11, Parent: 10}
+	grandchild := appdash.SpanID{Trace: 1, Span: 12, Parent: 11}
+	mustCollect(t, s, root, ann("Name", "root"))
+	mustCollect(t, s, child, ann("Name", "child"))
+	mustCollect(t, s, grandchild, ann("Name", "grandchild"), ann("k", "v"))
+
+	trace := mustTrace(t, s, 1)
+	if trace.Span.ID != root {
+		t.Errorf("got root span %v, want %v", trace.Span.ID, root)
+	}
+	if len(trace.Sub) != 1 || len(trace.Sub[0].Sub) != 1 {
+		t.Fatalf("got trace %v, want a chain of 3 spans", trace)
+	}
+	got := trace.Sub[0].Sub[0]
+	if got.Span.ID != grandchild {
+		t.Errorf("got grandchild span %v, want %v", got.Span.ID, grandchild)
+	}
+	if want := (appdash.Annotations{ann("Name", "grandchild"), ann("k", "v")}); !AnnotationsEqual(got.Span.Annotations, want) {
+		t.Errorf("got grandchild annotations %v, want %v", got.Span.Annotations, want)
+	}
+
+	// Other traces are unaffected.
+	mustCollect(t, s, appdash.SpanID{Trace: 2, Span: 20}, ann("Name", "other"))
+	if trace := mustTrace(t, s, 1); trace.Span.Name() != "root" || len(trace.Sub) != 1 {
+		t.Errorf("collecting another trace changed trace 1 to %v", trace)
+	}
+}
+
+// Annotations of a span collected in several calls are kept in the order
+// they were collected.
+func testStoreAnnotationOrder(t *testing.T, s appdash.Store) {
+	span := appdash.SpanID{Trace: 1, Span: 10}
+	mustCollect(t, s, span, ann("a", "1"), ann("b", "2"))
+	mustCollect(t, s, span, ann("a", "3"))
+	want := appdash.Annotations{ann("a", "1"), ann("b", "2"), ann("a", "3")}
+	if got := mustTrace(t, s, 1).Span.Annotations; !reflect.DeepEqual(got, want) {
+		t.Errorf("got annotations %v, want %v (in order)", got, want)
+	}
+}
+
+// A child collected before its parent is placed under it once the parent
+// is collected.
+func testStoreChildBeforeParent(t *testing.T, s appdash.Store) {
+	mustCollect(t, s, appdash.SpanID{Trace: 1, Span: 12, Parent: 11}, ann("Name", "grandchild"))
+	mustCollect(t, s, appdash.SpanID{Trace: 1, Span: 11, Parent: 10}, ann("Name", "child"))
+	mustCollect(t, s, appdash.SpanID{Trace: 1, Span: 10}, ann("Name", "root"))
+
+	trace := mustTrace(t, s, 1)
+	if trace.Span.Name() != "root" {
+		t.Fatalf("got root span %v, want the span collected last", trace.Span.ID)
+	}
+	if len(trace.Sub) != 1 || trace.Sub[0].Span.Name() != "child" ||
+		len(trace.Sub[0].Sub) != 1 || trace.Sub[0].Sub[0].Span.Name() != "grandchild" {
+		t.Errorf("got trace %v, want root > child > grandchild", trace)
+	}
+}
+
+// A trace whose spans have no annotations is found.
+func testStoreEmptySpan(t *testing.T, s appdash.Store) {
+	mustCollect(t, s, appdash.SpanID{Trace: 1, Span: 10})
+	trace := mustTrace(t, s, 1)
+	if len(trace.Span.Annotations) != 0 {
+		t.Errorf("got annotations %v, want none", trace.Span.Annotations)
+	}
+}
+
+// A trace that was never collected is reported as
+// appdash.ErrTraceNotFound itself.
+func testStoreUnknownTrace(t *testing.T, s appdash.Store) {
+	mustCollect(t, s, appdash.SpanID{Trace: 1, Span: 10}, ann("Name", "root"))
+	if _, err := s.Trace(2); err != appdash.ErrTraceNotFound {
+		t.Errorf("got error %v for an unknown trace, want appdash.ErrTraceNotFound", err)
+	}
+}
+
+// Concurrent collections are all stored.
+func testStoreConcurrentCollect(t *testing.T, s appdash.Store) {
+	const goroutines, traces = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < traces; i++ {
+				id := appdash.ID(g*traces + i + 1)
+				for _, span := range []appdash.SpanID{{Trace: id, Span: id}, {Trace: id, Span: id + 1000, Parent: id}} {
+					if err := s.Collect(span, ann("Name", fmt.Sprint(span.Span))); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	for id := appdash.ID(1); id <= goroutines*traces; id++ {
+		trace, err := s.Trace(id)
+		if err != nil {
+			t.Errorf("trace %v: %s", id, err)
+			continue
+		}
+		if len(trace.Sub) != 1 {
+			t.Errorf("trace %v: got %d sub-spans, want 1", id, len(trace.Sub))
+		}
+	}
+}
+
+// A Queryer lists the collected traces, filters them by ID, and sorts them
+// by recency on request.
+func testStoreQueryer(t *testing.T, s appdash.Store) {
+	q, ok := s.(appdash.Queryer)
+	if !ok {
+		t.Skip("store does not implement appdash.Queryer")
+	}
+	base := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []appdash.ID{2, 3, 1} {
+		as, err := appdash.MarshalEvent(appdash.Timespan{S: base.Add(time.Duration(i) * time.Second), E: base.Add(time.Duration(i)*time.Second + time.Millisecond)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mustCollect(t, s, appdash.SpanID{Trace: id, Span: id}, as...)
+	}
+
+	traceIDs := func(traces []*appdash.Trace) []appdash.ID {
+		var ids []appdash.ID
+		for _, t := range traces {
+			ids = append(ids, t.ID.Trace)
+		}
+		return ids
+	}
+	sortIDs := func(ids []appdash.ID) []appdash.ID {
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				if ids[j] < ids[i] {
+					ids[i], ids[j] = ids[j], ids[i]
+				}
+			}
+		}
+		return ids
+	}
+
+	all, err := q.Traces(appdash.TracesOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sortIDs(traceIDs(all)), []appdash.ID{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got traces %v, want %v", got, want)
+	}
+
+	some, err := q.Traces(appdash.TracesOpts{TraceIDs: []appdash.ID{3, 1, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sortIDs(traceIDs(some)), []appdash.ID{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got traces %v filtered by ID, want %v", got, want)
+	}
+
+	recent, err := q.Traces(appdash.TracesOpts{SortByRecency: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := traceIDs(recent), []appdash.ID{1, 3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got traces %v sorted by recency, want %v", got, want)
+	}
+}
+
+// A DeleteStore deletes the given traces, and those matching filters.
+func testStoreDelete(t *testing.T, s appdash.Store) {
+	ds, ok := s.(appdash.DeleteStore)
+	if !ok {
+		t.Skip("store does not implement appdash.DeleteStore")
+	}
+	for id := appdash.ID(1); id <= 4; id++ {
+		mustCollect(t, s, appdash.SpanID{Trace: id, Span: id}, ann("Name", fmt.Sprintf("trace %d", id)))
+		mustCollect(t, s, appdash.SpanID{Trace: id, Span: id + 10, Parent: id})
+	}
+
+	if err := ds.Delete(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []appdash.ID{1, 2} {
+		if _, err := s.Trace(id); err != appdash.ErrTraceNotFound {
+			t.Errorf("trace %v: got error %v after Delete, want appdash.ErrTraceNotFound", id, err)
+		}
+	}
+
+	n, err := ds.DeleteWhere(func(t *appdash.Trace) bool { return t.Span.Name() == "trace 3" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("got %d traces deleted by DeleteWhere, want 1", n)
+	}
+	if _, err := s.Trace(3); err != appdash.ErrTraceNotFound {
+		t.Errorf("trace 3: got error %v after DeleteWhere, want appdash.ErrTraceNotFound", err)
+	}
+	if trace := mustTrace(t, s, 4); len(trace.Sub) != 1 {
+		t.Errorf("got trace 4 %v after deleting others, want it intact", trace)
+	}
+
+	// A deleted trace may be collected anew.
+	mustCollect(t, s, appdash.SpanID{Trace: 1, Span: 1}, ann("Name", "again"))
+	if trace := mustTrace(t, s, 1); trace.Span.Name() != "again" || len(trace.Sub) != 0 {
+		t.Errorf("got trace %v collected after deletion, want only the new span", trace)
+	}
+}
@@ -0,0 +1,81 @@
+package appdash
+
+import "runtime/debug"
+
+func init() {
+	RegisterEvent(BuildEvent{})
+}
+
+// BuildEvent records the build of the program that recorded a span, so
+// that a regression seen in traces can be correlated with a release. See
+// BuildCollector.
+type BuildEvent struct {
+	Version  string `trace:"Build.Version"`  // the version of the main module, such as v1.2.3
+	Revision string `trace:"Build.Revision"` // the VCS revision, such as a git commit hash
+}
+
+// Schema returns the constant "build".
+func (BuildEvent) Schema() string { return "build" }
+
+// Important implements the ImportantEvent interface, so that the build is
+// displayed in the web UI.
+func (BuildEvent) Important() []string { return []string{"Build.Version", "Build.Revision"} }
+
+// ReadBuildInfo returns the build of the running program, as embedded in
+// its binary by the Go toolchain (see runtime/debug.ReadBuildInfo). Fields
+// that are unknown, such as the revision of a program built outside of a
+// VCS checkout, are empty.
+func ReadBuildInfo() BuildEvent {
+	var e BuildEvent
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return e
+	}
+	if v := bi.Main.Version; v != "(devel)" {
+		e.Version = v
+	}
+	for _, s := range bi.Settings {
+		if s.Key == "vcs.revision" {
+			e.Revision = s.Value
+		}
+	}
+	return e
+}
+
+// A BuildCollector is a Collector that adds a BuildEvent to the spans
+// collected through it before passing them on, so that every span records
+// the build of the program that recorded it.
+type BuildCollector struct {
+	// Collector is the underlying collector that spans are sent to.
+	Collector
+
+	// Build is the build that spans are tagged with.
+	Build BuildEvent
+}
+
+// NewBuildCollector returns a BuildCollector that tags spans with the build
+// of the running program (see ReadBuildInfo). The non-empty fields of
+// overrides take precedence, e.g. to set a version that is only known to
+// the release process.
+func NewBuildCollector(c Collector, overrides BuildEvent) *BuildCollector {
+	b := ReadBuildInfo()
+	if overrides.Version != "" {
+		b.Version = overrides.Version
+	}
+	if overrides.Revision != "" {
+		b.Revision = overrides.Revision
+	}
+	return &BuildCollector{Collector: c, Build: b}
+}
+
+// Collect implements the Collector interface.
+func (bc *BuildCollector) Collect(span SpanID, anns ...Annotation) error {
+	if !Annotations(anns).has(schemaPrefix + bc.Build.Schema()) {
+		as, err := MarshalEvent(bc.Build)
+		if err != nil {
+			return err
+		}
+		anns = append(anns[:len(anns):len(anns)], as...)
+	}
+	return bc.Collector.Collect(span, anns...)
+}
@@ -0,0 +1,37 @@
+package appdash
+
+import "testing"
+
+func TestBuildCollector(t *testing.T) {
+	ms := NewMemoryStore()
+	bc := NewBuildCollector(ms, BuildEvent{Version: "v1.2.3", Revision: "abc123"})
+	if want := (BuildEvent{Version: "v1.2.3", Revision: "abc123"}); bc.Build != want {
+		t.Errorf("got build %+v, want the overrides %+v", bc.Build, want)
+	}
+
+	rec := NewRecorder(SpanID{Trace: 1, Span: 1}, bc)
+	rec.Name("root")
+	child := rec.Child()
+	child.Name("child")
+	child.Finish()
+	rec.Finish()
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, span := range []*Span{&trace.Span, &trace.Sub[0].Span} {
+		var e BuildEvent
+		if err := UnmarshalEvent(span.Annotations, &e); err != nil {
+			t.Fatalf("span %s: %s", span.Name(), err)
+		}
+		if e != bc.Build {
+			t.Errorf("span %s: got build %+v, want %+v", span.Name(), e, bc.Build)
+		}
+	}
+
+	// Overrides only replace the fields they set.
+	if b := NewBuildCollector(ms, BuildEvent{Revision: "def456"}).Build; b.Revision != "def456" || b.Version != ReadBuildInfo().Version {
+		t.Errorf("got build %+v, want revision def456 and the binary's version", b)
+	}
+}
@@ -0,0 +1,51 @@
+package appdash
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// BaggageKeyPrefix is the prefix of the annotation keys under which
+// ResumeRecorder records the baggage of a resumed span context.
+const BaggageKeyPrefix = "Baggage."
+
+// A SpanContext is what a process must remember to continue a trace later,
+// such as after a restart in the middle of a long-running workflow: the ID
+// of the span to continue from, and its baggage, which are arbitrary
+// key-value pairs that the application carries along with the trace.
+type SpanContext struct {
+	SpanID  SpanID
+	Baggage map[string]string `json:",omitempty"`
+}
+
+// MarshalSpanContext encodes sc for durable storage, such as in the
+// checkpoint of a workflow. The encoding is JSON, with the IDs formatted as
+// hex strings.
+func MarshalSpanContext(sc SpanContext) ([]byte, error) {
+	return json.Marshal(sc)
+}
+
+// UnmarshalSpanContext decodes a span context encoded by
+// MarshalSpanContext.
+func UnmarshalSpanContext(data []byte) (SpanContext, error) {
+	var sc SpanContext
+	if err := json.Unmarshal(data, &sc); err != nil {
+		return SpanContext{}, err
+	}
+	if sc.SpanID.Trace == 0 || sc.SpanID.Span == 0 {
+		return SpanContext{}, errors.New("appdash: span context has no span ID")
+	}
+	return sc, nil
+}
+
+// ResumeRecorder returns a recorder for a new child of the span of sc, so
+// that the spans recorded after restoring a checkpointed span context are
+// linked into the original trace. The baggage of sc is recorded on the new
+// span, with keys prefixed by BaggageKeyPrefix.
+func ResumeRecorder(sc SpanContext, c Collector) *Recorder {
+	r := NewRecorder(NewSpanID(sc.SpanID), c)
+	for k, v := range sc.Baggage {
+		r.Annotation(Annotation{Key: BaggageKeyPrefix + k, Value: []byte(v)})
+	}
+	return r
+}
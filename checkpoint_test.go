@@ -0,0 +1,68 @@
+package appdash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResumeRecorder(t *testing.T) {
+	ms := NewMemoryStore()
+
+	// Before the restart, the workflow records its root span and a step,
+	// and checkpoints its span context.
+	rec := NewRecorder(NewRootSpanID(), NewLocalCollector(ms))
+	rec.Name("workflow")
+	step := rec.Child()
+	step.Name("step 1")
+	step.Finish()
+	want := SpanContext{SpanID: rec.SpanID, Baggage: map[string]string{"user": "alice"}}
+	checkpoint, err := MarshalSpanContext(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Finish()
+
+	// After the restart, only the checkpoint is left.
+	sc, err := UnmarshalSpanContext(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sc, want) {
+		t.Fatalf("got span context %+v, want %+v", sc, want)
+	}
+	resumed := ResumeRecorder(sc, NewLocalCollector(ms))
+	resumed.Name("step 2")
+	resumed.Finish()
+	if errs := resumed.Errors(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	trace, err := ms.Trace(want.SpanID.Trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.Sub) != 2 {
+		t.Fatalf("got %d sub-spans of the root, want 2", len(trace.Sub))
+	}
+	var step2 *Trace
+	for _, sub := range trace.Sub {
+		if sub.Span.Name() == "step 2" {
+			step2 = sub
+		}
+	}
+	if step2 == nil {
+		t.Fatal("resumed span is not a child of the checkpointed span")
+	}
+	if step2.ID.Parent != want.SpanID.Span || step2.ID.Trace != want.SpanID.Trace {
+		t.Errorf("got resumed span ID %v, want a child of %v", step2.ID, want.SpanID)
+	}
+	if got := string(step2.Span.Annotations.get("Baggage.user")); got != "alice" {
+		t.Errorf("got baggage %q on resumed span, want %q", got, "alice")
+	}
+
+	for _, data := range []string{"", "{}", `{"SpanID":{"Trace":"zz"}}`} {
+		if _, err := UnmarshalSpanContext([]byte(data)); err == nil {
+			t.Errorf("%q: got no error", data)
+		}
+	}
+}
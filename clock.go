@@ -0,0 +1,18 @@
+package appdash
+
+import "time"
+
+// A Clock tells the current time. Recorders and the httptrace package read
+// the time from a Clock, so that tests may supply a deterministic one.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// RealClock is the Clock that reads the system time via time.Now. It is
+// used wherever no other Clock is configured.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
@@ -4,6 +4,7 @@ import (
 	"crypto/subtle"
 	"crypto/tls"
 	"encoding/base64"
+	"expvar"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -38,6 +39,10 @@ type ServeCmd struct {
 	HTTPAddr      string `long:"http" description:"HTTP listen address" default:":7700"`
 	SampleData    bool   `long:"sample-data" description:"add sample data"`
 
+	Replay     string  `long:"replay" description:"continuously replay the traces of a recording file into the store, with recent timestamps (for demos)"`
+	ReplayRate float64 `long:"replay-rate" description:"number of recorded collections replayed per second (0 for each pass at once)" default:"10"`
+	ReplayOnce bool    `long:"replay-once" description:"replay the recording only once, rather than in a loop"`
+
 	StoreFile       string        `short:"f" long:"store-file" description:"persisted store file" default:"/tmp/appdash.gob"`
 	PersistInterval time.Duration `short:"p" long:"persist-interval" description:"interval between persisting store to file" default:"2s"`
 
@@ -50,6 +55,8 @@ type ServeCmd struct {
 	TLSKey  string `long:"tls-key" description:"TLS key file (if set, enables TLS)"`
 
 	BasicAuth string `long:"basic-auth" description:"if set to 'user:passwd', require HTTP Basic Auth for web app"`
+
+	DebugAddr string `long:"debug-http" description:"if set, serve ingestion metrics (expvar, at /debug/vars) on this address"`
 }
 
 var serveCmd ServeCmd
@@ -61,7 +68,10 @@ func (c *ServeCmd) Execute(args []string) error {
 		memStore = appdash.NewMemoryStore()
 		Store    = appdash.Store(memStore)
 		Queryer  = memStore
+		stats    = &appdash.Stats{}
 	)
+	memStore.Stats = stats
+	expvar.Publish("appdash", stats)
 
 	if c.StoreFile != "" {
 		f, err := os.Open(c.StoreFile)
@@ -126,6 +136,21 @@ func (c *ServeCmd) Execute(args []string) error {
 	if c.SampleData {
 		sampleData(Store)
 	}
+	if c.Replay != "" {
+		f, err := os.Open(c.Replay)
+		if err != nil {
+			return err
+		}
+		lr, err := appdash.NewLiveReplayer(f, Store)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		lr.Rate, lr.Loop = c.ReplayRate, !c.ReplayOnce
+		lr.Log = log.New(os.Stderr, "appdash: ", log.LstdFlags)
+		lr.Start()
+		log.Printf("Replaying %s", c.Replay)
+	}
 
 	var l net.Listener
 	var proto string
@@ -159,6 +184,14 @@ func (c *ServeCmd) Execute(args []string) error {
 		proto = "plaintext TCP (no security)"
 	}
 	log.Printf("appdash collector listening on %s (%s)", c.CollectorAddr, proto)
+	if c.DebugAddr != "" {
+		// expvar registers its handler on http.DefaultServeMux.
+		log.Printf("appdash debug HTTP server listening on %s", c.DebugAddr)
+		go func() {
+			log.Fatal(http.ListenAndServe(c.DebugAddr, nil))
+		}()
+	}
+
 	cs := appdash.NewServer(l, appdash.NewLocalCollector(Store))
 	cs.Debug = c.Debug
 	cs.Trace = c.Trace
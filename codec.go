@@ -0,0 +1,345 @@
+package appdash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	"sourcegraph.com/sourcegraph/appdash/internal/wire"
+)
+
+// A Codec encodes batches of spans for the collector wire protocol (see
+// RemoteCollector.Codec). Codecs trade CPU for payload size differently, so
+// the best choice depends on the deployment.
+//
+// Each frame sent with a codec starts with a header naming the codec's ID,
+// and a CollectorServer decodes every frame with the registered codec it
+// names (see RegisterCodec). Frames of clients that set no codec have no
+// header and hold a single protobuf-encoded span, as in earlier versions of
+// the protocol. Servers must therefore be upgraded before their clients
+// start using a codec, but a server can receive from clients using
+// different codecs at once.
+type Codec interface {
+	// CodecID returns the ID identifying the codec in frame headers. It
+	// must be nonzero and unique among the registered codecs.
+	CodecID() byte
+
+	// Marshal encodes the spans.
+	Marshal(spans []Span) ([]byte, error)
+
+	// Unmarshal decodes spans encoded by Marshal.
+	Unmarshal(data []byte) ([]Span, error)
+}
+
+var (
+	// ProtobufCodec encodes spans as consecutive length-prefixed protobuf
+	// messages, as in the headerless frames of the original protocol.
+	ProtobufCodec Codec = protobufCodec{}
+
+	// MsgpackCodec encodes spans in MessagePack. Span IDs take only as many
+	// bytes as their values need, making it more compact than
+	// ProtobufCodec for spans with few, small annotations.
+	MsgpackCodec Codec = msgpackCodec{}
+)
+
+var (
+	codecs   = map[byte]Codec{}
+	codecsMu sync.RWMutex
+)
+
+func init() {
+	RegisterCodec(ProtobufCodec)
+	RegisterCodec(MsgpackCodec)
+}
+
+// RegisterCodec registers a codec, so that a CollectorServer can decode
+// frames sent with it. ProtobufCodec and MsgpackCodec are registered by
+// default. RegisterCodec panics if c's ID is zero or already registered.
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	id := c.CodecID()
+	if id == 0 {
+		panic("appdash: codec ID must be nonzero")
+	}
+	if _, dup := codecs[id]; dup {
+		panic(fmt.Sprintf("appdash: codec ID %d is already registered", id))
+	}
+	codecs[id] = c
+}
+
+// codecByID returns the registered codec with the given ID, or nil if
+// there is none.
+func codecByID(id byte) Codec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	return codecs[id]
+}
+
+// codecFrameMarker is the first byte of the payload of a frame encoded with
+// a codec, which is followed by the codec ID. Headerless frames never start
+// with it, because protobuf field number 0 is invalid.
+const codecFrameMarker = 0
+
+// errTruncated is returned by codecs for data that ends prematurely.
+var errTruncated = errors.New("truncated span batch")
+
+type protobufCodec struct{}
+
+func (protobufCodec) CodecID() byte { return 1 }
+
+func (protobufCodec) Marshal(spans []Span) ([]byte, error) {
+	var buf []byte
+	for _, s := range spans {
+		b, err := proto.Marshal(newCollectPacket(s.ID, s.Annotations))
+		if err != nil {
+			return nil, err
+		}
+		buf = appendUvarint(buf, uint64(len(b)))
+		buf = append(buf, b...)
+	}
+	return buf, nil
+}
+
+func (protobufCodec) Unmarshal(data []byte) ([]Span, error) {
+	var spans []Span
+	for len(data) > 0 {
+		n, k := binary.Uvarint(data)
+		if k <= 0 || n > uint64(len(data)-k) {
+			return nil, errTruncated
+		}
+		data = data[k:]
+		p := &wire.CollectPacket{}
+		if err := proto.Unmarshal(data[:n], p); err != nil {
+			return nil, err
+		}
+		if !validCollectPacket(p) {
+			return nil, errors.New("collect packet is missing its span ID")
+		}
+		spans = append(spans, Span{ID: spanIDFromWire(p.Spanid), Annotations: annotationsFromWire(p.Annotation)})
+		data = data[n:]
+	}
+	return spans, nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+// msgpackCodec encodes a batch as an array of spans, each an array of its
+// trace, span and parent IDs followed by an array of alternating annotation
+// keys (strings) and values (binary).
+type msgpackCodec struct{}
+
+func (msgpackCodec) CodecID() byte { return 2 }
+
+func (msgpackCodec) Marshal(spans []Span) ([]byte, error) {
+	var e msgpackEncoder
+	e.array(len(spans))
+	for _, s := range spans {
+		e.array(4)
+		e.uint(uint64(s.ID.Trace))
+		e.uint(uint64(s.ID.Span))
+		e.uint(uint64(s.ID.Parent))
+		e.array(2 * len(s.Annotations))
+		for _, a := range s.Annotations {
+			e.bytes(0xa0, 0xd9, []byte(a.Key))
+			e.bytes(0, 0xc4, a.Value)
+		}
+	}
+	return e.buf, nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte) ([]Span, error) {
+	d := msgpackDecoder{buf: data}
+	n := d.array()
+	spans := make([]Span, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		if d.array() != 4 && d.err == nil {
+			d.err = errors.New("malformed span")
+		}
+		s := Span{ID: SpanID{Trace: ID(d.uint()), Span: ID(d.uint()), Parent: ID(d.uint())}}
+		m := d.array()
+		if m%2 != 0 && d.err == nil {
+			d.err = errors.New("malformed annotations")
+		}
+		for j := 0; j < m/2 && d.err == nil; j++ {
+			s.Annotations = append(s.Annotations, Annotation{Key: string(d.bytes()), Value: d.bytes()})
+		}
+		spans = append(spans, s)
+	}
+	if d.err == nil && len(d.buf) > 0 {
+		d.err = fmt.Errorf("%d bytes of trailing data", len(d.buf))
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return spans, nil
+}
+
+// msgpackEncoder writes the subset of MessagePack used by msgpackCodec.
+type msgpackEncoder struct {
+	buf []byte
+}
+
+func (e *msgpackEncoder) array(n int) {
+	switch {
+	case n < 16:
+		e.buf = append(e.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xdc, byte(n>>8), byte(n))
+	default:
+		e.buf = append(e.buf, 0xdd)
+		e.buf = appendUint32(e.buf, uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) uint(v uint64) {
+	switch {
+	case v < 0x80:
+		e.buf = append(e.buf, byte(v))
+	case v <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		e.buf = append(e.buf, 0xcd, byte(v>>8), byte(v))
+	case v <= math.MaxUint32:
+		e.buf = append(e.buf, 0xce)
+		e.buf = appendUint32(e.buf, uint32(v))
+	default:
+		e.buf = append(e.buf, 0xcf)
+		e.buf = appendUint32(e.buf, uint32(v>>32))
+		e.buf = appendUint32(e.buf, uint32(v))
+	}
+}
+
+// bytes writes b as a string (if fix is 0xa0 and family is 0xd9) or as
+// binary data (if fix is 0 and family is 0xc4). Binary data has no fixed
+// size form; the 8, 16 and 32-bit forms follow family.
+func (e *msgpackEncoder) bytes(fix, family byte, b []byte) {
+	n := len(b)
+	switch {
+	case fix != 0 && n < 32:
+		e.buf = append(e.buf, fix|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, family, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, family+1, byte(n>>8), byte(n))
+	default:
+		e.buf = append(e.buf, family+2)
+		e.buf = appendUint32(e.buf, uint32(n))
+	}
+	e.buf = append(e.buf, b...)
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// msgpackDecoder reads the subset of MessagePack written by
+// msgpackEncoder. After the first error, its methods return zero values and
+// the error is kept in err.
+type msgpackDecoder struct {
+	buf []byte
+	err error
+}
+
+// next consumes and returns the next n bytes.
+func (d *msgpackDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = errTruncated
+		return nil
+	}
+	b := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *msgpackDecoder) readByte() byte {
+	if b := d.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+// length reads a big-endian length of the given number of bytes.
+func (d *msgpackDecoder) length(size int) int {
+	var n uint64
+	for _, b := range d.next(size) {
+		n = n<<8 | uint64(b)
+	}
+	if n > uint64(len(d.buf)) {
+		// Every element takes at least a byte, so this is a corrupt
+		// length; reject it before allocating for it.
+		d.err = errTruncated
+		return 0
+	}
+	return int(n)
+}
+
+func (d *msgpackDecoder) array() int {
+	switch t := d.readByte(); {
+	case d.err != nil:
+		return 0
+	case t&0xf0 == 0x90:
+		return int(t & 0x0f)
+	case t == 0xdc:
+		return d.length(2)
+	case t == 0xdd:
+		return d.length(4)
+	default:
+		d.err = fmt.Errorf("expected array, got type byte %#x", t)
+		return 0
+	}
+}
+
+func (d *msgpackDecoder) uint() uint64 {
+	size := 0
+	switch t := d.readByte(); {
+	case d.err != nil:
+		return 0
+	case t < 0x80:
+		return uint64(t)
+	case t >= 0xcc && t <= 0xcf:
+		size = 1 << (t - 0xcc)
+	default:
+		d.err = fmt.Errorf("expected unsigned integer, got type byte %#x", t)
+		return 0
+	}
+	var v uint64
+	for _, b := range d.next(size) {
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
+
+func (d *msgpackDecoder) bytes() []byte {
+	var n int
+	switch t := d.readByte(); {
+	case d.err != nil:
+		return nil
+	case t&0xe0 == 0xa0:
+		n = int(t & 0x1f)
+	case t == 0xd9 || t == 0xc4:
+		n = d.length(1)
+	case t == 0xda || t == 0xc5:
+		n = d.length(2)
+	case t == 0xdb || t == 0xc6:
+		n = d.length(4)
+	default:
+		d.err = fmt.Errorf("expected string or binary data, got type byte %#x", t)
+		return nil
+	}
+	if n == 0 {
+		return nil
+	}
+	// Copy the bytes, since the data may be a reused frame buffer.
+	return append([]byte(nil), d.next(n)...)
+}
@@ -0,0 +1,134 @@
+package appdash
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// codecTestSpans returns a batch of spans exercising every size class of
+// the codecs' encodings.
+func codecTestSpans() []Span {
+	return []Span{
+		{ID: SpanID{1, 2, 0}},
+		{ID: SpanID{1<<64 - 1, 1 << 32, 1<<16 + 1}, Annotations: Annotations{
+			{Key: "Name", Value: []byte("hello")},
+			{Key: "Name", Value: []byte("duplicate keys are kept")},
+			{Key: "Empty"},
+			{Key: string(bytes.Repeat([]byte("k"), 300)), Value: bytes.Repeat([]byte{0xff}, 70000)},
+		}},
+		{ID: SpanID{200, 70000, 1 << 40}, Annotations: func() Annotations {
+			as := make(Annotations, 40)
+			for i := range as {
+				as[i] = Annotation{Key: fmt.Sprintf("key%d", i), Value: []byte{byte(i)}}
+			}
+			return as
+		}()},
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	for _, c := range []Codec{ProtobufCodec, MsgpackCodec} {
+		want := codecTestSpans()
+		data, err := c.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("codec %d: %s", c.CodecID(), err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("codec %d: got %v, want %v", c.CodecID(), got, want)
+		}
+
+		// Corrupt data is rejected.
+		for i := 1; i < len(data); i += len(data) / 20 {
+			if _, err := c.Unmarshal(data[:i]); err == nil {
+				t.Errorf("codec %d: got no error for data truncated to %d bytes", c.CodecID(), i)
+			}
+		}
+	}
+}
+
+func TestCollectorServer_codecs(t *testing.T) {
+	var (
+		collected   []Span
+		collectedMu sync.Mutex
+	)
+	mc := collectorFunc(func(span SpanID, anns ...Annotation) error {
+		collectedMu.Lock()
+		defer collectedMu.Unlock()
+		collected = append(collected, Span{ID: span, Annotations: anns})
+		return nil
+	})
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go NewServer(l, mc).Start()
+
+	// Clients with and without codecs send to the same server.
+	var want []Span
+	for i, codec := range []Codec{nil, ProtobufCodec, MsgpackCodec} {
+		rc := NewRemoteCollector(l.Addr().String())
+		rc.Codec = codec
+		span := Span{ID: SpanID{ID(i + 1), 1, 0}, Annotations: Annotations{{Key: "k", Value: []byte(fmt.Sprint(i))}}}
+		if err := rc.Collect(span.ID, span.Annotations...); err != nil {
+			t.Fatal(err)
+		}
+		if err := rc.Close(); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, span)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	collectedMu.Lock()
+	defer collectedMu.Unlock()
+	sort.Slice(collected, func(i, j int) bool { return collected[i].ID.Trace < collected[j].ID.Trace })
+	if !reflect.DeepEqual(collected, want) {
+		t.Errorf("server collected %v, want %v", collected, want)
+	}
+
+	// Frames naming an unknown codec are rejected.
+	if _, err := serveFrames(t, maxMessageSize, []byte{3, codecFrameMarker, 99, 0}); err == nil {
+		t.Error("got no error for a frame with an unknown codec")
+	}
+}
+
+// BenchmarkCodecs measures the CPU time and payload size of encoding and
+// decoding a typical span with each codec.
+func BenchmarkCodecs(b *testing.B) {
+	span := Span{ID: NewRootSpanID(), Annotations: Annotations{
+		{Key: "Name", Value: []byte("Serve /users/{id}")},
+		{Key: "_schema:HTTPServer", Value: nil},
+		{Key: "Server.Request.Method", Value: []byte("GET")},
+		{Key: "Server.Request.URI", Value: []byte("/users/42?tab=repos")},
+		{Key: "Server.Response.StatusCode", Value: []byte("200")},
+		{Key: "Server.Recv", Value: []byte("2016-01-01T12:00:00.123456789Z")},
+		{Key: "Server.Send", Value: []byte("2016-01-01T12:00:00.223456789Z")},
+	}}
+	for name, c := range map[string]Codec{"protobuf": ProtobufCodec, "msgpack": MsgpackCodec} {
+		c := c
+		b.Run(name, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := c.Marshal([]Span{span})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := c.Unmarshal(data); err != nil {
+					b.Fatal(err)
+				}
+				size = len(data)
+			}
+			b.ReportMetric(float64(size), "bytes/span")
+		})
+	}
+}
@@ -1,23 +1,27 @@
 package appdash
 
 import (
+	"bufio"
 	"crypto/tls"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math/rand"
 	"net"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	pio "github.com/gogo/protobuf/io"
+	"github.com/gogo/protobuf/proto"
 	"sourcegraph.com/sourcegraph/appdash/internal/wire"
 )
 
-// maxMessageSize is the maximum buffer size for delimited protobuf messages.
-// Effectively, the client may request the server to allocate a buffer of up to
-// maxMessageSize -- so choose carefully.
+// maxMessageSize is the default maximum buffer size for delimited protobuf
+// messages. Effectively, the client may request the server to allocate a buffer
+// of up to maxMessageSize -- so choose carefully.
 //
 // We use 1 MB here.
 const maxMessageSize = 1 * 1024 * 1024
@@ -87,6 +91,17 @@ type ChunkedCollector struct {
 	// Default MinInterval = 500 * time.Millisecond (500ms).
 	MinInterval time.Duration
 
+	// FlushJitter, if non-zero, randomly varies each interval between
+	// automatic flushes by up to this fraction of MinInterval in either
+	// direction (e.g. 0.2 for ±20%), so that many processes started at the
+	// same time do not all flush to the same server at once. The intervals
+	// average out to MinInterval. Values are clamped to [0, 1].
+	FlushJitter float64
+
+	// Rand, if non-nil, is the source of randomness used for FlushJitter.
+	// If nil, the math/rand package's default source is used.
+	Rand *rand.Rand
+
 	// FlushTimeout, if non-zero, specifies the time after which a flush operation
 	// is considered timed out. If timeout occurs, the pending queue is entirely
 	// dropped (trace data lost) and ErrQueueDropped is returned by Flush.
@@ -114,6 +129,10 @@ type ChunkedCollector struct {
 	// It is primarily used for debugging purposes.
 	OnFlush func(queueSize int)
 
+	// Stats, if non-nil, is updated with the number of spans dropped and
+	// the number of errors returned by the underlying collector.
+	Stats *Stats
+
 	// The last error from the underlying Collector's Collect method,
 	// if any. It will be returned to the next caller of Collect and
 	// this field will be set to nil.
@@ -178,6 +197,7 @@ func (cc *ChunkedCollector) Collect(span SpanID, anns ...Annotation) error {
 			cc.Log.Println("ChunkedCollector: queue entirely dropped (trace data will be missing)")
 			cc.Log.Printf("ChunkedCollector: queueSize:%v queueSizeBytes:%v + collectionSize:%v\n", len(cc.pendingBySpanID), cc.queueSizeBytes, collectionSize)
 		}
+		cc.Stats.droppedSpans(int64(len(cc.pendingBySpanID)))
 		cc.pendingBySpanID = nil
 		cc.queueSizeBytes = 0
 		return ErrQueueDropped
@@ -192,7 +212,9 @@ func (cc *ChunkedCollector) Collect(span SpanID, anns ...Annotation) error {
 			cc.pendingBySpanID[span] = append(p, anns...)
 		}
 	} else {
-		cc.pendingBySpanID[span] = anns
+		// Cap the caller's slice so that coalescing later annotations
+		// into it never writes to the caller's backing array.
+		cc.pendingBySpanID[span] = anns[:len(anns):len(anns)]
 	}
 
 	if err := cc.lastErr; err != nil {
@@ -218,12 +240,18 @@ func (cc *ChunkedCollector) Flush() error {
 		cc.OnFlush(len(pendingBySpanID))
 	}
 
-	var errs []error
+	var (
+		errs []error
+		sent int
+	)
 	for spanID, p := range pendingBySpanID {
+		sent++
 		if err := cc.Collector.Collect(spanID, p...); err != nil {
+			cc.Stats.collectError()
 			errs = append(errs, err)
 		}
 		if cc.FlushTimeout != 0 && time.Since(start) > cc.FlushTimeout {
+			cc.Stats.droppedSpans(int64(len(pendingBySpanID) - sent))
 			cc.mu.Lock()
 			if cc.Log != nil {
 				cc.Log.Println("ChunkedCollector: queue entirely dropped (trace data will be missing)")
@@ -248,7 +276,7 @@ func (cc *ChunkedCollector) start() {
 	cc.started = true
 	go func() {
 		for {
-			t := time.After(cc.MinInterval)
+			t := time.After(cc.flushInterval())
 			select {
 			case <-t:
 				if err := cc.Flush(); err != nil {
@@ -263,6 +291,22 @@ func (cc *ChunkedCollector) start() {
 	}()
 }
 
+// flushInterval returns the time to wait until the next automatic flush.
+func (cc *ChunkedCollector) flushInterval() time.Duration {
+	jitter := cc.FlushJitter
+	if jitter <= 0 {
+		return cc.MinInterval
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+	f := rand.Float64
+	if cc.Rand != nil {
+		f = cc.Rand.Float64
+	}
+	return cc.MinInterval + time.Duration(jitter*(2*f()-1)*float64(cc.MinInterval))
+}
+
 // Stop stops the collector. After stopping, no more data will be sent
 // to the underlying collector and calls to Collect will fail.
 func (cc *ChunkedCollector) Stop() {
@@ -272,6 +316,247 @@ func (cc *ChunkedCollector) Stop() {
 	cc.stopped = true
 }
 
+// ErrCollectTimeout is returned by TimeoutCollector.Collect when the
+// underlying collector did not return in time, or when too many earlier
+// calls are still running.
+var ErrCollectTimeout = errors.New("TimeoutCollector: collect timed out (trace data may be missing)")
+
+// TimeoutCollector bounds the time that a call to Collect may take, so that
+// a slow store or network never adds more than Timeout to the operation
+// being traced. Each call to the underlying collector runs in its own
+// goroutine; if it does not return within Timeout, Collect returns
+// ErrCollectTimeout and the call is abandoned (it keeps running, but its
+// result is ignored).
+type TimeoutCollector struct {
+	// Collector is the underlying collector that spans are sent to.
+	Collector
+
+	// Timeout is the maximum time a call to Collect may take.
+	Timeout time.Duration
+
+	// MaxPending, if non-zero, is the maximum number of calls to the
+	// underlying collector that may be running at once, including abandoned
+	// ones. Once it is reached, Collect returns ErrCollectTimeout
+	// immediately, so that sustained slowness does not leak goroutines.
+	MaxPending int
+
+	// Log, if non-nil, is used to log timed out collections.
+	Log *log.Logger
+
+	pending, timeouts int64 // accessed atomically
+}
+
+// NewTimeoutCollector is shorthand for:
+//
+// 	c := &TimeoutCollector{
+// 		Collector:  c,
+// 		Timeout:    d,
+// 		MaxPending: 1000,
+// 	}
+//
+func NewTimeoutCollector(c Collector, d time.Duration) *TimeoutCollector {
+	return &TimeoutCollector{
+		Collector:  c,
+		Timeout:    d,
+		MaxPending: 1000,
+	}
+}
+
+// Collect calls the underlying collector's Collect, returning
+// ErrCollectTimeout if it takes longer than tc.Timeout.
+func (tc *TimeoutCollector) Collect(span SpanID, anns ...Annotation) error {
+	if n := atomic.AddInt64(&tc.pending, 1); tc.MaxPending != 0 && n > int64(tc.MaxPending) {
+		atomic.AddInt64(&tc.pending, -1)
+		tc.timedOut(span, "too many pending collections")
+		return ErrCollectTimeout
+	}
+
+	done := make(chan error, 1) // buffered, so an abandoned call can finish
+	go func() {
+		defer atomic.AddInt64(&tc.pending, -1)
+		done <- tc.Collector.Collect(span, anns...)
+	}()
+
+	t := time.NewTimer(tc.Timeout)
+	defer t.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-t.C:
+		tc.timedOut(span, fmt.Sprintf("exceeded %s", tc.Timeout))
+		return ErrCollectTimeout
+	}
+}
+
+func (tc *TimeoutCollector) timedOut(span SpanID, reason string) {
+	atomic.AddInt64(&tc.timeouts, 1)
+	if tc.Log != nil {
+		tc.Log.Printf("TimeoutCollector: abandoned collection of %v: %s", span, reason)
+	}
+}
+
+// Timeouts returns the number of calls to Collect that have returned
+// ErrCollectTimeout.
+func (tc *TimeoutCollector) Timeouts() int64 {
+	return atomic.LoadInt64(&tc.timeouts)
+}
+
+// ErrQueueFull is returned by AsyncCollector.Collect when its queue is full
+// and the collection was dropped.
+var ErrQueueFull = errors.New("AsyncCollector: queue full (trace data will be missing)")
+
+// An AsyncCollector queues collections and passes them to the underlying
+// collector from a background goroutine, so that Collect returns without
+// waiting for a slow store or network. Wrapping the collector given to
+// httptrace.Middleware in one keeps collection off the request path.
+//
+// The queue holds at most QueueSize collections. When it is full, new
+// collections are dropped (and counted, see Dropped) rather than queued,
+// so that a slow underlying collector neither grows memory without bound
+// nor blocks callers. Call Close on shutdown to send the queued
+// collections and stop the background goroutine.
+type AsyncCollector struct {
+	// Collector is the underlying collector that spans are sent to.
+	Collector
+
+	// QueueSize is the maximum number of collections queued at once. It
+	// must be set before the first call to Collect.
+	QueueSize int
+
+	// Log, if non-nil, is used to log errors returned by the underlying
+	// collector.
+	Log *log.Logger
+
+	// Stats, if non-nil, is updated with the number of spans dropped and
+	// the number of errors returned by the underlying collector.
+	Stats *Stats
+
+	queue            chan asyncCollection
+	started, stopped bool
+	stopChan         chan struct{}
+	dropped          int64 // accessed atomically
+
+	mu sync.Mutex // protects queue, started, stopped and stopChan
+}
+
+// asyncCollection is an entry of an AsyncCollector's queue: either a
+// collection or, if flushed is non-nil, a marker to close flushed once the
+// entries before it have been sent.
+type asyncCollection struct {
+	spanCollection
+	flushed chan struct{}
+}
+
+// NewAsyncCollector is shorthand for:
+//
+// 	c := &AsyncCollector{
+// 		Collector: c,
+// 		QueueSize: 1000,
+// 	}
+//
+func NewAsyncCollector(c Collector) *AsyncCollector {
+	return &AsyncCollector{
+		Collector: c,
+		QueueSize: 1000,
+	}
+}
+
+// Collect queues the span and annotations to be sent to the underlying
+// collector, returning ErrQueueFull if the queue is full.
+func (ac *AsyncCollector) Collect(span SpanID, anns ...Annotation) error {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if ac.stopped {
+		return errors.New("AsyncCollector is stopped")
+	}
+	if !ac.started {
+		ac.start()
+	}
+	select {
+	case ac.queue <- asyncCollection{spanCollection: spanCollection{span: span, anns: anns}}:
+		return nil
+	default:
+		atomic.AddInt64(&ac.dropped, 1)
+		ac.Stats.droppedSpans(1)
+		return ErrQueueFull
+	}
+}
+
+// start starts the goroutine that sends queued collections to the
+// underlying collector. The ac.mu lock must be held while calling start.
+func (ac *AsyncCollector) start() {
+	ac.queue = make(chan asyncCollection, ac.QueueSize)
+	ac.stopChan = make(chan struct{})
+	ac.started = true
+	go func() {
+		for {
+			select {
+			case c := <-ac.queue:
+				if c.flushed != nil {
+					close(c.flushed)
+					continue
+				}
+				if err := ac.Collector.Collect(c.span, c.anns...); err != nil {
+					ac.Stats.collectError()
+					if ac.Log != nil {
+						ac.Log.Printf("AsyncCollector: collecting %v: %s", c.span, err)
+					}
+				}
+			case <-ac.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Flush waits until the collections queued before the call have been sent
+// to the underlying collector.
+func (ac *AsyncCollector) Flush() {
+	ac.mu.Lock()
+	queue, stopChan := ac.queue, ac.stopChan
+	ac.mu.Unlock()
+	if queue == nil {
+		return // nothing was ever collected
+	}
+	flushed := make(chan struct{})
+	select {
+	case queue <- asyncCollection{flushed: flushed}:
+	case <-stopChan:
+		return
+	}
+	select {
+	case <-flushed:
+	case <-stopChan:
+	}
+}
+
+// Close stops accepting collections, waits until the queued ones have been
+// sent to the underlying collector, and then stops the background
+// goroutine. After closing, calls to Collect will fail.
+func (ac *AsyncCollector) Close() error {
+	ac.mu.Lock()
+	if ac.stopped {
+		ac.mu.Unlock()
+		return nil
+	}
+	ac.stopped = true
+	ac.mu.Unlock()
+
+	ac.Flush()
+	ac.mu.Lock()
+	if ac.started {
+		close(ac.stopChan)
+	}
+	ac.mu.Unlock()
+	return nil
+}
+
+// Dropped returns the number of collections that were dropped because the
+// queue was full.
+func (ac *AsyncCollector) Dropped() int64 {
+	return atomic.LoadInt64(&ac.dropped)
+}
+
 // NewRemoteCollector creates a collector that sends data to a
 // collector server (created with NewServer). It sends data
 // immediately when Collect is called. To send data in chunks, use a
@@ -297,13 +582,17 @@ func NewTLSRemoteCollector(addr string, tlsConfig *tls.Config) *RemoteCollector
 
 // A RemoteCollector sends data to a collector server (created with
 // NewServer).
+//
+// If Sampler is set, the spans of traces it rejects are dropped before
+// they are sent, so that sampling saves bandwidth and collector server
+// load rather than being applied after the fact.
 type RemoteCollector struct {
 	addr string
 
 	dial func() (net.Conn, error)
 
-	mu    sync.Mutex      // guards pconn
-	pconn pio.WriteCloser // delimited-protobuf remote connection
+	mu   sync.Mutex // guards conn
+	conn net.Conn   // remote connection
 
 	// Log is the logger to use for errors and warnings. If nil, a new
 	// logger is created.
@@ -312,28 +601,88 @@ type RemoteCollector struct {
 
 	// Debug is whether to log debug messages.
 	Debug bool
+
+	// Stats, if non-nil, is updated with the number of spans that could
+	// not be sent to the collector server.
+	Stats *Stats
+
+	// Sampler, if non-nil, decides which traces are sent to the collector
+	// server. It is consulted on the first span of each trace that is
+	// collected, and its decision is applied to all later spans of the
+	// trace, so that traces are sent whole or not at all. Only the
+	// decisions for the most recent 10000 traces are kept.
+	Sampler Sampler
+
+	// Codec, if non-nil, encodes the spans sent to the collector server,
+	// which must be recent enough to support codecs (see Codec).
+	// Otherwise spans are sent in the original, protobuf-based format.
+	Codec Codec
+
+	sent, unsampled int64 // accessed atomically
+
+	decisionsMu sync.Mutex
+	decisions   traceDecisions
 }
 
 // Collect implements the Collector interface by sending the events that
-// occured in the span to the remote collector server (see CollectorServer).
+// occured in the span to the remote collector server (see CollectorServer),
+// unless Sampler rejects the span's trace.
 func (rc *RemoteCollector) Collect(span SpanID, anns ...Annotation) error {
-	return rc.collectAndRetry(newCollectPacket(span, anns))
+	if !rc.shouldSample(span, anns) {
+		atomic.AddInt64(&rc.unsampled, 1)
+		return nil
+	}
+	frame, err := rc.frame(span, anns)
+	if err == nil {
+		err = rc.collectAndRetry(span, frame)
+	}
+	if err != nil {
+		rc.Stats.collectError()
+		return err
+	}
+	atomic.AddInt64(&rc.sent, 1)
+	return nil
+}
+
+// shouldSample reports whether the span should be sent, according to the
+// decision made for its trace.
+func (rc *RemoteCollector) shouldSample(span SpanID, anns Annotations) bool {
+	if rc.Sampler == nil {
+		return true
+	}
+	rc.decisionsMu.Lock()
+	defer rc.decisionsMu.Unlock()
+	if sample, ok := rc.decisions.get(span.Trace); ok {
+		return sample
+	}
+	sample := rc.Sampler.ShouldSample(span, anns)
+	rc.decisions.put(span.Trace, sample)
+	return sample
+}
+
+// Sent returns the number of spans that have been sent to the collector
+// server.
+func (rc *RemoteCollector) Sent() int64 {
+	return atomic.LoadInt64(&rc.sent)
+}
+
+// Unsampled returns the number of spans that were dropped without being
+// sent because Sampler rejected their trace.
+func (rc *RemoteCollector) Unsampled() int64 {
+	return atomic.LoadInt64(&rc.unsampled)
 }
 
 // connect makes a connection to the collector server. It must be
 // called with rc.mu held.
 func (rc *RemoteCollector) connect() error {
-	if rc.pconn != nil {
-		rc.pconn.Close()
-		rc.pconn = nil
+	if rc.conn != nil {
+		rc.conn.Close()
+		rc.conn = nil
 	}
 
 	c, err := rc.dial()
 	if err == nil {
-		// Create a protobuf delimited writer wrapping the connection. When the
-		// writer is closed, it also closes the underlying connection (see
-		// source code for details).
-		rc.pconn = pio.NewDelimitedWriter(c)
+		rc.conn = c
 	}
 	return err
 }
@@ -343,44 +692,57 @@ func (rc *RemoteCollector) Close() error {
 	rc.mu.Lock()
 	defer rc.mu.Unlock()
 
-	if rc.pconn != nil {
-		err := rc.pconn.Close()
-		rc.pconn = nil
+	if rc.conn != nil {
+		err := rc.conn.Close()
+		rc.conn = nil
 		return err
 	}
 	return nil
 }
 
-func (rc *RemoteCollector) collectAndRetry(p *wire.CollectPacket) error {
+// frame encodes the span as the payload of a frame: with a header naming
+// rc.Codec, or as a single protobuf message if it is nil.
+func (rc *RemoteCollector) frame(span SpanID, anns Annotations) ([]byte, error) {
+	if rc.Codec == nil {
+		return proto.Marshal(newCollectPacket(span, anns))
+	}
+	data, err := rc.Codec.Marshal([]Span{{ID: span, Annotations: anns}})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{codecFrameMarker, rc.Codec.CodecID()}, data...), nil
+}
+
+func (rc *RemoteCollector) collectAndRetry(span SpanID, frame []byte) error {
 	rc.mu.Lock()
 	defer rc.mu.Unlock()
 
-	if rc.pconn != nil {
-		if err := rc.collect(p); err == nil {
+	if rc.conn != nil {
+		if err := rc.collect(span, frame); err == nil {
 			return nil
 		}
 		if rc.Debug {
-			rc.log().Printf("Reconnecting to send %v", spanIDFromWire(p.Spanid))
+			rc.log().Printf("Reconnecting to send %v", span)
 		}
 	}
 	if err := rc.connect(); err != nil {
 		return err
 	}
-	return rc.collect(p)
+	return rc.collect(span, frame)
 }
 
-func (rc *RemoteCollector) collect(p *wire.CollectPacket) error {
+func (rc *RemoteCollector) collect(span SpanID, frame []byte) error {
 	if rc.Debug {
-		rc.log().Printf("Sending %v", spanIDFromWire(p.Spanid))
+		rc.log().Printf("Sending %v", span)
 	}
 
-	// Send our message, close writer.
-	if err := rc.pconn.WriteMsg(p); err != nil {
+	// Send the length-prefixed frame in a single write.
+	if _, err := rc.conn.Write(append(appendUvarint(nil, uint64(len(frame))), frame...)); err != nil {
 		return err
 	}
 
 	if rc.Debug {
-		rc.log().Printf("Sent %v", spanIDFromWire(p.Spanid))
+		rc.log().Printf("Sent %v", span)
 	}
 	return nil
 }
@@ -420,6 +782,27 @@ type CollectorServer struct {
 
 	// Trace is whether to log all data that is received.
 	Trace bool
+
+	// MaxFrameSize is the maximum size in bytes of a single frame (a
+	// length-prefixed collect packet) that the server will accept. A client
+	// sending a larger frame is disconnected before the server allocates
+	// any memory for it. If zero, a default of 1 MB is used.
+	MaxFrameSize int
+
+	// IdleTimeout, if non-zero, is how long a client connection may go
+	// without sending a frame before the server closes it, so that
+	// clients that stop sending without closing their connections do not
+	// hold on to the server's resources. Connections closed this way are
+	// counted by Reaped.
+	IdleTimeout time.Duration
+
+	// KeepAlive, if non-zero, enables TCP keep-alive probes on client
+	// connections, sent at the given interval, so that connections to
+	// clients that vanished (e.g. whose host went down) are detected and
+	// closed even if IdleTimeout is not set.
+	KeepAlive time.Duration
+
+	reaped int64 // accessed atomically
 }
 
 // Start starts the server.
@@ -446,32 +829,151 @@ func (cs *CollectorServer) handleConn(conn net.Conn) (err error) {
 		}
 	}()
 	defer conn.Close()
+	if cs.KeepAlive > 0 {
+		setKeepAlive(conn, cs.KeepAlive)
+	}
 
-	rdr := pio.NewDelimitedReader(conn, maxMessageSize)
-	defer rdr.Close()
+	maxSize := cs.MaxFrameSize
+	if maxSize <= 0 {
+		maxSize = maxMessageSize
+	}
+	rdr := newFrameReader(conn, maxSize)
 	for {
-		p := &wire.CollectPacket{}
-		if err = rdr.ReadMsg(p); err != nil {
+		if cs.IdleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(cs.IdleTimeout))
+		}
+		var frame []byte
+		if frame, err = rdr.ReadFrame(); err != nil {
 			if err == io.EOF {
 				return nil
 			}
+			if ne, ok := err.(net.Error); ok && ne.Timeout() && cs.IdleTimeout > 0 {
+				atomic.AddInt64(&cs.reaped, 1)
+				if cs.Debug {
+					cs.log().Printf("Client %s: closing idle connection", conn.RemoteAddr())
+				}
+				return nil
+			}
 			return fmt.Errorf("ReadMsg: %s", err)
 		}
-
-		spanID := spanIDFromWire(p.Spanid)
-		if cs.Debug || cs.Trace {
-			cs.log().Printf("Client %s: received span %v with %d annotations", conn.RemoteAddr(), spanID, len(p.Annotation))
+		var spans []Span
+		if spans, err = decodeFrame(frame); err != nil {
+			return fmt.Errorf("ReadMsg: %s", err)
 		}
-		if cs.Trace {
-			for i, ann := range p.Annotation {
-				cs.log().Printf("Client %s: span %v: annotation %d: %s=%q", conn.RemoteAddr(), p.Spanid.Span, i, *ann.Key, ann.Value)
+
+		for _, span := range spans {
+			if cs.Debug || cs.Trace {
+				cs.log().Printf("Client %s: received span %v with %d annotations", conn.RemoteAddr(), span.ID, len(span.Annotations))
+			}
+			if cs.Trace {
+				for i, ann := range span.Annotations {
+					cs.log().Printf("Client %s: span %v: annotation %d: %s=%q", conn.RemoteAddr(), span.ID.Span, i, ann.Key, ann.Value)
+				}
+			}
+
+			if err = cs.c.Collect(span.ID, span.Annotations...); err != nil {
+				return fmt.Errorf("Collect %v: %s", span.ID, err)
 			}
 		}
+	}
+}
 
-		if err = cs.c.Collect(spanID, annotationsFromWire(p.Annotation)...); err != nil {
-			return fmt.Errorf("Collect %v: %s", spanID, err)
+// Reaped returns the number of client connections that the server closed
+// because they were idle for longer than IdleTimeout.
+func (cs *CollectorServer) Reaped() int64 {
+	return atomic.LoadInt64(&cs.reaped)
+}
+
+// setKeepAlive enables TCP keep-alive probes on conn, or the connection
+// underlying it if it is a TLS connection, at the given interval.
+func setKeepAlive(conn net.Conn, period time.Duration) {
+	if tc, ok := conn.(*tls.Conn); ok {
+		conn = tc.NetConn()
+	}
+	if tc, ok := conn.(*net.TCPConn); ok {
+		tc.SetKeepAlive(true)
+		tc.SetKeepAlivePeriod(period)
+	}
+}
+
+// decodeFrame decodes the spans in the payload of a frame, which either
+// starts with a header naming the codec it was encoded with or is a single
+// protobuf message.
+func decodeFrame(frame []byte) ([]Span, error) {
+	if len(frame) > 0 && frame[0] == codecFrameMarker {
+		if len(frame) < 2 {
+			return nil, errors.New("frame header is truncated")
+		}
+		c := codecByID(frame[1])
+		if c == nil {
+			return nil, fmt.Errorf("unknown codec %d", frame[1])
 		}
+		return c.Unmarshal(frame[2:])
+	}
+	p := &wire.CollectPacket{}
+	if err := proto.Unmarshal(frame, p); err != nil {
+		return nil, err
 	}
+	if !validCollectPacket(p) {
+		return nil, errors.New("collect packet is missing its span ID")
+	}
+	return []Span{{ID: spanIDFromWire(p.Spanid), Annotations: annotationsFromWire(p.Annotation)}}, nil
+}
+
+// validCollectPacket reports whether p has a complete span ID, such that
+// spanIDFromWire will not panic.
+func validCollectPacket(p *wire.CollectPacket) bool {
+	id := p.Spanid
+	return id != nil && id.Trace != nil && id.Span != nil && id.Parent != nil
+}
+
+// frameReader reads varint length-prefixed frames, as written by
+// RemoteCollector and pio.NewDelimitedWriter, without trusting the length
+// prefix: frames larger than maxSize are rejected before any buffer is
+// allocated for them.
+type frameReader struct {
+	r       *bufio.Reader
+	buf     []byte
+	maxSize int
+}
+
+func newFrameReader(r io.Reader, maxSize int) *frameReader {
+	return &frameReader{r: bufio.NewReader(r), maxSize: maxSize}
+}
+
+// ReadFrame reads the payload of the next frame. The returned slice is only
+// valid until the next call to ReadFrame. It returns io.EOF only if the
+// stream ended cleanly between two frames; a stream that ends partway
+// through a frame yields io.ErrUnexpectedEOF.
+func (fr *frameReader) ReadFrame() ([]byte, error) {
+	length, err := binary.ReadUvarint(fr.r)
+	if err != nil {
+		return nil, err
+	}
+	if length > uint64(fr.maxSize) {
+		return nil, fmt.Errorf("frame size %d exceeds maximum of %d bytes", length, fr.maxSize)
+	}
+	if uint64(len(fr.buf)) < length {
+		fr.buf = make([]byte, length)
+	}
+	buf := fr.buf[:length]
+	if _, err := io.ReadFull(fr.r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf, nil
+}
+
+// ReadMsg reads the next frame into msg, which it must hold as a single
+// protobuf message.
+func (fr *frameReader) ReadMsg(msg proto.Message) error {
+	buf, err := fr.ReadFrame()
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(buf, msg)
 }
 
 func (cs *CollectorServer) log() *log.Logger {
@@ -482,3 +984,11 @@ func (cs *CollectorServer) log() *log.Logger {
 	}
 	return cs.Log
 }
+
+// collectorFunc is a Collector implemented by a function.
+type collectorFunc func(SpanID, ...Annotation) error
+
+// Collect implements the Collector interface by calling the function itself.
+func (c collectorFunc) Collect(id SpanID, as ...Annotation) error {
+	return c(id, as...)
+}
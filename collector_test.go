@@ -3,16 +3,23 @@ package appdash
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
 	"net"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"sort"
 
+	"github.com/gogo/protobuf/proto"
 	"sourcegraph.com/sourcegraph/appdash/internal/wire"
 )
 
@@ -56,6 +63,183 @@ func TestCollectorServer(t *testing.T) {
 	}
 }
 
+func TestRemoteCollector_Sampler(t *testing.T) {
+	var (
+		collected   []SpanID
+		collectedMu sync.Mutex
+	)
+	mc := collectorFunc(func(span SpanID, anns ...Annotation) error {
+		collectedMu.Lock()
+		defer collectedMu.Unlock()
+		collected = append(collected, span)
+		return nil
+	})
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := NewServer(l, mc)
+	go cs.Start()
+
+	// The sampler decides by an annotation that only some spans carry, so
+	// the decision made on a trace's first span must be remembered.
+	rc := NewRemoteCollector(l.Addr().String())
+	rc.Sampler = SamplerFunc(func(span SpanID, as Annotations) bool {
+		return string(as.get("sample")) == "yes"
+	})
+	yes := Annotation{Key: "sample", Value: []byte("yes")}
+	cc := &collectorT{t, rc}
+	cc.MustCollect(SpanID{1, 1, 0}, yes)
+	cc.MustCollect(SpanID{1, 2, 1})
+	cc.MustCollect(SpanID{2, 3, 0})
+	cc.MustCollect(SpanID{2, 4, 3}, yes)
+	cc.MustCollect(SpanID{1, 5, 2})
+	cc.MustCollect(SpanID{2, 6, 3})
+	if err := rc.Close(); err != nil {
+		t.Error(err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	collectedMu.Lock()
+	defer collectedMu.Unlock()
+	want := []SpanID{{1, 1, 0}, {1, 2, 1}, {1, 5, 2}}
+	if !reflect.DeepEqual(collected, want) {
+		t.Errorf("server collected %v, want %v", collected, want)
+	}
+	if sent, unsampled := rc.Sent(), rc.Unsampled(); sent != 3 || unsampled != 3 {
+		t.Errorf("got %d sent and %d unsampled spans, want 3 and 3", sent, unsampled)
+	}
+}
+
+// serveFrames feeds raw bytes to a CollectorServer's connection handler and
+// returns the handler's error and the number of packets it collected.
+func serveFrames(t *testing.T, maxFrameSize int, data []byte) (int, error) {
+	var collected int
+	cs := &CollectorServer{
+		c: collectorFunc(func(SpanID, ...Annotation) error {
+			collected++
+			return nil
+		}),
+		Log:          log.New(ioutil.Discard, "", 0),
+		MaxFrameSize: maxFrameSize,
+	}
+	client, server := net.Pipe()
+	go func() {
+		client.Write(data)
+		client.Close()
+	}()
+	err := cs.handleConn(server)
+	return collected, err
+}
+
+// frame returns p encoded as a varint length-prefixed frame.
+func frame(t *testing.T, p *wire.CollectPacket) []byte {
+	b, err := proto.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hdr := make([]byte, binary.MaxVarintLen64)
+	return append(hdr[:binary.PutUvarint(hdr, uint64(len(b)))], b...)
+}
+
+func TestCollectorServer_oversizedFrame(t *testing.T) {
+	small := frame(t, newCollectPacket(SpanID{1, 2, 3}, Annotations{{"k", []byte("v")}}))
+	big := frame(t, newCollectPacket(SpanID{1, 2, 3}, Annotations{{"k", make([]byte, 512)}}))
+
+	n, err := serveFrames(t, 256, append(small, big...))
+	if err == nil || !strings.Contains(err.Error(), "exceeds maximum") {
+		t.Errorf("got error %v, want frame size error", err)
+	}
+	if n != 1 {
+		t.Errorf("got %d collected packets, want 1", n)
+	}
+
+	// A length prefix claiming an enormous frame must be rejected without
+	// reading (or allocating) it.
+	hdr := make([]byte, binary.MaxVarintLen64)
+	hdr = hdr[:binary.PutUvarint(hdr, 1<<62)]
+	if _, err := serveFrames(t, 0, hdr); err == nil || !strings.Contains(err.Error(), "exceeds maximum") {
+		t.Errorf("got error %v, want frame size error", err)
+	}
+}
+
+func TestCollectorServer_truncatedFrame(t *testing.T) {
+	f := frame(t, newCollectPacket(SpanID{1, 2, 3}, Annotations{{"k", []byte("v")}}))
+
+	n, err := serveFrames(t, 0, f)
+	if err != nil {
+		t.Errorf("complete frame: got error %v, want nil", err)
+	}
+	if n != 1 {
+		t.Errorf("complete frame: got %d collected packets, want 1", n)
+	}
+
+	for _, data := range [][]byte{f[:len(f)-1], f[:1], {0x80}} {
+		n, err := serveFrames(t, 0, data)
+		if err == nil {
+			t.Errorf("truncated frame %x: got nil error", data)
+		}
+		if n != 0 {
+			t.Errorf("truncated frame %x: got %d collected packets, want 0", data, n)
+		}
+	}
+}
+
+func TestCollectorServer_idleTimeout(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		collected int
+	)
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := NewServer(l, collectorFunc(func(SpanID, ...Annotation) error {
+		mu.Lock()
+		defer mu.Unlock()
+		collected++
+		return nil
+	}))
+	cs.IdleTimeout = 100 * time.Millisecond
+	cs.KeepAlive = time.Second
+	go cs.Start()
+
+	// One client connects and never sends anything.
+	idle, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idle.Close()
+
+	// Another keeps sending spans, more often than the idle timeout, for
+	// several times as long.
+	rc := NewRemoteCollector(l.Addr().String())
+	const n = 15
+	for i := 0; i < n; i++ {
+		if err := rc.Collect(NewRootSpanID()); err != nil {
+			t.Fatalf("span %d: %s", i, err)
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	idle.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := idle.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("got error %v reading from the idle connection, want io.EOF as the server closed it", err)
+	}
+	if got := cs.Reaped(); got != 1 {
+		t.Errorf("got %d connections reaped, want only the idle one", got)
+	}
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if collected != n {
+		t.Errorf("got %d spans collected from the active connection, want %d", collected, n)
+	}
+}
+
 func TestCollectorServer_stress(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -229,6 +413,72 @@ func TestChunkedCollector(t *testing.T) {
 	}
 }
 
+func TestChunkedCollector_coalesce(t *testing.T) {
+	var calls []Annotations
+	mc := collectorFunc(func(span SpanID, anns ...Annotation) error {
+		calls = append(calls, anns)
+		return nil
+	})
+	cc := &ChunkedCollector{Collector: mc, MinInterval: time.Hour}
+	defer cc.Stop()
+
+	span := SpanID{1, 2, 3}
+	first, err := MarshalEvent(Msg("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first = append(make(Annotations, 0, 10), first...) // spare capacity
+	second, err := MarshalEvent(Log("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc.Collect(span, first...)
+	cc.Collect(span, second...)
+	if err := cc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 1 {
+		t.Fatalf("got %d collections, want 1", len(calls))
+	}
+	if want := append(append(Annotations{}, first...), second...); !reflect.DeepEqual(calls[0], want) {
+		t.Errorf("got annotations %v, want %v (in order)", calls[0], want)
+	}
+	if first[:cap(first)][len(first)].Key != "" {
+		t.Error("coalescing wrote to the caller's annotations slice")
+	}
+}
+
+func TestChunkedCollector_FlushJitter(t *testing.T) {
+	cc := &ChunkedCollector{MinInterval: time.Second, Rand: rand.New(rand.NewSource(1))}
+	if d := cc.flushInterval(); d != time.Second {
+		t.Errorf("got interval %s without jitter, want 1s", d)
+	}
+
+	cc.FlushJitter = 0.2
+	const n = 10000
+	var sum, min, max time.Duration
+	for i := 0; i < n; i++ {
+		d := cc.flushInterval()
+		if d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("got interval %s, want within 800ms-1.2s", d)
+		}
+		if i == 0 || d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+		sum += d
+	}
+	if min > 820*time.Millisecond || max < 1180*time.Millisecond {
+		t.Errorf("got intervals from %s to %s, want them spread over 800ms-1.2s", min, max)
+	}
+	if mean := sum / n; mean < 990*time.Millisecond || mean > 1010*time.Millisecond {
+		t.Errorf("got mean interval %s, want 1s", mean)
+	}
+}
+
 func TestChunkedCollectorFlushTimeout(t *testing.T) {
 	mc := collectorFunc(func(span SpanID, anns ...Annotation) error {
 		time.Sleep(200 * time.Millisecond) // Slow collector
@@ -254,12 +504,107 @@ func TestChunkedCollectorFlushTimeout(t *testing.T) {
 	}
 }
 
-// collectorFunc implements the Collector interface by calling the function.
-type collectorFunc func(SpanID, ...Annotation) error
+func TestTimeoutCollector(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow := collectorFunc(func(SpanID, ...Annotation) error {
+		<-release
+		return nil
+	})
+	tc := NewTimeoutCollector(slow, 10*time.Millisecond)
+	tc.MaxPending = 2
 
-// Collect implements the Collector interface by calling the function itself.
-func (c collectorFunc) Collect(id SpanID, as ...Annotation) error {
-	return c(id, as...)
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+		if err := tc.Collect(SpanID{1, 2, 3}); err != ErrCollectTimeout {
+			t.Errorf("got error %v, want %v", err, ErrCollectTimeout)
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("Collect took %s, want it to return after the timeout", d)
+		}
+	}
+
+	// Both abandoned calls are still pending, so this one must not even
+	// start (and wait for the timeout).
+	tc.Timeout = time.Hour
+	start := time.Now()
+	if err := tc.Collect(SpanID{1, 2, 3}); err != ErrCollectTimeout {
+		t.Errorf("got error %v, want %v", err, ErrCollectTimeout)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Collect took %s, want it to return immediately", d)
+	}
+	if got, want := tc.Timeouts(), int64(3); got != want {
+		t.Errorf("got %d timeouts, want %d", got, want)
+	}
+}
+
+func TestTimeoutCollector_fast(t *testing.T) {
+	wantErr := errors.New("x")
+	tc := NewTimeoutCollector(collectorFunc(func(SpanID, ...Annotation) error {
+		return wantErr
+	}), time.Second)
+	if err := tc.Collect(SpanID{1, 2, 3}); err != wantErr {
+		t.Errorf("got error %v, want %v", err, wantErr)
+	}
+	if got := tc.Timeouts(); got != 0 {
+		t.Errorf("got %d timeouts, want 0", got)
+	}
+}
+
+func TestAsyncCollector(t *testing.T) {
+	release := make(chan struct{})
+	var collected []SpanID
+	slow := collectorFunc(func(span SpanID, _ ...Annotation) error {
+		<-release
+		collected = append(collected, span)
+		return nil
+	})
+	ac := NewAsyncCollector(slow)
+	ac.QueueSize = 2
+	ac.Stats = &Stats{}
+
+	// The first collection is taken off the queue and blocks the
+	// background goroutine, and the next two fill the queue.
+	if err := ac.Collect(SpanID{1, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	for len(ac.queue) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	start := time.Now()
+	for i := ID(2); i <= 4; i++ {
+		err := ac.Collect(SpanID{1, i, 1})
+		if want := error(nil); i == 4 {
+			want = ErrQueueFull
+			if err != want {
+				t.Errorf("span %d: got error %v, want %v", i, err, want)
+			}
+		} else if err != nil {
+			t.Errorf("span %d: %s", i, err)
+		}
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Collect took %s, want it not to wait for the underlying collector", d)
+	}
+	if got := ac.Dropped(); got != 1 {
+		t.Errorf("got %d dropped, want 1", got)
+	}
+	if got := ac.Stats.Snapshot().Dropped; got != 1 {
+		t.Errorf("got %d dropped in stats, want 1", got)
+	}
+
+	// Closing sends the queued collections.
+	close(release)
+	if err := ac.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []SpanID{{1, 1, 0}, {1, 2, 1}, {1, 3, 1}}; !reflect.DeepEqual(collected, want) {
+		t.Errorf("got collected %v, want %v", collected, want)
+	}
+	if err := ac.Collect(SpanID{1, 5, 1}); err == nil {
+		t.Error("Collect succeeded after Close")
+	}
 }
 
 type collectorT struct {
@@ -0,0 +1,263 @@
+package appdash
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"time"
+)
+
+// A coldTrace holds the compressed annotations of a MemoryStore trace that
+// has not been accessed for a while (see MemoryStore.CompressAfter). While a
+// trace is cold, its spans in the store have no annotations.
+type coldTrace struct {
+	data  []byte   // compressed span IDs and annotations (see encodeSpans)
+	saved int64    // approximate number of bytes saved by compressing
+	cids  []string // the trace's correlation IDs, kept for deleting it from the index
+}
+
+// restore sets the annotations of the spans in t (a copy of the cold
+// trace's skeleton) to their decompressed values.
+func (c *coldTrace) restore(t *Trace) error {
+	r := flate.NewReader(bytes.NewReader(c.data))
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	collections, err := decodeSpans(data)
+	if err != nil {
+		return err
+	}
+	spans := map[ID]*Span{}
+	t.Walk(func(span *Span, depth int) error {
+		spans[span.ID.Span] = span
+		return nil
+	})
+	for _, c := range collections {
+		if span, present := spans[c.span.Span]; present {
+			span.Annotations = c.anns
+		}
+	}
+	return nil
+}
+
+// encodeSpans writes the span IDs and annotations of the given spans to buf
+// in a compact binary form, which decodeSpans reads.
+func encodeSpans(buf *bytes.Buffer, spans []spanCollection) {
+	var tmp [binary.MaxVarintLen64]byte
+	putUvarint := func(x uint64) {
+		buf.Write(tmp[:binary.PutUvarint(tmp[:], x)])
+	}
+	putUvarint(uint64(len(spans)))
+	for _, s := range spans {
+		putUvarint(uint64(s.span.Span))
+		putUvarint(uint64(len(s.anns)))
+		for _, a := range s.anns {
+			putUvarint(uint64(len(a.Key)))
+			buf.WriteString(a.Key)
+			putUvarint(uint64(len(a.Value)))
+			buf.Write(a.Value)
+		}
+	}
+}
+
+var errCorruptSpans = errors.New("appdash: corrupt compressed spans")
+
+// decodeSpans decodes spans written by encodeSpans. Only the Span field of
+// each span ID is set. The annotation values share memory with data, and
+// empty ones are nil.
+func decodeSpans(data []byte) ([]spanCollection, error) {
+	uvarint := func() (uint64, error) {
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, errCorruptSpans
+		}
+		data = data[n:]
+		return x, nil
+	}
+	readBytes := func() ([]byte, error) {
+		n, err := uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(len(data)) {
+			return nil, errCorruptSpans
+		} else if n == 0 {
+			return nil, nil
+		}
+		b := data[:n:n]
+		data = data[n:]
+		return b, nil
+	}
+
+	n, err := uvarint()
+	if err != nil {
+		return nil, err
+	}
+	spans := make([]spanCollection, 0, n)
+	for i := uint64(0); i < n; i++ {
+		var s spanCollection
+		id, err := uvarint()
+		if err != nil {
+			return nil, err
+		}
+		s.span.Span = ID(id)
+		nanns, err := uvarint()
+		if err != nil {
+			return nil, err
+		}
+		for j := uint64(0); j < nanns; j++ {
+			key, err := readBytes()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readBytes()
+			if err != nil {
+				return nil, err
+			}
+			s.anns = append(s.anns, Annotation{Key: string(key), Value: value})
+		}
+		spans = append(spans, s)
+	}
+	return spans, nil
+}
+
+// skeleton returns a copy of the tree t without annotations, adding each of
+// its nodes to spans (keyed by span ID).
+func skeleton(t *Trace, spans map[ID]*Trace) *Trace {
+	c := &Trace{Span: Span{ID: t.Span.ID}}
+	spans[c.Span.ID.Span] = c
+	for _, sub := range t.Sub {
+		c.Sub = append(c.Sub, skeleton(sub, spans))
+	}
+	return c
+}
+
+// spanIndex adds each node of the tree t to spans (keyed by span ID).
+func spanIndex(t *Trace, spans map[ID]*Trace) {
+	spans[t.Span.ID.Span] = t
+	for _, sub := range t.Sub {
+		spanIndex(sub, spans)
+	}
+}
+
+// touchNoLock records that the given trace was just collected into or read,
+// if the store compresses idle traces. It does not grab the lock.
+func (ms *MemoryStore) touchNoLock(trace ID) {
+	if ms.CompressAfter <= 0 {
+		return
+	}
+	if ms.accessed == nil {
+		ms.accessed = map[ID]int64{}
+	}
+	ms.accessed[trace] = time.Now().UnixNano()
+}
+
+// maybeCompressNoLock starts compressing idle traces in the background, if
+// the store compresses idle traces and has not checked for them in a while.
+// It does not grab the lock.
+func (ms *MemoryStore) maybeCompressNoLock() {
+	if ms.CompressAfter <= 0 || ms.compressing {
+		return
+	}
+	now := time.Now().UnixNano()
+	if now-ms.lastCompress < int64(ms.CompressAfter/2) {
+		return
+	}
+	ms.lastCompress = now
+	ms.compressing = true
+	go func() {
+		ms.compressIdle(now - int64(ms.CompressAfter))
+		ms.Lock()
+		ms.compressing = false
+		ms.Unlock()
+	}()
+}
+
+// compressIdle compresses the annotations of the traces last accessed
+// before the given UnixNano time, and returns the number of traces it
+// compressed. The lock is only held to snapshot and then to swap each trace,
+// so that compressing does not stall concurrent collections; a trace that
+// is accessed in the meantime is left as is.
+func (ms *MemoryStore) compressIdle(before int64) int {
+	ms.Lock()
+	var ids []ID
+	for id, accessed := range ms.accessed {
+		if accessed < before {
+			ids = append(ids, id)
+		}
+	}
+	ms.Unlock()
+
+	var (
+		buf, zbuf bytes.Buffer
+		zw, _     = flate.NewWriter(&zbuf, flate.DefaultCompression)
+		n         int
+	)
+	for _, id := range ids {
+		ms.Lock()
+		accessed, present := ms.accessed[id]
+		if !present || accessed >= before {
+			ms.Unlock()
+			continue
+		}
+		spans := make([]spanCollection, 0, len(ms.span[id]))
+		for _, t := range ms.span[id] {
+			spans = append(spans, spanCollection{span: t.Span.ID, anns: t.Annotations[:len(t.Annotations):len(t.Annotations)]})
+		}
+		cids := ms.trace[id].CorrelationIDs()
+		size := ms.traceBytes[id]
+		ms.Unlock()
+
+		buf.Reset()
+		zbuf.Reset()
+		zw.Reset(&zbuf)
+		encodeSpans(&buf, spans)
+		zw.Write(buf.Bytes())
+		zw.Close()
+		if int64(zbuf.Len()) >= size {
+			continue // not worth it
+		}
+		c := &coldTrace{
+			data:  append([]byte(nil), zbuf.Bytes()...),
+			saved: size - int64(zbuf.Len()),
+			cids:  cids,
+		}
+
+		ms.Lock()
+		if ms.accessed[id] == accessed {
+			// Replace the tree rather than clearing its annotations in place,
+			// so that trees previously returned by Trace remain intact.
+			ms.span[id] = map[ID]*Trace{}
+			ms.trace[id] = skeleton(ms.trace[id], ms.span[id])
+			if ms.cold == nil {
+				ms.cold = map[ID]*coldTrace{}
+			}
+			ms.cold[id] = c
+			delete(ms.accessed, id)
+			ms.Stats.compressed(1, c.saved)
+			n++
+		}
+		ms.Unlock()
+	}
+	return n
+}
+
+// thawNoLock decompresses the annotations of the given trace back into the
+// store, if it is cold. It does not grab the lock.
+func (ms *MemoryStore) thawNoLock(trace ID) error {
+	c, present := ms.cold[trace]
+	if !present {
+		return nil
+	}
+	if err := c.restore(ms.trace[trace]); err != nil {
+		return err
+	}
+	delete(ms.cold, trace)
+	ms.Stats.compressed(-1, -c.saved)
+	ms.touchNoLock(trace)
+	return nil
+}
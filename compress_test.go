@@ -0,0 +1,156 @@
+package appdash
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+)
+
+// collectSyntheticTrace collects a trace resembling that of an HTTP request
+// that makes a few queries.
+func collectSyntheticTrace(s Store, trace ID, spans int) error {
+	start := time.Unix(1e9, 0).Add(time.Duration(trace) * time.Second)
+	for i := 1; i <= spans; i++ {
+		id := SpanID{Trace: trace, Span: ID(i), Parent: ID(i - 1)}
+		as, err := MarshalEvent(Timespan{S: start, E: start.Add(time.Duration(i) * time.Millisecond)})
+		if err != nil {
+			return err
+		}
+		as = append(as,
+			Annotation{Key: "Name", Value: []byte(fmt.Sprintf("span %d", i))},
+			Annotation{Key: "Server.Request.Method", Value: []byte("GET")},
+			Annotation{Key: "Server.Request.URI", Value: []byte(fmt.Sprintf("/api/repos/github.com/user/repo%d/commits", trace))},
+			Annotation{Key: "Server.Request.Headers.User-Agent", Value: []byte("Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko)")},
+			Annotation{Key: "Server.Response.StatusCode", Value: []byte("200")},
+			Annotation{Key: "SQL.SQL", Value: []byte("SELECT id, name, created_at FROM repos WHERE owner = $1 ORDER BY created_at DESC")},
+		)
+		if err := s.Collect(id, as...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestMemoryStore_compressIdle(t *testing.T) {
+	ms := NewMemoryStore()
+	ms.CompressAfter = time.Hour
+	ms.Stats = &Stats{}
+	s := storeT{t, ms}
+	for id := ID(1); id <= 3; id++ {
+		if err := collectSyntheticTrace(ms, id, 5); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.MustCollect(SpanID{1, 1, 0}, CorrelationID("req-1"))
+
+	traces := func(s Store) []*Trace {
+		ts, err := s.(Queryer).Traces(TracesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(tracesByID(ts))
+		return ts
+	}
+	compressed := func(want int64) {
+		if got := ms.Stats.Snapshot().CompressedTraces; got != want {
+			t.Fatalf("got %d compressed traces, want %d", got, want)
+		}
+	}
+	want := traces(ms)
+	held := s.MustTrace(2)
+	bytes0 := ms.Bytes()
+
+	later := time.Now().Add(2 * time.Hour).UnixNano()
+	if n := ms.compressIdle(later); n != 3 {
+		t.Fatalf("compressed %d traces, want 3", n)
+	}
+	compressed(3)
+	if saved := ms.Stats.Snapshot().CompressionSavedBytes; saved <= 0 || saved >= bytes0 {
+		t.Errorf("got %d bytes saved, want 0 < saved < %d", saved, bytes0)
+	}
+	if got := ms.Bytes(); got != bytes0 {
+		t.Errorf("got Bytes %d, want uncompressed size %d", got, bytes0)
+	}
+	if !reflect.DeepEqual(held, want[1]) {
+		t.Error("trace returned by Trace before compressing was modified")
+	}
+
+	// Traces and Write decompress copies, leaving the store compressed.
+	if got := traces(ms); !reflect.DeepEqual(got, want) {
+		t.Errorf("got compressed traces %v, want %v", got, want)
+	}
+	var buf bytes.Buffer
+	if err := ms.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ms2 := NewMemoryStore()
+	if _, err := ms2.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := traces(ms2); !reflect.DeepEqual(got, want) {
+		t.Errorf("got written traces %v, want %v", got, want)
+	}
+	compressed(3)
+
+	// Deleting a compressed trace removes it from the index.
+	if err := ms.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	compressed(2)
+	if got, err := ms.Traces(TracesOpts{CorrelationID: "req-1"}); err != nil || len(got) != 0 {
+		t.Errorf("got %v (error %v) linked to a deleted trace, want none", got, err)
+	}
+
+	// Trace and Collect decompress the trace in the store.
+	if got := s.MustTrace(2); !reflect.DeepEqual(got, want[1]) {
+		t.Errorf("got decompressed trace %v, want %v", got, want[1])
+	}
+	compressed(1)
+	s.MustCollect(SpanID{3, 6, 5}, Annotation{Key: "k", Value: []byte("v")})
+	compressed(0)
+	if got := s.MustTrace(3).FindSpan(5).Annotations; !reflect.DeepEqual(got, want[2].FindSpan(5).Annotations) {
+		t.Errorf("got span annotations %v, want %v", got, want[2].FindSpan(5).Annotations)
+	}
+	if saved := ms.Stats.Snapshot().CompressionSavedBytes; saved != 0 {
+		t.Errorf("got %d bytes saved with no compressed traces, want 0", saved)
+	}
+
+	// Recently accessed traces are left alone.
+	if n := ms.compressIdle(time.Now().Add(-time.Minute).UnixNano()); n != 0 {
+		t.Errorf("compressed %d recently accessed traces, want 0", n)
+	}
+}
+
+func benchmarkMemoryStoreCompress(b *testing.B, compress bool) {
+	const traces, spans = 2000, 10
+	var heap uint64
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		ms := NewMemoryStore()
+		ms.CompressAfter = time.Hour
+		for id := ID(1); id <= traces; id++ {
+			if err := collectSyntheticTrace(ms, id, spans); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if compress {
+			ms.compressIdle(time.Now().Add(2 * time.Hour).UnixNano())
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		heap += after.HeapAlloc - before.HeapAlloc
+		runtime.KeepAlive(ms)
+	}
+	b.ReportMetric(float64(heap)/float64(b.N), "heap-bytes/store")
+}
+
+func BenchmarkMemoryStore_uncompressed(b *testing.B) { benchmarkMemoryStoreCompress(b, false) }
+func BenchmarkMemoryStore_compressed(b *testing.B)   { benchmarkMemoryStoreCompress(b, true) }
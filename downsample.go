@@ -0,0 +1,155 @@
+package appdash
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// A DownsamplingQueryer wraps a Queryer and returns only a representative
+// sample of its traces, which is useful for long-term archival: passing it
+// to Export archives both the normal and the worst-case behavior of each
+// endpoint over time at a fraction of the storage.
+//
+// Traces are grouped by Key and by time bucket (of width Interval, based on
+// each trace's start time). From each group, the slowest trace is always
+// kept, along with up to Keep-1 other traces chosen at random as typical
+// examples. All other traces are dropped.
+type DownsamplingQueryer struct {
+	// Queryer is the underlying queryer whose traces are downsampled.
+	Queryer
+
+	// Key, if non-nil, returns the key that traces are grouped by, such as
+	// their route. If nil, traces are grouped by root span name, like the
+	// results of an Aggregator.
+	Key func(*Trace) string
+
+	// Interval is the width of the time buckets that traces are grouped
+	// into. If zero, traces are grouped by key alone.
+	Interval time.Duration
+
+	// Keep is the number of traces kept per group, including the slowest.
+	// Values less than 1 are treated as 1.
+	Keep int
+
+	// Rand, if non-nil, is the source of randomness used to pick typical
+	// traces. If nil, the math/rand package's default source is used.
+	Rand *rand.Rand
+}
+
+// NewDownsamplingQueryer is shorthand for:
+//
+// 	&DownsamplingQueryer{
+// 		Queryer:  q,
+// 		Interval: interval,
+// 		Keep:     2,
+// 	}
+//
+func NewDownsamplingQueryer(q Queryer, interval time.Duration) *DownsamplingQueryer {
+	return &DownsamplingQueryer{
+		Queryer:  q,
+		Interval: interval,
+		Keep:     2,
+	}
+}
+
+// downsampleGroup identifies a group of traces by key and time bucket.
+type downsampleGroup struct {
+	key    string
+	bucket int64
+}
+
+// downsampledTrace is a trace along with its duration.
+type downsampledTrace struct {
+	trace    *Trace
+	duration time.Duration
+}
+
+// Traces implements the Queryer interface by returning the representative
+// traces of the underlying queryer's traces, in order of trace ID.
+func (dq *DownsamplingQueryer) Traces(opts TracesOpts) ([]*Trace, error) {
+	traces, err := dq.Queryer.Traces(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[downsampleGroup][]downsampledTrace{}
+	for _, t := range traces {
+		start, end, _ := t.times()
+		g := downsampleGroup{key: dq.key(t)}
+		if dq.Interval > 0 && !start.IsZero() {
+			g.bucket = start.UnixNano() / int64(dq.Interval)
+		}
+		groups[g] = append(groups[g], downsampledTrace{trace: t, duration: end.Sub(start)})
+	}
+
+	var kept []*Trace
+	for _, group := range groups {
+		kept = append(kept, dq.pick(group)...)
+	}
+	sort.Sort(tracesByID(kept))
+	return kept, nil
+}
+
+func (dq *DownsamplingQueryer) key(t *Trace) string {
+	if dq.Key != nil {
+		return dq.Key(t)
+	}
+	return t.Span.Name()
+}
+
+// pick returns the slowest trace of the group followed by Keep-1 other
+// traces chosen at random.
+func (dq *DownsamplingQueryer) pick(group []downsampledTrace) []*Trace {
+	slowest := 0
+	for i, t := range group {
+		s := group[slowest]
+		if t.duration > s.duration || (t.duration == s.duration && t.trace.ID.Trace < s.trace.ID.Trace) {
+			slowest = i
+		}
+	}
+	kept := []*Trace{group[slowest].trace}
+
+	others := make([]*Trace, 0, len(group)-1)
+	for i, t := range group {
+		if i != slowest {
+			others = append(others, t.trace)
+		}
+	}
+	sort.Sort(tracesByID(others)) // make the choice depend only on Rand
+	perm := rand.Perm
+	if dq.Rand != nil {
+		perm = dq.Rand.Perm
+	}
+	for _, i := range perm(len(others)) {
+		if len(kept) >= dq.Keep {
+			break
+		}
+		kept = append(kept, others[i])
+	}
+	return kept
+}
+
+// times returns the earliest start and latest end time of any
+// TimespanEvent in t or its descendants, or ok == false if there are none.
+func (t *Trace) times() (start, end time.Time, ok bool) {
+	t.Walk(func(span *Span, depth int) error {
+		var events []Event
+		if err := UnmarshalEvents(span.Annotations, &events); err != nil {
+			return nil
+		}
+		s, e, found := findTraceTimes(events)
+		if !found {
+			return nil
+		}
+		if !ok || s.Before(start) {
+			start = s
+		}
+		if !ok || e.After(end) {
+			end = e
+		}
+		ok = true
+		return nil
+	})
+	return start, end, ok
+}
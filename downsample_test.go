@@ -0,0 +1,61 @@
+package appdash
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestDownsamplingQueryer(t *testing.T) {
+	ms := NewMemoryStore()
+	base := time.Unix(600, 0) // start of a minute
+	collect := func(trace ID, name string, start, d time.Duration) {
+		span := SpanID{Trace: trace, Span: trace}
+		for _, e := range []Event{SpanName(name), Timespan{S: base.Add(start), E: base.Add(start + d)}} {
+			as, err := MarshalEvent(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ms.Collect(span, as...); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Route "a" in the first minute; trace 3 is the slowest.
+	collect(1, "a", 0, time.Second)
+	collect(2, "a", 10*time.Second, 2*time.Second)
+	collect(3, "a", 20*time.Second, 9*time.Second)
+	collect(4, "a", 30*time.Second, time.Second)
+	collect(5, "a", 40*time.Second, 3*time.Second)
+	// Route "a" in the second minute, and route "b" in the first.
+	collect(6, "a", 70*time.Second, time.Second)
+	collect(7, "b", 0, time.Second)
+
+	for seed := int64(0); seed < 20; seed++ {
+		dq := NewDownsamplingQueryer(ms, time.Minute)
+		dq.Rand = rand.New(rand.NewSource(seed))
+		traces, err := dq.Traces(TracesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		kept := map[ID]bool{}
+		for _, tr := range traces {
+			kept[tr.ID.Trace] = true
+		}
+		if !kept[3] {
+			t.Errorf("seed %d: slowest trace was dropped (kept %v)", seed, kept)
+		}
+		if !kept[6] || !kept[7] {
+			t.Errorf("seed %d: single traces of other groups were dropped (kept %v)", seed, kept)
+		}
+		if len(traces) != 4 {
+			t.Errorf("seed %d: got %d traces, want 4 (2 + 1 + 1)", seed, len(traces))
+		}
+		for i := 1; i < len(traces); i++ {
+			if traces[i-1].ID.Trace >= traces[i].ID.Trace {
+				t.Errorf("seed %d: traces not sorted by ID", seed)
+			}
+		}
+	}
+}
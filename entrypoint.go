@@ -0,0 +1,27 @@
+package appdash
+
+// EntrypointKey is the reserved annotation key that marks a span as the
+// entrypoint of its trace: the root span recorded for an external request
+// that started the trace, such as an inbound HTTP request that carried no
+// span ID (see httptrace.Middleware). Spans that continue a trace begun
+// elsewhere are never marked, so traces can be told apart by whether they
+// were triggered by a request or are internal work.
+const EntrypointKey = "_entrypoint"
+
+// Entrypoint returns an annotation marking the span it is collected on as
+// the entrypoint of its trace (see EntrypointKey).
+func Entrypoint() Annotation {
+	return Annotation{Key: EntrypointKey, Value: []byte("1")}
+}
+
+// IsEntrypoint reports whether the span is marked as the entrypoint of its
+// trace.
+func (s *Span) IsEntrypoint() bool {
+	return s.ID.IsRoot() && s.Annotations.has(EntrypointKey)
+}
+
+// EntrypointFilter is a QueryFilter matching the traces whose root span is
+// an entrypoint, i.e. that were started by an external request.
+func EntrypointFilter(t *Trace) bool {
+	return t.Span.IsEntrypoint()
+}
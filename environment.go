@@ -0,0 +1,48 @@
+package appdash
+
+// EnvironmentKey is the annotation key under which the deployment
+// environment (such as "dev", "staging" or "prod") that recorded a span is
+// stored. It lets the traces of several environments be sent to the same
+// collector and then told apart (see TracesOpts.Environment).
+const EnvironmentKey = "Environment"
+
+// Environment returns an annotation recording that the span it is
+// collected on was recorded in the named deployment environment.
+func Environment(name string) Annotation {
+	return Annotation{Key: EnvironmentKey, Value: []byte(name)}
+}
+
+// Environment returns the deployment environment that recorded the span
+// (see EnvironmentKey), or "" if it has none.
+func (s *Span) Environment() string {
+	return string(s.Annotations.get(EnvironmentKey))
+}
+
+// An EnvironmentCollector is a Collector that tags the spans collected
+// through it with a deployment environment (see EnvironmentKey) before
+// passing them on. Spans that already name an environment are passed on
+// unchanged.
+type EnvironmentCollector struct {
+	// Collector is the underlying collector that spans are sent to.
+	Collector
+
+	// Environment is the name of the environment that spans are tagged
+	// with.
+	Environment string
+}
+
+// NewEnvironmentCollector is shorthand for:
+//
+// 	c := &EnvironmentCollector{
+// 		Collector:   c,
+// 		Environment: env,
+// 	}
+//
+func NewEnvironmentCollector(c Collector, env string) *EnvironmentCollector {
+	return &EnvironmentCollector{Collector: c, Environment: env}
+}
+
+// Collect implements the Collector interface.
+func (ec *EnvironmentCollector) Collect(span SpanID, anns ...Annotation) error {
+	return ec.Collector.Collect(span, withDefault(anns, Environment(ec.Environment))...)
+}
@@ -3,6 +3,7 @@ package appdash
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 )
 
@@ -38,7 +39,19 @@ type EventUnmarshaler interface {
 
 const schemaPrefix = "_schema:"
 
-// MarshalEvent marshals an event into annotations.
+// MarshalEvent marshals an event into annotations. The annotations are
+// validated against the event's registered schema according to
+// SchemaValidation. If an allow-list is registered for the schema (see
+// RegisterEventAllowList), only the annotations it allows are returned.
+//
+// Unless the event implements EventMarshaler, each exported field becomes
+// an annotation keyed by the field's name, with the fields of nested
+// structs joined by "." (e.g. "Request.Method"). A field's key may be set
+// independently of its Go name with an "appdash" struct tag, e.g.
+// `appdash:"request_method"`, which UnmarshalEvent honors as well.
+//
+// The annotations of any functions registered for the event's schema with
+// RegisterDerivedAnnotations are appended.
 func MarshalEvent(e Event) (Annotations, error) {
 	// Handle event marshalers.
 	if v, ok := e.(EventMarshaler); ok {
@@ -46,16 +59,25 @@ func MarshalEvent(e Event) (Annotations, error) {
 		if err != nil {
 			return nil, err
 		}
-		as = append(as, Annotation{Key: schemaPrefix + e.Schema()})
-		return as, nil
+		return finishEvent(e.Schema(), as)
 	}
 
 	var as Annotations
 	flattenValue("", reflect.ValueOf(e), func(k, v string) {
 		as = append(as, Annotation{Key: k, Value: []byte(v)})
 	})
-	as = append(as, Annotation{Key: schemaPrefix + e.Schema()})
-	return as, nil
+	return finishEvent(e.Schema(), as)
+}
+
+// finishEvent validates the marshaled annotations of an event of the given
+// schema, and returns them along with their derived annotations, trimmed to
+// the schema's allow-list, and the schema annotation.
+func finishEvent(schema string, as Annotations) (Annotations, error) {
+	if err := checkSchema(schema, as, true); err != nil {
+		return nil, err
+	}
+	as = applyAllowList(schema, derive(schema, as))
+	return append(as, Annotation{Key: schemaPrefix + schema}), nil
 }
 
 // An EventSchemaUnmarshalError is when annotations are attempted to
@@ -70,7 +92,9 @@ func (e *EventSchemaUnmarshalError) Error() string {
 	return fmt.Sprintf("event: can't unmarshal annotations with schemas %v into event of schema %s", e.Found, e.Target)
 }
 
-// UnmarshalEvent unmarshals annotations into an event.
+// UnmarshalEvent unmarshals annotations into an event. If SchemaValidation
+// is enabled, the annotations are checked for keys missing from the event's
+// registered schema.
 func UnmarshalEvent(as Annotations, e Event) error {
 	aSchemas := as.schemas()
 	schemaOK := false
@@ -83,6 +107,9 @@ func UnmarshalEvent(as Annotations, e Event) error {
 	if !schemaOK {
 		return &EventSchemaUnmarshalError{Found: aSchemas, Target: e.Schema()}
 	}
+	if err := checkSchema(e.Schema(), as, false); err != nil {
+		return err
+	}
 
 	// Handle event unmarshalers.
 	if v, ok := e.(EventUnmarshaler); ok {
@@ -109,6 +136,9 @@ func UnmarshalEvent(as Annotations, e Event) error {
 //      _ "sourcegraph.com/sourcegraph/appdash/sqltrace"
 //  )
 //
+// The annotation types (see AnnotationType) of the event's numeric and time
+// fields, and its expected annotation keys (see EventSchema), are registered
+// as well.
 func RegisterEvent(e Event) {
 	if _, present := registeredEvents[e.Schema()]; present {
 		panic("event schema is already registered: " + e.Schema())
@@ -117,6 +147,15 @@ func RegisterEvent(e Event) {
 		panic("event schema is empty")
 	}
 	registeredEvents[e.Schema()] = e
+	if _, ok := e.(EventMarshaler); !ok {
+		registerFieldTypes("", reflect.TypeOf(e))
+		var s EventSchema
+		deriveEventSchema("", reflect.TypeOf(e), &s)
+		if s.reservesTags() {
+			panic("event has fields keyed under the reserved tag prefix " + TagPrefix + ": " + e.Schema())
+		}
+		RegisterEventSchema(e.Schema(), s)
+	}
 }
 
 var registeredEvents = map[string]Event{} // event schema -> event type
@@ -127,6 +166,10 @@ func init() {
 	RegisterEvent(msgEvent{})
 	RegisterEvent(timespanEvent{})
 	RegisterEvent(Timespan{})
+	RegisterEvent(CallerEvent{})
+	RegisterEvent(Attempt{})
+	RegisterEvent(GoroutinesEvent{})
+	RegisterAnnotationType(GoroutinesSpawnedKey, NumberAnnotation)
 }
 
 // UnmarshalEvents unmarshals all events found in anns into
@@ -169,6 +212,113 @@ type msgEvent struct {
 
 func (msgEvent) Schema() string { return "msg" }
 
+// followsFromKey is the reserved annotation key under which a span records
+// the ID of a span it follows from.
+const followsFromKey = "FollowsFrom"
+
+// A followsFrom event records that a span is causally linked to (but not a
+// child of) another span.
+type followsFrom struct{ Span SpanID }
+
+func (followsFrom) Schema() string { return "followsFrom" }
+
+func (e followsFrom) MarshalEvent() (Annotations, error) {
+	return Annotations{{Key: followsFromKey, Value: []byte(e.Span.String())}}, nil
+}
+
+// FollowsFrom returns an Event recording that the span it is recorded on
+// follows from the given span. Unlike a parent-child relationship, the
+// preceding span does not wait on (or contain) the one that follows it, as
+// is the case with fire-and-forget or otherwise asynchronous work.
+func FollowsFrom(span SpanID) Event {
+	return followsFrom{Span: span}
+}
+
+// Reserved annotation keys of the Attempt event.
+const (
+	retryOfKey      = "RetryOf"
+	attemptKey      = "Attempt"
+	finalAttemptKey = "Attempt.Final"
+)
+
+// An Attempt event records that a span is one of several attempts at a
+// single logical operation, such as a retried HTTP request. All attempts
+// after the first link to the first attempt's span, so that attempts can
+// be grouped. See Recorder.Attempt and Span.Attempt.
+type Attempt struct {
+	RetryOf SpanID // the span of the first attempt; zero on the first attempt itself
+	N       int    // the attempt number, starting at 1
+	Final   bool   // whether this is the last attempt, successful or not
+}
+
+// Schema returns the constant "attempt".
+func (Attempt) Schema() string { return "attempt" }
+
+// Important implements the ImportantEvent interface.
+func (Attempt) Important() []string { return []string{attemptKey, finalAttemptKey} }
+
+// MarshalEvent implements the EventMarshaler interface.
+func (e Attempt) MarshalEvent() (Annotations, error) {
+	as := Annotations{{Key: attemptKey, Value: []byte(strconv.Itoa(e.N))}}
+	if e.N > 1 {
+		as = append(as, Annotation{Key: retryOfKey, Value: []byte(e.RetryOf.String())})
+	}
+	if e.Final {
+		as = append(as, Annotation{Key: finalAttemptKey, Value: []byte("true")})
+	}
+	return as, nil
+}
+
+// UnmarshalEvent implements the EventUnmarshaler interface.
+func (Attempt) UnmarshalEvent(as Annotations) (Event, error) {
+	var e Attempt
+	n, err := strconv.Atoi(string(as.get(attemptKey)))
+	if err != nil {
+		return nil, fmt.Errorf("event: bad %s annotation: %s", attemptKey, err)
+	}
+	e.N = n
+	if v := as.get(retryOfKey); v != nil {
+		id, err := ParseSpanID(string(v))
+		if err != nil {
+			return nil, fmt.Errorf("event: bad %s annotation: %s", retryOfKey, err)
+		}
+		e.RetryOf = *id
+	}
+	e.Final = string(as.get(finalAttemptKey)) == "true"
+	return e, nil
+}
+
+// CallerEvent records the source location of the code that created a span.
+// See Recorder.RecordCaller.
+type CallerEvent struct {
+	File string `trace:"Caller.File"`
+	Line int    `trace:"Caller.Line"`
+	Func string `trace:"Caller.Func"`
+}
+
+func (CallerEvent) Schema() string { return "caller" }
+
+// Important implements the ImportantEvent interface, so that the origin of a
+// span is displayed in the web UI.
+func (CallerEvent) Important() []string { return []string{"Caller.File", "Caller.Line", "Caller.Func"} }
+
+// GoroutinesEvent records the number of goroutines in the process (as
+// reported by runtime.NumGoroutine) when a span started and ended. See
+// Recorder.RecordGoroutines.
+type GoroutinesEvent struct {
+	Start int `trace:"Goroutines.Start"`
+	End   int `trace:"Goroutines.End"`
+}
+
+func (GoroutinesEvent) Schema() string { return "goroutines" }
+
+// GoroutinesSpawnedKey is the annotation key under which
+// Recorder.TrackGoroutines records the approximate number of goroutines
+// spawned during a span: the growth in the goroutine count from its start
+// to its end, or zero if the count shrank. Goroutines that were spawned and
+// exited within the span are not counted, so it is a lower bound.
+const GoroutinesSpawnedKey = "Goroutines.Spawned"
+
 // A TimespanEvent is an Event with a start and an end time.
 type TimespanEvent interface {
 	Event
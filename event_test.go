@@ -174,6 +174,105 @@ func TestLog_Schema(t *testing.T) {
 	}
 }
 
+func TestMarshalEvent_schemaValidation(t *testing.T) {
+	origSchemas, origValidation := eventSchemas, SchemaValidation
+	defer func() {
+		eventSchemas, SchemaValidation = origSchemas, origValidation
+	}()
+	eventSchemas = make(map[string]*EventSchema)
+
+	var s EventSchema
+	deriveEventSchema("", reflect.TypeOf(dummyEvent2{}), &s)
+	RegisterEventSchema("dummy2", s)
+
+	// With validation off, the misspelled key goes unnoticed.
+	if _, err := MarshalEvent(misspelledEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	SchemaValidation = ValidationError
+	_, err := MarshalEvent(misspelledEvent{})
+	want := &EventSchemaValidationError{Schema: "dummy2", Unknown: []string{"Y"}, Missing: []string{"X"}}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("got error %v, want %v", err, want)
+	}
+	if _, err := MarshalEvent(dummyEvent2{}); err != nil {
+		t.Errorf("valid event: %s", err)
+	}
+
+	// Unmarshaling only checks for missing keys.
+	as := Annotations{{Key: "A"}, {Key: "Y"}, {Key: "_schema:dummy2"}}
+	err = UnmarshalEvent(as, &dummyEvent2{})
+	want = &EventSchemaValidationError{Schema: "dummy2", Missing: []string{"X"}}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("got error %v, want %v", err, want)
+	}
+
+	// Unregistered schemas pass through.
+	if _, err := MarshalEvent(dummyEvent{}); err != nil {
+		t.Errorf("unregistered schema: %s", err)
+	}
+}
+
+func TestMarshalEvent_allowList(t *testing.T) {
+	origSchemas, origValidation := eventSchemas, SchemaValidation
+	defer func() {
+		eventSchemas, SchemaValidation = origSchemas, origValidation
+		RegisterEventAllowList("dummy", nil)
+	}()
+	eventSchemas = make(map[string]*EventSchema)
+	var s EventSchema
+	deriveEventSchema("", reflect.TypeOf(dummyEvent{}), &s)
+	RegisterEventSchema("dummy", s)
+	SchemaValidation = ValidationError
+
+	e := dummyEvent{A: "a", B: "b", C: 1, D: map[string]string{"k": "v"}, F: dummyEventF{G: "g", H: map[string]string{"k2": "v2"}}}
+	RegisterEventAllowList("dummy", []string{"A", "F"})
+	as, err := MarshalEvent(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, a := range as {
+		keys = append(keys, a.Key)
+	}
+	sort.Strings(keys)
+	if want := []string{"A", "F.G", "F.H.k2", "_schema:dummy"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got keys %v, want only the allowed %v", keys, want)
+	}
+
+	// The trimmed fields unmarshal to zero values, without validation
+	// errors for the missing keys.
+	var got dummyEvent
+	if err := UnmarshalEvent(as, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (dummyEvent{A: "a", F: e.F}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Filtering leaves the annotations it was given intact.
+	all := Annotations{{Key: "A"}, {Key: "B"}, {Key: "F.G"}}
+	if kept := applyAllowList("dummy", all); len(kept) != 2 || all[1].Key != "B" {
+		t.Errorf("applyAllowList: got %v, and the input was changed to %v", kept, all)
+	}
+
+	// Removing the list restores the default of emitting every field.
+	RegisterEventAllowList("dummy", nil)
+	if as, err := MarshalEvent(e); err != nil || len(as) != 8 {
+		t.Errorf("got annotations %v (error %v), want all 7 fields and the schema", as, err)
+	}
+}
+
+// misspelledEvent is a dummyEvent2 whose MarshalEvent misspells the X key.
+type misspelledEvent struct{}
+
+func (misspelledEvent) Schema() string { return "dummy2" }
+
+func (misspelledEvent) MarshalEvent() (Annotations, error) {
+	return Annotations{{Key: "A"}, {Key: "Y"}}, nil
+}
+
 type dummyEvent struct {
 	A, B string
 	C    int
@@ -62,13 +62,10 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	tapp, err := traceapp.New(nil, url)
+	tapp, err := traceapp.NewWithStorage(nil, url, store)
 	if err != nil {
 		log.Fatal(err)
 	}
-	tapp.Store = store
-	tapp.Queryer = store
-	tapp.Aggregator = store
 	log.Println("Appdash web UI running on HTTP :8700")
 	go func() {
 		log.Fatal(http.ListenAndServe(":8700", tapp))
@@ -0,0 +1,123 @@
+package appdash
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultExemplarBuckets are the latency buckets of an ExemplarSelector
+// without Buckets: fast (under 100ms), medium (under 1s) and slow.
+var DefaultExemplarBuckets = []time.Duration{100 * time.Millisecond, time.Second}
+
+// An ExemplarSelector keeps exemplar traces: for each key, such as each
+// route of an HTTP server, and each latency bucket, it keeps the first
+// trace seen in each Window and drops the rest. This keeps an inspectable
+// example of every performance tier of every route, rather than only of
+// the most common (or only of the slowest) requests.
+//
+// Its Keep method is used as the Keep function of a TailSamplingCollector,
+// which buffers whole traces so that their durations are known (see
+// NewExemplarCollector).
+//
+// A trace's key and duration are those of its root span. Traces whose root
+// span was not collected, or has no timespan events, have no duration;
+// they form a bucket of their own.
+type ExemplarSelector struct {
+	// Key, if non-nil, returns the key of a trace given its root span.
+	// Otherwise RouteSamplingKey is used.
+	Key func(span SpanID, as Annotations) string
+
+	// Buckets are the upper bounds of the latency buckets, in increasing
+	// order. A trace is in the first bucket whose bound exceeds its
+	// duration, or in a final bucket if there is none. If nil,
+	// DefaultExemplarBuckets is used.
+	Buckets []time.Duration
+
+	// Window is the period for which a kept exemplar stands for its key
+	// and bucket. Windows are aligned to multiples of Window. If zero, only
+	// the first trace of each key and bucket is ever kept.
+	Window time.Duration
+
+	// Clock, if non-nil, is used instead of RealClock to determine the
+	// current window.
+	Clock Clock
+
+	mu     sync.Mutex
+	window time.Time            // start of the current window
+	kept   map[exemplarKey]bool // keys and buckets with an exemplar in the current window
+}
+
+// exemplarKey identifies a key and latency bucket of an ExemplarSelector.
+// Traces without a duration are in bucket -1.
+type exemplarKey struct {
+	key    string
+	bucket int
+}
+
+// NewExemplarCollector is shorthand for a TailSamplingCollector, as
+// returned by NewTailSamplingCollector, that passes on to c one exemplar
+// trace per route and latency bucket (DefaultExemplarBuckets) per window:
+//
+// 	tc := NewTailSamplingCollector(c)
+// 	tc.Keep = (&ExemplarSelector{Window: window}).Keep
+//
+func NewExemplarCollector(c Collector, window time.Duration) *TailSamplingCollector {
+	tc := NewTailSamplingCollector(c)
+	tc.Keep = (&ExemplarSelector{Window: window}).Keep
+	return tc
+}
+
+// Keep reports whether the trace with the given spans is an exemplar, and
+// should be kept.
+func (s *ExemplarSelector) Keep(spans []Span) bool {
+	k := exemplarKey{bucket: -1}
+	for i := range spans {
+		root := &spans[i]
+		if !root.ID.IsRoot() {
+			continue
+		}
+		keyFunc := s.Key
+		if keyFunc == nil {
+			keyFunc = RouteSamplingKey
+		}
+		k.key = keyFunc(root.ID, root.Annotations)
+		if d, ok := root.Duration(); ok {
+			k.bucket = s.bucket(d)
+		}
+		break
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Window > 0 {
+		now := RealClock.Now()
+		if s.Clock != nil {
+			now = s.Clock.Now()
+		}
+		if w := now.Truncate(s.Window); !w.Equal(s.window) {
+			s.window, s.kept = w, nil
+		}
+	}
+	if s.kept[k] {
+		return false
+	}
+	if s.kept == nil {
+		s.kept = make(map[exemplarKey]bool)
+	}
+	s.kept[k] = true
+	return true
+}
+
+// bucket returns the index of the latency bucket of duration d.
+func (s *ExemplarSelector) bucket(d time.Duration) int {
+	buckets := s.Buckets
+	if buckets == nil {
+		buckets = DefaultExemplarBuckets
+	}
+	for i, b := range buckets {
+		if d < b {
+			return i
+		}
+	}
+	return len(buckets)
+}
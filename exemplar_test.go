@@ -0,0 +1,90 @@
+package appdash
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestExemplarCollector(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ms := NewMemoryStore()
+	tc := NewTailSamplingCollector(ms)
+	tc.Keep = (&ExemplarSelector{Window: time.Minute, Clock: clock}).Keep
+	defer tc.Stop()
+
+	// collect collects and decides a trace with a root span of the given
+	// route and duration, and a child span.
+	collect := func(id ID, route string, d time.Duration) {
+		root := SpanID{Trace: id, Span: id}
+		as, err := MarshalEvent(Timespan{S: clock.t, E: clock.t.Add(d)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.Collect(root, Annotation{Key: "Server.Route", Value: []byte(route)}); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.Collect(NewSpanID(root)); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.Collect(root, as...); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	collect(1, "/a", 10*time.Millisecond)  // fast
+	collect(2, "/a", 50*time.Millisecond)  // fast again
+	collect(3, "/a", 500*time.Millisecond) // medium
+	collect(4, "/a", 2*time.Second)        // slow
+	collect(5, "/a", 3*time.Second)        // slow again
+	collect(6, "/b", 10*time.Millisecond)  // fast on another route
+	collect(7, "/a", 900*time.Millisecond) // medium again
+	clock.Advance(time.Minute)
+	collect(8, "/a", 20*time.Millisecond) // fast in a new window
+
+	traces, err := ms.Traces(TracesOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []ID
+	for _, tr := range traces {
+		kept = append(kept, tr.ID.Trace)
+		if len(tr.Sub) != 1 {
+			t.Errorf("trace %s: got %d sub-spans, want the whole trace", tr.ID.Trace, len(tr.Sub))
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i] < kept[j] })
+	if want := []ID{1, 3, 4, 6, 8}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("got exemplars %v, want %v", kept, want)
+	}
+}
+
+func TestExemplarSelector_buckets(t *testing.T) {
+	s := &ExemplarSelector{Buckets: []time.Duration{time.Second}}
+	span := func(d time.Duration) []Span {
+		var as Annotations
+		if d >= 0 {
+			as, _ = MarshalEvent(Timespan{S: time.Unix(0, 0), E: time.Unix(0, 0).Add(d)})
+		}
+		return []Span{{ID: SpanID{Trace: 1, Span: 1}, Annotations: as}}
+	}
+	tests := []struct {
+		d    time.Duration // -1 for no duration
+		keep bool
+	}{
+		{999 * time.Millisecond, true},
+		{0, false},
+		{time.Second, true},
+		{time.Hour, false},
+		{-1, true},
+		{-1, false},
+	}
+	for _, test := range tests {
+		if got := s.Keep(span(test.d)); got != test.keep {
+			t.Errorf("duration %s: got keep %v, want %v", test.d, got, test.keep)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package appdash
+
+// ErrorKey is the annotation key that marks a span as failed when its
+// value is "true". Integrations record it for failures that no status code
+// annotation already conveys, such as calls returning a non-OK gRPC status
+// (see grpctrace). MetricsOnlyCollector and the web UI count marked spans
+// as errors, as they do spans with a 5xx HTTP status.
+const ErrorKey = "Error"
+
+// Failed returns an annotation marking the span it is collected on as
+// failed (see ErrorKey).
+func Failed() Annotation {
+	return Annotation{Key: ErrorKey, Value: []byte("true")}
+}
+
+// Failed reports whether the span is marked as failed (see ErrorKey).
+func (s *Span) Failed() bool {
+	return string(s.Annotations.get(ErrorKey)) == "true"
+}
@@ -0,0 +1,247 @@
+package appdash
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// TraceFinishedKey is the reserved annotation key marking a trace as
+// complete. Recorder.Finish records it on root spans.
+const TraceFinishedKey = "_traceFinished"
+
+// TraceFinished returns an annotation marking the trace of the span it is
+// collected on as complete (see FinalizingCollector). It is only
+// meaningful on root spans.
+func TraceFinished() Annotation {
+	return Annotation{Key: TraceFinishedKey, Value: []byte("true")}
+}
+
+// TraceCompletion describes how a FinalizingCollector determined that a
+// trace is complete.
+type TraceCompletion int
+
+const (
+	// TraceRootFinished means that the trace's root span was collected
+	// with a TraceFinished annotation or a timespan event.
+	TraceRootFinished TraceCompletion = iota
+
+	// TraceQuiescent means that no spans of the trace were collected for
+	// the FinalizingCollector's QuietPeriod.
+	TraceQuiescent
+)
+
+func (c TraceCompletion) String() string {
+	switch c {
+	case TraceRootFinished:
+		return "root finished"
+	case TraceQuiescent:
+		return "quiescent"
+	}
+	return "unknown"
+}
+
+// A TraceFinalizer is called once for each trace that a FinalizingCollector
+// determines to be complete, after all of the trace's spans collected so
+// far have been passed to the underlying collector.
+type TraceFinalizer func(trace ID, how TraceCompletion)
+
+// A FinalizingCollector passes spans to an underlying collector and calls
+// the finalizers registered with OnComplete when a trace is complete, so
+// that consumers such as per-trace aggregations know when to flush.
+//
+// A trace is complete once its root span is collected with either a
+// TraceFinished annotation (as recorded by Recorder.Finish) or a timespan
+// event with both a start and an end time. Lacking either, a trace is
+// complete once no new spans have been collected for it for QuietPeriod.
+// Spans collected after their trace was completed are tracked as a new
+// trace, which completes in the same way.
+type FinalizingCollector struct {
+	// Collector is the underlying collector that spans are sent to.
+	Collector
+
+	// QuietPeriod is how long a trace must go without new spans before it
+	// is complete, if its root span has not finished.
+	QuietPeriod time.Duration
+
+	// Clock, if non-nil, is used instead of RealClock to measure
+	// QuietPeriod.
+	Clock Clock
+
+	// Log, if non-nil, is used to log panics of finalizers.
+	Log *log.Logger
+
+	finalizers       []*TraceFinalizer
+	pending          map[ID]time.Time // trace ID -> when its latest span was collected
+	started, stopped bool
+	stopChan         chan struct{}
+
+	mu sync.Mutex // protects finalizers, pending, started, stopped and stopChan
+}
+
+// NewFinalizingCollector is shorthand for:
+//
+// 	c := &FinalizingCollector{
+// 		Collector:   c,
+// 		QuietPeriod: 30 * time.Second,
+// 	}
+//
+func NewFinalizingCollector(c Collector) *FinalizingCollector {
+	return &FinalizingCollector{
+		Collector:   c,
+		QuietPeriod: 30 * time.Second,
+	}
+}
+
+func (fc *FinalizingCollector) now() time.Time {
+	if fc.Clock != nil {
+		return fc.Clock.Now()
+	}
+	return RealClock.Now()
+}
+
+// OnComplete registers fn to be called for each trace once it is complete.
+// Finalizers are called synchronously, either from Collect or from the
+// collector's background goroutine, and so should not block.
+//
+// Call the returned function to unregister the finalizer.
+func (fc *FinalizingCollector) OnComplete(fn TraceFinalizer) (remove func()) {
+	f := &fn
+	fc.mu.Lock()
+	fc.finalizers = append(fc.finalizers, f)
+	fc.mu.Unlock()
+	return func() {
+		fc.mu.Lock()
+		defer fc.mu.Unlock()
+		for i, other := range fc.finalizers {
+			if other == f {
+				fc.finalizers = append(fc.finalizers[:i:i], fc.finalizers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Collect implements the Collector interface by passing the span to the
+// underlying collector and then calling the finalizers of any traces that
+// are complete.
+func (fc *FinalizingCollector) Collect(span SpanID, anns ...Annotation) error {
+	finished := span.IsRoot() && rootFinished(anns)
+
+	fc.mu.Lock()
+	if fc.stopped {
+		fc.mu.Unlock()
+		return errors.New("FinalizingCollector is stopped")
+	}
+	if !fc.started {
+		fc.start()
+	}
+	if fc.pending == nil {
+		fc.pending = make(map[ID]time.Time)
+	}
+	now := fc.now()
+	complete := fc.quiescentNoLock(now)
+	if finished {
+		delete(fc.pending, span.Trace)
+	} else {
+		fc.pending[span.Trace] = now
+	}
+	finalizers := fc.finalizers
+	fc.mu.Unlock()
+
+	err := fc.Collector.Collect(span, anns...)
+	if finished {
+		fc.finalize(finalizers, span.Trace, TraceRootFinished)
+	}
+	for _, id := range complete {
+		fc.finalize(finalizers, id, TraceQuiescent)
+	}
+	return err
+}
+
+// rootFinished reports whether the annotations of a root span show that it
+// has finished.
+func rootFinished(anns Annotations) bool {
+	if anns.has(TraceFinishedKey) {
+		return true
+	}
+	var events []Event
+	if err := UnmarshalEvents(anns, &events); err != nil {
+		return false
+	}
+	for _, e := range events {
+		if e, ok := e.(TimespanEvent); ok && !e.Start().IsZero() && !e.End().IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// quiescentNoLock removes and returns the IDs of the tracked traces that
+// are quiescent as of now. The fc.mu lock must be held while calling
+// quiescentNoLock.
+func (fc *FinalizingCollector) quiescentNoLock(now time.Time) []ID {
+	var ids []ID
+	for id, last := range fc.pending {
+		if now.Sub(last) >= fc.QuietPeriod {
+			ids = append(ids, id)
+			delete(fc.pending, id)
+		}
+	}
+	return ids
+}
+
+// finalize calls the given finalizers for the trace, logging (rather than
+// propagating) their panics so that one bad finalizer does not break
+// collection.
+func (fc *FinalizingCollector) finalize(finalizers []*TraceFinalizer, trace ID, how TraceCompletion) {
+	for _, f := range finalizers {
+		func() {
+			defer func() {
+				if rv := recover(); rv != nil && fc.Log != nil {
+					fc.Log.Printf("FinalizingCollector: finalizer for trace %v panicked: %v", trace, rv)
+				}
+			}()
+			(*f)(trace, how)
+		}()
+	}
+}
+
+// start starts the goroutine that periodically completes quiescent traces,
+// so that a trace is completed even if no further spans are collected.
+func (fc *FinalizingCollector) start() {
+	fc.stopChan = make(chan struct{})
+	fc.started = true
+	interval := fc.QuietPeriod
+	if interval <= 0 {
+		interval = time.Second
+	}
+	go func() {
+		for {
+			select {
+			case <-time.After(interval):
+				fc.mu.Lock()
+				complete := fc.quiescentNoLock(fc.now())
+				finalizers := fc.finalizers
+				fc.mu.Unlock()
+				for _, id := range complete {
+					fc.finalize(finalizers, id, TraceQuiescent)
+				}
+			case <-fc.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the collector's background goroutine. Traces that are not yet
+// complete are not finalized. After stopping, calls to Collect will fail.
+func (fc *FinalizingCollector) Stop() {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.started && !fc.stopped {
+		close(fc.stopChan)
+	}
+	fc.stopped = true
+}
@@ -0,0 +1,117 @@
+package appdash
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFinalizingCollector(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ms := NewMemoryStore()
+	fc := NewFinalizingCollector(ms)
+	fc.QuietPeriod = time.Minute
+	fc.Clock = clock
+	defer fc.Stop()
+
+	type completion struct {
+		trace ID
+		how   TraceCompletion
+		spans int // spans stored when the finalizer was called
+	}
+	var (
+		mu          sync.Mutex
+		completions []completion
+	)
+	fc.OnComplete(func(trace ID, how TraceCompletion) {
+		var spans int
+		if t, err := ms.Trace(trace); err == nil {
+			t.Walk(func(*Span, int) error { spans++; return nil })
+		}
+		mu.Lock()
+		completions = append(completions, completion{trace, how, spans})
+		mu.Unlock()
+	})
+	check := func(want ...completion) {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(completions, want) {
+			t.Fatalf("got completions %+v, want %+v", completions, want)
+		}
+	}
+
+	// Trace 1 is completed by an explicit Recorder.Finish of its root.
+	root := NewRecorder(SpanID{Trace: 1, Span: 1}, fc)
+	child := root.Child()
+	child.Name("child")
+	child.Finish()
+	check()
+	root.Finish()
+	check(completion{1, TraceRootFinished, 2})
+
+	// Trace 2 is completed by its root's timespan event.
+	if err := fc.Collect(SpanID{2, 20, 0}, mustMarshalTimespan(t, clock.Now())...); err != nil {
+		t.Fatal(err)
+	}
+	check(completion{1, TraceRootFinished, 2}, completion{2, TraceRootFinished, 1})
+
+	// Trace 3 has no finished root, so it is completed once quiescent.
+	if err := fc.Collect(SpanID{3, 31, 30}); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(59 * time.Second)
+	if err := fc.Collect(SpanID{3, 32, 30}); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(59 * time.Second)
+	if err := fc.Collect(SpanID{4, 40, 0}); err != nil {
+		t.Fatal(err)
+	}
+	check(completion{1, TraceRootFinished, 2}, completion{2, TraceRootFinished, 1})
+	clock.Advance(time.Second)
+	if err := fc.Collect(SpanID{4, 41, 40}); err != nil {
+		t.Fatal(err)
+	}
+	check(completion{1, TraceRootFinished, 2}, completion{2, TraceRootFinished, 1}, completion{3, TraceQuiescent, 2})
+}
+
+func TestFinalizingCollector_background(t *testing.T) {
+	fc := NewFinalizingCollector(NewMemoryStore())
+	fc.QuietPeriod = 10 * time.Millisecond
+	defer fc.Stop()
+
+	done := make(chan TraceCompletion, 1)
+	remove := fc.OnComplete(func(trace ID, how TraceCompletion) { done <- how })
+	if err := fc.Collect(SpanID{1, 2, 1}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case how := <-done:
+		if how != TraceQuiescent {
+			t.Errorf("got completion %v, want %v", how, TraceQuiescent)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("quiescent trace was not completed")
+	}
+
+	// Removed finalizers are not called.
+	remove()
+	if err := NewRecorder(SpanID{Trace: 2, Span: 2}, fc).failsafeAnnotation(TraceFinished()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+		t.Error("removed finalizer was called")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func mustMarshalTimespan(t *testing.T, start time.Time) Annotations {
+	as, err := MarshalEvent(Timespan{S: start, E: start.Add(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return as
+}
@@ -0,0 +1,96 @@
+//go:build grpc
+// +build grpc
+
+package grpctrace
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func init() {
+	appdash.RegisterEvent(ServerEvent{})
+	appdash.RegisterEvent(ClientEvent{})
+}
+
+// ServerEvent records a gRPC server call handling event.
+type ServerEvent struct {
+	Method     string    `trace:"Server.Method"`
+	Err        string    `trace:"Server.Err"`
+	ServerRecv time.Time `trace:"Server.Recv"`
+	ServerSend time.Time `trace:"Server.Send"`
+
+	// Status is the status that the call returned, and Failed whether it
+	// was not OK (which marks the span as failed; see appdash.ErrorKey).
+	Status Status `trace:"Server.Status"`
+	Failed bool   `trace:"Error"`
+}
+
+// Schema returns the constant "GRPCServer".
+func (ServerEvent) Schema() string { return "GRPCServer" }
+
+// Important implements the appdash ImportantEvent.
+func (ServerEvent) Important() []string {
+	return []string{"Server.Method", "Server.Err", "Server.Status.CodeName"}
+}
+
+// Start implements the appdash TimespanEvent interface.
+func (e ServerEvent) Start() time.Time { return e.ServerRecv }
+
+// End implements the appdash TimespanEvent interface.
+func (e ServerEvent) End() time.Time { return e.ServerSend }
+
+// ClientEvent records a gRPC client call event.
+type ClientEvent struct {
+	Method     string    `trace:"Client.Method"`
+	Err        string    `trace:"Client.Err"`
+	ClientSend time.Time `trace:"Client.Send"`
+	ClientRecv time.Time `trace:"Client.Recv"`
+
+	// Status is the status that the call returned, and Failed whether it
+	// was not OK (which marks the span as failed; see appdash.ErrorKey).
+	Status Status `trace:"Client.Status"`
+	Failed bool   `trace:"Error"`
+}
+
+// Schema returns the constant "GRPCClient".
+func (ClientEvent) Schema() string { return "GRPCClient" }
+
+// Important implements the appdash ImportantEvent.
+func (ClientEvent) Important() []string {
+	return []string{"Client.Method", "Client.Err", "Client.Status.CodeName"}
+}
+
+// Start implements the appdash TimespanEvent interface.
+func (e ClientEvent) Start() time.Time { return e.ClientSend }
+
+// End implements the appdash TimespanEvent interface.
+func (e ClientEvent) End() time.Time { return e.ClientRecv }
+
+// Status is the gRPC status of a call, recorded by ServerEvent and
+// ClientEvent.
+type Status struct {
+	Code     int    // numeric status code, e.g. 14
+	CodeName string // name of the status code, e.g. "Unavailable"
+	Message  string // status message
+}
+
+// statusOf returns the status of a call that returned err (see
+// status.FromError; errors that carry no status have code Unknown), and
+// whether it was not OK.
+func statusOf(err error) (Status, bool) {
+	s, _ := status.FromError(err)
+	return Status{Code: int(s.Code()), CodeName: s.Code().String(), Message: s.Message()}, s.Code() != codes.OK
+}
+
+// errString returns err's message, or "" if err is nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
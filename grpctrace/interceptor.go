@@ -0,0 +1,158 @@
+//go:build grpc
+// +build grpc
+
+package grpctrace
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// serverSpan returns the span of the call whose incoming metadata is stored
+// in ctx, and a copy of ctx carrying that span.
+func serverSpan(ctx context.Context, method string) (appdash.SpanID, context.Context) {
+	spanID, err := spanIDFromIncomingContext(ctx)
+	if err != nil {
+		log.Printf("Warning: invalid %s metadata for %s: %s. (Continuing with call handling.)", MetadataSpanID, method, err)
+		newSpanID := appdash.NewRootSpanID()
+		spanID = &newSpanID
+	}
+	return *spanID, NewContext(ctx, *spanID)
+}
+
+// recordServer records e on span.
+func recordServer(c appdash.Collector, span appdash.SpanID, e *ServerEvent) {
+	rec := appdash.NewRecorder(span, c)
+	rec.Name("Serve " + e.Method)
+	rec.Event(e)
+	rec.Finish()
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor that records incoming
+// unary calls to the collector c as "GRPCServer"-schema events. The call's
+// span is taken from the incoming metadata (or created anew) and stored in
+// the handler's context; see FromContext.
+func UnaryServerInterceptor(c appdash.Collector) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		span, ctx := serverSpan(ctx, info.FullMethod)
+		e := &ServerEvent{Method: info.FullMethod, ServerRecv: time.Now()}
+		resp, err := handler(ctx, req)
+		e.ServerSend = time.Now()
+		e.Err = errString(err)
+		e.Status, e.Failed = statusOf(err)
+		recordServer(c, span, e)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor that records incoming
+// streaming calls to the collector c as "GRPCServer"-schema events, spanning
+// the lifetime of the stream.
+func StreamServerInterceptor(c appdash.Collector) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		span, ctx := serverSpan(ss.Context(), info.FullMethod)
+		e := &ServerEvent{Method: info.FullMethod, ServerRecv: time.Now()}
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		e.ServerSend = time.Now()
+		e.Err = errString(err)
+		e.Status, e.Failed = statusOf(err)
+		recordServer(c, span, e)
+		return err
+	}
+}
+
+// serverStream is a grpc.ServerStream whose context carries the call's span.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context implements the grpc.ServerStream interface.
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// clientSpan returns a new span for an outgoing call made with ctx: a child
+// of the span stored in ctx, or a new root span if there is none.
+func clientSpan(ctx context.Context) appdash.SpanID {
+	if parent, ok := FromContext(ctx); ok {
+		return appdash.NewSpanID(parent)
+	}
+	return appdash.NewRootSpanID()
+}
+
+// recordClient records e on span.
+func recordClient(c appdash.Collector, span appdash.SpanID, e *ClientEvent) {
+	rec := appdash.NewRecorder(span, c)
+	rec.Name("Call " + e.Method)
+	rec.Event(e)
+	rec.Finish()
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that records outgoing
+// unary calls to the collector c as "GRPCClient"-schema events. Each call is
+// given a new span (see clientSpan), which is sent to the server in the
+// call's metadata.
+func UnaryClientInterceptor(c appdash.Collector) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		span := clientSpan(ctx)
+		e := &ClientEvent{Method: method, ClientSend: time.Now()}
+		err := invoker(newOutgoingContext(ctx, span), method, req, reply, cc, opts...)
+		e.ClientRecv = time.Now()
+		e.Err = errString(err)
+		e.Status, e.Failed = statusOf(err)
+		recordClient(c, span, e)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC interceptor that records outgoing
+// streaming calls to the collector c as "GRPCClient"-schema events. The
+// event is recorded once the stream ends, that is when receiving from it
+// fails or reports io.EOF.
+func StreamClientInterceptor(c appdash.Collector) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		span := clientSpan(ctx)
+		e := &ClientEvent{Method: method, ClientSend: time.Now()}
+		cs, err := streamer(newOutgoingContext(ctx, span), desc, cc, method, opts...)
+		if err != nil {
+			e.ClientRecv = time.Now()
+			e.Err = errString(err)
+			e.Status, e.Failed = statusOf(err)
+			recordClient(c, span, e)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, finish: func(err error) {
+			e.ClientRecv = time.Now()
+			e.Err = errString(err)
+			e.Status, e.Failed = statusOf(err)
+			recordClient(c, span, e)
+		}}, nil
+	}
+}
+
+// clientStream is a grpc.ClientStream that calls finish once the stream
+// ends.
+type clientStream struct {
+	grpc.ClientStream
+	finish func(error)
+	once   sync.Once
+}
+
+// RecvMsg implements the grpc.ClientStream interface.
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		if err == io.EOF {
+			s.once.Do(func() { s.finish(nil) })
+		} else {
+			s.once.Do(func() { s.finish(err) })
+		}
+	}
+	return err
+}
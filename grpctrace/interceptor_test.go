@@ -0,0 +1,80 @@
+//go:build grpc
+// +build grpc
+
+package grpctrace
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestInterceptors_status(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	unavailable := status.New(codes.Unavailable, "backend is down").Err()
+
+	// The client calls the server directly through the interceptors,
+	// passing the outgoing metadata on as incoming metadata, so that both
+	// record on the call's span.
+	server := UnaryServerInterceptor(ms)
+	client := UnaryClientInterceptor(ms)
+	call := func(handlerErr error) (appdash.SpanID, error) {
+		var span appdash.SpanID
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			_, err := server(metadata.NewIncomingContext(ctx, md), req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				span, _ = FromContext(ctx)
+				return nil, handlerErr
+			})
+			return err
+		}
+		err := client(context.Background(), "/pkg.Svc/Get", nil, nil, nil, invoker)
+		return span, err
+	}
+
+	span, err := call(unavailable)
+	if err != unavailable {
+		t.Fatalf("got error %v, want %v", err, unavailable)
+	}
+	trace, err := ms.Trace(span.Trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anns := trace.Span.Annotations.StringMap()
+	for key, want := range map[string]string{
+		"Client.Status.Code":     "14",
+		"Client.Status.CodeName": "Unavailable",
+		"Client.Status.Message":  "backend is down",
+		"Server.Status.Code":     "14",
+		"Server.Status.CodeName": "Unavailable",
+		"Server.Status.Message":  "backend is down",
+	} {
+		if got := anns[key]; got != want {
+			t.Errorf("got %s %q, want %q", key, got, want)
+		}
+	}
+	if !trace.Span.Failed() {
+		t.Errorf("span %v is not marked failed", trace.Span.Annotations)
+	}
+
+	// A successful call is not marked failed.
+	span, err = call(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace, err = ms.Trace(span.Trace); err != nil {
+		t.Fatal(err)
+	}
+	if got := trace.Span.Annotations.StringMap()["Server.Status.CodeName"]; got != "OK" {
+		t.Errorf("got status %q, want OK", got)
+	}
+	if trace.Span.Failed() {
+		t.Error("successful call is marked failed")
+	}
+}
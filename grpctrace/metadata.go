@@ -0,0 +1,131 @@
+//go:build grpc
+// +build grpc
+
+// Package grpctrace implements support for tracing gRPC applications.
+//
+// Span IDs are propagated between services in gRPC metadata, using the same
+// encoding as the httptrace package uses for its Span-ID header. The
+// interceptors in this package record a GRPCServer event for each call a
+// server handles and a GRPCClient event for each call a client makes.
+//
+// A server is instrumented with:
+//
+//  s := grpc.NewServer(
+//      grpc.UnaryInterceptor(grpctrace.UnaryServerInterceptor(collector)),
+//      grpc.StreamInterceptor(grpctrace.StreamServerInterceptor(collector)),
+//  )
+//
+// And a client with:
+//
+//  conn, err := grpc.Dial(addr,
+//      grpc.WithUnaryInterceptor(grpctrace.UnaryClientInterceptor(collector)),
+//      grpc.WithStreamInterceptor(grpctrace.StreamClientInterceptor(collector)),
+//  )
+//
+// Calls made with a context carrying a span (see NewContext) become children
+// of that span; the server interceptor stores the span of the call it is
+// handling in the handler's context, so outgoing calls made while handling a
+// request are linked to it automatically.
+//
+// The package is only built with the grpc build tag, because gRPC is not
+// vendored with appdash: get google.golang.org/grpc and build with
+// "go build -tags grpc".
+package grpctrace
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+const (
+	// MetadataSpanID is the gRPC metadata key by which the trace and
+	// span IDs are passed along. It is the lowercase form of the
+	// httptrace.HeaderSpanID header, as gRPC requires lowercase keys.
+	MetadataSpanID = "span-id"
+
+	// MetadataParentSpanID is the gRPC metadata key by which the parent
+	// trace and span IDs are passed along, for clients that are
+	// incapable of creating their own span IDs.
+	MetadataParentSpanID = "parent-span-id"
+)
+
+// SetSpanIDMetadata sets the span-id metadata key.
+func SetSpanIDMetadata(md metadata.MD, span appdash.SpanID) {
+	md[MetadataSpanID] = []string{span.String()}
+}
+
+// GetSpanID returns the SpanID for the current call, based on the given
+// metadata. If a span-id key is present, it is parsed; if a parent-span-id
+// key is present, a new child span is created and it is returned; otherwise
+// a new root SpanID is created.
+func GetSpanID(md metadata.MD) (*appdash.SpanID, error) {
+	spanID, err := getSpanIDMetadata(md, MetadataSpanID)
+	if err != nil {
+		return nil, err
+	}
+	if spanID != nil {
+		return spanID, nil
+	}
+
+	parent, err := getSpanIDMetadata(md, MetadataParentSpanID)
+	if err != nil {
+		return nil, err
+	}
+	if parent != nil {
+		newSpanID := appdash.NewSpanID(*parent)
+		return &newSpanID, nil
+	}
+
+	newSpanID := appdash.NewRootSpanID()
+	return &newSpanID, nil
+}
+
+// getSpanIDMetadata returns the SpanID in the metadata (specified by key),
+// nil if no such key was provided, or an error if the value was
+// unparseable.
+func getSpanIDMetadata(md metadata.MD, key string) (*appdash.SpanID, error) {
+	v := md[key]
+	if len(v) == 0 || v[0] == "" {
+		return nil, nil
+	}
+	return appdash.ParseSpanID(v[0])
+}
+
+// contextKey is the type of the context key under which the current span is
+// stored.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the given span. Outgoing
+// calls made with the returned context are recorded as its children.
+func NewContext(ctx context.Context, span appdash.SpanID) context.Context {
+	return context.WithValue(ctx, contextKey{}, span)
+}
+
+// FromContext returns the span stored in ctx by NewContext, if any.
+func FromContext(ctx context.Context) (appdash.SpanID, bool) {
+	span, ok := ctx.Value(contextKey{}).(appdash.SpanID)
+	return span, ok
+}
+
+// newOutgoingContext returns a copy of ctx whose outgoing metadata carries
+// the given span ID, preserving any metadata already present.
+func newOutgoingContext(ctx context.Context, span appdash.SpanID) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	SetSpanIDMetadata(md, span)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// spanIDFromIncomingContext returns the span ID of the call whose incoming
+// metadata is stored in ctx, as GetSpanID does.
+func spanIDFromIncomingContext(ctx context.Context) (*appdash.SpanID, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return GetSpanID(md)
+}
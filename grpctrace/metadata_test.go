@@ -0,0 +1,95 @@
+//go:build grpc
+// +build grpc
+
+package grpctrace
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestSetSpanIDMetadata(t *testing.T) {
+	md := metadata.MD{}
+	SetSpanIDMetadata(md, appdash.SpanID{Trace: 100, Span: 150, Parent: 200})
+	if got, want := md[MetadataSpanID], []string{"0000000000000064/0000000000000096/00000000000000c8"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("got %s metadata %q, want %q", MetadataSpanID, got, want)
+	}
+}
+
+func TestGetSpanID(t *testing.T) {
+	want := appdash.SpanID{Trace: 100, Span: 150, Parent: 200}
+	md := metadata.MD{}
+	SetSpanIDMetadata(md, want)
+
+	spanID, err := GetSpanID(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *spanID != want {
+		t.Errorf("got %+v, want %+v", *spanID, want)
+	}
+}
+
+func TestGetSpanID_parent(t *testing.T) {
+	md := metadata.Pairs(MetadataParentSpanID, "0000000000000064/0000000000000096")
+
+	spanID, err := GetSpanID(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spanID.Trace != 100 || spanID.Parent != 150 {
+		t.Errorf("got %+v, want a child of 100/150", *spanID)
+	}
+}
+
+func TestGetSpanID_root(t *testing.T) {
+	spanID, err := GetSpanID(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !spanID.IsRoot() {
+		t.Errorf("got %+v, want a new root span", *spanID)
+	}
+}
+
+func TestGetSpanID_malformed(t *testing.T) {
+	if _, err := GetSpanID(metadata.Pairs(MetadataSpanID, "foo")); err != appdash.ErrBadSpanID {
+		t.Errorf("got error %v, want %v", err, appdash.ErrBadSpanID)
+	}
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	want := appdash.SpanID{Trace: 1, Span: 2, Parent: 3}
+
+	// Inject into the client's outgoing metadata, keeping any existing keys.
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("k", "v"))
+	ctx = newOutgoingContext(ctx, want)
+	out, _ := metadata.FromOutgoingContext(ctx)
+	if got := out["k"]; len(got) != 1 || got[0] != "v" {
+		t.Errorf("got existing metadata %q, want %q", got, "v")
+	}
+
+	// Extract from the server's incoming metadata.
+	spanID, err := spanIDFromIncomingContext(metadata.NewIncomingContext(context.Background(), out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *spanID != want {
+		t.Errorf("got %+v, want %+v", *spanID, want)
+	}
+}
+
+func TestContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("got span from empty context")
+	}
+	want := appdash.SpanID{Trace: 1, Span: 2}
+	span, ok := FromContext(NewContext(context.Background(), want))
+	if !ok || span != want {
+		t.Errorf("got %+v (ok=%v), want %+v", span, ok, want)
+	}
+}
@@ -0,0 +1,74 @@
+// Package chitrace adapts the httptrace middleware to chi routers, so that
+// spans are named after the matched route pattern rather than the request
+// path. It is a separate package to keep the router out of httptrace's
+// dependencies.
+//
+// Because the middleware usually wraps the router, chi has not routed the
+// request yet when the middleware runs, so its route context is still
+// empty. The functions in this package therefore match the request against
+// the router themselves:
+//
+//  router := chi.NewRouter()
+//  router.Get("/users/{id}", userHandler)
+//
+//  tracemw := httptrace.Middleware(collector, &httptrace.MiddlewareConfig{
+//      RouteName:   chitrace.RouteName(router),
+//      RouteParams: chitrace.RouteParams(router),
+//  })
+package chitrace
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+)
+
+// match matches the request against router, returning the route context
+// that chi would route it with, or nil if no route matches.
+func match(router chi.Routes, r *http.Request) *chi.Context {
+	rctx := chi.NewRouteContext()
+	if !router.Match(rctx, r.Method, r.URL.Path) {
+		return nil
+	}
+	return rctx
+}
+
+// RouteName returns a function, for use as
+// httptrace.MiddlewareConfig.RouteName, that returns the pattern (such as
+// "/users/{id}") of the route of router that matches the request. If no
+// route matches, "" is returned.
+func RouteName(router chi.Routes) func(*http.Request) string {
+	return func(r *http.Request) string {
+		rctx := match(router, r)
+		if rctx == nil {
+			return ""
+		}
+		return rctx.RoutePattern()
+	}
+}
+
+// RouteParams returns a function, for use as
+// httptrace.MiddlewareConfig.RouteParams, that returns the URL parameters
+// captured by the route of router that matches the request. If no route
+// matches, nil is returned.
+//
+// The "*" parameter that chi captures for each mounted subrouter (such as
+// one added with Route) is omitted; only a wildcard at the end of the
+// matched route is returned.
+func RouteParams(router chi.Routes) func(*http.Request) map[string]string {
+	return func(r *http.Request) map[string]string {
+		rctx := match(router, r)
+		if rctx == nil {
+			return nil
+		}
+		keys := rctx.URLParams.Keys
+		params := make(map[string]string, len(keys))
+		for i, key := range keys {
+			if key == "*" && i < len(keys)-1 {
+				continue
+			}
+			params[key] = rctx.URLParams.Values[i]
+		}
+		return params
+	}
+}
@@ -0,0 +1,80 @@
+package chitrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi"
+
+	"sourcegraph.com/sourcegraph/appdash"
+	"sourcegraph.com/sourcegraph/appdash/httptrace"
+)
+
+func TestRouteName(t *testing.T) {
+	nop := func(http.ResponseWriter, *http.Request) {}
+	router := chi.NewRouter()
+	router.Get("/users/{id}", nop)
+	router.Route("/repos/{owner}", func(r chi.Router) {
+		r.Get("/{name}", nop)
+	})
+	router.Get("/items/{n:[0-9]+}", nop)
+	router.Get("/static", nop)
+	router.Get("/files/*", nop)
+
+	routeName, routeParams := RouteName(router), RouteParams(router)
+	tests := []struct {
+		method     string
+		path       string
+		wantName   string
+		wantParams map[string]string
+	}{
+		{"GET", "/users/42", "/users/{id}", map[string]string{"id": "42"}},
+		{"GET", "/repos/a/b", "/repos/{owner}/{name}", map[string]string{"owner": "a", "name": "b"}},
+		{"GET", "/items/7", "/items/{n:[0-9]+}", map[string]string{"n": "7"}},
+		{"GET", "/static", "/static", map[string]string{}},
+		{"GET", "/files/a/b", "/files/*", map[string]string{"*": "a/b"}},
+		{"GET", "/items/x", "", nil},
+		{"GET", "/nowhere", "", nil},
+		{"POST", "/static", "", nil},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, "http://example.com"+test.path, nil)
+		if got := routeName(req); got != test.wantName {
+			t.Errorf("%s %s: got route name %q, want %q", test.method, test.path, got, test.wantName)
+		}
+		if got := routeParams(req); !reflect.DeepEqual(got, test.wantParams) {
+			t.Errorf("%s %s: got route params %v, want %v", test.method, test.path, got, test.wantParams)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	router := chi.NewRouter()
+	router.Get("/users/{id}", func(http.ResponseWriter, *http.Request) {})
+
+	ms := appdash.NewMemoryStore()
+	mw := httptrace.Middleware(appdash.NewLocalCollector(ms), &httptrace.MiddlewareConfig{
+		RouteName:   RouteName(router),
+		RouteParams: RouteParams(router),
+	})
+	req, _ := http.NewRequest("GET", "http://example.com/users/42", nil)
+	httptrace.SetSpanIDHeader(req.Header, appdash.SpanID{Trace: 1, Span: 1})
+	mw(httptest.NewRecorder(), req, router.ServeHTTP)
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := trace.Span.Name(), "Serve /users/{id}"; got != want {
+		t.Errorf("got span name %q, want %q", got, want)
+	}
+	var e httptrace.ServerEvent
+	if err := appdash.UnmarshalEvent(trace.Span.Annotations, &e); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"id": "42"}; !reflect.DeepEqual(e.Request.RouteParams, want) {
+		t.Errorf("got route params %v, want %v", e.Request.RouteParams, want)
+	}
+}
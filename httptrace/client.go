@@ -1,6 +1,8 @@
 package httptrace
 
 import (
+	"context"
+	"mime"
 	"net/http"
 	"strings"
 	"sync"
@@ -13,9 +15,24 @@ var (
 	// RedactedHeaders is a slice of header names whose values should be
 	// entirely redacted from logs.
 	RedactedHeaders = []string{"Authorization"}
+
+	// StripContentTypeParams is whether the ContentType of recorded
+	// requests and responses omits the parameters of the Content-Type
+	// header (e.g. "; charset=utf-8"), so that it only names the media
+	// type and is easy to filter by.
+	StripContentTypeParams = true
+
+	// RedactedRouteParams is a slice of route parameter names (see
+	// MiddlewareConfig.RouteParams) whose values should be entirely
+	// redacted from logs. Names are matched case-insensitively.
+	RedactedRouteParams []string
 )
 
-func init() { appdash.RegisterEvent(ClientEvent{}) }
+func init() {
+	appdash.RegisterEvent(ClientEvent{})
+	appdash.RegisterEvent(RedirectEvent{})
+	registerStatusClass(ClientEvent{}.Schema(), "Client.Response")
+}
 
 // NewClientEvent returns an event which records various aspects of an
 // HTTP request.  The returned value is incomplete, and should have
@@ -34,6 +51,26 @@ type RequestInfo struct {
 	Host          string
 	RemoteAddr    string
 	ContentLength int64
+
+	// ContentType is the request's normalized Content-Type header (see
+	// StripContentTypeParams).
+	ContentType string
+
+	// ClientIP is the IP address of the client that made the request, as
+	// reported by trusted proxies in front of the server. It is only set by
+	// Middleware, when MiddlewareConfig.TrustedProxies is non-empty.
+	ClientIP string
+
+	// HeaderOrder lists the names of the request's headers, separated by
+	// ", ", in the order they were received, including repeated headers
+	// once per line. It is only set by Middleware, for servers set up
+	// with CaptureHeaderOrder.
+	HeaderOrder string
+
+	// RouteParams holds the parameters captured by the server's router
+	// (e.g. "id" for a route "/users/:id"). It is only set by Middleware,
+	// when MiddlewareConfig.RouteParams is non-nil.
+	RouteParams map[string]string `trace:"RouteParam"`
 }
 
 func requestInfo(r *http.Request) RequestInfo {
@@ -45,7 +82,23 @@ func requestInfo(r *http.Request) RequestInfo {
 		Host:          r.Host,
 		RemoteAddr:    r.RemoteAddr,
 		ContentLength: r.ContentLength,
+		ContentType:   contentType(r.Header),
+	}
+}
+
+// contentType returns the Content-Type in h, with its media type in lower
+// case and, if StripContentTypeParams is true, without its parameters. A
+// malformed header is returned as is.
+func contentType(h http.Header) string {
+	v := strings.TrimSpace(h.Get("Content-Type"))
+	mediaType, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		return v
+	}
+	if StripContentTypeParams || len(params) == 0 {
+		return mediaType
 	}
+	return mime.FormatMediaType(mediaType, params)
 }
 
 // ClientEvent records an HTTP client request event.
@@ -74,6 +127,39 @@ func (e ClientEvent) Start() time.Time { return e.ClientSend }
 // End implements the appdash TimespanEvent interface.
 func (e ClientEvent) End() time.Time { return e.ClientRecv }
 
+// RedirectEvent records that a client request is a hop of a redirect
+// chain, made because the previous request's response redirected to it.
+// See Transport.MaxRecordedRedirects.
+type RedirectEvent struct {
+	Hop        int    `trace:"Redirect.Hop"`        // 1 for the first redirect, and so on
+	From       string `trace:"Redirect.From"`       // the URL of the previous request
+	StatusCode int    `trace:"Redirect.StatusCode"` // the status of the previous response
+	Location   string `trace:"Redirect.Location"`   // the Location header of the previous response
+}
+
+// Schema returns the constant "HTTPRedirect".
+func (RedirectEvent) Schema() string { return "HTTPRedirect" }
+
+// Important implements the appdash ImportantEvent.
+func (RedirectEvent) Important() []string { return []string{"Redirect.Hop"} }
+
+// redirectOf returns the event describing the redirect that req was made
+// for, if any, from the redirect responses that http.Client links it to.
+func redirectOf(req *http.Request) (RedirectEvent, bool) {
+	prev := req.Response
+	if prev == nil {
+		return RedirectEvent{}, false
+	}
+	e := RedirectEvent{StatusCode: prev.StatusCode, Location: prev.Header.Get("Location")}
+	if prev.Request != nil {
+		e.From = prev.Request.URL.String()
+	}
+	for r := req; r != nil && r.Response != nil; r = r.Response.Request {
+		e.Hop++
+	}
+	return e, true
+}
+
 var (
 	redacted = []string{"REDACTED"}
 )
@@ -102,7 +188,25 @@ func redactHeaders(header, trailer http.Header) map[string]string {
 }
 
 func isRedacted(name string) bool {
-	for _, v := range RedactedHeaders {
+	return containsFold(RedactedHeaders, name)
+}
+
+// redactRouteParams returns a copy of params with the values of
+// RedactedRouteParams redacted.
+func redactRouteParams(params map[string]string) map[string]string {
+	m := make(map[string]string, len(params))
+	for k, v := range params {
+		if containsFold(RedactedRouteParams, k) {
+			v = redacted[0]
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// containsFold reports whether names contains name, ignoring case.
+func containsFold(names []string, name string) bool {
+	for _, v := range names {
 		if strings.EqualFold(name, v) {
 			return true
 		}
@@ -112,7 +216,15 @@ func isRedacted(name string) bool {
 
 // Transport is an HTTP transport that adds appdash span ID headers
 // to requests so that downstream operations are associated with the
-// same trace.
+// same trace. Spans of requests that fail or receive a 5xx status have
+// their sampling priority raised to 1 (see appdash.SamplingPriorityKey).
+//
+// If a request's context carries a sampling decision (see
+// NewSampledContext), as that of a request handled by Middleware does, the
+// decision is propagated in the sampled header, and the request is not
+// recorded if it is not sampled. Likewise, a detail level in the context
+// (see NewDetailContext) is propagated in the detail header, and the
+// request is recorded at that level.
 type Transport struct {
 	// Recorder is the current span's recorder. A new child Recorder
 	// (with a new child SpanID) is created for each HTTP roundtrip.
@@ -124,6 +236,38 @@ type Transport struct {
 
 	SetName bool
 
+	// ClientTrace, if true, records the connection lifecycle of each
+	// request as a ClientTraceEvent on its span (see WithClientTrace).
+	// Requests whose context already came from WithClientTrace are not
+	// traced again.
+	ClientTrace bool
+
+	// MaxRetries is the maximum number of times a failed request is
+	// retried. Each attempt is recorded on its own span, and if a request
+	// is retried its attempts are linked with Attempt events (see
+	// appdash.Recorder.Attempt). Requests with a body are only retried if
+	// their GetBody field is set.
+	MaxRetries int
+
+	// RetryIf, if non-nil, reports whether an attempt that returned the
+	// given response or error should be retried. By default, requests with
+	// idempotent methods are retried if they fail or receive a 502, 503 or
+	// 504 status.
+	RetryIf func(*http.Request, *http.Response, error) bool
+
+	// RetryWait is how long to wait before each retry.
+	RetryWait time.Duration
+
+	// MaxRecordedRedirects is the maximum number of redirect hops of a
+	// request that are recorded. When an http.Client follows redirects,
+	// each hop is made with its own call to RoundTrip and is recorded on
+	// its own span, with a RedirectEvent naming the hop and the response
+	// that caused it. Hops beyond the maximum are still made, and carry the
+	// span ID header, but are not recorded. If zero, 10 hops (the limit of
+	// http.Client's default redirect policy) are recorded; if negative, no
+	// hops are.
+	MaxRecordedRedirects int
+
 	// requests keeps clone request
 	reqMu    sync.Mutex
 	requests map[*http.Request]*http.Request
@@ -131,41 +275,156 @@ type Transport struct {
 
 // RoundTrip implements the RoundTripper interface.
 func (t *Transport) RoundTrip(original *http.Request) (*http.Response, error) {
+	redirect, isRedirect := redirectOf(original)
+	if isRedirect && redirect.Hop > t.maxRecordedRedirects() {
+		req := cloneRequest(original)
+		SetSpanIDHeader(req.Header, appdash.NewSpanID(t.Recorder.SpanID))
+		return t.getTransport().RoundTrip(req)
+	}
+
+	var first appdash.SpanID
+	for attempt := 1; ; attempt++ {
+		req := original
+		if attempt > 1 && original.GetBody != nil {
+			body, err := original.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = cloneRequest(original)
+			req.Body = body
+		}
+
+		resp, child, err := t.roundTrip(original, req)
+		retry := attempt <= t.MaxRetries && t.shouldRetry(req, resp, err)
+		if attempt == 1 {
+			first = child.SpanID
+		}
+		if attempt > 1 || retry {
+			child.Attempt(first, attempt, !retry)
+		}
+		if isRedirect {
+			child.Event(redirect)
+		}
+		child.Finish()
+		if !retry {
+			return resp, err
+		}
+
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+		if t.RetryWait > 0 {
+			select {
+			case <-time.After(t.RetryWait):
+			case <-original.Context().Done():
+				return nil, original.Context().Err()
+			}
+		}
+	}
+}
+
+func (t *Transport) maxRecordedRedirects() int {
+	if t.MaxRecordedRedirects == 0 {
+		return 10
+	}
+	return t.MaxRecordedRedirects
+}
+
+// shouldRetry reports whether an attempt of req that returned resp or err
+// should be retried.
+func (t *Transport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false // the body cannot be sent again
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+	if t.RetryIf != nil {
+		return t.RetryIf(req, resp, err)
+	}
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+	default:
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// roundTrip makes a single attempt at the request req, which is original
+// or a copy of it, and returns its result along with the attempt's span
+// recorder, which the caller must finish.
+func (t *Transport) roundTrip(original, req *http.Request) (*http.Response, *appdash.Recorder, error) {
 	// To set extra querystring params, we must make a copy of the Request so
 	// that we don't modify the Request we were given. This is required by the
 	// specification of http.RoundTripper.
-	req := cloneRequest(original)
-	t.setCloneRequest(original, req)
-	defer t.setCloneRequest(original, nil)
+	req = cloneRequest(req)
 
 	child := t.Recorder.Child()
 	if t.SetName {
 		child.Name("Request " + req.URL.Host)
 	}
 
+	var clientTraceDone func()
+	if t.ClientTrace && req.Context().Value(clientTraceKey{}) == nil {
+		var ctx context.Context
+		ctx, clientTraceDone = WithClientTrace(req.Context(), child)
+		req = req.WithContext(ctx)
+	}
+
+	t.setCloneRequest(original, req)
+	defer t.setCloneRequest(original, nil)
+
 	// New child span is created and set as HTTP header instead of using `child`
 	// in order to have a single span recording operation per httptrace event
 	// (HTTPClient or HTTPServer).
 	span := appdash.NewSpanID(t.Recorder.SpanID)
 
 	SetSpanIDHeader(req.Header, span)
+	if sampled, ok := SampledFromContext(req.Context()); ok {
+		SetSampledHeader(req.Header, sampled)
+		if !sampled {
+			child.Sampler = appdash.NeverSample
+		}
+	}
+	detail, hasDetail := DetailFromContext(req.Context())
+	if hasDetail {
+		SetDetailHeader(req.Header, detail)
+	}
 
+	// The request is timestamped using the Recorder's clock, if any.
+	clock := t.Recorder.Clock
+	if clock == nil {
+		clock = appdash.RealClock
+	}
 	e := NewClientEvent(req)
-	e.ClientSend = time.Now()
+	e.ClientSend = clock.Now()
 
 	// Make the HTTP request.
 	transport := t.getTransport()
 	resp, err := transport.RoundTrip(req)
 
-	e.ClientRecv = time.Now()
+	e.ClientRecv = clock.Now()
 	if err == nil {
 		e.Response = responseInfo(resp)
 	} else {
 		e.Response.StatusCode = -1
 	}
+	applyDetail(detail, &e.Request, &e.Response)
 	child.Event(e)
-	child.Finish()
-	return resp, err
+	if clientTraceDone != nil {
+		clientTraceDone()
+	}
+	if err != nil || e.Response.StatusCode >= 500 {
+		child.SamplingPriority(1)
+	}
+	return resp, child, err
 }
 
 // cloneRequest returns a clone of the provided *http.Request. The clone is a
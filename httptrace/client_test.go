@@ -1,14 +1,22 @@
 package httptrace
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"sourcegraph.com/sourcegraph/appdash"
+	"sourcegraph.com/sourcegraph/appdash/appdashtest"
 )
 
 var _ appdash.Event = ClientEvent{}
@@ -51,7 +59,12 @@ func TestNewClientEvent(t *testing.T) {
 		"Client.Request.Method":                "GET",
 		"Client.Request.URI":                   "/foo",
 		"Client.Response.StatusCode":           "200",
+		"Client.Response.StatusClass":          "2xx",
 		"Client.Response.ContentLength":        "0",
+		"Client.Request.ContentType":           "",
+		"Client.Request.ClientIP":              "",
+		"Client.Request.HeaderOrder":           "",
+		"Client.Response.ContentType":          "",
 		"Client.Send":                          "0001-01-01T00:00:00Z",
 		"Client.Recv":                          "0001-01-01T00:00:00Z",
 	}
@@ -63,6 +76,7 @@ func TestNewClientEvent(t *testing.T) {
 func TestTransport(t *testing.T) {
 	ms := appdash.NewMemoryStore()
 	rec := appdash.NewRecorder(appdash.SpanID{1, 2, 3}, appdash.NewLocalCollector(ms))
+	rec.Clock = newTestClock()
 
 	req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
 	req.Header.Set("X-Req-Header", "a")
@@ -114,12 +128,174 @@ func TestTransport(t *testing.T) {
 			ContentLength: 123,
 			Headers:       map[string]string{"X-Resp-Header": "b"},
 		},
+		ClientSend: testTime.Add(1 * time.Second),
+		ClientRecv: testTime.Add(2 * time.Second),
 	}
-	delete(e.Request.Headers, "Span-Id")
-	e.ClientSend = time.Time{}
-	e.ClientRecv = time.Time{}
-	if !reflect.DeepEqual(e, wantEvent) {
-		t.Errorf("got ClientEvent %+v, want %+v", e, wantEvent)
+	got, want := mustMarshalEvent(t, e), mustMarshalEvent(t, wantEvent)
+	if !appdashtest.AnnotationsEqual(got, want, "Client.Request.Headers.Span-Id") {
+		t.Errorf("got ClientEvent %v, want %v", got, want)
+	}
+}
+
+func TestTransport_retries(t *testing.T) {
+	tests := []struct {
+		method       string
+		body         io.Reader
+		statuses     []int // of each attempt; 0 for an error
+		wantAttempts int
+		wantStatus   int
+	}{
+		{"GET", nil, []int{503, 0, 200}, 3, 200},
+		{"GET", nil, []int{200}, 1, 200},
+		{"GET", nil, []int{502, 503, 504, 200}, 3, 504},
+		{"GET", nil, []int{500}, 1, 500},
+		{"POST", nil, []int{503, 200}, 1, 503},
+		{"PUT", strings.NewReader("x"), []int{503, 200}, 2, 200},
+	}
+	for _, test := range tests {
+		ms := appdash.NewMemoryStore()
+		rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, appdash.NewLocalCollector(ms))
+		var bodies []string
+		attempts := 0
+		transport := &Transport{
+			Recorder:   rec,
+			MaxRetries: 2,
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Body != nil {
+					b, _ := ioutil.ReadAll(req.Body)
+					bodies = append(bodies, string(b))
+				}
+				status := test.statuses[attempts]
+				attempts++
+				if status == 0 {
+					return nil, errors.New("connection refused")
+				}
+				return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+			}),
+		}
+		label := fmt.Sprintf("%s %v", test.method, test.statuses)
+
+		req, _ := http.NewRequest(test.method, "http://example.com/foo", test.body)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Errorf("%s: %s", label, err)
+			continue
+		}
+		if resp.StatusCode != test.wantStatus {
+			t.Errorf("%s: got status %d, want %d", label, resp.StatusCode, test.wantStatus)
+		}
+		if attempts != test.wantAttempts {
+			t.Errorf("%s: got %d attempts, want %d", label, attempts, test.wantAttempts)
+		}
+		for i, b := range bodies {
+			if b != "x" {
+				t.Errorf("%s: got body %q on attempt %d, want %q", label, b, i+1, "x")
+			}
+		}
+
+		// Each attempt has its own span, numbered and linked to the first.
+		trace, err := ms.Trace(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []appdash.Attempt
+		var first appdash.SpanID
+		trace.Walk(func(span *appdash.Span, depth int) error {
+			a, ok := span.Attempt()
+			if !ok {
+				return nil
+			}
+			if a.N == 1 {
+				first = span.ID
+			}
+			got = append(got, a)
+			return nil
+		})
+		var want []appdash.Attempt
+		if test.wantAttempts > 1 {
+			for n := 1; n <= test.wantAttempts; n++ {
+				a := appdash.Attempt{N: n, Final: n == test.wantAttempts}
+				if n > 1 {
+					a.RetryOf = first
+				}
+				want = append(want, a)
+			}
+		}
+		sort.Slice(got, func(i, j int) bool { return got[i].N < got[j].N })
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got attempts %+v, want %+v", label, got, want)
+		}
+	}
+}
+
+func TestTransport_redirects(t *testing.T) {
+	var served []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		served = append(served, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusMovedPermanently)
+		case "/c":
+			http.Redirect(w, r, "/d", http.StatusTemporaryRedirect)
+		}
+	}))
+	defer srv.Close()
+
+	for _, max := range []int{0, 2} {
+		served = nil
+		ms := appdash.NewMemoryStore()
+		rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, appdash.NewLocalCollector(ms))
+		rec.Finish()
+		client := &http.Client{Transport: &Transport{Recorder: rec, MaxRecordedRedirects: max}}
+		resp, err := client.Get(srv.URL + "/a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if want := []string{"/a", "/b", "/c", "/d"}; !reflect.DeepEqual(served, want) {
+			t.Errorf("max %d: served %v, want %v", max, served, want)
+		}
+
+		trace, err := ms.Trace(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var uris []string
+		var redirects []RedirectEvent
+		for _, sub := range trace.Sub {
+			var e ClientEvent
+			if err := appdash.UnmarshalEvent(sub.Annotations, &e); err != nil {
+				t.Fatal(err)
+			}
+			uris = append(uris, e.Request.URI)
+			var r RedirectEvent
+			if err := appdash.UnmarshalEvent(sub.Annotations, &r); err == nil && r.Hop > 0 {
+				redirects = append(redirects, r)
+			}
+		}
+		sort.Strings(uris)
+		sort.Slice(redirects, func(i, j int) bool { return redirects[i].Hop < redirects[j].Hop })
+
+		want := []RedirectEvent{
+			{Hop: 1, From: srv.URL + "/a", StatusCode: http.StatusFound, Location: "/b"},
+			{Hop: 2, From: srv.URL + "/b", StatusCode: http.StatusMovedPermanently, Location: "/c"},
+			{Hop: 3, From: srv.URL + "/c", StatusCode: http.StatusTemporaryRedirect, Location: "/d"},
+		}
+		wantURIs := []string{"/a", "/b", "/c", "/d"}
+		if max == 2 {
+			want, wantURIs = want[:2], wantURIs[:3]
+		}
+		if !reflect.DeepEqual(uris, wantURIs) {
+			t.Errorf("max %d: got spans of requests %v, want %v", max, uris, wantURIs)
+		}
+		if !reflect.DeepEqual(redirects, want) {
+			t.Errorf("max %d: got redirects %+v, want %+v", max, redirects, want)
+		}
 	}
 }
 
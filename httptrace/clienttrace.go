@@ -0,0 +1,170 @@
+package httptrace
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func init() {
+	appdash.RegisterEvent(ClientTraceEvent{})
+	for _, key := range clientTraceTimeKeys {
+		appdash.RegisterAnnotationType(key, appdash.TimeAnnotation)
+	}
+	appdash.RegisterEventSchema(ClientTraceEvent{}.Schema(), appdash.EventSchema{
+		Optional: append([]string{
+			"ClientTrace.ConnReused",
+			"ClientTrace.ConnWasIdle",
+			"ClientTrace.RemoteAddr",
+		}, clientTraceTimeKeys...),
+	})
+}
+
+// ClientTraceEvent records the connection lifecycle of an outbound HTTP
+// request, as reported by the standard library's net/http/httptrace
+// package, to break down where the request's time went. Phases that did not
+// happen (e.g. DNS resolution on a reused connection) have zero times and
+// are omitted from the annotations.
+type ClientTraceEvent struct {
+	DNSStart             time.Time `trace:"ClientTrace.DNSStart"`
+	DNSDone              time.Time `trace:"ClientTrace.DNSDone"`
+	ConnectStart         time.Time `trace:"ClientTrace.ConnectStart"`
+	ConnectDone          time.Time `trace:"ClientTrace.ConnectDone"`
+	TLSHandshakeStart    time.Time `trace:"ClientTrace.TLSHandshakeStart"`
+	TLSHandshakeDone     time.Time `trace:"ClientTrace.TLSHandshakeDone"`
+	GotConn              time.Time `trace:"ClientTrace.GotConn"`
+	WroteRequest         time.Time `trace:"ClientTrace.WroteRequest"`
+	GotFirstResponseByte time.Time `trace:"ClientTrace.GotFirstResponseByte"`
+
+	ConnReused  bool   `trace:"ClientTrace.ConnReused"`
+	ConnWasIdle bool   `trace:"ClientTrace.ConnWasIdle"`
+	RemoteAddr  string `trace:"ClientTrace.RemoteAddr"`
+}
+
+// Schema returns the constant "HTTPClientTrace".
+func (ClientTraceEvent) Schema() string { return "HTTPClientTrace" }
+
+var clientTraceTimeKeys = []string{
+	"ClientTrace.DNSStart",
+	"ClientTrace.DNSDone",
+	"ClientTrace.ConnectStart",
+	"ClientTrace.ConnectDone",
+	"ClientTrace.TLSHandshakeStart",
+	"ClientTrace.TLSHandshakeDone",
+	"ClientTrace.GotConn",
+	"ClientTrace.WroteRequest",
+	"ClientTrace.GotFirstResponseByte",
+}
+
+// MarshalEvent implements the appdash.EventMarshaler interface, omitting
+// phases that did not happen.
+func (e ClientTraceEvent) MarshalEvent() (appdash.Annotations, error) {
+	times := []time.Time{
+		e.DNSStart, e.DNSDone,
+		e.ConnectStart, e.ConnectDone,
+		e.TLSHandshakeStart, e.TLSHandshakeDone,
+		e.GotConn, e.WroteRequest, e.GotFirstResponseByte,
+	}
+	var as appdash.Annotations
+	for i, t := range times {
+		if !t.IsZero() {
+			as = append(as, appdash.Annotation{Key: clientTraceTimeKeys[i], Value: []byte(t.Format(time.RFC3339Nano))})
+		}
+	}
+	if !e.GotConn.IsZero() {
+		as = append(as,
+			appdash.Annotation{Key: "ClientTrace.ConnReused", Value: []byte(strconv.FormatBool(e.ConnReused))},
+			appdash.Annotation{Key: "ClientTrace.ConnWasIdle", Value: []byte(strconv.FormatBool(e.ConnWasIdle))},
+			appdash.Annotation{Key: "ClientTrace.RemoteAddr", Value: []byte(e.RemoteAddr)},
+		)
+	}
+	return as, nil
+}
+
+// clientTraceKey is the context key marking a context whose requests are
+// already traced by WithClientTrace.
+type clientTraceKey struct{}
+
+// WithClientTrace returns a copy of ctx carrying a net/http/httptrace
+// ClientTrace that times the connection lifecycle (DNS, connect, TLS
+// handshake, getting a connection, writing the request and reading the
+// first response byte) of requests made with it. Call done once the request
+// has completed to record the timings on rec as a ClientTraceEvent; done
+// must be called before rec.Finish.
+//
+// A Transport with ClientTrace set does not trace requests whose context
+// came from WithClientTrace, so the timings are never recorded twice.
+func WithClientTrace(ctx context.Context, rec *appdash.Recorder) (newCtx context.Context, done func()) {
+	clock := rec.Clock
+	if clock == nil {
+		clock = appdash.RealClock
+	}
+	ct := &clientTrace{clock: clock}
+	ctx = context.WithValue(ctx, clientTraceKey{}, true)
+	return httptrace.WithClientTrace(ctx, ct.hooks()), func() {
+		rec.Event(ct.event())
+	}
+}
+
+// clientTrace accumulates a ClientTraceEvent from the hooks of a
+// net/http/httptrace ClientTrace, which may be called concurrently.
+type clientTrace struct {
+	clock appdash.Clock
+
+	mu sync.Mutex
+	e  ClientTraceEvent
+}
+
+// set sets *t to the current time, if it is not already set (a request
+// may e.g. try several addresses when connecting).
+func (ct *clientTrace) set(t *time.Time) {
+	now := ct.clock.Now()
+	ct.mu.Lock()
+	if t.IsZero() {
+		*t = now
+	}
+	ct.mu.Unlock()
+}
+
+// setLast sets *t to the current time, overwriting any earlier time.
+func (ct *clientTrace) setLast(t *time.Time) {
+	now := ct.clock.Now()
+	ct.mu.Lock()
+	*t = now
+	ct.mu.Unlock()
+}
+
+func (ct *clientTrace) event() ClientTraceEvent {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.e
+}
+
+func (ct *clientTrace) hooks() *httptrace.ClientTrace {
+	e := &ct.e
+	return &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { ct.set(&e.DNSStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { ct.setLast(&e.DNSDone) },
+		ConnectStart:      func(network, addr string) { ct.set(&e.ConnectStart) },
+		ConnectDone:       func(network, addr string, err error) { ct.setLast(&e.ConnectDone) },
+		TLSHandshakeStart: func() { ct.set(&e.TLSHandshakeStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { ct.setLast(&e.TLSHandshakeDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			ct.set(&e.GotConn)
+			ct.mu.Lock()
+			e.ConnReused = info.Reused
+			e.ConnWasIdle = info.WasIdle
+			if info.Conn != nil {
+				e.RemoteAddr = info.Conn.RemoteAddr().String()
+			}
+			ct.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { ct.set(&e.WroteRequest) },
+		GotFirstResponseByte: func() { ct.set(&e.GotFirstResponseByte) },
+	}
+}
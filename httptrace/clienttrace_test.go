@@ -0,0 +1,106 @@
+package httptrace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestWithClientTrace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	ms := appdash.NewMemoryStore()
+	rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, appdash.NewLocalCollector(ms))
+
+	ctx, done := WithClientTrace(context.Background(), rec)
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := http.DefaultTransport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	done()
+	rec.Finish()
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anns := trace.Span.Annotations.StringMap()
+	for _, key := range []string{
+		"ClientTrace.ConnectStart",
+		"ClientTrace.ConnectDone",
+		"ClientTrace.GotConn",
+		"ClientTrace.WroteRequest",
+		"ClientTrace.GotFirstResponseByte",
+		"ClientTrace.RemoteAddr",
+		"_schema:HTTPClientTrace",
+	} {
+		if _, ok := anns[key]; !ok {
+			t.Errorf("missing annotation %q", key)
+		}
+	}
+	if _, ok := anns["ClientTrace.TLSHandshakeStart"]; ok {
+		t.Error("got TLS handshake annotation for a plain HTTP request")
+	}
+
+	var e ClientTraceEvent
+	if err := appdash.UnmarshalEvent(trace.Span.Annotations, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.GotFirstResponseByte.Before(e.WroteRequest) || e.WroteRequest.Before(e.GotConn) {
+		t.Errorf("got out of order times %+v", e)
+	}
+}
+
+func TestTransport_clientTrace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	ms := appdash.NewMemoryStore()
+	rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, appdash.NewLocalCollector(ms))
+	transport := &Transport{Recorder: rec, ClientTrace: true}
+
+	countClientTraces := func() (n int) {
+		trace, err := ms.Trace(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trace.Walk(func(span *appdash.Span, depth int) error {
+			for _, a := range span.Annotations {
+				if a.Key == "_schema:HTTPClientTrace" {
+					n++
+				}
+			}
+			return nil
+		})
+		return n
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if n := countClientTraces(); n != 1 {
+		t.Errorf("got %d client trace events, want 1", n)
+	}
+
+	// A request already traced with WithClientTrace is not traced again.
+	ctx, done := WithClientTrace(context.Background(), rec)
+	resp, err = transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	done()
+	rec.Finish()
+	if n := countClientTraces(); n != 2 {
+		t.Errorf("got %d client trace events, want 2", n)
+	}
+}
@@ -0,0 +1,78 @@
+package httptrace
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// A DetailLevel is how much of the requests and responses of a trace
+// Middleware and Transport record. Recording some traces with
+// MinimalDetail and only a few with FullDetail keeps many traces cheap to
+// store while still capturing the full picture of some.
+type DetailLevel int
+
+const (
+	// FullDetail records everything, including the request and response
+	// headers. It is the default.
+	FullDetail DetailLevel = iota
+
+	// MinimalDetail records the method, URI, route, status code and
+	// timing of requests, but not their request and response headers
+	// (nor the order of the request headers).
+	MinimalDetail
+)
+
+// String returns "full" or "minimal", as propagated in the detail header.
+func (l DetailLevel) String() string {
+	if l == MinimalDetail {
+		return "minimal"
+	}
+	return "full"
+}
+
+// SetDetailHeader sets the detail header (named by DetailHeaderName) to
+// the given detail level.
+func SetDetailHeader(h http.Header, l DetailLevel) {
+	h.Set(DetailHeaderName, l.String())
+}
+
+// GetDetail returns the detail level in the detail header (named by
+// DetailHeaderName), or ok == false if the header is absent or malformed.
+func GetDetail(h http.Header) (l DetailLevel, ok bool) {
+	switch strings.ToLower(h.Get(DetailHeaderName)) {
+	case "full":
+		return FullDetail, true
+	case "minimal":
+		return MinimalDetail, true
+	}
+	return FullDetail, false
+}
+
+// detailKey is the type of the context key under which a detail level is
+// stored.
+type detailKey struct{}
+
+// NewDetailContext returns a copy of ctx that carries the given detail
+// level. Middleware stores the level of each request in its context this
+// way, and Transport propagates the level of a request's context to the
+// server and records the request at that level.
+func NewDetailContext(ctx context.Context, l DetailLevel) context.Context {
+	return context.WithValue(ctx, detailKey{}, l)
+}
+
+// DetailFromContext returns the detail level stored in ctx by
+// NewDetailContext, if any.
+func DetailFromContext(ctx context.Context) (l DetailLevel, ok bool) {
+	l, ok = ctx.Value(detailKey{}).(DetailLevel)
+	return l, ok
+}
+
+// applyDetail removes the parts of a request and response that are not
+// recorded at detail level l.
+func applyDetail(l DetailLevel, req *RequestInfo, resp *ResponseInfo) {
+	if l == MinimalDetail {
+		req.Headers, req.HeaderOrder = nil, ""
+		resp.Headers = nil
+	}
+}
@@ -1,7 +1,9 @@
 package httptrace
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 
 	"sourcegraph.com/sourcegraph/appdash"
 )
@@ -18,17 +20,65 @@ const (
 	// easily pass along an existing parent span ID but not create a
 	// new child span ID).
 	HeaderParentSpanID = "Parent-Span-ID"
+
+	// HeaderSampled is the name of the HTTP header by which the sampling
+	// decision of a trace is passed along, as "1" if the trace is sampled
+	// and "0" if it is not.
+	HeaderSampled = "Span-Sampled"
+
+	// HeaderDetail is the name of the HTTP header by which the detail
+	// level of a trace is passed along, as "full" or "minimal" (see
+	// DetailLevel).
+	HeaderDetail = "Span-Detail"
+)
+
+// SpanIDHeaderName, ParentSpanIDHeaderName, SampledHeaderName and
+// DetailHeaderName are the names of the HTTP headers actually used to
+// propagate span IDs, sampling decisions and detail levels by
+// SetSpanIDHeader, GetSpanID, SetSampledHeader, GetSampled,
+// SetDetailHeader, GetDetail, Middleware and Transport. They default to
+// HeaderSpanID, HeaderParentSpanID, HeaderSampled and HeaderDetail, and may
+// be changed (e.g. to "X-Appdash-Span-ID") when running behind proxies that
+// strip non-standard headers. Both ends of a connection must use the same
+// names, and they should be set before any requests are handled.
+var (
+	SpanIDHeaderName       = HeaderSpanID
+	ParentSpanIDHeaderName = HeaderParentSpanID
+	SampledHeaderName      = HeaderSampled
+	DetailHeaderName       = HeaderDetail
 )
 
-// SetSpanIDHeader sets the Span-ID header.
+// SetSpanIDHeader sets the Span-ID header (named by SpanIDHeaderName).
 func SetSpanIDHeader(h http.Header, e appdash.SpanID) {
-	h.Set(HeaderSpanID, e.String())
+	h.Set(SpanIDHeaderName, e.String())
+}
+
+// A SpanIDEmitter communicates the span ID that Middleware assigned to a
+// request back to the client, by adding it to the response w. It is
+// called before the handler runs.
+type SpanIDEmitter func(w http.ResponseWriter, r *http.Request, span appdash.SpanID)
+
+// EmitSpanIDHeader is a SpanIDEmitter that sets the span ID in the
+// response's Span-ID header (named by SpanIDHeaderName). It is the
+// default.
+func EmitSpanIDHeader(w http.ResponseWriter, r *http.Request, span appdash.SpanID) {
+	SetSpanIDHeader(w.Header(), span)
+}
+
+// EmitSpanIDTrailer is a SpanIDEmitter that sets the span ID in the
+// response's Span-ID trailer (named by SpanIDHeaderName), for clients
+// that cannot read custom response headers but can read trailers. The
+// response is sent with chunked encoding, and clients must read the body
+// to the end before the trailer is available.
+func EmitSpanIDTrailer(w http.ResponseWriter, r *http.Request, span appdash.SpanID) {
+	w.Header().Set(http.TrailerPrefix+SpanIDHeaderName, span.String())
 }
 
 // GetSpanID returns the SpanID for the current request, based on the
 // values in the HTTP headers. If a Span-ID header is provided, it is
 // parsed; if a Parent-Span-ID header is provided, a new child span is
-// created and it is returned; otherwise a new root SpanID is created.
+// created and it is returned; otherwise a new root SpanID is created. The
+// header names are given by SpanIDHeaderName and ParentSpanIDHeaderName.
 func GetSpanID(h http.Header) (*appdash.SpanID, error) {
 	spanID, _, err := getSpanID(h)
 	return spanID, err
@@ -36,16 +86,16 @@ func GetSpanID(h http.Header) (*appdash.SpanID, error) {
 
 func getSpanID(h http.Header) (spanID *appdash.SpanID, fromHeader string, err error) {
 	// Check for Span-ID.
-	fromHeader = HeaderSpanID
-	spanID, err = getSpanIDHeader(h, HeaderSpanID)
+	fromHeader = SpanIDHeaderName
+	spanID, err = getSpanIDHeader(h, SpanIDHeaderName)
 	if err != nil {
 		return nil, fromHeader, err
 	}
 
 	// Check for Parent-Span-ID.
 	if spanID == nil {
-		fromHeader = HeaderParentSpanID
-		spanID, err = getSpanIDHeader(h, HeaderParentSpanID)
+		fromHeader = ParentSpanIDHeaderName
+		spanID, err = getSpanIDHeader(h, ParentSpanIDHeaderName)
 		if err != nil {
 			return nil, fromHeader, err
 		}
@@ -74,3 +124,46 @@ func getSpanIDHeader(h http.Header, key string) (*appdash.SpanID, error) {
 	}
 	return appdash.ParseSpanID(s)
 }
+
+// SetSampledHeader sets the sampled header (named by SampledHeaderName) to
+// the given sampling decision.
+func SetSampledHeader(h http.Header, sampled bool) {
+	v := "0"
+	if sampled {
+		v = "1"
+	}
+	h.Set(SampledHeaderName, v)
+}
+
+// GetSampled returns the sampling decision in the sampled header (named by
+// SampledHeaderName), or ok == false if the header is absent or malformed.
+func GetSampled(h http.Header) (sampled, ok bool) {
+	s := h.Get(SampledHeaderName)
+	if s == "" {
+		return false, false
+	}
+	sampled, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, false
+	}
+	return sampled, true
+}
+
+// sampledKey is the type of the context key under which a sampling decision
+// is stored.
+type sampledKey struct{}
+
+// NewSampledContext returns a copy of ctx that carries the given sampling
+// decision. Middleware stores its decision in the request context this way,
+// and Transport propagates the decision of a request's context to the
+// server and honors it when recording the request.
+func NewSampledContext(ctx context.Context, sampled bool) context.Context {
+	return context.WithValue(ctx, sampledKey{}, sampled)
+}
+
+// SampledFromContext returns the sampling decision stored in ctx by
+// NewSampledContext, if any.
+func SampledFromContext(ctx context.Context) (sampled, ok bool) {
+	sampled, ok = ctx.Value(sampledKey{}).(bool)
+	return sampled, ok
+}
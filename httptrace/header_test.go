@@ -2,6 +2,7 @@ package httptrace
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"sourcegraph.com/sourcegraph/appdash"
@@ -78,3 +79,72 @@ func TestGetSpanID_hasSpanIDAndParentSpanID(t *testing.T) {
 		t.Errorf("unexpected span ID: %+v", id)
 	}
 }
+
+// roundTripperFunc is an http.RoundTripper implemented by a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestSpanIDHeaderName_custom(t *testing.T) {
+	defer func(name, parentName string) {
+		SpanIDHeaderName, ParentSpanIDHeaderName = name, parentName
+	}(SpanIDHeaderName, ParentSpanIDHeaderName)
+	SpanIDHeaderName = "X-Appdash-Span-ID"
+	ParentSpanIDHeaderName = "X-Appdash-Parent-Span-ID"
+
+	ms := appdash.NewMemoryStore()
+	c := appdash.NewLocalCollector(ms)
+
+	var serverSpan appdash.SpanID
+	mw := Middleware(c, &MiddlewareConfig{
+		SetContextSpan: func(r *http.Request, id appdash.SpanID) { serverSpan = id },
+	})
+
+	// The server is reached through a proxy that strips the default,
+	// non-standard header names.
+	var reqHeader http.Header
+	transport := &Transport{
+		Recorder: appdash.NewRecorder(appdash.SpanID{1, 2, 0}, c),
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Del(HeaderSpanID)
+			req.Header.Del(HeaderParentSpanID)
+			reqHeader = req.Header
+			w := httptest.NewRecorder()
+			mw(w, req, func(http.ResponseWriter, *http.Request) {})
+			return w.Result(), nil
+		}),
+	}
+	req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientSpan, err := appdash.ParseSpanID(reqHeader.Get("X-Appdash-Span-ID"))
+	if err != nil {
+		t.Fatalf("request header: %s", err)
+	}
+	if clientSpan.Trace != 1 || clientSpan.Parent != 2 {
+		t.Errorf("got request span %v, want a child of 1/2", clientSpan)
+	}
+	if serverSpan != *clientSpan {
+		t.Errorf("server used span %v, want propagated span %v", serverSpan, *clientSpan)
+	}
+	if got, want := resp.Header.Get("X-Appdash-Span-ID"), clientSpan.String(); got != want {
+		t.Errorf("got response header %q, want %q", got, want)
+	}
+	if got := resp.Header.Get(HeaderSpanID); got != "" {
+		t.Errorf("got unexpected %s response header %q", HeaderSpanID, got)
+	}
+
+	// A parent span ID passed using the custom name starts a child span.
+	h := make(http.Header)
+	h.Set("X-Appdash-Parent-Span-ID", "0000000000000064/0000000000000096")
+	id, err := GetSpanID(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Trace != 100 || id.Parent != 150 {
+		t.Errorf("got span %v, want a child of 100/150", id)
+	}
+}
@@ -0,0 +1,283 @@
+package httptrace
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CaptureHeaderOrder makes the request headers that srv receives on the
+// connections of l be recorded in the order they arrived in, as
+// Server.Request.HeaderOrder (see RequestInfo.HeaderOrder), by Middleware.
+// It returns the listener that srv must serve, and sets srv.ConnContext,
+// calling any function already set. It is off unless called, since few
+// users need it:
+//
+// 	l, err := net.Listen("tcp", ":8080")
+// 	...
+// 	srv := &http.Server{Handler: h}
+// 	err = srv.Serve(httptrace.CaptureHeaderOrder(srv, l))
+//
+// The order is lost once net/http parses a request into an http.Header, a
+// map, so it is recovered by scanning the bytes read from each connection
+// before net/http parses them. This has limitations:
+//
+// - Only HTTP/1.x is understood. Connections that speak anything else,
+// such as HTTP/2, are passed through without recording an order.
+//
+// - The connections of l must carry plaintext HTTP. To capture the order
+// of HTTPS requests, pass a listener that already terminates TLS (e.g.
+// one from tls.NewListener) and call srv.Serve, not srv.ServeTLS.
+//
+// - Header lines longer than a few kilobytes, and requests that do not
+// frame their bodies as net/http expects, stop the capture for the rest of
+// the connection.
+//
+// The order of response headers is not captured: net/http writes them in
+// sorted order, so it carries no information.
+func CaptureHeaderOrder(srv *http.Server, l net.Listener) net.Listener {
+	connContext := srv.ConnContext
+	srv.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		if connContext != nil {
+			ctx = connContext(ctx, c)
+		}
+		if hc, ok := c.(*headerOrderConn); ok {
+			ctx = context.WithValue(ctx, headerOrderConnKey{}, hc)
+		}
+		return ctx
+	}
+	return headerOrderListener{l}
+}
+
+// headerOrderConnKey is the type of the context key under which the
+// headerOrderConn of a request's connection is stored.
+type headerOrderConnKey struct{}
+
+// receivedHeaderOrder returns the names of the headers of r in the order
+// they were received, joined with ", ", or "" if they were not captured
+// (see CaptureHeaderOrder).
+func receivedHeaderOrder(r *http.Request) string {
+	hc, ok := r.Context().Value(headerOrderConnKey{}).(*headerOrderConn)
+	if !ok {
+		return ""
+	}
+	return strings.Join(hc.pop(r.Method, r.RequestURI), ", ")
+}
+
+// headerOrderListener wraps the connections it accepts in headerOrderConns.
+type headerOrderListener struct {
+	net.Listener
+}
+
+func (l headerOrderListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &headerOrderConn{Conn: c}, nil
+}
+
+const (
+	// maxHeaderOrderLine is the longest line of a request head that
+	// headerOrderConn scans.
+	maxHeaderOrderLine = 8 << 10
+
+	// maxHeaderOrderQueue is the greatest number of request heads whose
+	// order headerOrderConn holds before they are handled. The oldest are
+	// dropped first.
+	maxHeaderOrderQueue = 8
+)
+
+// headerOrderState is the part of an HTTP/1.x request stream that a
+// headerOrderConn is scanning.
+type headerOrderState int
+
+const (
+	stateRequestLine headerOrderState = iota
+	stateHeaders
+	stateBody      // remaining bytes of a body with a Content-Length
+	stateChunkSize // line holding the size of the next chunk
+	stateChunkData // remaining bytes of a chunk and its CRLF
+	stateTrailers
+	stateDone // scanning stopped
+)
+
+// headerOrder is the order of the headers of a request.
+type headerOrder struct {
+	method, uri string
+	names       []string
+}
+
+// A headerOrderConn is a connection that scans the HTTP/1.x requests read
+// from it for the order of their headers.
+type headerOrderConn struct {
+	net.Conn
+
+	mu      sync.Mutex
+	state   headerOrderState
+	line    []byte // the incomplete line being scanned
+	n       int64  // remaining bytes, in stateBody and stateChunkData
+	cur     headerOrder
+	length  int64 // Content-Length of the current request, or -1
+	chunked bool
+	queue   []headerOrder // scanned, but not yet handled
+}
+
+func (c *headerOrderConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.mu.Lock()
+		c.scan(p[:n])
+		c.mu.Unlock()
+	}
+	return n, err
+}
+
+// pop removes the order of the request with the given method and URI
+// from the queue, with those of the requests scanned before it, which
+// were handled without it being popped, and returns it.
+func (c *headerOrderConn) pop(method, uri string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, o := range c.queue {
+		if o.method == method && o.uri == uri {
+			c.queue = c.queue[i+1:]
+			return o.names
+		}
+	}
+	return nil
+}
+
+// scan advances the scanner over p.
+func (c *headerOrderConn) scan(p []byte) {
+	for len(p) > 0 && c.state != stateDone {
+		switch c.state {
+		case stateBody, stateChunkData:
+			skip := int64(len(p))
+			if skip > c.n {
+				skip = c.n
+			}
+			p, c.n = p[skip:], c.n-skip
+			if c.n == 0 {
+				if c.state == stateBody {
+					c.state = stateRequestLine
+				} else {
+					c.state = stateChunkSize
+				}
+			}
+			continue
+		}
+
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			c.line = append(c.line, p...)
+			if len(c.line) > maxHeaderOrderLine {
+				c.stop()
+			}
+			return
+		}
+		c.line = append(c.line, p[:i]...)
+		p = p[i+1:]
+		line := strings.TrimSuffix(string(c.line), "\r")
+		c.line = c.line[:0]
+		if len(line) > maxHeaderOrderLine {
+			c.stop()
+			return
+		}
+		c.scanLine(line)
+	}
+}
+
+// scanLine scans a complete line, without its line terminator.
+func (c *headerOrderConn) scanLine(line string) {
+	switch c.state {
+	case stateRequestLine:
+		if line == "" {
+			return // net/http ignores blank lines before a request
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], "HTTP/1.") {
+			c.stop()
+			return
+		}
+		c.cur = headerOrder{method: fields[0], uri: fields[1]}
+		c.length, c.chunked = -1, false
+		c.state = stateHeaders
+
+	case stateHeaders:
+		if line == "" {
+			c.endHead()
+			return
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			return // continuation of the previous header
+		}
+		i := strings.IndexByte(line, ':')
+		if i <= 0 {
+			c.stop()
+			return
+		}
+		name := textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(line[:i]))
+		value := strings.TrimSpace(line[i+1:])
+		c.cur.names = append(c.cur.names, name)
+		switch name {
+		case "Content-Length":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				c.stop()
+				return
+			}
+			c.length = n
+		case "Transfer-Encoding":
+			c.chunked = strings.Contains(strings.ToLower(value), "chunked")
+		}
+
+	case stateChunkSize:
+		if i := strings.IndexByte(line, ';'); i >= 0 {
+			line = line[:i]
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(line), 16, 64)
+		if err != nil || n < 0 {
+			c.stop()
+			return
+		}
+		if n == 0 {
+			c.state = stateTrailers
+			return
+		}
+		c.n, c.state = n+2, stateChunkData // the chunk and its CRLF
+
+	case stateTrailers:
+		if line == "" {
+			c.state = stateRequestLine
+		}
+	}
+}
+
+// endHead records the order of the request head just scanned, and starts
+// skipping its body.
+func (c *headerOrderConn) endHead() {
+	if len(c.queue) == maxHeaderOrderQueue {
+		c.queue = c.queue[1:]
+	}
+	c.queue = append(c.queue, c.cur)
+	c.cur = headerOrder{}
+	switch {
+	case c.chunked:
+		c.state = stateChunkSize
+	case c.length > 0:
+		c.n, c.state = c.length, stateBody
+	default:
+		c.state = stateRequestLine
+	}
+}
+
+// stop stops scanning the connection, whose stream it cannot follow.
+func (c *headerOrderConn) stop() {
+	c.state, c.line = stateDone, nil
+}
@@ -0,0 +1,118 @@
+package httptrace
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// pipelinedRequests are requests sent back to back on one connection. The
+// body of the second looks like a request head, which must be skipped.
+const pipelinedRequests = "GET /a HTTP/1.1\r\n" +
+	"X-Zeta: 1\r\n" +
+	"Host: example.com\r\n" +
+	"accept: */*\r\n" +
+	"X-Zeta: 2\r\n" +
+	"\r\n" +
+	"POST /b HTTP/1.1\r\n" +
+	"Host: example.com\r\n" +
+	"Content-Length: 30\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"GET /x HTTP/1.1\r\nX-Body: 1\r\n\r\n" +
+	"POST /c HTTP/1.1\r\n" +
+	"Transfer-Encoding: chunked\r\n" +
+	"Host: example.com\r\n" +
+	"\r\n" +
+	"5\r\nhello\r\n0\r\nX-Trailer: 1\r\n\r\n"
+
+var wantHeaderOrders = map[string]string{
+	"/a": "X-Zeta, Host, Accept, X-Zeta",
+	"/b": "Host, Content-Length, Content-Type",
+	"/c": "Transfer-Encoding, Host",
+}
+
+func TestCaptureHeaderOrder(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mw(w, r, func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(ioutil.Discard, r.Body)
+		})
+	})}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(CaptureHeaderOrder(srv, l))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, pipelinedRequests); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	for range wantHeaderOrders {
+		resp, err := http.ReadResponse(br, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	traces, err := ms.Traces(appdash.TracesOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, trace := range traces {
+		var e ServerEvent
+		if err := appdash.UnmarshalEvent(trace.Span.Annotations, &e); err != nil {
+			t.Fatal(err)
+		}
+		got[e.Request.URI] = e.Request.HeaderOrder
+	}
+	if !reflect.DeepEqual(got, wantHeaderOrders) {
+		t.Errorf("got header orders %q, want %q", got, wantHeaderOrders)
+	}
+}
+
+func TestHeaderOrderConn_scanByteByByte(t *testing.T) {
+	c := &headerOrderConn{}
+	for i := 0; i < len(pipelinedRequests); i++ {
+		c.scan([]byte{pipelinedRequests[i]})
+	}
+	for _, uri := range []string{"/a", "/b", "/c"} {
+		method := "POST"
+		if uri == "/a" {
+			method = "GET"
+		}
+		if got := c.pop(method, uri); strings.Join(got, ", ") != wantHeaderOrders[uri] {
+			t.Errorf("%s: got header order %q, want %q", uri, strings.Join(got, ", "), wantHeaderOrders[uri])
+		}
+	}
+	if c.state != stateRequestLine {
+		t.Errorf("got state %d after the requests, want %d", c.state, stateRequestLine)
+	}
+}
+
+func TestHeaderOrderConn_notHTTP1(t *testing.T) {
+	c := &headerOrderConn{}
+	c.scan([]byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"))
+	if c.state != stateDone || len(c.queue) != 0 {
+		t.Errorf("got state %d and %d queued orders, want scanning to stop", c.state, len(c.queue))
+	}
+}
@@ -0,0 +1,84 @@
+// Package muxtrace adapts the httptrace middleware to gorilla/mux routers,
+// so that spans are named after the matched route rather than the request
+// path. It is a separate package to keep the router out of httptrace's
+// dependencies.
+//
+// Because the middleware usually wraps the router, the route has not been
+// matched yet (or its gorilla/context values have already been cleared) when
+// the middleware runs. The functions in this package therefore match the
+// request against the router themselves:
+//
+//  router := mux.NewRouter()
+//  router.HandleFunc("/users/{id}", userHandler)
+//
+//  tracemw := httptrace.Middleware(collector, &httptrace.MiddlewareConfig{
+//      RouteName:   muxtrace.RouteName(router),
+//      RouteParams: muxtrace.RouteParams(router),
+//  })
+package muxtrace
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// RouteName returns a function, for use as
+// httptrace.MiddlewareConfig.RouteName, that returns the path template
+// (such as "/users/{id}") of the route of router that matches the request.
+// If the template cannot be determined, the route's name is returned
+// instead. If no route matches, "" is returned.
+func RouteName(router *mux.Router) func(*http.Request) string {
+	return func(r *http.Request) string {
+		var match mux.RouteMatch
+		if !router.Match(r, &match) || match.Route == nil {
+			return ""
+		}
+		if tmpl := pathTemplate(&match); tmpl != "" {
+			return tmpl
+		}
+		return match.Route.GetName()
+	}
+}
+
+// pathTemplate returns the path template of the matched route, or "" if it
+// cannot be determined.
+func pathTemplate(match *mux.RouteMatch) string {
+	// Newer versions of gorilla/mux expose the template directly.
+	if r, ok := interface{}(match.Route).(interface {
+		GetPathTemplate() (string, error)
+	}); ok {
+		tmpl, err := r.GetPathTemplate()
+		if err != nil {
+			return ""
+		}
+		return tmpl
+	}
+
+	// Otherwise build the route's URL with the variables' names as their
+	// values. This fails for variables with patterns (such as
+	// "{id:[0-9]+}") that their names do not match.
+	pairs := make([]string, 0, 2*len(match.Vars))
+	for name := range match.Vars {
+		pairs = append(pairs, name, "{"+name+"}")
+	}
+	u, err := match.Route.URLPath(pairs...)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+// RouteParams returns a function, for use as
+// httptrace.MiddlewareConfig.RouteParams, that returns the variables
+// captured by the route of router that matches the request. If no route
+// matches, nil is returned.
+func RouteParams(router *mux.Router) func(*http.Request) map[string]string {
+	return func(r *http.Request) map[string]string {
+		var match mux.RouteMatch
+		if !router.Match(r, &match) {
+			return nil
+		}
+		return match.Vars
+	}
+}
@@ -0,0 +1,72 @@
+package muxtrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"sourcegraph.com/sourcegraph/appdash"
+	"sourcegraph.com/sourcegraph/appdash/httptrace"
+)
+
+func TestRouteName(t *testing.T) {
+	router := mux.NewRouter()
+	router.Path("/users/{id}")
+	router.Path("/repos/{owner}/{name}")
+	router.Path("/items/{n:[0-9]+}").Name("item")
+	router.Path("/static").Name("static")
+
+	routeName, routeParams := RouteName(router), RouteParams(router)
+	tests := []struct {
+		path       string
+		wantName   string
+		wantParams map[string]string
+	}{
+		{"/users/42", "/users/{id}", map[string]string{"id": "42"}},
+		{"/repos/a/b", "/repos/{owner}/{name}", map[string]string{"owner": "a", "name": "b"}},
+		{"/items/7", "item", map[string]string{"n": "7"}},
+		{"/static", "/static", map[string]string{}},
+		{"/nowhere", "", nil},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest("GET", "http://example.com"+test.path, nil)
+		if got := routeName(req); got != test.wantName {
+			t.Errorf("%s: got route name %q, want %q", test.path, got, test.wantName)
+		}
+		if got := routeParams(req); !reflect.DeepEqual(got, test.wantParams) {
+			t.Errorf("%s: got route params %v, want %v", test.path, got, test.wantParams)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/users/{id}", func(http.ResponseWriter, *http.Request) {})
+
+	ms := appdash.NewMemoryStore()
+	mw := httptrace.Middleware(appdash.NewLocalCollector(ms), &httptrace.MiddlewareConfig{
+		RouteName:   RouteName(router),
+		RouteParams: RouteParams(router),
+	})
+	req, _ := http.NewRequest("GET", "http://example.com/users/42", nil)
+	httptrace.SetSpanIDHeader(req.Header, appdash.SpanID{Trace: 1, Span: 1})
+	mw(httptest.NewRecorder(), req, router.ServeHTTP)
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := trace.Span.Name(), "Serve /users/{id}"; got != want {
+		t.Errorf("got span name %q, want %q", got, want)
+	}
+	var e httptrace.ServerEvent
+	if err := appdash.UnmarshalEvent(trace.Span.Annotations, &e); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"id": "42"}; !reflect.DeepEqual(e.Request.RouteParams, want) {
+		t.Errorf("got route params %v, want %v", e.Request.RouteParams, want)
+	}
+}
@@ -1,14 +1,24 @@
 package httptrace
 
 import (
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 
 	"sourcegraph.com/sourcegraph/appdash"
 )
 
-func init() { appdash.RegisterEvent(ServerEvent{}) }
+func init() {
+	appdash.RegisterEvent(ServerEvent{})
+	appdash.RegisterEvent(PanicEvent{})
+	appdash.RegisterEvent(CanceledEvent{})
+	registerStatusClass(ServerEvent{}.Schema(), "Server.Response")
+}
 
 // NewServerEvent returns an event which records various aspects of an
 // HTTP response. It takes an HTTP request, not response, as input
@@ -28,6 +38,40 @@ type ResponseInfo struct {
 	Headers       map[string]string
 	ContentLength int64
 	StatusCode    int
+
+	// ContentType is the response's normalized Content-Type header (see
+	// StripContentTypeParams).
+	ContentType string
+}
+
+// StatusClass returns the class of an HTTP status code, such as "2xx" for
+// 200 or "5xx" for 503, or "" if code is not a valid status code.
+func StatusClass(code int) string {
+	if code < 100 || code > 999 {
+		return ""
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
+// registerStatusClass registers the derived annotation prefix.StatusClass,
+// holding the StatusClass of the prefix.StatusCode annotation, for events
+// of the given schema. It lets traces be filtered and aggregated by status
+// class without parsing the status code.
+func registerStatusClass(schema, prefix string) {
+	codeKey, classKey := prefix+".StatusCode", prefix+".StatusClass"
+	appdash.RegisterDerivedAnnotations(schema, []string{classKey}, func(as appdash.Annotations) appdash.Annotations {
+		for _, a := range as {
+			if a.Key != codeKey {
+				continue
+			}
+			code, _ := strconv.Atoi(string(a.Value))
+			if class := StatusClass(code); class != "" {
+				return appdash.Annotations{{Key: classKey, Value: []byte(class)}}
+			}
+			break
+		}
+		return nil
+	})
 }
 
 func responseInfo(r *http.Response) ResponseInfo {
@@ -35,6 +79,7 @@ func responseInfo(r *http.Response) ResponseInfo {
 		Headers:       redactHeaders(r.Header, r.Trailer),
 		ContentLength: r.ContentLength,
 		StatusCode:    r.StatusCode,
+		ContentType:   contentType(r.Header),
 	}
 }
 
@@ -62,66 +107,367 @@ func (e ServerEvent) Start() time.Time { return e.ServerRecv }
 // End implements the appdash TimespanEvent interface.
 func (e ServerEvent) End() time.Time { return e.ServerSend }
 
+// PanicEvent records a panic that occurred while handling an HTTP request.
+// See MiddlewareConfig.RecordPanics.
+type PanicEvent struct {
+	Value string `trace:"Panic.Value"`
+	Stack string `trace:"Panic.Stack"`
+}
+
+// Schema returns the constant "HTTPPanic".
+func (PanicEvent) Schema() string { return "HTTPPanic" }
+
+// Important implements the appdash ImportantEvent.
+func (PanicEvent) Important() []string { return []string{"Panic.Value"} }
+
+// CanceledEvent records that a request's context was canceled before the
+// handler finished, typically because the client disconnected.
+type CanceledEvent struct {
+	Canceled bool   `trace:"Canceled"`
+	Error    string `trace:"Canceled.Error"`
+}
+
+// Schema returns the constant "HTTPCanceled".
+func (CanceledEvent) Schema() string { return "HTTPCanceled" }
+
+// Important implements the appdash ImportantEvent.
+func (CanceledEvent) Important() []string { return []string{"Canceled"} }
+
+// SampledKey is the reserved annotation key that Middleware records on the
+// spans of sampled requests, mirroring the sampling decision it propagates
+// (see appdash.SampledKey).
+const SampledKey = appdash.SampledKey
+
 // Middleware creates a new http.Handler middleware
 // (negroni-compliant) that records incoming HTTP requests to the
-// collector c as "HTTPServer"-schema events.
+// collector c as "HTTPServer"-schema events. Spans of requests answered
+// with a 5xx status have their sampling priority raised to 1 (see
+// appdash.SamplingPriorityKey). Requests whose context was canceled by the
+// time the handler returned are recorded with a CanceledEvent.
+//
+// Each request is sampled according to the sampling decision it carries
+// (see HeaderSampled) or, lacking one, MiddlewareConfig.Sampler. Only the
+// originator of a decision makes it: because every service downstream
+// honors the decision propagated to it, the edge's sampling rate is the
+// effective rate of the whole chain, rather than the product of the rates
+// of each service. Requests from upstreams that do not propagate a
+// decision are sampled by Sampler, like those that start a new trace. The
+// spans of sampled requests carry a SampledKey annotation. Likewise, each
+// request is recorded at the detail level it carries (see HeaderDetail)
+// or, lacking one, that MiddlewareConfig.DetailSampler decides.
+//
+// Requests that start a new trace, i.e. that carry no span ID header, are
+// marked as the entrypoint of their trace (see appdash.EntrypointKey).
+//
+// The span is collected after the handler returns, before the response is
+// complete. To keep a slow collector from delaying responses, pass an
+// appdash.AsyncCollector as c.
 func Middleware(c appdash.Collector, conf *MiddlewareConfig) func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	trusted := parseTrustedProxies(conf.TrustedProxies)
 	return func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 		spanID, spanFromHeader, err := getSpanID(r.Header)
 		if err != nil {
 			log.Printf("Warning: invalid %s header: %s. (Continuing with request handling.)", spanFromHeader, err)
 		}
-		usingProvidedSpanID := (spanFromHeader == HeaderSpanID)
+		usingProvidedSpanID := (spanFromHeader == SpanIDHeaderName)
+
+		// Honor the sampling decision made upstream, if any; otherwise
+		// decide here, before the handler runs, so that the decision is
+		// propagated to the requests the handler makes. The route is
+		// included, so that samplers can decide by it (see
+		// appdash.RouteSamplingKey).
+		var reqAnns appdash.Annotations
+		requestAnnotations := func() appdash.Annotations {
+			if reqAnns == nil {
+				e := NewServerEvent(r)
+				if conf.RouteName != nil {
+					e.Route = conf.RouteName(r)
+				}
+				var err error
+				if reqAnns, err = appdash.MarshalEvent(e); err != nil {
+					log.Printf("Warning: %s. (Continuing with request handling.)", err)
+				}
+			}
+			return reqAnns
+		}
+		sampled, decided := GetSampled(r.Header)
+		if !decided {
+			sampled = true
+			if conf.Sampler != nil {
+				sampled = conf.Sampler.ShouldSample(*spanID, requestAnnotations())
+			}
+		}
+		detail, decided := GetDetail(r.Header)
+		if !decided && conf.DetailSampler != nil && !conf.DetailSampler.ShouldSample(*spanID, requestAnnotations()) {
+			detail = MinimalDetail
+		}
+		r = r.WithContext(NewDetailContext(NewSampledContext(r.Context(), sampled), detail))
 
 		if conf.SetContextSpan != nil {
 			conf.SetContextSpan(r, *spanID)
 		}
 
+		headerOrder := receivedHeaderOrder(r)
+		clock := conf.clock()
 		e := NewServerEvent(r)
-		e.ServerRecv = time.Now()
+		e.ServerRecv = clock.Now()
 
 		rr := &responseInfoRecorder{ResponseWriter: rw}
-		next(rr, r)
-		SetSpanIDHeader(rr.Header(), *spanID)
+		conf.emitSpanID()(rr, r, *spanID)
+		SetSampledHeader(rr.Header(), sampled)
 
-		if !usingProvidedSpanID {
-			e.Request = requestInfo(r)
-		}
-		if conf.RouteName != nil {
-			e.Route = conf.RouteName(r)
-		}
-		if conf.CurrentUser != nil {
-			e.User = conf.CurrentUser(r)
+		// finish records the span, along with any additional events.
+		finish := func(events ...appdash.Event) {
+			if !usingProvidedSpanID {
+				e.Request = requestInfo(r)
+			}
+			e.Request.HeaderOrder = headerOrder
+			if conf.RouteParams != nil {
+				e.Request.RouteParams = redactRouteParams(conf.RouteParams(r))
+			}
+			if len(trusted) > 0 {
+				e.Request.ClientIP = clientIP(r, trusted, conf.clientIPHeaders())
+			}
+			if conf.RouteName != nil {
+				e.Route = conf.RouteName(r)
+			}
+			if conf.CurrentUser != nil {
+				e.User = conf.CurrentUser(r)
+			}
+			var correlationID string
+			if conf.CorrelationIDHeader != "" {
+				correlationID = r.Header.Get(conf.CorrelationIDHeader)
+			}
+			e.Response = responseInfo(rr.partialResponse())
+			applyDetail(detail, &e.Request, &e.Response)
+			e.ServerSend = clock.Now()
+			if err := r.Context().Err(); err != nil {
+				events = append(events, CanceledEvent{Canceled: true, Error: err.Error()})
+				if conf.CanceledStatusCode != 0 {
+					e.Response.StatusCode = conf.CanceledStatusCode
+				}
+			}
+
+			rec := appdash.NewRecorder(*spanID, c)
+			rec.Clock = conf.Clock
+			if sampled {
+				rec.Annotation(appdash.Annotation{Key: SampledKey, Value: []byte("1")})
+			} else {
+				rec.Sampler = appdash.NeverSample
+			}
+			if spanFromHeader == "" {
+				rec.Annotation(appdash.Entrypoint())
+			}
+			if e.Route != "" {
+				rec.Name("Serve " + e.Route)
+			} else {
+				rec.Name("Serve " + r.URL.Host + r.URL.Path)
+			}
+			rec.Event(e)
+			if correlationID != "" {
+				rec.TraceAnnotation(appdash.CorrelationIDKey, []byte(correlationID))
+			}
+			for _, ev := range events {
+				rec.Event(ev)
+			}
+			if e.Response.StatusCode >= 500 {
+				rec.SamplingPriority(1)
+			}
+			rec.Finish()
 		}
-		e.Response = responseInfo(rr.partialResponse())
-		e.ServerSend = time.Now()
-
-		rec := appdash.NewRecorder(*spanID, c)
-		if e.Route != "" {
-			rec.Name("Serve " + e.Route)
-		} else {
-			rec.Name("Serve " + r.URL.Host + r.URL.Path)
+
+		if conf.RecordPanics {
+			defer func() {
+				if v := recover(); v != nil {
+					if rr.statusCode == 0 {
+						rr.WriteHeader(http.StatusInternalServerError)
+					}
+					finish(PanicEvent{Value: fmt.Sprint(v), Stack: string(debug.Stack())})
+					panic(v)
+				}
+			}()
 		}
-		rec.Event(e)
-		rec.Finish()
+		next(rr, r)
+		finish()
 	}
 }
 
 // MiddlewareConfig configures the HTTP tracing middleware.
 type MiddlewareConfig struct {
 	// RouteName, if non-nil, is called to get the current route's
-	// name. This name is used as the span's name.
+	// name. This name is used as the span's name. For gorilla/mux and chi
+	// routers, see the muxtrace and chitrace packages.
 	RouteName func(*http.Request) string
 
+	// RouteParams, if non-nil, is called to get the parameters captured
+	// by the router for the current route (e.g. {"id": "42"} for a route
+	// "/users/:id"). They are recorded as Server.Request.RouteParam.<name>
+	// annotations, subject to RedactedRouteParams, so that traces can be
+	// filtered by parameter while the Route name stays low-cardinality.
+	RouteParams func(*http.Request) map[string]string
+
 	// CurrentUser, if non-nil, is called to get the current user ID
 	// (which may be a login or a numeric ID).
 	CurrentUser func(*http.Request) string
 
+	// CorrelationIDHeader, if non-empty, is the name of a request header
+	// (such as "X-Request-Id") holding an external ID of the request. Its
+	// value is recorded as the trace's correlation ID (see
+	// appdash.CorrelationID), so that the trace can be found by it.
+	CorrelationIDHeader string
+
+	// TrustedProxies, if non-empty, lists the IP addresses or CIDR ranges
+	// (e.g. "10.0.0.0/8") of the proxies and load balancers in front of the
+	// server, and causes the client's IP address to be recorded as
+	// Server.Request.ClientIP. The address is taken from ClientIPHeaders
+	// only if the request came from a trusted proxy, and is otherwise the
+	// request's RemoteAddr, so that clients cannot spoof it.
+	TrustedProxies []string
+
+	// ClientIPHeaders are the request headers, in order of preference,
+	// that trusted proxies record the client's IP address in. If empty,
+	// X-Forwarded-For and then X-Real-IP are used. In a comma-separated
+	// list such as X-Forwarded-For, the client is the rightmost address
+	// that is not a trusted proxy.
+	ClientIPHeaders []string
+
 	// SetContextSpan, if non-nil, is called to set the span (which is
 	// either taken from the client request header or created anew) in
 	// the HTTP request context, so it may be used by other parts of
 	// the handling process.
 	SetContextSpan func(*http.Request, appdash.SpanID)
+
+	// EmitSpanID, if non-nil, is called to communicate the span ID of
+	// each request back to the client, e.g. with EmitSpanIDTrailer or a
+	// function that writes it to the response body. If nil,
+	// EmitSpanIDHeader is used.
+	EmitSpanID SpanIDEmitter
+
+	// RecordPanics, if true, causes the middleware to recover from a panic
+	// in the handler, respond with a 500 status if no status was written,
+	// record the span along with a PanicEvent, and then re-panic with the
+	// same value so that other recovery logic still runs.
+	RecordPanics bool
+
+	// CanceledStatusCode, if non-zero, is recorded as the response status
+	// code of requests whose context was canceled (e.g. 499, as used by
+	// nginx for client disconnects), instead of whatever status the
+	// handler wrote. This keeps aborted requests from looking like server
+	// errors.
+	CanceledStatusCode int
+
+	// Clock, if non-nil, is used to timestamp requests instead of
+	// appdash.RealClock.
+	Clock appdash.Clock
+
+	// Sampler, if non-nil, decides whether the span of each request that
+	// does not carry a sampling decision (see HeaderSampled) is collected.
+	// It is consulted before the handler runs, with the annotations of the
+	// request's ServerEvent, and its decision is stored in the request
+	// context (see NewSampledContext) so that Transport propagates it
+	// downstream. Requests that carry a decision are collected only if it
+	// is to sample them, regardless of Sampler. Either way, the span ID is
+	// still propagated, and the decision is returned in the response.
+	Sampler appdash.Sampler
+
+	// DetailSampler, if non-nil, decides the detail level of each request
+	// that does not carry one (see HeaderDetail): requests it samples are
+	// recorded with FullDetail and others with MinimalDetail. It is
+	// consulted before the handler runs, and its decision is stored in the
+	// request context (see NewDetailContext) for Transport to propagate.
+	// If nil, such requests are recorded with FullDetail.
+	DetailSampler appdash.Sampler
+}
+
+func (c *MiddlewareConfig) emitSpanID() SpanIDEmitter {
+	if c.EmitSpanID != nil {
+		return c.EmitSpanID
+	}
+	return EmitSpanIDHeader
+}
+
+func (c *MiddlewareConfig) clock() appdash.Clock {
+	if c.Clock == nil {
+		return appdash.RealClock
+	}
+	return c.Clock
+}
+
+// defaultClientIPHeaders are the headers consulted for the client's IP
+// address when MiddlewareConfig.ClientIPHeaders is empty.
+var defaultClientIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+
+func (c *MiddlewareConfig) clientIPHeaders() []string {
+	if len(c.ClientIPHeaders) == 0 {
+		return defaultClientIPHeaders
+	}
+	return c.ClientIPHeaders
+}
+
+// parseTrustedProxies parses the IP addresses and CIDR ranges of
+// MiddlewareConfig.TrustedProxies. Invalid entries are logged and ignored.
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		} else if _, n, err := net.ParseCIDR(p); err == nil {
+			nets = append(nets, n)
+			continue
+		}
+		log.Printf("Warning: invalid trusted proxy %q. (Ignoring it.)", p)
+	}
+	return nets
+}
+
+// isTrusted reports whether ip is in one of the trusted networks.
+func isTrusted(ip net.IP, trusted []*net.IPNet) bool {
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the IP address of the client that made r. If r came
+// from a trusted proxy, it is taken from the first of headers that is set;
+// otherwise it is the address of the peer.
+func clientIP(r *http.Request, trusted []*net.IPNet, headers []string) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	if ip := net.ParseIP(peer); ip == nil || !isTrusted(ip, trusted) {
+		return peer
+	}
+	for _, h := range headers {
+		v := r.Header.Get(h)
+		if v == "" {
+			continue
+		}
+		// Each proxy appends the address it received the request from, so
+		// walk back from the nearest proxy to the first untrusted address.
+		addrs := strings.Split(v, ",")
+		for i := len(addrs) - 1; i >= 0; i-- {
+			addr := strings.TrimSpace(addrs[i])
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				break // malformed, so do not trust anything further back
+			}
+			if i == 0 || !isTrusted(ip, trusted) {
+				return ip.String()
+			}
+		}
+	}
+	return peer
 }
 
 // responseInfoRecorder is an http.ResponseWriter that records a
@@ -1,18 +1,41 @@
 package httptrace
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"sourcegraph.com/sourcegraph/appdash"
+	"sourcegraph.com/sourcegraph/appdash/appdashtest"
 )
 
 var _ appdash.Event = ServerEvent{}
 
+func ExampleServerEvent() {
+	collector := appdash.NewLocalCollector(appdash.NewMemoryStore())
+	rec := appdash.NewRecorder(appdash.NewRootSpanID(), collector)
+
+	r, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+	e := NewServerEvent(r)
+	e.ServerRecv = time.Now()
+	// ... handle the request ...
+	e.ServerSend = time.Now()
+
+	if err := rec.CollectEvent(e); err != nil {
+		log.Println(err)
+	}
+}
+
 func TestNewServerEvent(t *testing.T) {
 	r := &http.Request{
 		Host:          "example.com",
@@ -51,7 +74,12 @@ func TestNewServerEvent(t *testing.T) {
 		"Server.Request.Method":                "GET",
 		"Server.Request.URI":                   "/foo",
 		"Server.Response.StatusCode":           "200",
+		"Server.Response.StatusClass":          "2xx",
 		"Server.Response.ContentLength":        "0",
+		"Server.Request.ContentType":           "",
+		"Server.Request.ClientIP":              "",
+		"Server.Request.HeaderOrder":           "",
+		"Server.Response.ContentType":          "",
 		"Server.User":                          "",
 		"Server.Route":                         "",
 		"Server.Send":                          "0001-01-01T00:00:00Z",
@@ -77,6 +105,7 @@ func TestMiddleware_useSpanFromHeaders(t *testing.T) {
 		RouteName:      func(r *http.Request) string { return "r" },
 		CurrentUser:    func(r *http.Request) string { return "u" },
 		SetContextSpan: func(r *http.Request, id appdash.SpanID) { setContextSpan = id },
+		Clock:          newTestClock(),
 	})
 
 	w := httptest.NewRecorder()
@@ -106,17 +135,17 @@ func TestMiddleware_useSpanFromHeaders(t *testing.T) {
 		},
 		Response: ResponseInfo{
 			StatusCode: 200,
-			Headers:    map[string]string{"Span-Id": "0000000000000001/0000000000000002/0000000000000003"},
+			Headers:    map[string]string{"Span-Id": "0000000000000001/0000000000000002/0000000000000003", "Span-Sampled": "1"},
 		},
-		User:  "u",
-		Route: "r",
+		User:       "u",
+		Route:      "r",
+		ServerRecv: testTime.Add(1 * time.Second),
+		ServerSend: testTime.Add(2 * time.Second),
 	}
 
-	delete(e.Request.Headers, "Span-Id")
-	e.ServerRecv = time.Time{}
-	e.ServerSend = time.Time{}
-	if !reflect.DeepEqual(e, wantEvent) {
-		t.Errorf("got ServerEvent %+v, want %+v", e, wantEvent)
+	got, want := mustMarshalEvent(t, e), mustMarshalEvent(t, wantEvent)
+	if !appdashtest.AnnotationsEqual(got, want, "Server.Request.Headers.Span-Id") {
+		t.Errorf("got ServerEvent %v, want %v", got, want)
 	}
 }
 
@@ -129,6 +158,7 @@ func TestMiddleware_createNewSpan(t *testing.T) {
 	var setContextSpan appdash.SpanID
 	mw := Middleware(c, &MiddlewareConfig{
 		SetContextSpan: func(r *http.Request, id appdash.SpanID) { setContextSpan = id },
+		Clock:          newTestClock(),
 	})
 
 	w := httptest.NewRecorder()
@@ -157,14 +187,759 @@ func TestMiddleware_createNewSpan(t *testing.T) {
 		},
 		Response: ResponseInfo{
 			StatusCode: 200,
-			Headers:    map[string]string{"Span-Id": setContextSpan.String()},
+			Headers:    map[string]string{"Span-Id": setContextSpan.String(), "Span-Sampled": "1"},
 		},
+		ServerRecv: testTime.Add(1 * time.Second),
+		ServerSend: testTime.Add(2 * time.Second),
 	}
-	delete(e.Request.Headers, "Span-Id")
-	e.ServerRecv = time.Time{}
-	e.ServerSend = time.Time{}
-	if !reflect.DeepEqual(e, wantEvent) {
-		t.Errorf("got ServerEvent %+v, want %+v", e, wantEvent)
+	got, want := mustMarshalEvent(t, e), mustMarshalEvent(t, wantEvent)
+	if !appdashtest.AnnotationsEqual(got, want, "Server.Request.Headers.Span-Id") {
+		t.Errorf("got ServerEvent %v, want %v", got, want)
+	}
+}
+
+func TestMiddleware_tailSampling(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	tc := appdash.NewTailSamplingCollector(ms)
+	defer tc.Stop()
+	mw := Middleware(tc, &MiddlewareConfig{})
+
+	for _, status := range []int{http.StatusOK, http.StatusInternalServerError} {
+		req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: appdash.ID(status), Span: 1})
+		mw(httptest.NewRecorder(), req, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		})
+	}
+	if err := tc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ms.Trace(http.StatusInternalServerError); err != nil {
+		t.Errorf("errored trace was not retained: %s", err)
+	}
+	if _, err := ms.Trace(http.StatusOK); err != appdash.ErrTraceNotFound {
+		t.Errorf("got error %v for successful trace, want it dropped", err)
+	}
+}
+
+func TestMiddleware_sampler(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{
+		Sampler: appdash.SamplerFunc(func(span appdash.SpanID, as appdash.Annotations) bool {
+			return span.Trace == 2
+		}),
+	})
+	for _, trace := range []appdash.ID{1, 2} {
+		req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: trace, Span: 1})
+		w := httptest.NewRecorder()
+		mw(w, req, func(http.ResponseWriter, *http.Request) {})
+		if w.Header().Get(HeaderSpanID) == "" {
+			t.Errorf("trace %v: span ID was not propagated", trace)
+		}
+	}
+
+	if _, err := ms.Trace(1); err != appdash.ErrTraceNotFound {
+		t.Errorf("got error %v, want unsampled trace not to be collected", err)
+	}
+	if _, err := ms.Trace(2); err != nil {
+		t.Errorf("sampled trace was not collected: %s", err)
+	}
+}
+
+func TestMiddleware_keyedRateLimitSampler(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{
+		RouteName: func(r *http.Request) string { return r.URL.Path },
+		Sampler:   appdash.NewKeyedRateLimitSampler(1),
+	})
+	// Each route has its own bucket, so the first request on each is
+	// kept and the second is not.
+	sampled := map[string]int{}
+	var trace appdash.ID
+	for _, route := range []string{"/a", "/b", "/c", "/a", "/b", "/c"} {
+		trace++
+		req, _ := http.NewRequest("GET", "http://example.com"+route, nil)
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: trace, Span: 1})
+		mw(httptest.NewRecorder(), req, func(http.ResponseWriter, *http.Request) {})
+		if _, err := ms.Trace(trace); err == nil {
+			sampled[route]++
+		}
+	}
+	if want := map[string]int{"/a": 1, "/b": 1, "/c": 1}; !reflect.DeepEqual(sampled, want) {
+		t.Errorf("got sampled traces per route %v, want %v", sampled, want)
+	}
+}
+
+func TestMiddleware_entrypoint(t *testing.T) {
+	upstream := appdash.SpanID{Trace: 1, Span: 2}
+	tests := map[string]struct {
+		header         string // span ID header to send, if any
+		wantEntrypoint bool
+	}{
+		"new trace":         {"", true},
+		"continued trace":   {HeaderSpanID, false},
+		"parent span given": {HeaderParentSpanID, false},
+	}
+	for label, test := range tests {
+		ms := appdash.NewMemoryStore()
+		mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{})
+		req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+		if test.header != "" {
+			req.Header.Set(test.header, upstream.String())
+		}
+		mw(httptest.NewRecorder(), req, func(http.ResponseWriter, *http.Request) {})
+
+		traces, err := ms.Traces(appdash.TracesOpts{})
+		if err != nil || len(traces) != 1 {
+			t.Fatalf("%s: got traces %v (error %v), want 1", label, traces, err)
+		}
+		var span *appdash.Span
+		traces[0].Walk(func(s *appdash.Span, depth int) error {
+			if s.Name() == "Serve example.com/foo" {
+				span = s
+			}
+			return nil
+		})
+		if span == nil {
+			t.Fatalf("%s: server span not found in %v", label, traces[0])
+		}
+		if _, marked := span.Annotations.Get(appdash.EntrypointKey); marked != test.wantEntrypoint {
+			t.Errorf("%s: got server span marked as entrypoint %v, want %v", label, marked, test.wantEntrypoint)
+		}
+		if got := appdash.EntrypointFilter(traces[0]); got != test.wantEntrypoint {
+			t.Errorf("%s: got EntrypointFilter %v, want %v", label, got, test.wantEntrypoint)
+		}
+	}
+}
+
+func TestMiddleware_detail(t *testing.T) {
+	tests := map[string]struct {
+		header     string // inbound detail header; "" for none
+		sampler    appdash.Sampler
+		wantDetail DetailLevel
+	}{
+		"default":          {"", nil, FullDetail},
+		"header":           {"minimal", appdash.AlwaysSample, MinimalDetail},
+		"sampled down":     {"", appdash.NeverSample, MinimalDetail},
+		"sampled up":       {"", appdash.AlwaysSample, FullDetail},
+		"malformed":        {"bogus", appdash.NeverSample, MinimalDetail},
+		"header overrides": {"full", appdash.NeverSample, FullDetail},
+	}
+	for label, test := range tests {
+		ms := appdash.NewMemoryStore()
+		mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{
+			RouteName:     func(*http.Request) string { return "/foo" },
+			DetailSampler: test.sampler,
+		})
+		req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+		req.Header.Set("Accept", "text/plain")
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: 1, Span: 2})
+		if test.header != "" {
+			req.Header.Set(HeaderDetail, test.header)
+		}
+
+		// The handler makes an outbound request, which carries the
+		// detail level and is recorded at it.
+		var outbound http.Header
+		mw(httptest.NewRecorder(), req, func(w http.ResponseWriter, r *http.Request) {
+			transport := &Transport{
+				Recorder: appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 2}, appdash.NewLocalCollector(ms)),
+				Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					outbound = req.Header
+					return &http.Response{StatusCode: 200, Header: http.Header{"Server": {"x"}}, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+				}),
+			}
+			out, _ := http.NewRequest("GET", "http://example.com/bar", nil)
+			transport.RoundTrip(out.WithContext(r.Context()))
+			w.WriteHeader(http.StatusTeapot)
+		})
+
+		if got := outbound.Get(HeaderDetail); got != test.wantDetail.String() {
+			t.Errorf("%s: got outbound detail header %q, want %q", label, got, test.wantDetail)
+		}
+		trace, err := ms.Trace(1)
+		if err != nil {
+			t.Fatalf("%s: %s", label, err)
+		}
+		server, client := trace.Span.Annotations, trace.Sub[0].Span.Annotations
+		for _, key := range []string{"Server.Request.Method", "Server.Route", "Server.Response.StatusCode", "Server.Recv", "Server.Send"} {
+			if _, ok := server.Get(key); !ok {
+				t.Errorf("%s: server span has no %s annotation", label, key)
+			}
+		}
+		for _, key := range []string{"Client.Request.Method", "Client.Response.StatusCode"} {
+			if _, ok := client.Get(key); !ok {
+				t.Errorf("%s: client span has no %s annotation", label, key)
+			}
+		}
+		wantHeaders := test.wantDetail == FullDetail
+		for _, key := range []string{"Server.Request.Headers.Accept", "Server.Response.Headers.Span-Id"} {
+			if _, ok := server.Get(key); ok != wantHeaders {
+				t.Errorf("%s: got server span annotation %s %v, want %v", label, key, ok, wantHeaders)
+			}
+		}
+		for _, key := range []string{"Client.Request.Headers.Span-Id", "Client.Response.Headers.Server"} {
+			if _, ok := client.Get(key); ok != wantHeaders {
+				t.Errorf("%s: got client span annotation %s %v, want %v", label, key, ok, wantHeaders)
+			}
+		}
+	}
+}
+
+func TestMiddleware_sampledHeader(t *testing.T) {
+	tests := []struct {
+		header       string // inbound sampled header; "" for none
+		localSample  bool   // decision of the Sampler
+		wantSampled  bool
+		wantConsults int // calls to the Sampler
+	}{
+		{"1", false, true, 0},
+		{"0", true, false, 0},
+		{"", true, true, 1},
+		{"", false, false, 1},
+		{"bogus", true, true, 1},
+	}
+	for _, test := range tests {
+		label := fmt.Sprintf("header %q, local decision %v", test.header, test.localSample)
+		ms := appdash.NewMemoryStore()
+		consults := 0
+		mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{
+			Sampler: appdash.SamplerFunc(func(span appdash.SpanID, as appdash.Annotations) bool {
+				consults++
+				return test.localSample
+			}),
+		})
+
+		req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: 1, Span: 2})
+		if test.header != "" {
+			req.Header.Set(HeaderSampled, test.header)
+		}
+
+		// The handler makes an outbound request, which carries the
+		// decision and is recorded alike.
+		var outbound http.Header
+		w := httptest.NewRecorder()
+		mw(w, req, func(w http.ResponseWriter, r *http.Request) {
+			transport := &Transport{
+				Recorder: appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 2}, appdash.NewLocalCollector(ms)),
+				Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					outbound = req.Header
+					return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+				}),
+			}
+			out, _ := http.NewRequest("GET", "http://example.com/bar", nil)
+			transport.RoundTrip(out.WithContext(r.Context()))
+		})
+
+		if consults != test.wantConsults {
+			t.Errorf("%s: got %d calls to the Sampler, want %d", label, consults, test.wantConsults)
+		}
+		want := "0"
+		if test.wantSampled {
+			want = "1"
+		}
+		if got := outbound.Get(HeaderSampled); got != want {
+			t.Errorf("%s: got outbound sampled header %q, want %q", label, got, want)
+		}
+		if got := w.Header().Get(HeaderSampled); got != want {
+			t.Errorf("%s: got response sampled header %q, want %q", label, got, want)
+		}
+
+		trace, err := ms.Trace(1)
+		if !test.wantSampled {
+			if err != appdash.ErrTraceNotFound {
+				t.Errorf("%s: got error %v, want the trace not to be collected", label, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", label, err)
+			continue
+		}
+		if v, _ := trace.Span.Annotations.Get(SampledKey); string(v) != "1" {
+			t.Errorf("%s: stored span has no %s annotation", label, SampledKey)
+		}
+		if len(trace.Sub) != 1 {
+			t.Errorf("%s: got %d outbound request spans, want 1", label, len(trace.Sub))
+		}
+	}
+}
+
+func TestMiddleware_sampledHeaderWrittenResponse(t *testing.T) {
+	for _, sample := range []bool{true, false} {
+		mw := Middleware(appdash.NewLocalCollector(appdash.NewMemoryStore()), &MiddlewareConfig{
+			Sampler: appdash.SamplerFunc(func(appdash.SpanID, appdash.Annotations) bool { return sample }),
+		})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mw(w, r, func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, "body")
+			})
+		}))
+
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("local decision %v: %s", sample, err)
+		}
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		srv.Close()
+
+		want := "0"
+		if sample {
+			want = "1"
+		}
+		if got := resp.Header.Get(HeaderSampled); got != want {
+			t.Errorf("local decision %v: got response sampled header %q, want %q", sample, got, want)
+		}
+	}
+}
+
+func TestMiddleware_inheritedSampling(t *testing.T) {
+	const services = 4
+	for _, edgeSamples := range []bool{true, false} {
+		label := fmt.Sprintf("edge decision %v", edgeSamples)
+		ms := appdash.NewMemoryStore()
+		c := appdash.NewLocalCollector(ms)
+
+		// Only the edge may sample the trace out; every other service
+		// would drop it if it decided for itself.
+		consults := make([]int, services)
+		handlers := make([]http.HandlerFunc, services)
+		for i := services - 1; i >= 0; i-- {
+			i := i
+			decision := i == 0 && edgeSamples
+			var span appdash.SpanID
+			mw := Middleware(c, &MiddlewareConfig{
+				Sampler: appdash.SamplerFunc(func(appdash.SpanID, appdash.Annotations) bool {
+					consults[i]++
+					return decision
+				}),
+				SetContextSpan: func(r *http.Request, id appdash.SpanID) { span = id },
+			})
+			handlers[i] = func(w http.ResponseWriter, r *http.Request) {
+				mw(w, r, func(w http.ResponseWriter, r *http.Request) {
+					if i == services-1 {
+						return
+					}
+					transport := &Transport{
+						Recorder: appdash.NewRecorder(span, c),
+						Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+							w := httptest.NewRecorder()
+							handlers[i+1](w, req)
+							return w.Result(), nil
+						}),
+					}
+					out, _ := http.NewRequest("GET", fmt.Sprintf("http://service%d/", i+1), nil)
+					resp, err := transport.RoundTrip(out.WithContext(r.Context()))
+					if err != nil {
+						t.Errorf("%s: service %d: %s", label, i, err)
+						return
+					}
+					resp.Body.Close()
+				})
+			}
+		}
+
+		req, _ := http.NewRequest("GET", "http://service0/", nil)
+		handlers[0](httptest.NewRecorder(), req)
+
+		if consults[0] != 1 {
+			t.Errorf("%s: got %d calls to the edge's Sampler, want 1", label, consults[0])
+		}
+		for i := 1; i < services; i++ {
+			if consults[i] != 0 {
+				t.Errorf("%s: got %d calls to the Sampler of service %d, want 0", label, consults[i], i)
+			}
+		}
+
+		traces, err := ms.Traces(appdash.TracesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !edgeSamples {
+			if len(traces) != 0 {
+				t.Errorf("%s: got %d traces, want none", label, len(traces))
+			}
+			continue
+		}
+		if len(traces) != 1 {
+			t.Fatalf("%s: got %d traces, want 1", label, len(traces))
+		}
+
+		// Every service's server span is in the trace.
+		var servers []string
+		var walk func(*appdash.Trace)
+		walk = func(trace *appdash.Trace) {
+			if host, ok := trace.Span.Annotations.Get("Server.Request.Host"); ok {
+				servers = append(servers, string(host))
+			}
+			for _, sub := range trace.Sub {
+				walk(sub)
+			}
+		}
+		walk(traces[0])
+		sort.Strings(servers)
+		if want := []string{"service0", "service1", "service2", "service3"}; !reflect.DeepEqual(servers, want) {
+			t.Errorf("%s: got server spans for %q, want %q", label, servers, want)
+		}
+	}
+}
+
+func TestMiddleware_emitSpanID(t *testing.T) {
+	span := appdash.SpanID{Trace: 1, Span: 2}
+	var emitted appdash.SpanID
+	tests := map[string]struct {
+		emit                    SpanIDEmitter
+		wantHeader, wantTrailer string
+	}{
+		"default": {
+			wantHeader: span.String(),
+		},
+		"header": {
+			emit:       EmitSpanIDHeader,
+			wantHeader: span.String(),
+		},
+		"trailer": {
+			emit:        EmitSpanIDTrailer,
+			wantTrailer: span.String(),
+		},
+		"callback": {
+			emit: func(w http.ResponseWriter, r *http.Request, span appdash.SpanID) { emitted = span },
+		},
+	}
+	for label, test := range tests {
+		emitted = appdash.SpanID{}
+		mw := Middleware(appdash.NewLocalCollector(appdash.NewMemoryStore()), &MiddlewareConfig{
+			EmitSpanID: test.emit,
+		})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mw(w, r, func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, "body")
+			})
+		}))
+
+		req, _ := http.NewRequest("GET", srv.URL, nil)
+		SetSpanIDHeader(req.Header, span)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %s", label, err)
+		}
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		srv.Close()
+
+		if got := resp.Header.Get(HeaderSpanID); got != test.wantHeader {
+			t.Errorf("%s: got span ID header %q, want %q", label, got, test.wantHeader)
+		}
+		if got := resp.Trailer.Get(HeaderSpanID); got != test.wantTrailer {
+			t.Errorf("%s: got span ID trailer %q, want %q", label, got, test.wantTrailer)
+		}
+		if label == "callback" && emitted != span {
+			t.Errorf("%s: got span ID %v passed to the callback, want %v", label, emitted, span)
+		}
+	}
+}
+
+func TestMiddleware_correlationID(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{
+		CorrelationIDHeader: "X-Request-Id",
+	})
+	for trace, requestID := range map[appdash.ID]string{1: "req-1", 2: ""} {
+		req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: trace, Span: 1})
+		if requestID != "" {
+			req.Header.Set("X-Request-Id", requestID)
+		}
+		mw(httptest.NewRecorder(), req, func(http.ResponseWriter, *http.Request) {})
+	}
+
+	traces, err := ms.Traces(appdash.TracesOpts{CorrelationID: "req-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(traces) != 1 || traces[0].ID.Trace != 1 {
+		t.Fatalf("got traces %v, want just trace 1", traces)
+	}
+	if got, want := traces[0].CorrelationIDs(), []string{"req-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got correlation IDs %q, want %q", got, want)
+	}
+	trace, err := ms.Trace(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := trace.CorrelationIDs(); len(got) != 0 {
+		t.Errorf("got correlation IDs %q for a request without the header, want none", got)
+	}
+}
+
+func TestMiddleware_clientIP(t *testing.T) {
+	tests := []struct {
+		trusted    []string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		// Off by default.
+		{nil, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}, ""},
+		// Headers from untrusted peers are ignored.
+		{[]string{"10.0.0.1"}, "5.6.7.8:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "5.6.7.8"},
+		// No headers from a trusted peer.
+		{[]string{"10.0.0.1"}, "10.0.0.1:1234", nil, "10.0.0.1"},
+		{[]string{"10.0.0.0/8"}, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "1.2.3.4"},
+		{[]string{"10.0.0.0/8"}, "10.0.0.1:1234", map[string]string{"X-Real-IP": "1.2.3.4"}, "1.2.3.4"},
+		// Addresses prepended by the client are not trusted.
+		{[]string{"10.0.0.0/8"}, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "9.9.9.9, 1.2.3.4, 10.0.0.2"}, "1.2.3.4"},
+		{[]string{"10.0.0.0/8"}, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{[]string{"10.0.0.0/8"}, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "bogus"}, "10.0.0.1"},
+		{[]string{"::1"}, "[::1]:1234", map[string]string{"X-Forwarded-For": "2001:db8::1"}, "2001:db8::1"},
+	}
+	for _, test := range tests {
+		ms := appdash.NewMemoryStore()
+		mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{TrustedProxies: test.trusted})
+		req, _ := http.NewRequest("GET", "http://example.com/", nil)
+		req.RemoteAddr = test.remoteAddr
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: 1, Span: 1})
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		mw(httptest.NewRecorder(), req, func(http.ResponseWriter, *http.Request) {})
+
+		trace, err := ms.Trace(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e ServerEvent
+		if err := appdash.UnmarshalEvent(trace.Span.Annotations, &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Request.ClientIP != test.want {
+			t.Errorf("trusted %v, peer %s, headers %v: got client IP %q, want %q", test.trusted, test.remoteAddr, test.headers, e.Request.ClientIP, test.want)
+		}
+		if e.Request.RemoteAddr != test.remoteAddr {
+			t.Errorf("got remote address %q, want %q", e.Request.RemoteAddr, test.remoteAddr)
+		}
+	}
+}
+
+func TestMiddleware_contentType(t *testing.T) {
+	defer func(strip bool) { StripContentTypeParams = strip }(StripContentTypeParams)
+	tests := []struct {
+		strip        bool
+		req, resp    string
+		wantReq      string
+		wantResponse string
+	}{
+		{true, "multipart/form-data; boundary=xyz", "text/HTML; charset=UTF-8", "multipart/form-data", "text/html"},
+		{false, "multipart/form-data; boundary=xyz", "text/HTML; charset=UTF-8", "multipart/form-data; boundary=xyz", "text/html; charset=UTF-8"},
+		{true, "", "bogus;;", "", "bogus;;"},
+	}
+	for _, test := range tests {
+		StripContentTypeParams = test.strip
+		ms := appdash.NewMemoryStore()
+		mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{})
+		req, _ := http.NewRequest("POST", "http://example.com/upload", nil)
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: 1, Span: 1})
+		if test.req != "" {
+			req.Header.Set("Content-Type", test.req)
+		}
+		mw(httptest.NewRecorder(), req, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", test.resp)
+		})
+
+		trace, err := ms.Trace(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e ServerEvent
+		if err := appdash.UnmarshalEvent(trace.Span.Annotations, &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Request.ContentType != test.wantReq || e.Response.ContentType != test.wantResponse {
+			t.Errorf("strip %v, %q and %q: got content types %q and %q, want %q and %q", test.strip, test.req, test.resp, e.Request.ContentType, e.Response.ContentType, test.wantReq, test.wantResponse)
+		}
+
+		// The content types can be queried like any other annotation.
+		n, err := ms.DeleteWhere(appdash.AnnotationFilter("Server.Response.ContentType", test.wantResponse))
+		if err != nil || n != 1 {
+			t.Errorf("got %d traces (error %v) matching the response content type, want 1", n, err)
+		}
+	}
+}
+
+func TestMiddleware_asyncCollector(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	release := make(chan struct{})
+	slow := appdash.NewLocalCollector(ms)
+	ac := appdash.NewAsyncCollector(collectorFunc(func(span appdash.SpanID, anns ...appdash.Annotation) error {
+		<-release
+		return slow.Collect(span, anns...)
+	}))
+	mw := Middleware(ac, &MiddlewareConfig{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: 1, Span: 1})
+		mw(httptest.NewRecorder(), req, func(http.ResponseWriter, *http.Request) {})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("response was delayed by the slow collector")
+	}
+
+	close(release)
+	ac.Close()
+	if _, err := ms.Trace(1); err != nil {
+		t.Errorf("span was not collected after Close: %s", err)
+	}
+}
+
+// collectorFunc implements the appdash.Collector interface by calling the
+// function.
+type collectorFunc func(appdash.SpanID, ...appdash.Annotation) error
+
+func (f collectorFunc) Collect(span appdash.SpanID, anns ...appdash.Annotation) error {
+	return f(span, anns...)
+}
+
+func TestMiddleware_routeParams(t *testing.T) {
+	defer func(orig []string) { RedactedRouteParams = orig }(RedactedRouteParams)
+	RedactedRouteParams = []string{"Token"}
+
+	ms := appdash.NewMemoryStore()
+	mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{
+		RouteName: func(r *http.Request) string { return "user" },
+		RouteParams: func(r *http.Request) map[string]string {
+			return map[string]string{"id": "42", "token": "s3cret"}
+		},
+	})
+	req, _ := http.NewRequest("GET", "http://example.com/users/42/s3cret", nil)
+	SetSpanIDHeader(req.Header, appdash.SpanID{Trace: 1, Span: 1})
+	mw(httptest.NewRecorder(), req, func(http.ResponseWriter, *http.Request) {})
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anns := trace.Span.Annotations.StringMap()
+	if got, want := anns["Server.Request.RouteParam.id"], "42"; got != want {
+		t.Errorf("got id param %q, want %q", got, want)
+	}
+	if got, want := anns["Server.Request.RouteParam.token"], "REDACTED"; got != want {
+		t.Errorf("got token param %q, want %q", got, want)
+	}
+
+	var e ServerEvent
+	if err := appdash.UnmarshalEvent(trace.Span.Annotations, &e); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"id": "42", "token": "REDACTED"}
+	if !reflect.DeepEqual(e.Request.RouteParams, want) {
+		t.Errorf("got unmarshaled route params %v, want %v", e.Request.RouteParams, want)
+	}
+}
+
+func TestMiddleware_recordPanics(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	c := appdash.NewLocalCollector(ms)
+
+	req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+	SetSpanIDHeader(req.Header, appdash.SpanID{1, 2, 3})
+
+	mw := Middleware(c, &MiddlewareConfig{RecordPanics: true})
+	w := httptest.NewRecorder()
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Errorf("got panic value %v, want %q", v, "boom")
+			}
+		}()
+		mw(w, req, func(http.ResponseWriter, *http.Request) { panic("boom") })
+	}()
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("got response status %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pe PanicEvent
+	if err := appdash.UnmarshalEvent(trace.Span.Annotations, &pe); err != nil {
+		t.Fatal(err)
+	}
+	if pe.Value != "boom" {
+		t.Errorf("got panic value %q, want %q", pe.Value, "boom")
+	}
+	if !strings.Contains(pe.Stack, "TestMiddleware_recordPanics") {
+		t.Errorf("got stack %q, want it to include the test function", pe.Stack)
+	}
+	var se ServerEvent
+	if err := appdash.UnmarshalEvent(trace.Span.Annotations, &se); err != nil {
+		t.Fatal(err)
+	}
+	if se.Response.StatusCode != http.StatusInternalServerError {
+		t.Errorf("got recorded status %d, want %d", se.Response.StatusCode, http.StatusInternalServerError)
+	}
+}
+
+func TestMiddleware_canceled(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	mw := Middleware(appdash.NewLocalCollector(ms), &MiddlewareConfig{CanceledStatusCode: 499})
+
+	for _, cancelInHandler := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, _ := http.NewRequest("GET", "http://example.com/foo", nil)
+		req = req.WithContext(ctx)
+		trace := appdash.ID(1)
+		if cancelInHandler {
+			trace = 2
+		}
+		SetSpanIDHeader(req.Header, appdash.SpanID{Trace: trace, Span: 1})
+		mw(httptest.NewRecorder(), req, func(w http.ResponseWriter, r *http.Request) {
+			if cancelInHandler {
+				cancel() // the client disconnects
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		cancel()
+	}
+
+	completed, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ce CanceledEvent
+	if err := appdash.UnmarshalEvent(completed.Span.Annotations, &ce); err == nil {
+		t.Errorf("got %+v for a completed request, want no CanceledEvent", ce)
+	}
+
+	canceled, err := ms.Trace(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := appdash.UnmarshalEvent(canceled.Span.Annotations, &ce); err != nil {
+		t.Fatal(err)
+	}
+	if want := (CanceledEvent{Canceled: true, Error: context.Canceled.Error()}); ce != want {
+		t.Errorf("got %+v, want %+v", ce, want)
+	}
+	var se ServerEvent
+	if err := appdash.UnmarshalEvent(canceled.Span.Annotations, &se); err != nil {
+		t.Fatal(err)
+	}
+	if se.Response.StatusCode != 499 {
+		t.Errorf("got recorded status %d, want 499", se.Response.StatusCode)
+	}
+	if p := canceled.Span.SamplingPriority(); p != 0 {
+		t.Errorf("got sampling priority %d for a canceled request, want 0", p)
 	}
 }
 
@@ -214,6 +989,76 @@ func TestServerEvent_unmarshal(t *testing.T) {
 	}
 }
 
+func TestServerEvent_annotationTypes(t *testing.T) {
+	const key = "Server.Response.StatusCode"
+	if got := appdash.AnnotationTypeOf(key); got != appdash.NumberAnnotation {
+		t.Fatalf("got %s type %v, want %v", key, got, appdash.NumberAnnotation)
+	}
+	if c := appdash.CompareAnnotationValues(key, []byte("100"), []byte("20")); c <= 0 {
+		t.Errorf("got %s 100 <= 20, want 100 > 20", key)
+	}
+	if got := appdash.AnnotationTypeOf("Server.Recv"); got != appdash.TimeAnnotation {
+		t.Errorf("got Server.Recv type %v, want %v", got, appdash.TimeAnnotation)
+	}
+}
+
+func TestServerEvent_statusClass(t *testing.T) {
+	orig := appdash.SchemaValidation
+	appdash.SchemaValidation = appdash.ValidationError
+	defer func() { appdash.SchemaValidation = orig }()
+
+	for code, want := range map[int]string{503: "5xx", 200: "2xx", 404: "4xx", 0: ""} {
+		if got := StatusClass(code); got != want {
+			t.Errorf("StatusClass(%d): got %q, want %q", code, got, want)
+		}
+
+		e := NewServerEvent(&http.Request{URL: &url.URL{Path: "/"}})
+		e.Response.StatusCode = code
+		anns, err := appdash.MarshalEvent(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := appdash.ValidateEvent(e.Schema(), anns); err != nil {
+			t.Errorf("status %d: %s", code, err)
+		}
+		v, present := anns.Get("Server.Response.StatusClass")
+		if got := string(v); got != want || present != (want != "") {
+			t.Errorf("status %d: got Server.Response.StatusClass %q (present: %v), want %q", code, v, present, want)
+		}
+
+		// The derived annotation does not disturb unmarshaling.
+		var e2 ServerEvent
+		if err := appdash.UnmarshalEvent(anns, &e2); err != nil {
+			t.Fatal(err)
+		}
+		if e2.Response.StatusCode != code {
+			t.Errorf("status %d: got status %d after unmarshaling", code, e2.Response.StatusCode)
+		}
+	}
+}
+
+// testTime is the time at which a testClock starts.
+var testTime = time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testClock is a deterministic appdash.Clock which advances by one second
+// each time it is read.
+type testClock struct{ t time.Time }
+
+func mustMarshalEvent(t *testing.T, e appdash.Event) appdash.Annotations {
+	as, err := appdash.MarshalEvent(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return as
+}
+
+func newTestClock() *testClock { return &testClock{t: testTime} }
+
+func (c *testClock) Now() time.Time {
+	c.t = c.t.Add(time.Second)
+	return c.t
+}
+
 func mapToAnnotations(m map[string]string) appdash.Annotations {
 	anns := make(appdash.Annotations, 0, len(m))
 	for k, v := range m {
@@ -9,13 +9,16 @@ import (
 	"io"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
 // An ID is a unique, uniformly distributed 64-bit ID.
 type ID uint64
 
-// String returns the ID as a hex string.
+// String returns the ID as a hex string. It is always zero-padded to the full
+// 16 hex digits of a 64-bit ID, so that external systems may rely on its
+// width.
 func (id ID) String() string {
 	return fmt.Sprintf("%016x", uint64(id))
 }
@@ -41,7 +44,8 @@ func (id *ID) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("%s is not a valid ID", data)
 }
 
-// ParseID parses the given string as a hexadecimal string.
+// ParseID parses the given string as a hexadecimal string. Both the
+// zero-padded form returned by String and unpadded forms are accepted.
 func ParseID(s string) (ID, error) {
 	i, err := strconv.ParseUint(s, 16, 64)
 	if err != nil {
@@ -50,55 +54,125 @@ func ParseID(s string) (ID, error) {
 	return ID(i), nil
 }
 
-// generateID returns a randomly-generated 64-bit ID. This function is
-// thread-safe.  IDs are produced by consuming an AES-CTR-128 keystream in
-// 64-bit chunks. The AES key is randomly generated on initialization, as is the
-// counter's initial state. On machines with AES-NI support, ID generation takes
-// ~30ns and generates no garbage.
-func generateID() ID {
-	m.Lock()
-	if n == aes.BlockSize {
-		c.Encrypt(b, ctr)
+// An IDGenerator generates the random IDs of new spans and traces (see
+// NewRootSpanID and NewSpanID). Implementations must be safe for
+// concurrent use.
+//
+// Since IDs are 64-bit, some collisions are unavoidable even between
+// perfectly random IDs: among n IDs, the probability of any two being
+// equal is about n²/2⁶⁵, i.e. one in a million for 6 million traces, and
+// one in a hundred for 600 million. Generators differ in how close they
+// come to that ideal when processes are cloned or started in large
+// numbers; see AESIDGenerator and CryptoIDGenerator.
+type IDGenerator interface {
+	NewID() ID
+}
+
+// An AESIDGenerator generates IDs by consuming an AES-CTR-128 keystream in
+// 64-bit chunks, keyed and with an initial counter read from crypto/rand
+// when it is created. On machines with AES-NI support, ID generation takes
+// ~30ns and generates no garbage. It is the default IDGenerator.
+//
+// Its IDs are as collision-resistant as truly random ones, unless its
+// state is duplicated after it is created, as when a process is forked or
+// a VM or container is restored from a snapshot: every copy then generates
+// the same IDs. Use CryptoIDGenerator in such environments.
+type AESIDGenerator struct {
+	mu  sync.Mutex
+	c   cipher.Block
+	ctr []byte // counter
+	b   []byte // current block of keystream
+	n   int    // offset of the next ID in b
+}
+
+// NewAESIDGenerator returns a new AESIDGenerator with a random key and
+// initial counter.
+func NewAESIDGenerator() *AESIDGenerator {
+	buf := make([]byte, keySize+aes.BlockSize)
+	_, err := io.ReadFull(rand.Reader, buf)
+	if err != nil {
+		panic(err) // /dev/urandom had better work
+	}
+	c, err := aes.NewCipher(buf[:keySize])
+	if err != nil {
+		panic(err) // AES had better work
+	}
+	return &AESIDGenerator{
+		c:   c,
+		ctr: buf[keySize:],
+		b:   make([]byte, aes.BlockSize),
+		n:   aes.BlockSize,
+	}
+}
+
+// NewID implements the IDGenerator interface.
+func (g *AESIDGenerator) NewID() ID {
+	g.mu.Lock()
+	if g.n == aes.BlockSize {
+		g.c.Encrypt(g.b, g.ctr)
 		for i := aes.BlockSize - 1; i >= 0; i-- { // increment ctr
-			ctr[i]++
-			if ctr[i] != 0 {
+			g.ctr[i]++
+			if g.ctr[i] != 0 {
 				break
 			}
 		}
-		n = 0
+		g.n = 0
 	}
-	id := *(*ID)(unsafe.Pointer(&b[n])) // zero-copy b/c we're arch-neutral
-	n += idSize
-	m.Unlock()
+	id := *(*ID)(unsafe.Pointer(&g.b[g.n])) // zero-copy b/c we're arch-neutral
+	g.n += idSize
+	g.mu.Unlock()
 	return id
 }
 
+// CryptoIDGenerator is an IDGenerator that reads each ID from crypto/rand,
+// i.e. from the operating system's random number generator. It keeps no
+// state of its own, so unlike AESIDGenerator it never repeats IDs in
+// forked or cloned processes, at the cost of a read from the operating
+// system per ID, which is several times slower than the default.
+var CryptoIDGenerator IDGenerator = cryptoIDGenerator{}
+
+type cryptoIDGenerator struct{}
+
+func (cryptoIDGenerator) NewID() ID {
+	var b [idSize]byte
+	if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+		panic(err) // /dev/urandom had better work
+	}
+	return *(*ID)(unsafe.Pointer(&b[0]))
+}
+
 const (
 	idSize  = aes.BlockSize / 2 // 64 bits
 	keySize = aes.BlockSize     // 128 bits
 )
 
-var (
-	ctr []byte
-	n   int
-	b   []byte
-	c   cipher.Block
-	m   sync.Mutex
-)
+// idGenerator holds the current IDGenerator, wrapped in an
+// idGeneratorValue so that generators of any type may be stored.
+var idGenerator atomic.Value
+
+type idGeneratorValue struct{ IDGenerator }
 
 func init() {
-	buf := make([]byte, keySize+aes.BlockSize)
-	_, err := io.ReadFull(rand.Reader, buf)
-	if err != nil {
-		panic(err) // /dev/urandom had better work
-	}
-	c, err = aes.NewCipher(buf[:keySize])
-	if err != nil {
-		panic(err) // AES had better work
+	idGenerator.Store(idGeneratorValue{NewAESIDGenerator()})
+}
+
+// SetIDGenerator sets the IDGenerator used for the IDs of new spans and
+// traces, e.g. to CryptoIDGenerator. It is typically called once at
+// startup, but is safe to call concurrently with ID generation.
+func SetIDGenerator(g IDGenerator) {
+	idGenerator.Store(idGeneratorValue{g})
+}
+
+// generateID returns a new, non-zero ID from the current IDGenerator. This
+// function is thread-safe.
+func generateID() ID {
+	g := idGenerator.Load().(idGeneratorValue)
+	for {
+		// Zero is reserved for the parent of root spans.
+		if id := g.NewID(); id != 0 {
+			return id
+		}
 	}
-	n = aes.BlockSize
-	ctr = buf[keySize:]
-	b = make([]byte, aes.BlockSize)
 }
 
 func parseJSONString(data []byte) (ID, error) {
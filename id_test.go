@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -72,6 +73,59 @@ func TestIDGeneration(t *testing.T) {
 	}
 }
 
+func TestCryptoIDGenerator_concurrent(t *testing.T) {
+	const goroutines, perGoroutine = 32, 10000
+	results := make(chan []ID, goroutines)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]ID, perGoroutine)
+			for i := range ids {
+				ids[i] = CryptoIDGenerator.NewID()
+			}
+			results <- ids
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[ID]bool, goroutines*perGoroutine)
+	for ids := range results {
+		for _, id := range ids {
+			if seen[id] {
+				t.Errorf("duplicate ID: %v", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func TestSetIDGenerator(t *testing.T) {
+	defer SetIDGenerator(idGenerator.Load().(idGeneratorValue).IDGenerator)
+
+	// The zero ID is skipped.
+	var next ID
+	SetIDGenerator(idGeneratorFunc(func() ID {
+		id := next
+		next++
+		return id
+	}))
+	if id := NewRootSpanID(); id != (SpanID{Trace: 1, Span: 2}) {
+		t.Errorf("got root span ID %v, want IDs from the generator", id)
+	}
+
+	SetIDGenerator(CryptoIDGenerator)
+	if id := NewSpanID(SpanID{Trace: 1, Span: 2}); id.Span == 0 || id.Span == 3 {
+		t.Errorf("got span ID %v, want one from CryptoIDGenerator", id)
+	}
+}
+
+type idGeneratorFunc func() ID
+
+func (f idGeneratorFunc) NewID() ID { return f() }
+
 func TestParseID(t *testing.T) {
 	want := ID(10018181901)
 	got, err := ParseID(want.String())
@@ -83,6 +137,38 @@ func TestParseID(t *testing.T) {
 	}
 }
 
+func TestIDString_fixedWidth(t *testing.T) {
+	tests := map[ID]string{
+		0:                  "0000000000000000",
+		1:                  "0000000000000001",
+		0xabc:              "0000000000000abc",
+		0xffffffffffffffff: "ffffffffffffffff",
+	}
+	for id, want := range tests {
+		if got := id.String(); got != want {
+			t.Errorf("%d: got %q, want %q", uint64(id), got, want)
+		}
+	}
+
+	spanID := SpanID{Trace: 1, Span: 2, Parent: 3}
+	if got, want := spanID.String(), "0000000000000001/0000000000000002/0000000000000003"; got != want {
+		t.Errorf("got SpanID %q, want %q", got, want)
+	}
+}
+
+func TestParseID_unpadded(t *testing.T) {
+	for _, s := range []string{"abc", "0abc", "0000000000000abc"} {
+		got, err := ParseID(s)
+		if err != nil {
+			t.Errorf("%q: %s", s, err)
+			continue
+		}
+		if want := ID(0xabc); got != want {
+			t.Errorf("%q: got %v, want %v", s, got, want)
+		}
+	}
+}
+
 func TestParseIDError(t *testing.T) {
 	id, err := ParseID("woo")
 	if err == nil {
@@ -95,3 +181,9 @@ func BenchmarkIDGeneration(b *testing.B) {
 		generateID()
 	}
 }
+
+func BenchmarkCryptoIDGenerator(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		CryptoIDGenerator.NewID()
+	}
+}
@@ -0,0 +1,69 @@
+package appdash
+
+import "sort"
+
+// indexedKeys are the keys of the annotations that MemoryStore indexes
+// traces by, so that the TracesOpts filters on them (Service and
+// Environment) and IndexedValues need not scan every trace. They tag spans
+// with low-cardinality names.
+var indexedKeys = []string{ServiceKey, EnvironmentKey}
+
+// isIndexedKey reports whether key is one of indexedKeys.
+func isIndexedKey(key string) bool {
+	for _, k := range indexedKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// AnnotationValues returns the sorted values of the annotations with the
+// given key on any of t's spans, without duplicates. For example,
+// AnnotationValues(ServiceKey) returns the names of the services that a
+// trace touches, and AnnotationValues(EnvironmentKey) those of the
+// environments it belongs to: normally there is just one, but a trace
+// whose spans were recorded in several environments (e.g. a staging
+// service calling a production one) belongs to each of them.
+func (t *Trace) AnnotationValues(key string) []string {
+	seen := map[string]struct{}{}
+	var values []string
+	t.Walk(func(span *Span, depth int) error {
+		for _, a := range span.Annotations {
+			if a.Key != key {
+				continue
+			}
+			if _, dup := seen[string(a.Value)]; !dup {
+				seen[string(a.Value)] = struct{}{}
+				values = append(values, string(a.Value))
+			}
+		}
+		return nil
+	})
+	sort.Strings(values)
+	return values
+}
+
+// filterByValue returns the traces with a span that has an annotation
+// with the given key and value. It modifies traces in place.
+func filterByValue(traces []*Trace, key, value string) []*Trace {
+	filtered := traces[:0]
+	for _, t := range traces {
+		for _, v := range t.AnnotationValues(key) {
+			if v == value {
+				filtered = append(filtered, t)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// withDefault returns anns with a appended, unless anns already has an
+// annotation with a's key. It does not modify anns.
+func withDefault(anns []Annotation, a Annotation) []Annotation {
+	if Annotations(anns).has(a.Key) {
+		return anns
+	}
+	return append(anns[:len(anns):len(anns)], a)
+}
@@ -214,6 +214,9 @@ func (in *InfluxDBStore) Trace(id ID) (*Trace, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(spans) == 0 {
+		return nil, ErrTraceNotFound
+	}
 
 	// Iterate over spans to find and set `trace`'s root span & append children spans as sub-traces to `children` for later usage.
 	for _, span := range spans {
@@ -231,6 +234,13 @@ func (in *InfluxDBStore) Trace(id ID) (*Trace, error) {
 			children = append(children, &Trace{Span: *span})
 		}
 	}
+	if !rootSpanSet {
+		// The root span has not been collected (yet), so like MemoryStore use
+		// another span as a temporary root rather than returning an empty
+		// trace.
+		trace.Span = children[0].Span
+		children = children[1:]
+	}
 	if err := addChildren(trace, children); err != nil {
 		return nil, err
 	}
@@ -443,6 +453,18 @@ func (in *InfluxDBStore) Traces(opts TracesOpts) ([]*Trace, error) {
 		}
 		traces = append(traces, trace)
 	}
+	if opts.CorrelationID != "" {
+		traces = filterCorrelated(traces, opts.CorrelationID)
+	}
+	if opts.Service != "" {
+		traces = filterByValue(traces, ServiceKey, opts.Service)
+	}
+	if opts.Environment != "" {
+		traces = filterByValue(traces, EnvironmentKey, opts.Environment)
+	}
+	if opts.SortByRecency {
+		SortTracesByRecency(traces)
+	}
 	return traces, nil
 }
 
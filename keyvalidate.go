@@ -0,0 +1,153 @@
+package appdash
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// A KeyPolicy determines what a KeyValidatingCollector does with the
+// annotations whose keys are invalid.
+type KeyPolicy int
+
+const (
+	// DropInvalidKeys drops the annotations with invalid keys, and passes
+	// on the rest of the span.
+	DropInvalidKeys KeyPolicy = iota
+
+	// RewriteInvalidKeys rewrites invalid keys (see
+	// KeyValidatingCollector.Rewrite). Annotations whose rewritten keys
+	// are still invalid are dropped.
+	RewriteInvalidKeys
+
+	// RejectInvalidKeys rejects the whole collection, returning an error,
+	// if any of its annotations has an invalid key.
+	RejectInvalidKeys
+)
+
+// A KeyValidatingCollector is a Collector that enforces a naming convention
+// for annotation keys, such as a pattern that the keys of every team must
+// follow, before passing spans on. Annotations with invalid keys are
+// dropped, rewritten or rejected according to its Policy, and counted (see
+// Violations).
+//
+// Reserved keys, which begin with an underscore (e.g. the schema keys of
+// events), are always valid.
+type KeyValidatingCollector struct {
+	// Collector is the underlying collector that spans are sent to.
+	Collector
+
+	// Valid reports whether key follows the convention, such as
+	// KeyPattern's predicates do.
+	Valid func(key string) bool
+
+	// Policy determines what happens to annotations with invalid keys.
+	Policy KeyPolicy
+
+	// Rewrite, if non-nil, rewrites invalid keys under the
+	// RewriteInvalidKeys policy. By default, NormalizeKey is used.
+	Rewrite func(key string) string
+
+	violations, rejected int64 // accessed atomically
+}
+
+// NewKeyValidatingCollector is shorthand for:
+//
+// 	c := &KeyValidatingCollector{
+// 		Collector: c,
+// 		Valid:     valid,
+// 		Policy:    policy,
+// 	}
+//
+func NewKeyValidatingCollector(c Collector, valid func(key string) bool, policy KeyPolicy) *KeyValidatingCollector {
+	return &KeyValidatingCollector{Collector: c, Valid: valid, Policy: policy}
+}
+
+// KeyPattern returns a predicate for KeyValidatingCollector.Valid that
+// reports whether a key matches the regular expression expr. It panics if
+// expr does not compile.
+func KeyPattern(expr string) func(key string) bool {
+	re := regexp.MustCompile(expr)
+	return re.MatchString
+}
+
+// NormalizeKey rewrites key by replacing each run of whitespace and other
+// characters that are not letters, digits, '.', '-' or '_' with a single
+// '_'.
+func NormalizeKey(key string) string {
+	var b strings.Builder
+	replaced := false
+	for _, r := range key {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '_' {
+			b.WriteRune(r)
+			replaced = false
+		} else if !replaced {
+			b.WriteByte('_')
+			replaced = true
+		}
+	}
+	return b.String()
+}
+
+// Collect implements the Collector interface.
+func (kc *KeyValidatingCollector) Collect(span SpanID, anns ...Annotation) error {
+	var valid []Annotation // nil until an invalid key is found
+	for i, a := range anns {
+		if kc.valid(a.Key) {
+			if valid != nil {
+				valid = append(valid, a)
+			}
+			continue
+		}
+
+		if kc.Policy == RejectInvalidKeys {
+			n := int64(0)
+			for _, a := range anns[i:] {
+				if !kc.valid(a.Key) {
+					n++
+				}
+			}
+			atomic.AddInt64(&kc.violations, n)
+			atomic.AddInt64(&kc.rejected, 1)
+			return fmt.Errorf("KeyValidatingCollector: span %v has invalid annotation key %q", span, a.Key)
+		}
+		atomic.AddInt64(&kc.violations, 1)
+		if valid == nil {
+			valid = append(make([]Annotation, 0, len(anns)), anns[:i]...)
+		}
+		if kc.Policy == RewriteInvalidKeys {
+			if key := kc.rewrite(a.Key); kc.valid(key) {
+				valid = append(valid, Annotation{Key: key, Value: a.Value})
+			}
+		}
+	}
+	if valid != nil {
+		anns = valid
+	}
+	return kc.Collector.Collect(span, anns...)
+}
+
+func (kc *KeyValidatingCollector) valid(key string) bool {
+	return strings.HasPrefix(key, "_") || kc.Valid == nil || kc.Valid(key)
+}
+
+func (kc *KeyValidatingCollector) rewrite(key string) string {
+	if kc.Rewrite != nil {
+		return kc.Rewrite(key)
+	}
+	return NormalizeKey(key)
+}
+
+// Violations returns the number of annotations with invalid keys that
+// were collected, whatever the policy did with them.
+func (kc *KeyValidatingCollector) Violations() int64 {
+	return atomic.LoadInt64(&kc.violations)
+}
+
+// Rejected returns the number of collections rejected under the
+// RejectInvalidKeys policy.
+func (kc *KeyValidatingCollector) Rejected() int64 {
+	return atomic.LoadInt64(&kc.rejected)
+}
@@ -0,0 +1,89 @@
+package appdash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyValidatingCollector(t *testing.T) {
+	valid := KeyPattern(`^[A-Z][A-Za-z0-9]*(\.[A-Z][A-Za-z0-9]*)*$`)
+	conforming := Annotations{{Key: "Name", Value: []byte("a")}, {Key: "Server.Route", Value: []byte("b")}, {Key: "_schema:x"}}
+	mixed := Annotations{{Key: "Name", Value: []byte("a")}, {Key: "user id", Value: []byte("42")}, {Key: "Server.Route", Value: []byte("b")}, {Key: "bad key!", Value: []byte("c")}}
+
+	tests := []struct {
+		policy         KeyPolicy
+		rewrite        func(string) string
+		anns           Annotations
+		want           Annotations // nil if rejected
+		wantViolations int64
+	}{
+		{DropInvalidKeys, nil, conforming, conforming, 0},
+		{DropInvalidKeys, nil, mixed, Annotations{mixed[0], mixed[2]}, 2},
+		{RewriteInvalidKeys, func(key string) string { return "Team." + NormalizeKey(key) }, conforming, conforming, 0},
+		// NormalizeKey's "user_id" and "bad_key_" don't match the pattern,
+		// so are dropped.
+		{RewriteInvalidKeys, nil, mixed, Annotations{mixed[0], mixed[2]}, 2},
+		{
+			RewriteInvalidKeys,
+			func(key string) string {
+				if key == "user id" {
+					return "User.ID"
+				}
+				return key
+			},
+			mixed,
+			Annotations{mixed[0], {Key: "User.ID", Value: []byte("42")}, mixed[2]},
+			2,
+		},
+		{RejectInvalidKeys, nil, conforming, conforming, 0},
+		{RejectInvalidKeys, nil, mixed, nil, 2},
+	}
+	for i, test := range tests {
+		var got Annotations
+		kc := NewKeyValidatingCollector(collectorFunc(func(span SpanID, anns ...Annotation) error {
+			got = anns
+			return nil
+		}), valid, test.policy)
+		kc.Rewrite = test.rewrite
+
+		anns := append(Annotations(nil), test.anns...)
+		err := kc.Collect(SpanID{Trace: 1, Span: 2}, anns...)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("%d: got no error, want the span rejected", i)
+			}
+			if got != nil {
+				t.Errorf("%d: rejected span was passed on with %v", i, got)
+			}
+			if kc.Rejected() != 1 {
+				t.Errorf("%d: got %d rejected spans, want 1", i, kc.Rejected())
+			}
+		} else {
+			if err != nil {
+				t.Errorf("%d: %s", i, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%d: got annotations %v, want %v", i, got, test.want)
+			}
+		}
+		if kc.Violations() != test.wantViolations {
+			t.Errorf("%d: got %d violations, want %d", i, kc.Violations(), test.wantViolations)
+		}
+		if !reflect.DeepEqual(anns, test.anns) {
+			t.Errorf("%d: the caller's annotations were modified to %v", i, anns)
+		}
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	for key, want := range map[string]string{
+		"Server.Route":  "Server.Route",
+		"user id":       "user_id",
+		"a  \t b":       "a_b",
+		"résumé-count!": "résumé-count_",
+	} {
+		if got := NormalizeKey(key); got != want {
+			t.Errorf("NormalizeKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package appdash
+
+import (
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// A LiveReplayer replays a recording (see RecordingCollector) into a
+// collector continuously, so that a traceapp backed by a MemoryStore shows
+// realistic, recent traces without a real system to trace: for demos and
+// local development.
+//
+// Each pass through the recording collects its traces anew, under new
+// trace and span IDs, with their timestamps shifted so that each trace
+// starts at the time its first span is replayed. Only annotations of the
+// TimeAnnotation type (see RegisterAnnotationType), such as the time.Time
+// fields of registered events, are shifted.
+//
+// A LiveReplayer must be created with NewLiveReplayer.
+type LiveReplayer struct {
+	// Collector is the collector that the recording is replayed into.
+	Collector Collector
+
+	// Rate is the number of collections replayed per second. If zero,
+	// each pass is replayed at once, and passes are a second apart.
+	Rate float64
+
+	// Loop, if true, causes the recording to be replayed over and over
+	// until Stop is called. Otherwise it is replayed once.
+	Loop bool
+
+	// Clock, if non-nil, is used instead of RealClock to shift timestamps.
+	Clock Clock
+
+	// Log, if non-nil, is used to log collection errors.
+	Log *log.Logger
+
+	collections []replayedCollection
+	starts      map[ID]time.Time // trace ID -> earliest timestamp in the recording
+
+	mu       sync.Mutex
+	stopped  bool
+	stopChan chan struct{}
+}
+
+// replayedCollection is a collection read from a recording.
+type replayedCollection struct {
+	span SpanID
+	anns []Annotation
+}
+
+// NewLiveReplayer reads the recording from r, and returns a LiveReplayer
+// that replays it into c, looping at 10 collections per second.
+func NewLiveReplayer(r io.Reader, c Collector) (*LiveReplayer, error) {
+	lr := &LiveReplayer{
+		Collector: c,
+		Rate:      10,
+		Loop:      true,
+		starts:    map[ID]time.Time{},
+		stopChan:  make(chan struct{}),
+	}
+	err := Replay(r, collectorFunc(func(span SpanID, anns ...Annotation) error {
+		lr.collections = append(lr.collections, replayedCollection{span: span, anns: anns})
+		for _, a := range anns {
+			if AnnotationTypeOf(a.Key) != TimeAnnotation {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339Nano, string(a.Value))
+			if err != nil {
+				continue
+			}
+			if start, ok := lr.starts[span.Trace]; !ok || t.Before(start) {
+				lr.starts[span.Trace] = t
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if len(lr.collections) == 0 {
+		return nil, errors.New("LiveReplayer: recording is empty")
+	}
+	return lr, nil
+}
+
+func (lr *LiveReplayer) now() time.Time {
+	if lr.Clock != nil {
+		return lr.Clock.Now()
+	}
+	return RealClock.Now()
+}
+
+// Run replays the recording, returning when it is done or Stop is called.
+func (lr *LiveReplayer) Run() {
+	for {
+		if !lr.replay() || !lr.Loop {
+			return
+		}
+		if lr.Rate <= 0 && !lr.wait(time.Second) {
+			return
+		}
+	}
+}
+
+// Start calls Run in a separate goroutine.
+func (lr *LiveReplayer) Start() {
+	go lr.Run()
+}
+
+// Stop stops replaying the recording. It may be called more than once.
+func (lr *LiveReplayer) Stop() {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	if !lr.stopped {
+		close(lr.stopChan)
+		lr.stopped = true
+	}
+}
+
+// wait waits for d, and reports whether the replayer is still running.
+func (lr *LiveReplayer) wait(d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-lr.stopChan:
+		return false
+	}
+}
+
+// replay makes one pass through the recording, and reports whether it
+// finished without being stopped.
+func (lr *LiveReplayer) replay() bool {
+	traces := map[ID]ID{}             // recorded trace ID -> replayed trace ID
+	spans := map[ID]ID{}              // recorded span ID -> replayed span ID
+	offsets := map[ID]time.Duration{} // recorded trace ID -> time shift
+	newID := func(m map[ID]ID, id ID) ID {
+		if id == 0 {
+			return 0
+		}
+		if _, ok := m[id]; !ok {
+			m[id] = generateID()
+		}
+		return m[id]
+	}
+
+	for i, c := range lr.collections {
+		if i > 0 && lr.Rate > 0 && !lr.wait(time.Duration(float64(time.Second)/lr.Rate)) {
+			return false
+		}
+		select {
+		case <-lr.stopChan:
+			return false
+		default:
+		}
+
+		offset, ok := offsets[c.span.Trace]
+		if !ok {
+			if start, ok := lr.starts[c.span.Trace]; ok {
+				offset = lr.now().Sub(start)
+			}
+			offsets[c.span.Trace] = offset
+		}
+		span := SpanID{
+			Trace:  newID(traces, c.span.Trace),
+			Span:   newID(spans, c.span.Span),
+			Parent: newID(spans, c.span.Parent),
+		}
+		if err := lr.Collector.Collect(span, shiftTimes(c.anns, offset)...); err != nil && lr.Log != nil {
+			lr.Log.Printf("LiveReplayer: %s", err)
+		}
+	}
+	return true
+}
+
+// shiftTimes returns a copy of anns with the values of TimeAnnotation keys
+// shifted by d.
+func shiftTimes(anns []Annotation, d time.Duration) []Annotation {
+	shifted := make([]Annotation, len(anns))
+	for i, a := range anns {
+		shifted[i] = a
+		if AnnotationTypeOf(a.Key) != TimeAnnotation {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, string(a.Value)); err == nil {
+			shifted[i].Value = []byte(t.Add(d).Format(time.RFC3339Nano))
+		}
+	}
+	return shifted
+}
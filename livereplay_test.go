@@ -0,0 +1,78 @@
+package appdash
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLiveReplayer(t *testing.T) {
+	// Record two traces, each with a root and a child span, from 2015.
+	recorded := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	rc := NewRecordingCollector(&buf, nil)
+	for i, trace := range []ID{1, 2} {
+		start := recorded.Add(time.Duration(i) * time.Hour)
+		rec := NewRecorder(SpanID{Trace: trace, Span: 10}, rc)
+		rec.Name("root")
+		rec.Event(Timespan{S: start, E: start.Add(time.Second)})
+		child := rec.Child()
+		child.Name("child")
+		child.Event(Timespan{S: start.Add(100 * time.Millisecond), E: start.Add(600 * time.Millisecond)})
+		child.Finish()
+		rec.Finish()
+	}
+
+	ms := NewMemoryStore()
+	lr, err := NewLiveReplayer(&buf, ms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	lr.Clock = &manualClock{t: now}
+	lr.Rate, lr.Loop = 0, false
+
+	for pass := 1; pass <= 2; pass++ {
+		lr.Run()
+
+		traces, err := ms.Traces(TracesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(traces) != 2*pass {
+			t.Fatalf("pass %d: got %d traces, want %d", pass, len(traces), 2*pass)
+		}
+		for _, trace := range traces {
+			if trace.ID.Trace == 1 || trace.ID.Trace == 2 {
+				t.Errorf("pass %d: got recorded trace ID %v, want a new one", pass, trace.ID.Trace)
+			}
+			if len(trace.Sub) != 1 {
+				t.Fatalf("pass %d: got trace %v, want a root and a child span", pass, trace)
+			}
+
+			// Each trace starts when it is replayed, and its spans keep
+			// their relative times.
+			var root, child Timespan
+			if err := UnmarshalEvent(trace.Span.Annotations, &root); err != nil {
+				t.Fatal(err)
+			}
+			if err := UnmarshalEvent(trace.Sub[0].Span.Annotations, &child); err != nil {
+				t.Fatal(err)
+			}
+			if !root.S.Equal(now) || !root.E.Equal(now.Add(time.Second)) {
+				t.Errorf("pass %d: got root span %v-%v, want it shifted to start at %v", pass, root.S, root.E, now)
+			}
+			if !child.S.Equal(now.Add(100*time.Millisecond)) || !child.E.Equal(now.Add(600*time.Millisecond)) {
+				t.Errorf("pass %d: got child span %v-%v, want it shifted along with the root", pass, child.S, child.E)
+			}
+		}
+	}
+
+	// A stopped replayer replays nothing.
+	lr.Stop()
+	lr.Stop()
+	lr.Run()
+	if traces, _ := ms.Traces(TracesOpts{}); len(traces) != 4 {
+		t.Errorf("got %d traces after stopping, want 4", len(traces))
+	}
+}
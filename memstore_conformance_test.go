@@ -0,0 +1,22 @@
+package appdash_test
+
+import (
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+	"sourcegraph.com/sourcegraph/appdash/appdashtest"
+)
+
+func TestMemoryStore_conformance(t *testing.T) {
+	appdashtest.StoreTest(t, func() appdash.Store { return appdash.NewMemoryStore() })
+}
+
+func TestLimitStore_conformance(t *testing.T) {
+	appdashtest.StoreTest(t, func() appdash.Store {
+		return &appdash.LimitStore{Max: 1000, DeleteStore: appdash.NewMemoryStore()}
+	})
+}
+
+func TestShardedMemoryStore_conformance(t *testing.T) {
+	appdashtest.StoreTest(t, func() appdash.Store { return appdash.NewShardedMemoryStore(4) })
+}
@@ -0,0 +1,143 @@
+// Package messaging implements support for propagating traces through
+// message brokers, such as NATS, AMQP (RabbitMQ) or Kafka, whose messages
+// carry headers.
+//
+// A producer injects the span ID of the span that publishes a message into
+// the message's headers, using the same encoding as the httptrace package
+// uses for its Span-ID header, and a consumer extracts it to record the
+// processing of the message as a child of that span:
+//
+//  // Producer
+//  msg := nats.NewMsg(subject)
+//  messaging.Inject(rec.SpanID, messaging.HeaderCarrier(msg.Header))
+//
+//  // Consumer
+//  parent, err := messaging.Extract(messaging.HeaderCarrier(msg.Header))
+//  if err == nil && parent != nil {
+//      rec := appdash.NewRecorder(appdash.NewSpanID(*parent), collector)
+//      ...
+//  }
+//
+// Headers are accessed through the Carrier interface, which HeaderCarrier
+// (for NATS and other multi-valued headers), TableCarrier (for AMQP tables)
+// and MapCarrier (for plain string maps) implement. Other transports are
+// supported by implementing Carrier for their header type.
+package messaging
+
+import (
+	"errors"
+	"strings"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// HeaderSpanID is the name of the message header by which the trace and
+// span IDs are passed along. It is the same as httptrace.HeaderSpanID.
+const HeaderSpanID = "Span-ID"
+
+// A Carrier is the set of headers of a message, which span IDs are
+// injected into and extracted from.
+type Carrier interface {
+	// Set sets the header with the given key, replacing any existing
+	// values.
+	Set(key, val string)
+
+	// ForeachKey calls handler for each header, stopping at and
+	// returning the first error that handler returns.
+	ForeachKey(handler func(key, val string) error) error
+}
+
+// Inject sets the Span-ID header of c to the given span.
+func Inject(span appdash.SpanID, c Carrier) {
+	c.Set(HeaderSpanID, span.String())
+}
+
+// errFound stops the iteration of ForeachKey once the header is found.
+var errFound = errors.New("found")
+
+// Extract returns the span ID in the Span-ID header of c, nil if there is
+// no such header, or an error if its value is unparseable. Header keys are
+// compared case-insensitively, since some transports canonicalize them.
+func Extract(c Carrier) (*appdash.SpanID, error) {
+	var v string
+	err := c.ForeachKey(func(key, val string) error {
+		if strings.EqualFold(key, HeaderSpanID) {
+			v = val
+			return errFound
+		}
+		return nil
+	})
+	if err != nil && err != errFound {
+		return nil, err
+	}
+	if v == "" {
+		return nil, nil
+	}
+	return appdash.ParseSpanID(v)
+}
+
+// MapCarrier is a Carrier for headers held in a plain string map.
+type MapCarrier map[string]string
+
+// Set implements the Carrier interface.
+func (c MapCarrier) Set(key, val string) { c[key] = val }
+
+// ForeachKey implements the Carrier interface.
+func (c MapCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, v := range c {
+		if err := handler(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HeaderCarrier is a Carrier for multi-valued headers, such as those of
+// NATS messages (nats.Header) or HTTP requests, which convert to it
+// directly: HeaderCarrier(msg.Header). Only the first value of each header
+// is passed to ForeachKey handlers.
+type HeaderCarrier map[string][]string
+
+// Set implements the Carrier interface.
+func (c HeaderCarrier) Set(key, val string) { c[key] = []string{val} }
+
+// ForeachKey implements the Carrier interface.
+func (c HeaderCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vs := range c {
+		if len(vs) == 0 {
+			continue
+		}
+		if err := handler(k, vs[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TableCarrier is a Carrier for AMQP header tables (amqp.Table), which
+// convert to it directly: TableCarrier(msg.Headers). Values are set as
+// strings; values that are neither strings nor byte slices are not passed
+// to ForeachKey handlers.
+type TableCarrier map[string]interface{}
+
+// Set implements the Carrier interface.
+func (c TableCarrier) Set(key, val string) { c[key] = val }
+
+// ForeachKey implements the Carrier interface.
+func (c TableCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, v := range c {
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		default:
+			continue
+		}
+		if err := handler(k, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package messaging
+
+import (
+	"net/http"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestInjectExtract(t *testing.T) {
+	want := appdash.SpanID{Trace: 100, Span: 150, Parent: 200}
+	for name, c := range map[string]Carrier{
+		"map":    MapCarrier{"Other": "x"},
+		"header": HeaderCarrier(http.Header{"Other": {"x"}}), // as nats.Header
+		"table":  TableCarrier{"other": int32(1)},            // as amqp.Table
+	} {
+		Inject(want, c)
+		got, err := Extract(c)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		if got == nil || *got != want {
+			t.Errorf("%s: got span ID %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	want := appdash.SpanID{Trace: 100, Span: 150, Parent: 200}
+	tests := map[string]struct {
+		c       Carrier
+		want    *appdash.SpanID
+		wantErr bool
+	}{
+		"absent":          {c: MapCarrier{"Other": "x"}},
+		"empty":           {c: HeaderCarrier{HeaderSpanID: nil}},
+		"lowercase key":   {c: MapCarrier{"span-id": want.String()}, want: &want},
+		"byte slice":      {c: TableCarrier{HeaderSpanID: []byte(want.String())}, want: &want},
+		"non-string":      {c: TableCarrier{HeaderSpanID: 1}},
+		"malformed value": {c: MapCarrier{HeaderSpanID: "x"}, wantErr: true},
+	}
+	for name, test := range tests {
+		got, err := Extract(test.c)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %v", name, err, test.wantErr)
+			continue
+		}
+		if (got == nil) != (test.want == nil) || (got != nil && *got != *test.want) {
+			t.Errorf("%s: got span ID %v, want %v", name, got, test.want)
+		}
+	}
+}
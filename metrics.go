@@ -0,0 +1,280 @@
+package appdash
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// metricsRetention is how long a MetricsOnlyCollector keeps aggregates,
+// matching the window of the Aggregator interface.
+const metricsRetention = 72 * time.Hour
+
+// metricsSlowest is the number of slowest trace IDs a MetricsOnlyCollector
+// reports per key.
+const metricsSlowest = 5
+
+// A MetricsOnlyCollector is a Store that aggregates the latencies and
+// errors of the spans collected into it, but never stores the spans
+// themselves: Trace always returns ErrTraceNotFound and Traces returns no
+// traces. It suits services that need only aggregate metrics from their
+// instrumentation, at a small fraction of the cost of storing traces.
+//
+// Spans are aggregated by key (see Key) and by the minute in which they
+// started, for up to 72 hours. A span is counted once its timespan event
+// (with both a start and an end time) is collected; the key and error
+// status are taken from the annotations collected along with it, as
+// Recorder.Finish and the httptrace middleware do.
+//
+// The aggregates are exposed through the Aggregator interface, so that
+// they can be shown by traceapp's dashboard and exported to Prometheus.
+//
+// A MetricsOnlyCollector can also serve as the central collector of a
+// fleet: the aggregates streamed by each instance's AggregateExporter
+// (as AggregateEvents) are merged into its own.
+type MetricsOnlyCollector struct {
+	// Key, if non-nil, returns the key under which a span is aggregated,
+	// or "" to not aggregate it. By default spans are aggregated by
+	// RouteSamplingKey, i.e. by HTTP route or else by name.
+	Key func(span SpanID, as Annotations) string
+
+	// IsError, if non-nil, reports whether a span failed. By default,
+	// spans with a 5xx HTTP server or client response status, or marked
+	// with ErrorKey, failed.
+	IsError func(as Annotations) bool
+
+	// Clock, if non-nil, is used instead of RealClock to determine which
+	// aggregates are within the window passed to Aggregate.
+	Clock Clock
+
+	// Stats, if non-nil, is updated with the number of spans collected.
+	Stats *Stats
+
+	mu        sync.Mutex
+	buckets   map[string]map[int64]*metricsBucket // key -> start minute (Unix) -> aggregate
+	lastPrune time.Time
+}
+
+// metricsBucket aggregates the spans of a key that started in one minute.
+type metricsBucket struct {
+	count, errors int64
+	sum, sumSq    float64 // of durations in seconds
+	min, max      time.Duration
+	latency       LatencySketch
+	slowest       []slowSpan // longest first, at most metricsSlowest
+}
+
+// slowSpan is the duration of a span, for finding the slowest traces.
+type slowSpan struct {
+	trace    ID
+	duration time.Duration
+}
+
+func (mc *MetricsOnlyCollector) now() time.Time {
+	if mc.Clock != nil {
+		return mc.Clock.Now()
+	}
+	return RealClock.Now()
+}
+
+// Collect implements the Collector interface by aggregating the span if
+// anns include its timespan, and then discarding it. If anns are an
+// AggregateEvent, its aggregates are merged instead.
+func (mc *MetricsOnlyCollector) Collect(span SpanID, anns ...Annotation) error {
+	if Annotations(anns).has(schemaPrefix + AggregateEvent{}.Schema()) {
+		return mc.collectAggregate(anns)
+	}
+	mc.Stats.collected(anns)
+	start, end, ok := (&Span{ID: span, Annotations: anns}).times()
+	if !ok || start.IsZero() || end.IsZero() {
+		return nil
+	}
+	key := mc.key(span, anns)
+	if key == "" {
+		return nil
+	}
+	failed := mc.isError(anns)
+	d := end.Sub(start)
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.pruneNoLock()
+	if mc.buckets == nil {
+		mc.buckets = map[string]map[int64]*metricsBucket{}
+	}
+	if mc.buckets[key] == nil {
+		mc.buckets[key] = map[int64]*metricsBucket{}
+	}
+	minute := start.Unix() / 60
+	b := mc.buckets[key][minute]
+	if b == nil {
+		b = &metricsBucket{min: d, max: d}
+		mc.buckets[key][minute] = b
+	}
+	b.add(span.Trace, d, failed)
+	return nil
+}
+
+func (mc *MetricsOnlyCollector) key(span SpanID, as Annotations) string {
+	if mc.Key != nil {
+		return mc.Key(span, as)
+	}
+	return RouteSamplingKey(span, as)
+}
+
+func (mc *MetricsOnlyCollector) isError(as Annotations) bool {
+	if mc.IsError != nil {
+		return mc.IsError(as)
+	}
+	if string(as.get(ErrorKey)) == "true" {
+		return true
+	}
+	for _, key := range []string{"Server.Response.StatusCode", "Client.Response.StatusCode"} {
+		if code, err := strconv.Atoi(string(as.get(key))); err == nil && code >= 500 {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneNoLock deletes the aggregates that are older than metricsRetention,
+// at most once a minute. The mc.mu lock must be held while calling
+// pruneNoLock.
+func (mc *MetricsOnlyCollector) pruneNoLock() {
+	now := mc.now()
+	if now.Sub(mc.lastPrune) < time.Minute {
+		return
+	}
+	mc.lastPrune = now
+	oldest := now.Add(-metricsRetention).Unix() / 60
+	for key, buckets := range mc.buckets {
+		for minute := range buckets {
+			if minute < oldest {
+				delete(buckets, minute)
+			}
+		}
+		if len(buckets) == 0 {
+			delete(mc.buckets, key)
+		}
+	}
+}
+
+// add adds a span of the given trace and duration to the bucket.
+func (b *metricsBucket) add(trace ID, d time.Duration, failed bool) {
+	b.count++
+	if failed {
+		b.errors++
+	}
+	b.sum += d.Seconds()
+	b.sumSq += d.Seconds() * d.Seconds()
+	if d < b.min {
+		b.min = d
+	}
+	if d > b.max {
+		b.max = d
+	}
+	b.latency.Add(d)
+	b.slowest = addSlowest(b.slowest, slowSpan{trace: trace, duration: d})
+}
+
+// merge adds the aggregates of o to b, which must not be empty.
+func (b *metricsBucket) merge(o *metricsBucket) {
+	b.count += o.count
+	b.errors += o.errors
+	b.sum += o.sum
+	b.sumSq += o.sumSq
+	if o.min < b.min {
+		b.min = o.min
+	}
+	if o.max > b.max {
+		b.max = o.max
+	}
+	b.latency.Merge(&o.latency)
+	for _, s := range o.slowest {
+		b.slowest = addSlowest(b.slowest, s)
+	}
+}
+
+// addSlowest adds s to the list of the slowest spans, longest first, if it
+// is among the metricsSlowest slowest. Each trace is listed at most once.
+func addSlowest(slowest []slowSpan, s slowSpan) []slowSpan {
+	for i, other := range slowest {
+		if other.trace == s.trace {
+			if s.duration <= other.duration {
+				return slowest
+			}
+			slowest = append(slowest[:i], slowest[i+1:]...)
+			break
+		}
+	}
+	i := sort.Search(len(slowest), func(i int) bool { return slowest[i].duration < s.duration })
+	if i >= metricsSlowest {
+		return slowest
+	}
+	slowest = append(slowest, slowSpan{})
+	copy(slowest[i+1:], slowest[i:])
+	slowest[i] = s
+	if len(slowest) > metricsSlowest {
+		slowest = slowest[:metricsSlowest]
+	}
+	return slowest
+}
+
+// Aggregate implements the Aggregator interface by returning the
+// aggregates of the spans that started within the given window, one result
+// per key (in RootSpanName), sorted by key.
+func (mc *MetricsOnlyCollector) Aggregate(start, end time.Duration) ([]*AggregatedResult, error) {
+	now := mc.now()
+	first, last := now.Add(start).Unix()/60, now.Add(end).Unix()/60
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	var results []*AggregatedResult
+	for key, buckets := range mc.buckets {
+		var total *metricsBucket
+		for minute, b := range buckets {
+			if minute < first || minute > last {
+				continue
+			}
+			if total == nil {
+				total = &metricsBucket{min: b.min, max: b.max}
+			}
+			total.merge(b)
+		}
+		if total == nil {
+			continue
+		}
+		mean := total.sum / float64(total.count)
+		variance := total.sumSq/float64(total.count) - mean*mean
+		r := &AggregatedResult{
+			RootSpanName: key,
+			Average:      time.Duration(mean * float64(time.Second)),
+			Min:          total.min,
+			Max:          total.max,
+			StdDev:       time.Duration(math.Sqrt(math.Max(variance, 0)) * float64(time.Second)),
+			Samples:      total.count,
+			Errors:       total.errors,
+			Latency:      &total.latency,
+		}
+		for _, s := range total.slowest {
+			r.Slowest = append(r.Slowest, s.trace)
+		}
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].RootSpanName < results[j].RootSpanName })
+	return results, nil
+}
+
+// Trace implements the Store interface. Since spans are never stored, it
+// always returns ErrTraceNotFound.
+func (mc *MetricsOnlyCollector) Trace(id ID) (*Trace, error) {
+	return nil, ErrTraceNotFound
+}
+
+// Traces implements the Queryer interface. Since spans are never stored, it
+// always returns no traces.
+func (mc *MetricsOnlyCollector) Traces(opts TracesOpts) ([]*Trace, error) {
+	return nil, nil
+}
@@ -0,0 +1,96 @@
+package appdash
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMetricsOnlyCollector(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 12, 0, 0, 0, time.UTC)}
+	mc := &MetricsOnlyCollector{Clock: clock, Stats: &Stats{}}
+
+	collect := func(span SpanID, route string, started time.Duration, d time.Duration, status string) {
+		start := clock.Now().Add(started)
+		anns := Annotations{
+			{Key: "Name", Value: []byte("handler")},
+			{Key: "Server.Route", Value: []byte(route)},
+			{Key: "Server.Response.StatusCode", Value: []byte(status)},
+		}
+		ts, err := MarshalEvent(Timespan{S: start, E: start.Add(d)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := mc.Collect(span, append(anns, ts...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	collect(SpanID{1, 1, 0}, "/users", -time.Minute, 10*time.Millisecond, "200")
+	collect(SpanID{2, 2, 0}, "/users", -time.Minute, 30*time.Millisecond, "500")
+	collect(SpanID{3, 3, 0}, "/users", -2*time.Minute, 20*time.Millisecond, "200")
+	collect(SpanID{4, 4, 0}, "/repos", -time.Minute, 5*time.Millisecond, "503")
+	collect(SpanID{5, 5, 0}, "/repos", -2*time.Hour, time.Second, "200") // outside the window
+	// Spans without a timespan are not aggregated.
+	if err := mc.Collect(SpanID{6, 6, 0}, Annotation{Key: "Name", Value: []byte("handler")}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := mc.Aggregate(-time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*AggregatedResult{
+		{
+			RootSpanName: "/repos",
+			Average:      5 * time.Millisecond,
+			Min:          5 * time.Millisecond,
+			Max:          5 * time.Millisecond,
+			Samples:      1,
+			Errors:       1,
+			Slowest:      []ID{4},
+		},
+		{
+			RootSpanName: "/users",
+			Average:      20 * time.Millisecond,
+			Min:          10 * time.Millisecond,
+			Max:          30 * time.Millisecond,
+			Samples:      3,
+			Errors:       1,
+			Slowest:      []ID{2, 3, 1},
+		},
+	}
+	// Durations computed in float64 seconds may be off by a nanosecond.
+	for _, r := range got {
+		r.Average = r.Average.Round(time.Microsecond)
+		r.StdDev = r.StdDev.Round(time.Microsecond)
+		if r.Latency.Count() != r.Samples {
+			t.Errorf("%s: got latency sketch of %d spans, want %d", r.RootSpanName, r.Latency.Count(), r.Samples)
+		}
+		r.Latency = nil
+	}
+	want[1].StdDev = 8165 * time.Microsecond
+	if !reflect.DeepEqual(got, want) {
+		for _, r := range got {
+			t.Logf("got %+v", r)
+		}
+		t.Fatal("got wrong aggregates")
+	}
+
+	// The spans themselves were not stored.
+	if tr, err := mc.Trace(1); err != ErrTraceNotFound {
+		t.Errorf("got trace %v and error %v, want ErrTraceNotFound", tr, err)
+	}
+	if traces, err := mc.Traces(TracesOpts{}); err != nil || len(traces) != 0 {
+		t.Errorf("got traces %v and error %v, want none", traces, err)
+	}
+	if got := mc.Stats.Snapshot().SpansCollected; got != 6 {
+		t.Errorf("got %d spans collected, want 6", got)
+	}
+
+	// Aggregates older than 72 hours are pruned.
+	clock.Advance(73 * time.Hour)
+	collect(SpanID{7, 7, 0}, "/users", 0, time.Millisecond, "200")
+	if got, _ := mc.Aggregate(-100*time.Hour, 0); len(got) != 1 || got[0].Samples != 1 {
+		t.Errorf("got aggregates %+v after 73 hours, want just the new span", got)
+	}
+}
@@ -59,7 +59,7 @@ func (mq *multiQueryer) Traces(opts TracesOpts) ([]*Trace, error) {
 		all   []*Trace
 	)
 	for _, q := range mq.queryers {
-		traces, err := q.Traces(TracesOpts{})
+		traces, err := q.Traces(TracesOpts{CorrelationID: opts.CorrelationID, Service: opts.Service, Environment: opts.Environment})
 		if err != nil {
 			return nil, err
 		}
@@ -70,6 +70,9 @@ func (mq *multiQueryer) Traces(opts TracesOpts) ([]*Trace, error) {
 			}
 		}
 	}
+	if opts.SortByRecency {
+		SortTracesByRecency(all)
+	}
 	return all, nil
 }
 
@@ -0,0 +1,153 @@
+//go:build prometheus
+// +build prometheus
+
+// Package promstats exports the operational counters of an appdash.Stats as
+// Prometheus metrics.
+//
+// Usage:
+//
+//  stats := &appdash.Stats{}
+//  store := appdash.NewMemoryStore()
+//  store.Stats = stats
+//  prometheus.MustRegister(promstats.NewCollector(stats))
+//
+// It can also export the aggregates of an appdash.Aggregator, such as a
+// MetricsOnlyCollector (see NewAggregateCollector).
+//
+// The package is only built with the prometheus build tag, because the
+// Prometheus client is not vendored with appdash: get
+// github.com/prometheus/client_golang and build with
+// "go build -tags prometheus".
+package promstats
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+var (
+	spansCollectedDesc = prometheus.NewDesc(
+		"appdash_spans_collected_total",
+		"Number of Collect calls handled by the store.",
+		nil, nil,
+	)
+	bytesCollectedDesc = prometheus.NewDesc(
+		"appdash_bytes_collected_total",
+		"Total size of the annotations collected by the store.",
+		nil, nil,
+	)
+	tracesDesc = prometheus.NewDesc(
+		"appdash_traces",
+		"Number of traces currently held by the store.",
+		nil, nil,
+	)
+	evictionsDesc = prometheus.NewDesc(
+		"appdash_evictions_total",
+		"Number of traces deleted from the store.",
+		nil, nil,
+	)
+	droppedDesc = prometheus.NewDesc(
+		"appdash_dropped_spans_total",
+		"Number of spans dropped by collectors before reaching the store.",
+		nil, nil,
+	)
+	collectErrorsDesc = prometheus.NewDesc(
+		"appdash_collect_errors_total",
+		"Number of errors encountered by collectors.",
+		nil, nil,
+	)
+)
+
+// Collector is a prometheus.Collector that reports the counters of an
+// appdash.Stats.
+type Collector struct {
+	stats *appdash.Stats
+}
+
+// NewCollector returns a Collector reporting the given stats.
+func NewCollector(stats *appdash.Stats) *Collector {
+	return &Collector{stats: stats}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- spansCollectedDesc
+	ch <- bytesCollectedDesc
+	ch <- tracesDesc
+	ch <- evictionsDesc
+	ch <- droppedDesc
+	ch <- collectErrorsDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats.Snapshot()
+	ch <- prometheus.MustNewConstMetric(spansCollectedDesc, prometheus.CounterValue, float64(s.SpansCollected))
+	ch <- prometheus.MustNewConstMetric(bytesCollectedDesc, prometheus.CounterValue, float64(s.BytesCollected))
+	ch <- prometheus.MustNewConstMetric(tracesDesc, prometheus.GaugeValue, float64(s.Traces))
+	ch <- prometheus.MustNewConstMetric(evictionsDesc, prometheus.CounterValue, float64(s.Evictions))
+	ch <- prometheus.MustNewConstMetric(droppedDesc, prometheus.CounterValue, float64(s.Dropped))
+	ch <- prometheus.MustNewConstMetric(collectErrorsDesc, prometheus.CounterValue, float64(s.CollectErrors))
+}
+
+var (
+	aggregateSamplesDesc = prometheus.NewDesc(
+		"appdash_aggregate_spans",
+		"Number of spans aggregated under a name within the window.",
+		[]string{"name"}, nil,
+	)
+	aggregateErrorsDesc = prometheus.NewDesc(
+		"appdash_aggregate_errors",
+		"Number of failed spans aggregated under a name within the window.",
+		[]string{"name"}, nil,
+	)
+	aggregateAverageDesc = prometheus.NewDesc(
+		"appdash_aggregate_duration_seconds_average",
+		"Average duration of the spans aggregated under a name within the window.",
+		[]string{"name"}, nil,
+	)
+	aggregateMaxDesc = prometheus.NewDesc(
+		"appdash_aggregate_duration_seconds_max",
+		"Maximum duration of the spans aggregated under a name within the window.",
+		[]string{"name"}, nil,
+	)
+)
+
+// AggregateCollector is a prometheus.Collector that reports the aggregates
+// of an appdash.Aggregator over a trailing window, labeled by name.
+type AggregateCollector struct {
+	agg    appdash.Aggregator
+	window time.Duration
+}
+
+// NewAggregateCollector returns an AggregateCollector reporting the
+// aggregates of agg over the given trailing window (e.g. 5 * time.Minute).
+func NewAggregateCollector(agg appdash.Aggregator, window time.Duration) *AggregateCollector {
+	return &AggregateCollector{agg: agg, window: window}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *AggregateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- aggregateSamplesDesc
+	ch <- aggregateErrorsDesc
+	ch <- aggregateAverageDesc
+	ch <- aggregateMaxDesc
+}
+
+// Collect implements the prometheus.Collector interface. If the aggregator
+// returns an error, no metrics are reported.
+func (c *AggregateCollector) Collect(ch chan<- prometheus.Metric) {
+	results, err := c.agg.Aggregate(-c.window, 0)
+	if err != nil {
+		return
+	}
+	for _, r := range results {
+		ch <- prometheus.MustNewConstMetric(aggregateSamplesDesc, prometheus.GaugeValue, float64(r.Samples), r.RootSpanName)
+		ch <- prometheus.MustNewConstMetric(aggregateErrorsDesc, prometheus.GaugeValue, float64(r.Errors), r.RootSpanName)
+		ch <- prometheus.MustNewConstMetric(aggregateAverageDesc, prometheus.GaugeValue, r.Average.Seconds(), r.RootSpanName)
+		ch <- prometheus.MustNewConstMetric(aggregateMaxDesc, prometheus.GaugeValue, r.Max.Seconds(), r.RootSpanName)
+	}
+}
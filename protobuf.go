@@ -0,0 +1,110 @@
+package appdash
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// ProtobufKeyPrefix is the prefix of the annotation keys under which
+// serialized protocol buffer messages are stored (see ProtobufAnnotations).
+// A payload named "Request" is stored under "Protobuf.Request.TypeURL",
+// "Protobuf.Request.Payload" and, if it was truncated,
+// "Protobuf.Request.Truncated".
+const ProtobufKeyPrefix = "Protobuf."
+
+// protobufTypeURLPrefix is the conventional prefix of the type URLs of
+// protocol buffer message types, as used by google.protobuf.Any.
+const protobufTypeURLPrefix = "type.googleapis.com/"
+
+// MaxProtobufPayloadSize is the maximum size in bytes of a payload stored by
+// ProtobufAnnotations. Larger payloads are truncated to this size and marked
+// as such; they can then no longer be decoded, but remain available as
+// bytes.
+var MaxProtobufPayloadSize = 16 * 1024
+
+// A ProtobufPayload is a serialized protocol buffer message attached to a
+// span, e.g. the request or response of an RPC.
+type ProtobufPayload struct {
+	// Name distinguishes the payloads of a span, e.g. "Request".
+	Name string
+
+	// TypeURL identifies the message type of the payload, e.g.
+	// "type.googleapis.com/pkg.Message" (see ProtobufTypeURL).
+	TypeURL string
+
+	// Data is the serialized message.
+	Data []byte
+
+	// Truncated is whether Data was cut short because it exceeded
+	// MaxProtobufPayloadSize.
+	Truncated bool
+}
+
+// ProtobufTypeURL returns the type URL of the message type of m. It is
+// based on the fully-qualified name under which the type was registered
+// with proto.RegisterType, or on its Go type name if it was not registered.
+func ProtobufTypeURL(m proto.Message) string {
+	name := proto.MessageName(m)
+	if name == "" {
+		name = strings.TrimPrefix(fmt.Sprintf("%T", m), "*")
+	}
+	return protobufTypeURLPrefix + name
+}
+
+// ProtobufAnnotations serializes m and returns the annotations that store it
+// as the named payload of a span (see ProtobufKeyPrefix).
+func ProtobufAnnotations(name string, m proto.Message) (Annotations, error) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	p := ProtobufPayload{Name: name, TypeURL: ProtobufTypeURL(m), Data: data}
+	if len(p.Data) > MaxProtobufPayloadSize {
+		p.Data, p.Truncated = p.Data[:MaxProtobufPayloadSize], true
+	}
+	return p.annotations(), nil
+}
+
+func (p ProtobufPayload) annotations() Annotations {
+	prefix := ProtobufKeyPrefix + p.Name
+	as := Annotations{
+		{Key: prefix + ".TypeURL", Value: []byte(p.TypeURL)},
+		{Key: prefix + ".Payload", Value: p.Data},
+	}
+	if p.Truncated {
+		as = append(as, Annotation{Key: prefix + ".Truncated", Value: []byte("true")})
+	}
+	return as
+}
+
+// ProtobufPayloads returns the protocol buffer payloads stored on the span
+// by ProtobufAnnotations, in the order in which they were recorded.
+func (s *Span) ProtobufPayloads() []ProtobufPayload {
+	var payloads []ProtobufPayload
+	for _, a := range s.Annotations {
+		if !strings.HasPrefix(a.Key, ProtobufKeyPrefix) || !strings.HasSuffix(a.Key, ".TypeURL") {
+			continue
+		}
+		prefix := strings.TrimSuffix(a.Key, "TypeURL")
+		payloads = append(payloads, ProtobufPayload{
+			Name:      strings.TrimPrefix(prefix[:len(prefix)-1], ProtobufKeyPrefix),
+			TypeURL:   string(a.Value),
+			Data:      s.Annotations.get(prefix + "Payload"),
+			Truncated: s.Annotations.has(prefix + "Truncated"),
+		})
+	}
+	return payloads
+}
+
+// Protobuf records m as the named protocol buffer payload of the span (see
+// ProtobufAnnotations).
+func (r *Recorder) Protobuf(name string, m proto.Message) {
+	as, err := ProtobufAnnotations(name, m)
+	if err != nil {
+		r.error("Protobuf", err)
+		return
+	}
+	r.annotations = append(r.annotations, as...)
+}
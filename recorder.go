@@ -1,9 +1,12 @@
 package appdash
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"runtime"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -12,6 +15,17 @@ var (
 	errMultipleFinishCalls = errors.New("multiple Recorder.Finish calls")
 )
 
+// DefaultMaxDepth is the maximum depth of the spans created by
+// Recorder.Child when the recorder's MaxDepth is zero. It is far deeper than
+// any reasonable call tree, so that it only takes effect on runaway
+// recursion.
+const DefaultMaxDepth = 1000
+
+// DepthTruncatedKey is the reserved annotation key that Recorder.Child
+// records on a span whose children were collapsed into it because they
+// would have exceeded the recorder's MaxDepth.
+const DepthTruncatedKey = "_depthTruncated"
+
 // A Recorder is associated with a span and records annotations on the
 // span by sending them to a collector.
 type Recorder struct {
@@ -19,9 +33,77 @@ type Recorder struct {
 	// instead of being manually checked via the Error method.
 	Logger *log.Logger
 
-	SpanID                   // the span ID that annotations are about
-	annotations []Annotation // SpanID's annotations to be collected
-	finished    bool         // finished is whether Recorder.Finish was called
+	// RecordCaller, if true, causes Child to record the source location
+	// (file, line and function) of the code creating each child span as a
+	// Caller event. It is inherited by child recorders. It is off by
+	// default because capturing the caller is relatively expensive.
+	RecordCaller bool
+
+	// RecordGoroutines, if true, causes the spans created by Child to
+	// record the process's goroutine count when they are created and when
+	// they are finished, as a GoroutinesEvent, to help correlate latency
+	// with goroutine leaks and explosions. It is inherited by child
+	// recorders. It is off by default because of the cost of the extra
+	// annotations on every span.
+	RecordGoroutines bool
+
+	// CallerSkip is the number of additional stack frames to skip when
+	// recording a caller, so that helpers which wrap Child or Caller
+	// report their own caller rather than themselves.
+	CallerSkip int
+
+	// Clock, if non-nil, is used to timestamp Log events instead of
+	// RealClock. It is inherited by child recorders.
+	Clock Clock
+
+	// Sampler, if non-nil, is consulted before annotations are sent to the
+	// collector, and annotations it rejects are silently discarded. It is
+	// inherited by child recorders.
+	Sampler Sampler
+
+	// Enrichers are called in order when a span is finished, to add
+	// annotations that every span should carry, such as the user or tenant
+	// of the request being served (see Enricher). They are inherited by
+	// child recorders, so they are typically set once, on the recorder of
+	// the root span.
+	Enrichers []Enricher
+
+	// Context, if non-nil, is the context passed to Enrichers, from which
+	// they may read request-scoped values. It is inherited by child
+	// recorders. If nil, context.Background() is used.
+	Context context.Context
+
+	// InheritedKeys are the keys of annotations, such as a tenant or
+	// request ID, that Child copies from this recorder's span to the child
+	// span, so that every span of a trace can be filtered by them. The
+	// value copied is a snapshot of the last one recorded when the child
+	// is created; later changes are not propagated. Tags are inherited by
+	// listing their full keys (e.g. appdash.TagPrefix+"tenant"). It is
+	// inherited by child recorders, so the annotations reach every
+	// descendant.
+	InheritedKeys []string
+
+	// MaxDepth is the maximum depth (the number of ancestors) of the spans
+	// created by Child, counted from the span of the recorder that Child
+	// was first called on. Children that would be deeper are collapsed
+	// into their deepest allowed ancestor: they record their annotations
+	// on its span, along with a DepthTruncatedKey annotation, except for
+	// the events that identify a span (its name and Timespan), which would
+	// overwrite the ancestor's and are dropped. If zero,
+	// DefaultMaxDepth is used; if negative, depth is not limited. It is
+	// inherited by child recorders.
+	MaxDepth int
+
+	SpanID                            // the span ID that annotations are about
+	annotations []Annotation          // SpanID's annotations to be collected
+	inheritable map[string]Annotation // InheritedKeys key -> the last annotation with it collected by Annotation
+	finished    bool                  // finished is whether Recorder.Finish was called
+	depth       int                   // depth is the number of Child calls that created this recorder
+	collapsed   bool                  // collapsed is whether this recorder records on an ancestor's span (see MaxDepth)
+
+	goroutines      int  // the goroutine count when the span started, if countGoroutines
+	countGoroutines bool // whether Finish records a GoroutinesEvent
+	trackGoroutines bool // whether Finish also records GoroutinesSpawnedKey
 
 	collector Collector // the collector to send to
 
@@ -29,6 +111,13 @@ type Recorder struct {
 	errorsMu sync.Mutex // protects errors
 }
 
+// An Enricher returns annotations to add to a span when its recorder
+// finishes it (see Recorder.Enrichers), given the recorder's context, the
+// span's ID and the annotations recorded on it so far, including those
+// added by the enrichers before it. It must not modify as, and may return
+// nil to add nothing.
+type Enricher func(ctx context.Context, span SpanID, as Annotations) []Annotation
+
 // NewRecorder creates a new recorder for the given span and
 // collector. If c is nil, NewRecorder panics.
 func NewRecorder(span SpanID, c Collector) *Recorder {
@@ -43,8 +132,139 @@ func NewRecorder(span SpanID, c Collector) *Recorder {
 
 // Child creates a new Recorder with the same collector and a new
 // child SpanID whose parent is this recorder's SpanID.
+//
+// If the child span would be deeper than MaxDepth, the new Recorder
+// records on this recorder's span instead (see MaxDepth).
 func (r *Recorder) Child() *Recorder {
-	return NewRecorder(NewSpanID(r.SpanID), r.collector)
+	return r.child(1)
+}
+
+// child is Child, recording (if RecordCaller is set) the caller skip frames
+// above the caller of child, plus r.CallerSkip.
+func (r *Recorder) child(skip int) *Recorder {
+	var c *Recorder
+	if max := r.maxDepth(); max >= 0 && r.depth >= max {
+		c = NewRecorder(r.SpanID, r.collector)
+		c.depth, c.collapsed = r.depth, true
+		c.annotations = append(c.annotations, Annotation{Key: DepthTruncatedKey, Value: []byte("true")})
+	} else {
+		c = NewRecorder(NewSpanID(r.SpanID), r.collector)
+		c.depth = r.depth + 1
+	}
+	c.RecordCaller = r.RecordCaller
+	c.RecordGoroutines = r.RecordGoroutines
+	c.CallerSkip = r.CallerSkip
+	c.Clock = r.Clock
+	c.Sampler = r.Sampler
+	c.MaxDepth = r.MaxDepth
+	c.Enrichers = r.Enrichers
+	c.Context = r.Context
+	c.InheritedKeys = r.InheritedKeys
+	if c.SpanID != r.SpanID {
+		c.annotations = append(c.annotations, r.inheritedAnnotations()...)
+	}
+	if c.RecordCaller {
+		c.recordCaller(skip + 1)
+	}
+	if c.RecordGoroutines && c.SpanID != r.SpanID {
+		c.countGoroutines, c.goroutines = true, runtime.NumGoroutine()
+	}
+	return c
+}
+
+// inheritedAnnotations returns the last annotation recorded on r's span
+// with each of r.InheritedKeys.
+func (r *Recorder) inheritedAnnotations() []Annotation {
+	var inherited []Annotation
+	for _, key := range r.InheritedKeys {
+		last, ok := r.inheritable[key]
+		for _, a := range r.annotations {
+			if a.Key == key {
+				last, ok = a, true
+			}
+		}
+		if ok {
+			inherited = append(inherited, last)
+		}
+	}
+	return inherited
+}
+
+// An ActiveSpan is the recorder of a child span started with
+// Recorder.Start, which records the span's timespan when it is finished.
+type ActiveSpan struct {
+	*Recorder
+
+	start time.Time
+	once  sync.Once
+}
+
+// Start creates a child span with the given name, whose start time is now,
+// and returns its recorder. Its end time is recorded when its Finish method
+// is called, typically with defer:
+//
+// 	span := rec.Start("db query")
+// 	defer span.Finish()
+//
+func (r *Recorder) Start(name string) *ActiveSpan {
+	c := r.child(1)
+	c.Name(name)
+	return &ActiveSpan{Recorder: c, start: c.now()}
+}
+
+// Finish records a Timespan event from the span's start time until now,
+// and then finishes recording the span as Recorder.Finish does. Unlike
+// Recorder.Finish, it may safely be called more than once: only the first
+// call has an effect.
+func (s *ActiveSpan) Finish() {
+	s.once.Do(func() {
+		s.Event(Timespan{S: s.start, E: s.now()})
+		s.Recorder.Finish()
+	})
+}
+
+// maxDepth returns r.MaxDepth, or DefaultMaxDepth if it is zero. A negative
+// result means that depth is not limited.
+func (r *Recorder) maxDepth() int {
+	if r.MaxDepth == 0 {
+		return DefaultMaxDepth
+	}
+	return r.MaxDepth
+}
+
+// Caller records the source location of the code calling it (skipping
+// CallerSkip additional frames) as a Caller event on the span, regardless of
+// RecordCaller. It is useful for root spans, whose recorders are not created
+// by Child.
+func (r *Recorder) Caller() {
+	r.recordCaller(1)
+}
+
+// recordCaller records the location of the caller skip frames above the
+// caller of recordCaller, plus r.CallerSkip.
+func (r *Recorder) recordCaller(skip int) {
+	pc, file, line, ok := runtime.Caller(skip + 1 + r.CallerSkip)
+	if !ok {
+		return
+	}
+	e := CallerEvent{File: file, Line: line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		e.Func = fn.Name()
+	}
+	r.Event(e)
+}
+
+// TrackGoroutines flags the span to record, when it is finished, the
+// approximate number of goroutines spawned during it (see
+// GoroutinesSpawnedKey) along with a GoroutinesEvent, regardless of
+// RecordGoroutines. If the span is not already counting goroutines, its
+// start count is taken now; this is how root spans, whose recorders are not
+// created by Child, are counted.
+func (r *Recorder) TrackGoroutines() {
+	if !r.countGoroutines {
+		r.countGoroutines, r.goroutines = true, runtime.NumGoroutine()
+	}
+	r.trackGoroutines = true
 }
 
 // Name sets the name of this span.
@@ -61,7 +281,7 @@ func (r *Recorder) Msg(msg string) {
 // Log records a Log event (an event with the current timestamp and a
 // human-readable message) on the span.
 func (r *Recorder) Log(msg string) {
-	r.Event(Log(msg))
+	r.Event(LogWithTimestamp(msg, r.now()))
 }
 
 // LogWithTimestamp records a Log event with an explicit timestamp
@@ -69,9 +289,53 @@ func (r *Recorder) LogWithTimestamp(msg string, timestamp time.Time) {
 	r.Event(LogWithTimestamp(msg, timestamp))
 }
 
+// FollowsFrom records that this span follows from the given span, without
+// making it the span's parent.
+func (r *Recorder) FollowsFrom(span SpanID) {
+	r.Event(FollowsFrom(span))
+}
+
+// Attempt records that this span is attempt number n (starting at 1) of an
+// operation that is retried, whose first attempt was recorded on the span
+// first. final should be true on the last attempt, whether it succeeded
+// or not.
+func (r *Recorder) Attempt(first SpanID, n int, final bool) {
+	if n <= 1 {
+		first = SpanID{}
+	}
+	r.Event(Attempt{RetryOf: first, N: n, Final: final})
+}
+
+// TraceAnnotation records a trace-level annotation (see
+// TraceAnnotationPrefix) on the span. It is typically called on the recorder
+// of the root span.
+func (r *Recorder) TraceAnnotation(key string, value []byte) {
+	r.annotations = append(r.annotations, TraceAnnotation(key, value))
+}
+
+// Tag records a tag (see TagPrefix) on the span, for quick instrumentation
+// that does not warrant an Event type.
+func (r *Recorder) Tag(key, value string) {
+	r.annotations = append(r.annotations, Tag(key, value))
+}
+
+// SamplingPriority records the sampling priority of the span (see
+// SamplingPriorityKey), marking its trace as interesting to tail-based
+// samplers.
+func (r *Recorder) SamplingPriority(p int) {
+	r.annotations = append(r.annotations, SamplingPriority(p))
+}
+
 // Event records any event that implements the Event, TimespanEvent, or
 // TimestampedEvent interfaces.
 func (r *Recorder) Event(e Event) {
+	if r.collapsed {
+		switch e.(type) {
+		case spanName, Timespan:
+			// Keep the name and timespan of the ancestor's span.
+			return
+		}
+	}
 	as, err := MarshalEvent(e)
 	if err != nil {
 		r.error("Event", err)
@@ -80,9 +344,30 @@ func (r *Recorder) Event(e Event) {
 	r.annotations = append(r.annotations, as...)
 }
 
+// CollectEvent marshals e and collects it on the span in a single step,
+// returning any marshal or collect error to the caller. Unlike Event, the
+// annotations are sent to the collector immediately rather than when Finish
+// is called, and errors are not recorded for the Errors method.
+func (r *Recorder) CollectEvent(e Event) error {
+	as, err := MarshalEvent(e)
+	if err != nil {
+		return err
+	}
+	return r.failsafeAnnotation(as...)
+}
+
+// now returns the current time according to r.Clock.
+func (r *Recorder) now() time.Time {
+	if r.Clock == nil {
+		return RealClock.Now()
+	}
+	return r.Clock.Now()
+}
+
 // Finish finishes recording and saves the recorded information to the
 // underlying collector. If Finish is not called, then no data will be written
-// to the underlying collector.
+// to the underlying collector. Finishing a root span also records a
+// TraceFinished annotation, marking the trace as complete.
 // Finish must be called once, otherwise r.error is called, this constraint
 // ensures that collector is called once per Recorder, in order to avoid
 // for performance reasons extra operations(span look up & span's annotations update)
@@ -93,18 +378,62 @@ func (r *Recorder) Finish() {
 		return
 	}
 	r.finished = true
+	r.enrich()
+	if r.countGoroutines {
+		end := runtime.NumGoroutine()
+		r.Event(GoroutinesEvent{Start: r.goroutines, End: end})
+		if r.trackGoroutines {
+			spawned := end - r.goroutines
+			if spawned < 0 {
+				spawned = 0
+			}
+			r.annotations = append(r.annotations, Annotation{Key: GoroutinesSpawnedKey, Value: []byte(strconv.Itoa(spawned))})
+		}
+	}
+	if r.SpanID.IsRoot() {
+		// Mark the trace as complete (see FinalizingCollector).
+		r.annotations = append(r.annotations, TraceFinished())
+	}
 	r.Annotation(r.annotations...)
 }
 
+// enrich adds the annotations of r.Enrichers, in order.
+func (r *Recorder) enrich() {
+	if len(r.Enrichers) == 0 {
+		return
+	}
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, e := range r.Enrichers {
+		r.annotations = append(r.annotations, e(ctx, r.SpanID, r.annotations)...)
+	}
+}
+
 // Annotation records raw annotations on the span.
 func (r *Recorder) Annotation(as ...Annotation) {
+	for _, key := range r.InheritedKeys {
+		for _, a := range as {
+			if a.Key == key {
+				if r.inheritable == nil {
+					r.inheritable = map[string]Annotation{}
+				}
+				r.inheritable[key] = a
+			}
+		}
+	}
 	if err := r.failsafeAnnotation(as...); err != nil {
 		r.error("Annotation", err)
 	}
 }
 
-// Annotation records raw annotations on the span.
+// failsafeAnnotation collects raw annotations on the span, unless r.Sampler
+// rejects them, and returns any collector error.
 func (r *Recorder) failsafeAnnotation(as ...Annotation) error {
+	if r.Sampler != nil && !r.Sampler.ShouldSample(r.SpanID, as) {
+		return nil
+	}
 	return r.collector.Collect(r.SpanID, as...)
 }
 
@@ -121,7 +450,7 @@ func (r *Recorder) Errors() []error {
 
 func (r *Recorder) error(method string, err error) {
 	logMsg := fmt.Sprintf("Recorder.%s error: %s", method, err)
-	as, _ := MarshalEvent(Log(logMsg))
+	as, _ := MarshalEvent(LogWithTimestamp(logMsg, r.now()))
 	r.failsafeAnnotation(as...)
 
 	// If we have a logger, we're not doing manual error checking but rather
@@ -2,11 +2,15 @@ package appdash
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRecorder(t *testing.T) {
@@ -43,6 +47,36 @@ func TestRecorder(t *testing.T) {
 	}
 }
 
+func TestRecorder_CollectEvent(t *testing.T) {
+	id := SpanID{1, 2, 3}
+
+	var anns Annotations
+	c := collectorFunc(func(spanID SpanID, as ...Annotation) error {
+		if spanID != id {
+			t.Errorf("Collect: got spanID arg %v, want %v", spanID, id)
+		}
+		anns = append(anns, as...)
+		return nil
+	})
+
+	r := NewRecorder(id, c)
+	if err := r.CollectEvent(Msg("msg")); err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffAnnotationsFromEvent(anns, Msg("msg")); len(diff) > 0 {
+		t.Errorf("got diff annotations for Msg event:\n%s", strings.Join(diff, "\n"))
+	}
+
+	wantErr := errors.New("x")
+	r = NewRecorder(id, collectorFunc(func(SpanID, ...Annotation) error { return wantErr }))
+	if err := r.CollectEvent(Msg("msg")); err != wantErr {
+		t.Errorf("got error %v, want %v", err, wantErr)
+	}
+	if errs := r.Errors(); len(errs) != 0 {
+		t.Errorf("got Errors %v, want none", errs)
+	}
+}
+
 func TestRecorder_Errors(t *testing.T) {
 	collectErr := errors.New("Collect error")
 	calledCollect := 0
@@ -73,6 +107,283 @@ func TestRecorder_Errors(t *testing.T) {
 	}
 }
 
+func TestRecorder_RecordCaller(t *testing.T) {
+	var anns Annotations
+	c := collectorFunc(func(spanID SpanID, as ...Annotation) error {
+		anns = append(anns, as...)
+		return nil
+	})
+	callerOf := func(r *Recorder) CallerEvent {
+		anns = nil
+		r.Finish()
+		var e CallerEvent
+		if err := UnmarshalEvent(anns, &e); err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	r := NewRecorder(SpanID{1, 2, 0}, c)
+	r.RecordCaller = true
+
+	_, file, line, _ := runtime.Caller(0)
+	child := r.Child()
+	want := CallerEvent{File: file, Line: line + 1, Func: "sourcegraph.com/sourcegraph/appdash.TestRecorder_RecordCaller"}
+	if got := callerOf(child); got != want {
+		t.Errorf("Child: got caller %+v, want %+v", got, want)
+	}
+
+	// The setting is inherited, so grandchildren record their callers too.
+	_, _, line, _ = runtime.Caller(0)
+	grandchild := child.Child()
+	if got := callerOf(grandchild); got.Line != line+1 {
+		t.Errorf("Child of child: got caller line %d, want %d", got.Line, line+1)
+	}
+
+	// Start records its caller, not itself.
+	_, _, line, _ = runtime.Caller(0)
+	span := r.Start("op")
+	if got := callerOf(span.Recorder); got.Line != line+1 || got.Func != want.Func {
+		t.Errorf("Start: got caller %+v, want line %d of %s", got, line+1, want.Func)
+	}
+
+	// Wrappers skip their own frame.
+	r.CallerSkip = 1
+	newChild := func() *Recorder { return r.Child() }
+	_, _, line, _ = runtime.Caller(0)
+	if got := callerOf(newChild()); got.Line != line+1 {
+		t.Errorf("wrapped Child: got caller line %d, want %d", got.Line, line+1)
+	}
+
+	r.CallerSkip = 0
+	_, _, line, _ = runtime.Caller(0)
+	r.Caller()
+	if got := callerOf(r); got.Line != line+1 {
+		t.Errorf("Caller: got caller line %d, want %d", got.Line, line+1)
+	}
+
+	// Off by default.
+	r = NewRecorder(SpanID{1, 2, 0}, c)
+	anns = nil
+	r.Child().Finish()
+	if len(anns.schemas()) != 0 {
+		t.Errorf("got annotations %v, want none", anns)
+	}
+}
+
+func TestRecorder_RecordGoroutines(t *testing.T) {
+	anns := map[SpanID]Annotations{}
+	c := collectorFunc(func(spanID SpanID, as ...Annotation) error {
+		anns[spanID] = append(anns[spanID], as...)
+		return nil
+	})
+	hasCounts := func(span SpanID) bool {
+		return anns[span].has("Goroutines.Start") && anns[span].has("Goroutines.End")
+	}
+
+	// Off by default.
+	r := NewRecorder(SpanID{1, 2, 0}, c)
+	child := r.Child()
+	child.Finish()
+	r.Finish()
+	if hasCounts(child.SpanID) || hasCounts(r.SpanID) {
+		t.Errorf("got goroutine counts %v without RecordGoroutines", anns)
+	}
+
+	r = NewRecorder(SpanID{3, 4, 0}, c)
+	r.RecordGoroutines = true
+	child = r.Child()
+	grandchild := child.Start("leak")
+	before := runtime.NumGoroutine()
+	stop := make(chan struct{})
+	defer close(stop)
+	for i := 0; i < 3; i++ {
+		go func() { <-stop }()
+	}
+	grandchild.Finish()
+	child.Finish()
+	if !hasCounts(child.SpanID) || !hasCounts(grandchild.SpanID) {
+		t.Errorf("got annotations %v, want goroutine counts on the child spans", anns)
+	}
+	var e GoroutinesEvent
+	if err := UnmarshalEvent(anns[grandchild.SpanID], &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Start > before || e.End < before+3 {
+		t.Errorf("got %+v, want a start of at most %d and an end of at least %d", e, before, before+3)
+	}
+	if anns[child.SpanID].has(GoroutinesSpawnedKey) {
+		t.Error("unflagged span recorded the number of goroutines spawned")
+	}
+
+	// A flagged root span records the goroutines spawned during it.
+	r.TrackGoroutines()
+	for i := 0; i < 2; i++ {
+		go func() { <-stop }()
+	}
+	r.Finish()
+	if !hasCounts(r.SpanID) {
+		t.Errorf("got root annotations %v, want goroutine counts", anns[r.SpanID])
+	}
+	if got, _ := strconv.Atoi(string(anns[r.SpanID].get(GoroutinesSpawnedKey))); got < 2 {
+		t.Errorf("got %d goroutines spawned, want at least 2", got)
+	}
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestRecorder_Clock(t *testing.T) {
+	var anns Annotations
+	c := collectorFunc(func(spanID SpanID, as ...Annotation) error {
+		anns = append(anns, as...)
+		return nil
+	})
+
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRecorder(SpanID{1, 2, 0}, c)
+	r.Clock = fixedClock(now)
+	child := r.Child()
+	child.Log("msg")
+	child.Finish()
+
+	if diff := diffAnnotationsFromEvent(anns, LogWithTimestamp("msg", now)); len(diff) > 0 {
+		t.Errorf("got diff annotations for Log event:\n%s", strings.Join(diff, "\n"))
+	}
+}
+
+func TestRecorder_Enrichers(t *testing.T) {
+	type ctxKey struct{}
+	anns := map[SpanID]Annotations{}
+	c := collectorFunc(func(spanID SpanID, as ...Annotation) error {
+		anns[spanID] = append(anns[spanID], as...)
+		return nil
+	})
+
+	tenant := func(ctx context.Context, span SpanID, as Annotations) []Annotation {
+		return []Annotation{{Key: "Tenant", Value: []byte(ctx.Value(ctxKey{}).(string))}}
+	}
+	// order sees the annotations added by the enrichers before it.
+	order := func(ctx context.Context, span SpanID, as Annotations) []Annotation {
+		if !as.has("Tenant") {
+			t.Errorf("span %v: Tenant not added before order enricher ran", span)
+		}
+		return []Annotation{{Key: "Last", Value: []byte("order")}}
+	}
+
+	r := NewRecorder(SpanID{1, 2, 0}, c)
+	r.Enrichers = []Enricher{tenant, order}
+	r.Context = context.WithValue(context.Background(), ctxKey{}, "acme")
+	r.Name("root")
+	child := r.Child()
+	child.Name("child")
+	child.Finish()
+	r.Finish()
+
+	if len(anns) != 2 {
+		t.Fatalf("got %d spans, want 2", len(anns))
+	}
+	for id, as := range anns {
+		if got := string(as.get("Tenant")); got != "acme" {
+			t.Errorf("span %v: got Tenant %q, want %q", id, got, "acme")
+		}
+		var keys []string
+		for _, a := range as {
+			if a.Key == "Tenant" || a.Key == "Last" {
+				keys = append(keys, a.Key)
+			}
+		}
+		if want := []string{"Tenant", "Last"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("span %v: got enriched keys %v, want %v", id, keys, want)
+		}
+	}
+}
+
+func TestRecorder_Enrichers_none(t *testing.T) {
+	record := func(enrichers []Enricher) (anns Annotations) {
+		c := collectorFunc(func(spanID SpanID, as ...Annotation) error {
+			anns = append(anns, as...)
+			return nil
+		})
+		r := NewRecorder(SpanID{1, 2, 3}, c)
+		r.Enrichers = enrichers
+		r.Name("n")
+		r.Finish()
+		return anns
+	}
+
+	want := record(nil)
+	if got := record([]Enricher{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("with an empty chain: got %#v, want %#v", got, want)
+	}
+	noop := func(context.Context, SpanID, Annotations) []Annotation { return nil }
+	if got := record([]Enricher{noop}); !reflect.DeepEqual(got, want) {
+		t.Errorf("with an enricher adding nothing: got %#v, want %#v", got, want)
+	}
+}
+
+func TestRecorder_InheritedKeys(t *testing.T) {
+	ms := NewMemoryStore()
+	r := NewRecorder(SpanID{1, 2, 0}, NewLocalCollector(ms))
+	r.InheritedKeys = []string{"Tenant", TagPrefix + "requestID"}
+	r.Annotation(Annotation{Key: "Tenant", Value: []byte("acme")})
+	r.Tag("requestID", "r1")
+	r.Tag("other", "x")
+	r.Annotation(Annotation{Key: "Local", Value: []byte("root only")})
+
+	child := r.Child()
+	// Later changes to the parent are not propagated to existing children.
+	r.Tag("requestID", "r2")
+	grandchild := child.Child()
+	grandchild.Finish()
+	child.Finish()
+	sibling := r.Child()
+	sibling.Finish()
+	r.Finish()
+
+	want := map[SpanID][2]string{
+		child.SpanID:      {"acme", "r1"},
+		grandchild.SpanID: {"acme", "r1"},
+		sibling.SpanID:    {"acme", "r2"},
+	}
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, w := range want {
+		sub := trace.FindSpan(id.Span)
+		if sub == nil {
+			t.Fatalf("span %v not found", id)
+		}
+		if got := string(sub.Span.Annotations.get("Tenant")); got != w[0] {
+			t.Errorf("span %v: got Tenant %q, want %q", id, got, w[0])
+		}
+		tags := sub.Span.Tags()
+		if tags["requestID"] != w[1] {
+			t.Errorf("span %v: got requestID tag %q, want %q", id, tags["requestID"], w[1])
+		}
+		if _, ok := tags["other"]; ok {
+			t.Errorf("span %v: non-inherited tag was copied", id)
+		}
+		if sub.Span.Annotations.has("Local") {
+			t.Errorf("span %v: non-inherited annotation was copied", id)
+		}
+	}
+
+	// Only the last annotation with each inherited key is kept, however
+	// many are recorded on a long-lived span.
+	for i := 0; i < 100; i++ {
+		r.Annotation(Annotation{Key: "Tenant", Value: []byte(fmt.Sprint(i))})
+	}
+	if len(r.inheritable) != len(r.InheritedKeys) {
+		t.Errorf("got %d inheritable annotations kept, want one per key", len(r.inheritable))
+	}
+	if got := string(r.inheritedAnnotations()[0].Value); got != "99" {
+		t.Errorf("got inherited Tenant %q, want the last one", got)
+	}
+}
+
 func diffAnnotationsFromEvent(anns Annotations, e Event) (diff []string) {
 	eventAnns, err := MarshalEvent(e)
 	if err != nil {
@@ -95,3 +406,135 @@ func diffAnnotationsFromEvent(anns Annotations, e Event) (diff []string) {
 	}
 	return diff
 }
+
+func TestRecorder_MaxDepth(t *testing.T) {
+	ms := NewMemoryStore()
+	r := NewRecorder(SpanID{1, 1, 0}, NewLocalCollector(ms))
+	r.MaxDepth = 3
+	r.Name("root")
+	r.Finish()
+
+	// Recurse well beyond the limit.
+	var recurse func(r *Recorder, n int)
+	recurse = func(r *Recorder, n int) {
+		if n == 0 {
+			return
+		}
+		c := r.Start(fmt.Sprintf("level %d", n))
+		c.Msg(fmt.Sprintf("level %d", n))
+		recurse(c.Recorder, n-1)
+		c.Finish()
+	}
+	recurse(r, 10)
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deepest *Span
+	var maxDepth, truncated int
+	trace.Walk(func(span *Span, depth int) error {
+		if depth > maxDepth {
+			maxDepth, deepest = depth, span
+		}
+		if span.Annotations.has(DepthTruncatedKey) {
+			truncated++
+		}
+		return nil
+	})
+	if maxDepth != 3 {
+		t.Errorf("got max depth %d, want 3", maxDepth)
+	}
+	if truncated != 1 || !deepest.Annotations.has(DepthTruncatedKey) {
+		t.Errorf("got %d truncated spans, want just the deepest one %v", truncated, deepest)
+	}
+	// The collapsed children's annotations are kept on the deepest span.
+	var msgs int
+	for _, a := range deepest.Annotations {
+		if a.Key == "Msg" {
+			msgs++
+		}
+	}
+	if msgs != 8 {
+		t.Errorf("got %d messages on the deepest span, want 8", msgs)
+	}
+	// ...but not their names and timespans, which would overwrite the
+	// deepest span's own.
+	if got, want := deepest.Name(), "level 8"; got != want {
+		t.Errorf("got deepest span name %q, want %q", got, want)
+	}
+	var timespans int
+	for _, a := range deepest.Annotations {
+		if a.Key == "Span.Start" {
+			timespans++
+		}
+	}
+	if timespans != 1 {
+		t.Errorf("got %d timespans on the deepest span, want 1", timespans)
+	}
+
+	// Negative MaxDepth disables the limit.
+	r = NewRecorder(SpanID{2, 2, 0}, NewLocalCollector(ms))
+	r.MaxDepth = -1
+	r.Finish()
+	recurse(r, 10)
+	trace, err = ms.Trace(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxDepth = 0
+	trace.Walk(func(span *Span, depth int) error {
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		return nil
+	})
+	if maxDepth != 10 {
+		t.Errorf("got max depth %d with no limit, want 10", maxDepth)
+	}
+}
+
+func TestRecorder_Start(t *testing.T) {
+	ms := NewMemoryStore()
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := NewRecorder(SpanID{1, 1, 0}, NewLocalCollector(ms))
+	r.Clock = clock
+	r.Finish()
+
+	func() {
+		span := r.Start("db query")
+		defer span.Finish()
+		clock.Advance(25 * time.Millisecond)
+	}()
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.Sub) != 1 {
+		t.Fatalf("got %d child spans, want 1", len(trace.Sub))
+	}
+	span := trace.Sub[0].Span
+	if name := span.Name(); name != "db query" {
+		t.Errorf("got name %q, want %q", name, "db query")
+	}
+	if d, ok := span.Duration(); !ok || d != 25*time.Millisecond {
+		t.Errorf("got duration %v (ok %v), want 25ms", d, ok)
+	}
+
+	// Finishing again has no effect.
+	var anns Annotations
+	r = NewRecorder(SpanID{2, 2, 0}, collectorFunc(func(span SpanID, as ...Annotation) error {
+		anns = append(anns, as...)
+		return nil
+	}))
+	as := r.Start("twice")
+	as.Finish()
+	as.Finish()
+	if errs := as.Errors(); len(errs) != 0 {
+		t.Errorf("got errors %v from finishing twice, want none", errs)
+	}
+	if got := len(anns.schemas()); got != 2 {
+		t.Errorf("got %d events %v, want a name and a timespan", got, anns)
+	}
+}
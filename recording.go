@@ -0,0 +1,174 @@
+package appdash
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	pio "github.com/gogo/protobuf/io"
+	"sourcegraph.com/sourcegraph/appdash/internal/wire"
+)
+
+// A recording written by RecordingCollector starts with the header line
+//
+//  appdash-recording <version>\n
+//
+// followed by each collection as a varint length-prefixed protobuf
+// CollectPacket, the same framing used between RemoteCollector and
+// CollectorServer. The version is incremented whenever the format changes.
+const (
+	recordingMagic   = "appdash-recording"
+	recordingVersion = 1
+)
+
+// ErrBadRecording is returned by Replay when its input does not start with
+// a recording header.
+var ErrBadRecording = errors.New("not an appdash recording")
+
+// A RecordingCollector records every collection, in order, to a writer so
+// that it may later be fed back into a collector using Replay. This is
+// useful to capture traffic as a test fixture, or to load production traces
+// into a development instance. Because span IDs are recorded verbatim, a
+// replay reproduces exactly the same traces.
+type RecordingCollector struct {
+	// Collector, if non-nil, is the underlying collector that collections
+	// are passed on to after being recorded.
+	Collector
+
+	raw           io.Writer       // the writer given to NewRecordingCollector
+	w             pio.WriteCloser // delimited-protobuf writer wrapping raw
+	headerWritten bool
+	mu            sync.Mutex // protects w and headerWritten
+}
+
+// NewRecordingCollector returns a RecordingCollector that records to w and
+// then passes each collection on to c, which may be nil.
+func NewRecordingCollector(w io.Writer, c Collector) *RecordingCollector {
+	return &RecordingCollector{
+		Collector: c,
+		raw:       w,
+		w:         pio.NewDelimitedWriter(w),
+	}
+}
+
+// Collect implements the Collector interface by recording the collection
+// and then passing it on to the underlying collector, if any.
+func (rc *RecordingCollector) Collect(id SpanID, as ...Annotation) error {
+	rc.mu.Lock()
+	if !rc.headerWritten {
+		if _, err := fmt.Fprintf(rc.raw, "%s %d\n", recordingMagic, recordingVersion); err != nil {
+			rc.mu.Unlock()
+			return err
+		}
+		rc.headerWritten = true
+	}
+	err := rc.w.WriteMsg(newCollectPacket(id, as))
+	rc.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if rc.Collector == nil {
+		return nil
+	}
+	return rc.Collector.Collect(id, as...)
+}
+
+// Replay reads a recording made by a RecordingCollector from r and collects
+// each recorded collection, in order, into c. An empty input is a valid
+// recording of no collections.
+func Replay(r io.Reader, c Collector) error {
+	br := bufio.NewReader(r)
+	header, err := br.ReadString('\n')
+	if err == io.EOF && header == "" {
+		return nil
+	}
+	if err != nil {
+		return ErrBadRecording
+	}
+	var (
+		magic   string
+		version int
+	)
+	if _, err := fmt.Sscanf(header, "%s %d\n", &magic, &version); err != nil || magic != recordingMagic {
+		return ErrBadRecording
+	}
+	if version != recordingVersion {
+		return fmt.Errorf("unsupported appdash recording version %d (want %d)", version, recordingVersion)
+	}
+
+	fr := newFrameReader(br, maxMessageSize)
+	for {
+		var p wire.CollectPacket
+		if err := fr.ReadMsg(&p); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("Replay: %s", err)
+		}
+		if !validCollectPacket(&p) {
+			return errors.New("Replay: collect packet is missing its span ID")
+		}
+		if err := c.Collect(spanIDFromWire(p.Spanid), annotationsFromWire(p.Annotation)...); err != nil {
+			return err
+		}
+	}
+}
+
+// Export writes every span of every trace returned by q to w, in the
+// recording format read by Replay and Import. Traces are written in order
+// of trace ID and their spans in the order of Trace.Walk, so that exporting
+// the same data twice yields the same output. Together with Import, it
+// supports backups and migrating data between store implementations.
+func Export(w io.Writer, q Queryer) error {
+	traces, err := q.Traces(TracesOpts{})
+	if err != nil {
+		return err
+	}
+	sort.Sort(tracesByID(traces))
+
+	rc := NewRecordingCollector(w, nil)
+	for _, t := range traces {
+		err := t.Walk(func(span *Span, depth int) error {
+			return rc.Collect(span.ID, span.Annotations...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import reads a recording made by Export (or a RecordingCollector) from r
+// and collects it into s, one span at a time. Traces that already exist in
+// s are skipped entirely, so importing the same data more than once has no
+// further effect.
+func Import(r io.Reader, s Store) error {
+	skip := map[ID]bool{}
+	return Replay(r, collectorFunc(func(id SpanID, as ...Annotation) error {
+		skipTrace, seen := skip[id.Trace]
+		if !seen {
+			_, err := s.Trace(id.Trace)
+			switch err {
+			case nil:
+				skipTrace = true
+			case ErrTraceNotFound:
+			default:
+				return err
+			}
+			skip[id.Trace] = skipTrace
+		}
+		if skipTrace {
+			return nil
+		}
+		return s.Collect(id, as...)
+	}))
+}
+
+type tracesByID []*Trace
+
+func (t tracesByID) Len() int           { return len(t) }
+func (t tracesByID) Less(i, j int) bool { return t[i].ID.Trace < t[j].ID.Trace }
+func (t tracesByID) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
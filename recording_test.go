@@ -0,0 +1,146 @@
+package appdash
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordingCollector_Replay(t *testing.T) {
+	type collection struct {
+		ID  SpanID
+		Ann Annotations
+	}
+	var got, passedOn []collection
+
+	var buf bytes.Buffer
+	rc := NewRecordingCollector(&buf, collectorFunc(func(id SpanID, as ...Annotation) error {
+		passedOn = append(passedOn, collection{id, as})
+		return nil
+	}))
+	want := []collection{
+		{SpanID{1, 2, 0}, Annotations{{Key: "k1", Value: []byte("v1")}}},
+		{SpanID{1, 3, 2}, Annotations{{Key: "k2", Value: []byte("v2")}, {Key: "k3", Value: []byte{}}}},
+		{SpanID{1, 2, 0}, Annotations{{Key: "k4", Value: []byte("v4")}}},
+	}
+	for _, c := range want {
+		if err := rc.Collect(c.ID, c.Ann...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(passedOn, want) {
+		t.Errorf("passed on %v, want %v", passedOn, want)
+	}
+	if !strings.HasPrefix(buf.String(), "appdash-recording 1\n") {
+		t.Errorf("recording does not start with a header: %q", buf.String())
+	}
+
+	err := Replay(&buf, collectorFunc(func(id SpanID, as ...Annotation) error {
+		got = append(got, collection{id, as})
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed %v, want %v", got, want)
+	}
+}
+
+func TestRecordingCollector_ReplayIntoStore(t *testing.T) {
+	var buf bytes.Buffer
+	orig := NewMemoryStore()
+	rec := NewRecorder(NewRootSpanID(), NewRecordingCollector(&buf, orig))
+	rec.Name("root")
+	rec.Finish()
+	child := rec.Child()
+	child.Msg("hello")
+	child.Finish()
+
+	replayed := NewMemoryStore()
+	if err := Replay(&buf, replayed); err != nil {
+		t.Fatal(err)
+	}
+	want, err := orig.Trace(rec.SpanID.Trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := replayed.Trace(rec.SpanID.Trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got replayed trace %v, want %v", got, want)
+	}
+}
+
+func TestReplay_badInput(t *testing.T) {
+	c := collectorFunc(func(SpanID, ...Annotation) error { return nil })
+	if err := Replay(strings.NewReader(""), c); err != nil {
+		t.Errorf("empty recording: got error %v, want nil", err)
+	}
+	if err := Replay(strings.NewReader("foo\n"), c); err != ErrBadRecording {
+		t.Errorf("got error %v, want %v", err, ErrBadRecording)
+	}
+	if err := Replay(strings.NewReader("appdash-recording 99\n"), c); err == nil {
+		t.Error("unsupported version: got nil error")
+	}
+	if err := Replay(strings.NewReader("appdash-recording 1\n\x05ab"), c); err == nil {
+		t.Error("truncated recording: got nil error")
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	orig := NewMemoryStore()
+	for _, trace := range []ID{2, 1} {
+		root := NewRecorder(SpanID{Trace: trace, Span: 10}, orig)
+		root.Name("root")
+		root.TraceAnnotation("tenant", []byte("acme"))
+		root.Finish()
+		child := NewRecorder(SpanID{Trace: trace, Span: 11, Parent: 10}, orig)
+		child.Msg("hello")
+		child.Finish()
+		grandchild := NewRecorder(SpanID{Trace: trace, Span: 12, Parent: 11}, orig)
+		grandchild.Log("world")
+		grandchild.Finish()
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, orig); err != nil {
+		t.Fatal(err)
+	}
+	exported := buf.String()
+
+	imported := NewMemoryStore()
+	for i := 0; i < 2; i++ {
+		if err := Import(strings.NewReader(exported), imported); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range []ID{1, 2} {
+		want, err := orig.Trace(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := imported.Trace(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("trace %v: got imported\n%s\nwant\n%s", id, got.TreeString(), want.TreeString())
+		}
+	}
+	if anns, _ := imported.TraceAnnotations(1); len(anns) != 1 {
+		t.Errorf("got trace annotations %v, want 1", anns)
+	}
+
+	// Exporting the imported store yields the same output.
+	buf.Reset()
+	if err := Export(&buf, imported); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != exported {
+		t.Error("re-exported data differs from the original export")
+	}
+}
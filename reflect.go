@@ -184,9 +184,17 @@ func unflattenValue(prefix string, v reflect.Value, t reflect.Type, kv *[][2]str
 	if len(*kv) == 0 {
 		return nil
 	}
-	if !strings.HasPrefix((*kv)[0][0], prefix) && t.Kind() != reflect.Map { // map can have 0 fields
-		*kv = (*kv)[1:]
-		return unflattenValue(prefix, v, t, kv)
+	if t.Kind() != reflect.Map { // map can have 0 fields
+		// Skip values that sort before prefix and so do not belong to any
+		// field. If the next value does not have the prefix either, the
+		// value is absent (e.g. annotations recorded before a field was
+		// added to an event), so leave it for the following fields.
+		for len(*kv) > 0 && (*kv)[0][0] < prefix {
+			*kv = (*kv)[1:]
+		}
+		if len(*kv) == 0 || !strings.HasPrefix((*kv)[0][0], prefix) {
+			return nil
+		}
 	}
 
 	if v.IsValid() {
@@ -337,8 +345,15 @@ func fieldNames(v reflect.Value) map[int]string {
 	return m
 }
 
+// fieldName returns the annotation key of the struct field f, relative to
+// the key of the struct. It is given by the field's "appdash" struct tag
+// (e.g. `appdash:"request_method"`), or else by its older "trace" tag, or
+// else is the field's Go name.
 func fieldName(f reflect.StructField) string {
-	name := f.Tag.Get("trace")
+	name := f.Tag.Get("appdash")
+	if name == "" {
+		name = f.Tag.Get("trace")
+	}
 	if name == "" {
 		name = f.Name
 	}
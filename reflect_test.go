@@ -94,6 +94,49 @@ func TestFlattenNamedValues(t *testing.T) {
 	}
 }
 
+func TestFlattenTaggedNames(t *testing.T) {
+	type Request struct {
+		Method string `appdash:"method"`
+		URI    string
+	}
+	type T struct {
+		Request  Request `appdash:"http_request"`
+		Status   int     `appdash:"status_code"`
+		Legacy   string  `trace:"legacy"`
+		Override string  `appdash:"new_name" trace:"old_name"`
+	}
+	e := T{
+		Request:  Request{Method: "GET", URI: "/foo"},
+		Status:   404,
+		Legacy:   "x",
+		Override: "y",
+	}
+
+	got := make(map[string]string)
+	flattenValue("", reflect.ValueOf(e), func(k, v string) {
+		got[k] = v
+	})
+
+	want := map[string]string{
+		"http_request.method": "GET",
+		"http_request.URI":    "/foo",
+		"status_code":         "404",
+		"legacy":              "x",
+		"new_name":            "y",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	var gotE T
+	if err := unflattenValue("", reflect.ValueOf(&gotE), reflect.TypeOf(&gotE), mapToKVs(want)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotE, e) {
+		t.Errorf("got %#v, want %#v", gotE, e)
+	}
+}
+
 func TestFlattenTime(t *testing.T) {
 	type T struct {
 		Value time.Time
@@ -0,0 +1,182 @@
+package appdash
+
+import (
+	"container/list"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// A RingBufferCollector is a flight recorder for traces: it keeps the most
+// recent N complete traces in memory, overwriting the oldest, so that they
+// may be dumped on demand (e.g. when an incident happens) without storing
+// every trace all of the time.
+//
+// A trace is considered complete once no new spans have been collected for
+// it for QuietPeriod. Completeness is checked whenever a span is collected
+// or the buffer is dumped. Spans arriving after their trace was considered
+// complete start a new, separate copy of that trace.
+type RingBufferCollector struct {
+	// N is the number of complete traces to retain.
+	N int
+
+	// QuietPeriod is how long a trace must go without new spans before it
+	// is considered complete and moved into the ring buffer.
+	QuietPeriod time.Duration
+
+	// Clock, if non-nil, is used instead of RealClock to determine when
+	// traces become complete.
+	Clock Clock
+
+	// Log is the logger used by DumpOnSignal. If nil, the log package's
+	// default logger is used.
+	Log *log.Logger
+
+	mu       sync.Mutex
+	pending  *MemoryStore         // traces still receiving spans
+	lastSeen map[ID]*list.Element // trace ID -> its element of quiet
+	quiet    *list.List           // *pendingTrace of each pending trace, least recently seen first
+	ring     []*Trace             // circular list of complete traces in completion order
+	next     int                  // ring index for the next complete trace
+}
+
+// pendingTrace is a trace that is still receiving spans.
+type pendingTrace struct {
+	id   ID
+	seen time.Time // when its last span was collected
+}
+
+// NewRingBufferCollector returns a RingBufferCollector that retains the
+// most recent n complete traces, where a trace is complete after receiving
+// no new spans for quiet.
+func NewRingBufferCollector(n int, quiet time.Duration) *RingBufferCollector {
+	return &RingBufferCollector{N: n, QuietPeriod: quiet}
+}
+
+func (rc *RingBufferCollector) now() time.Time {
+	if rc.Clock != nil {
+		return rc.Clock.Now()
+	}
+	return RealClock.Now()
+}
+
+// Collect implements the Collector interface by buffering the span until
+// its trace is complete.
+func (rc *RingBufferCollector) Collect(id SpanID, as ...Annotation) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.pending == nil {
+		rc.pending = NewMemoryStore()
+		rc.lastSeen = make(map[ID]*list.Element)
+		rc.quiet = list.New()
+	}
+
+	now := rc.now()
+	rc.completeNoLock(now)
+	if e, ok := rc.lastSeen[id.Trace]; ok {
+		e.Value.(*pendingTrace).seen = now
+		rc.quiet.MoveToBack(e)
+	} else {
+		rc.lastSeen[id.Trace] = rc.quiet.PushBack(&pendingTrace{id: id.Trace, seen: now})
+	}
+	return rc.pending.Collect(id, as...)
+}
+
+// completeNoLock moves every pending trace that has been quiet for
+// QuietPeriod as of now into the ring buffer, least recently seen first.
+// Since rc.quiet is in that order, it stops at the first trace that is not
+// quiet yet, so that collecting a span does not visit every pending trace.
+// The rc.mu lock must be held while calling completeNoLock.
+func (rc *RingBufferCollector) completeNoLock(now time.Time) {
+	for e := rc.quiet.Front(); e != nil; e = rc.quiet.Front() {
+		p := e.Value.(*pendingTrace)
+		if now.Sub(p.seen) < rc.QuietPeriod {
+			return
+		}
+		rc.quiet.Remove(e)
+		delete(rc.lastSeen, p.id)
+		t, err := rc.pending.Trace(p.id)
+		if err != nil {
+			continue
+		}
+		rc.pending.Delete(p.id)
+		rc.pushNoLock(t)
+	}
+}
+
+// pushNoLock adds a complete trace to the ring buffer, overwriting the
+// oldest one if it is full. The rc.mu lock must be held while calling
+// pushNoLock.
+func (rc *RingBufferCollector) pushNoLock(t *Trace) {
+	if rc.N <= 0 {
+		return
+	}
+	if len(rc.ring) < rc.N {
+		rc.ring = append(rc.ring, t)
+		return
+	}
+	rc.ring[rc.next] = t
+	rc.next = (rc.next + 1) % rc.N
+}
+
+// Dump returns a snapshot of the complete traces currently held in the
+// ring buffer, oldest first. Traces that are still receiving spans are not
+// included.
+func (rc *RingBufferCollector) Dump() []*Trace {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.pending != nil {
+		rc.completeNoLock(rc.now())
+	}
+
+	traces := make([]*Trace, 0, len(rc.ring))
+	traces = append(traces, rc.ring[rc.next:]...)
+	traces = append(traces, rc.ring[:rc.next]...)
+	return traces
+}
+
+// DumpOnSignal writes the result of Dump as JSON (the format accepted by
+// traceapp's Import JSON button) to the named file each time one of the
+// given signals is received, typically syscall.SIGUSR1. Call the returned
+// function to stop handling the signals.
+func (rc *RingBufferCollector) DumpOnSignal(filename string, sig ...os.Signal) (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, sig...)
+	go func() {
+		for {
+			select {
+			case <-c:
+				if err := rc.dumpToFile(filename); err != nil {
+					rc.logf("RingBufferCollector: failed to dump traces: %s", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
+
+func (rc *RingBufferCollector) dumpToFile(filename string) error {
+	data, err := json.MarshalIndent(rc.Dump(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0600)
+}
+
+func (rc *RingBufferCollector) logf(format string, args ...interface{}) {
+	if rc.Log != nil {
+		rc.Log.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
@@ -0,0 +1,94 @@
+package appdash
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock that only advances when told to.
+type manualClock struct{ t time.Time }
+
+func (c *manualClock) Now() time.Time { return c.t }
+
+func (c *manualClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestRingBufferCollector(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rc := NewRingBufferCollector(3, time.Second)
+	rc.Clock = clock
+
+	// Continuously ingest traces, each with a root and a child span; each
+	// trace completes while the next one is being collected.
+	for i := 1; i <= 10; i++ {
+		root := SpanID{Trace: ID(i), Span: ID(100 + i)}
+		child := SpanID{Trace: ID(i), Span: ID(200 + i), Parent: root.Span}
+		for _, id := range []SpanID{root, child} {
+			if err := rc.Collect(id, Annotation{Key: "k", Value: []byte("v")}); err != nil {
+				t.Fatal(err)
+			}
+			clock.Advance(500 * time.Millisecond)
+		}
+		clock.Advance(time.Second)
+	}
+
+	// A trace that is still receiving spans is not dumped.
+	if err := rc.Collect(SpanID{Trace: 11, Span: 111}); err != nil {
+		t.Fatal(err)
+	}
+
+	traces := rc.Dump()
+	var got []ID
+	for _, tr := range traces {
+		got = append(got, tr.Span.ID.Trace)
+		if len(tr.Sub) != 1 {
+			t.Errorf("trace %v: got %d sub-spans, want 1", tr.Span.ID.Trace, len(tr.Sub))
+		}
+	}
+	if want := []ID{8, 9, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got traces %v, want %v", got, want)
+	}
+
+	clock.Advance(time.Second)
+	traces = rc.Dump()
+	got = got[:0]
+	for _, tr := range traces {
+		got = append(got, tr.Span.ID.Trace)
+	}
+	if want := []ID{9, 10, 11}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after trace 11 completed, got traces %v, want %v", got, want)
+	}
+}
+
+func TestRingBufferCollector_lastSeenOrder(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rc := NewRingBufferCollector(10, time.Second)
+	rc.Clock = clock
+
+	// Trace 1 starts first but keeps receiving spans, so traces 2 and 3
+	// complete before it, in the order they were last seen.
+	collect := func(trace ID, span ID) {
+		if err := rc.Collect(SpanID{Trace: trace, Span: span}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	collect(1, 1)
+	clock.Advance(100 * time.Millisecond)
+	collect(3, 3)
+	clock.Advance(100 * time.Millisecond)
+	collect(2, 2)
+	clock.Advance(700 * time.Millisecond)
+	collect(1, 4)
+	clock.Advance(350 * time.Millisecond)
+
+	var got []ID
+	for _, tr := range rc.Dump() {
+		got = append(got, tr.Span.ID.Trace)
+	}
+	if want := []ID{3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got complete traces %v, want %v", got, want)
+	}
+	if n := rc.quiet.Len(); n != 1 || len(rc.lastSeen) != 1 {
+		t.Errorf("got %d pending traces (%d in lastSeen), want just trace 1", n, len(rc.lastSeen))
+	}
+}
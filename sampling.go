@@ -0,0 +1,769 @@
+package appdash
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SamplingPriorityKey is the reserved annotation key under which a span's
+// sampling priority is recorded. The priority signals to tail-based samplers
+// (such as TailSamplingCollector) that a trace is interesting and should be
+// kept, e.g. because it errored or was slow. Unprioritized spans have
+// priority 0; instrumentation in this repository bumps the priority of
+// failed or slow operations to 1.
+const SamplingPriorityKey = "_samplingPriority"
+
+// SamplingPriority returns an annotation setting the sampling priority of
+// the span it is collected on. If a span has several, its priority is the
+// highest of them.
+func SamplingPriority(p int) Annotation {
+	return Annotation{Key: SamplingPriorityKey, Value: []byte(strconv.Itoa(p))}
+}
+
+// SamplingPriority returns the sampling priority of the span (see
+// SamplingPriorityKey), or 0 if it has none.
+func (s *Span) SamplingPriority() int {
+	return maxSamplingPriority(s.Annotations)
+}
+
+// maxSamplingPriority returns the highest sampling priority in as, or 0.
+// Malformed priorities are ignored.
+func maxSamplingPriority(as Annotations) int {
+	var max int
+	for _, a := range as {
+		if a.Key != SamplingPriorityKey {
+			continue
+		}
+		if p, err := strconv.Atoi(string(a.Value)); err == nil && p > max {
+			max = p
+		}
+	}
+	return max
+}
+
+// A TailSamplingCollector makes sampling decisions after seeing whole
+// traces: it buffers each trace until it is quiescent and then passes it on
+// to the underlying collector only if the sampling priority of any of its
+// spans (see SamplingPriorityKey) exceeds Threshold. All other traces are
+// dropped. This keeps errored and slow traces while dropping the boring
+// ones.
+//
+// A trace is decided once no new spans have been collected for it for
+// QuietPeriod, or once it has been buffered for MaxAge, whichever comes
+// first. When MaxTraces traces are buffered, the oldest is decided early to
+// make room for a new one. Spans arriving after their trace was decided are
+// buffered and decided as a new trace.
+//
+// Other policies may be used to decide which traces to keep by setting
+// Keep; see ExemplarSelector for an example.
+type TailSamplingCollector struct {
+	// Collector is the underlying collector that kept traces are sent to.
+	Collector
+
+	// Threshold is the sampling priority that a trace's highest span
+	// priority must exceed for the trace to be kept. With the default of 0,
+	// traces are kept if any span has a positive priority.
+	Threshold int
+
+	// Keep, if non-nil, decides whether to keep a trace instead of
+	// Threshold. It is called with the trace's spans, in the order in which
+	// they were first collected, each with all of its collected
+	// annotations.
+	Keep func(spans []Span) bool
+
+	// QuietPeriod is how long a trace must go without new spans before it
+	// is decided.
+	QuietPeriod time.Duration
+
+	// MaxAge is the longest time a trace may be buffered before it is
+	// decided, even if it is still receiving spans.
+	MaxAge time.Duration
+
+	// MaxTraces is the maximum number of traces buffered at once.
+	MaxTraces int
+
+	// Clock, if non-nil, is used instead of RealClock to measure
+	// QuietPeriod and MaxAge.
+	Clock Clock
+
+	// Log, if non-nil, is used to log errors returned by the underlying
+	// collector when a kept trace is sent to it in the background.
+	Log *log.Logger
+
+	// Stats, if non-nil, is updated with the number of spans dropped and
+	// the number of errors returned by the underlying collector.
+	Stats *Stats
+
+	pending          map[ID]*tailTrace
+	started, stopped bool
+	stopChan         chan struct{}
+
+	mu sync.Mutex // protects pending, started, stopped and stopChan
+}
+
+// tailTrace is a trace buffered by a TailSamplingCollector.
+type tailTrace struct {
+	first, last time.Time // when the first and latest span were collected
+	priority    int       // highest sampling priority seen so far
+	collections []spanCollection
+}
+
+// spanCollection holds the arguments of a single call to Collect.
+type spanCollection struct {
+	span SpanID
+	anns []Annotation
+}
+
+// NewTailSamplingCollector is shorthand for:
+//
+// 	c := &TailSamplingCollector{
+// 		Collector:   c,
+// 		QuietPeriod: 5 * time.Second,
+// 		MaxAge:      time.Minute,
+// 		MaxTraces:   10000,
+// 	}
+//
+func NewTailSamplingCollector(c Collector) *TailSamplingCollector {
+	return &TailSamplingCollector{
+		Collector:   c,
+		QuietPeriod: 5 * time.Second,
+		MaxAge:      time.Minute,
+		MaxTraces:   10000,
+	}
+}
+
+func (tc *TailSamplingCollector) now() time.Time {
+	if tc.Clock != nil {
+		return tc.Clock.Now()
+	}
+	return RealClock.Now()
+}
+
+// Collect implements the Collector interface by buffering the span until
+// its trace is decided. It returns any error of the underlying collector
+// for traces that were decided (and kept) during the call.
+func (tc *TailSamplingCollector) Collect(span SpanID, anns ...Annotation) error {
+	tc.mu.Lock()
+	if tc.stopped {
+		tc.mu.Unlock()
+		return errors.New("TailSamplingCollector is stopped")
+	}
+	if !tc.started {
+		tc.start()
+	}
+	if tc.pending == nil {
+		tc.pending = make(map[ID]*tailTrace)
+	}
+
+	now := tc.now()
+	decided := tc.expiredNoLock(now)
+	t, present := tc.pending[span.Trace]
+	if !present {
+		if tc.MaxTraces > 0 && len(tc.pending) >= tc.MaxTraces {
+			decided = append(decided, tc.removeNoLock(tc.oldestNoLock()))
+		}
+		t = &tailTrace{first: now}
+		tc.pending[span.Trace] = t
+	}
+	t.last = now
+	if p := maxSamplingPriority(anns); p > t.priority {
+		t.priority = p
+	}
+	t.collections = append(t.collections, spanCollection{span: span, anns: anns})
+	tc.mu.Unlock()
+
+	return tc.decide(decided)
+}
+
+// Flush immediately decides all buffered traces, regardless of whether
+// they are quiescent.
+func (tc *TailSamplingCollector) Flush() error {
+	tc.mu.Lock()
+	var decided []*tailTrace
+	for id := range tc.pending {
+		decided = append(decided, tc.removeNoLock(id))
+	}
+	tc.mu.Unlock()
+	return tc.decide(decided)
+}
+
+// expiredNoLock removes and returns the buffered traces that are quiescent
+// or have reached MaxAge as of now. The tc.mu lock must be held while
+// calling expiredNoLock.
+func (tc *TailSamplingCollector) expiredNoLock(now time.Time) []*tailTrace {
+	var expired []*tailTrace
+	for id, t := range tc.pending {
+		if now.Sub(t.last) >= tc.QuietPeriod || (tc.MaxAge > 0 && now.Sub(t.first) >= tc.MaxAge) {
+			expired = append(expired, tc.removeNoLock(id))
+		}
+	}
+	return expired
+}
+
+// oldestNoLock returns the ID of the buffered trace whose first span was
+// collected the earliest. The tc.mu lock must be held while calling
+// oldestNoLock.
+func (tc *TailSamplingCollector) oldestNoLock() ID {
+	var (
+		oldest ID
+		first  time.Time
+	)
+	for id, t := range tc.pending {
+		if first.IsZero() || t.first.Before(first) || (t.first.Equal(first) && id < oldest) {
+			oldest, first = id, t.first
+		}
+	}
+	return oldest
+}
+
+// removeNoLock removes the given trace from the buffer and returns it. The
+// tc.mu lock must be held while calling removeNoLock.
+func (tc *TailSamplingCollector) removeNoLock(id ID) *tailTrace {
+	t := tc.pending[id]
+	delete(tc.pending, id)
+	return t
+}
+
+// decide sends each of the given traces whose priority exceeds the
+// threshold to the underlying collector, and drops the rest.
+func (tc *TailSamplingCollector) decide(traces []*tailTrace) error {
+	var firstErr error
+	for _, t := range traces {
+		if !tc.keep(t) {
+			tc.Stats.droppedSpans(int64(len(t.collections)))
+			continue
+		}
+		for _, c := range t.collections {
+			if err := tc.Collector.Collect(c.span, c.anns...); err != nil {
+				tc.Stats.collectError()
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	return firstErr
+}
+
+// keep reports whether the buffered trace t should be kept.
+func (tc *TailSamplingCollector) keep(t *tailTrace) bool {
+	if tc.Keep == nil {
+		return t.priority > tc.Threshold
+	}
+	var spans []Span
+	index := make(map[SpanID]int, len(t.collections))
+	for _, c := range t.collections {
+		i, present := index[c.span]
+		if !present {
+			i = len(spans)
+			index[c.span] = i
+			spans = append(spans, Span{ID: c.span})
+		}
+		spans[i].Annotations = append(spans[i].Annotations, c.anns...)
+	}
+	return tc.Keep(spans)
+}
+
+// start starts the goroutine that periodically decides expired traces, so
+// that a trace is decided even if no further spans are collected.
+func (tc *TailSamplingCollector) start() {
+	tc.stopChan = make(chan struct{})
+	tc.started = true
+	interval := tc.QuietPeriod
+	if interval <= 0 {
+		interval = time.Second
+	}
+	go func() {
+		for {
+			select {
+			case <-time.After(interval):
+				tc.mu.Lock()
+				expired := tc.expiredNoLock(tc.now())
+				tc.mu.Unlock()
+				if err := tc.decide(expired); err != nil && tc.Log != nil {
+					tc.Log.Printf("TailSamplingCollector: %s", err)
+				}
+			case <-tc.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the collector's background goroutine. Buffered traces are not
+// decided; call Flush first to decide them. After stopping, calls to Collect
+// will fail.
+func (tc *TailSamplingCollector) Stop() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.started && !tc.stopped {
+		close(tc.stopChan)
+	}
+	tc.stopped = true
+}
+
+// A Sampler decides whether a span should be collected. Recorders and the
+// httptrace middleware consult their configured Sampler before collecting,
+// so that sampling policy is configured in one place.
+//
+// Implementations should make the same decision for every span of a trace
+// (e.g. by keying on span.Trace), so that traces are collected whole.
+type Sampler interface {
+	// ShouldSample reports whether the given annotations of the span should
+	// be collected.
+	ShouldSample(span SpanID, as Annotations) bool
+}
+
+// SamplerFunc is a Sampler implemented by a function.
+type SamplerFunc func(span SpanID, as Annotations) bool
+
+// ShouldSample implements the Sampler interface by calling the function
+// itself.
+func (f SamplerFunc) ShouldSample(span SpanID, as Annotations) bool { return f(span, as) }
+
+var (
+	// AlwaysSample is a Sampler that collects every span.
+	AlwaysSample Sampler = SamplerFunc(func(SpanID, Annotations) bool { return true })
+
+	// NeverSample is a Sampler that collects no spans.
+	NeverSample Sampler = SamplerFunc(func(SpanID, Annotations) bool { return false })
+)
+
+// ProbabilitySampler is a Sampler that collects the given fraction (between
+// 0 and 1) of traces. The decision is derived from the trace ID, which is
+// uniformly distributed, so all spans of a trace are sampled alike, even
+// across processes.
+//
+// A trace is sampled if TraceSampleValue(span.Trace) is less than the
+// fraction, so in tests, trace IDs on either side of a fraction's boundary
+// are easily constructed: at 0.25, trace 1<<62 - 1 is sampled and trace
+// 1<<62 is not.
+type ProbabilitySampler float64
+
+// ShouldSample implements the Sampler interface.
+func (p ProbabilitySampler) ShouldSample(span SpanID, as Annotations) bool {
+	return sampleFraction(float64(p), uint64(span.Trace))
+}
+
+// sampleFraction reports whether the hash h of a trace ID falls in the
+// given fraction of the uint64 range.
+func sampleFraction(f float64, h uint64) bool {
+	switch {
+	case f >= 1:
+		return true
+	case f <= 0:
+		return false
+	}
+	return h < uint64(f*math.MaxUint64)
+}
+
+// TraceSampleValue returns the value in [0, 1) that ProbabilitySampler
+// compares against its fraction to decide whether to sample the trace: the
+// trace ID divided by 2^64.
+func TraceSampleValue(trace ID) float64 {
+	return float64(uint64(trace)>>11) / (1 << 53)
+}
+
+// A SeededProbabilitySampler is a Sampler that collects the given Fraction
+// of traces, like ProbabilitySampler, but hashes trace IDs with Seed before
+// comparing them against the fraction. Samplers with different seeds make
+// independent decisions, which keeps several tiers of sampling (e.g. one
+// for collection and one for export) from selecting the same traces, and
+// keeps IDs from a deterministic ID source from falling into one range.
+//
+// For a fixed seed, decisions are reproducible: use Value to find trace IDs
+// that fall above or below a given fraction.
+type SeededProbabilitySampler struct {
+	Fraction float64 // fraction of traces to collect, between 0 and 1
+	Seed     uint64  // seed of the trace ID hash
+}
+
+// ShouldSample implements the Sampler interface.
+func (s SeededProbabilitySampler) ShouldSample(span SpanID, as Annotations) bool {
+	return sampleFraction(s.Fraction, s.hash(span.Trace))
+}
+
+// Value returns the value in [0, 1) that the sampler compares against its
+// Fraction to decide whether to sample the trace: a trace is sampled if its
+// value is less than the fraction.
+func (s SeededProbabilitySampler) Value(trace ID) float64 {
+	return TraceSampleValue(ID(s.hash(trace)))
+}
+
+// hash mixes the seed into the trace ID with the SplitMix64 finalizer,
+// which maps distinct IDs to distinct, uniformly distributed hashes.
+func (s SeededProbabilitySampler) hash(trace ID) uint64 {
+	z := uint64(trace) + s.Seed*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// SampledKey is the reserved annotation key under which the sampling
+// decision propagated with a span is recorded, as "1" if its trace is
+// sampled and "0" if not. Instrumentation that propagates decisions across
+// processes, such as httptrace's, records it so that samplers consulted
+// downstream can honor the decision made where the trace started.
+const SampledKey = "_sampled"
+
+// propagatedSampled returns the sampling decision recorded in as under
+// SampledKey, or ok == false if there is none.
+func propagatedSampled(as Annotations) (sampled, ok bool) {
+	v := as.get(SampledKey)
+	if v == nil {
+		return false, false
+	}
+	sampled, err := strconv.ParseBool(string(v))
+	return sampled, err == nil
+}
+
+// maxRateLimitedTraces is the number of trace decisions a RateLimitSampler
+// remembers.
+const maxRateLimitedTraces = 10000
+
+// traceDecisions remembers the sampling decisions for the most recent
+// maxRateLimitedTraces traces, forgetting the oldest first, so that the
+// spans of traces in progress keep being sampled alike as new traces
+// arrive. The zero value is empty. It is not safe for concurrent use.
+type traceDecisions struct {
+	decisions map[ID]bool // trace ID -> whether it is sampled
+	order     []ID        // circular list of the traces in decisions, in order
+	next      int         // order index of the oldest trace, once order is full
+}
+
+// get returns the decision for the given trace, if it is remembered.
+func (d *traceDecisions) get(trace ID) (sample, ok bool) {
+	sample, ok = d.decisions[trace]
+	return sample, ok
+}
+
+// put remembers the decision for the given trace, which must not already
+// be remembered, forgetting the oldest one if there are too many.
+func (d *traceDecisions) put(trace ID, sample bool) {
+	if d.decisions == nil {
+		d.decisions = make(map[ID]bool)
+	}
+	if len(d.order) < maxRateLimitedTraces {
+		d.order = append(d.order, trace)
+	} else {
+		delete(d.decisions, d.order[d.next])
+		d.order[d.next] = trace
+		d.next = (d.next + 1) % maxRateLimitedTraces
+	}
+	d.decisions[trace] = sample
+}
+
+// A RateLimitSampler is a Sampler that collects at most TracesPerSecond
+// traces per second on average, allowing bursts of up to one second's
+// worth. Decisions are remembered per trace so that all spans of a trace
+// seen by the sampler are sampled alike; only the decisions for the most
+// recent 10000 traces are kept.
+type RateLimitSampler struct {
+	// TracesPerSecond is the maximum average rate of sampled traces.
+	TracesPerSecond float64
+
+	// Clock, if non-nil, is used instead of RealClock to measure the rate.
+	Clock Clock
+
+	mu        sync.Mutex
+	bucket    tokenBucket
+	decisions traceDecisions
+}
+
+// NewRateLimitSampler returns a RateLimitSampler that collects at most
+// tracesPerSecond traces per second.
+func NewRateLimitSampler(tracesPerSecond float64) *RateLimitSampler {
+	return &RateLimitSampler{TracesPerSecond: tracesPerSecond}
+}
+
+// ShouldSample implements the Sampler interface.
+func (s *RateLimitSampler) ShouldSample(span SpanID, as Annotations) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sample, ok := s.decisions.get(span.Trace); ok {
+		return sample
+	}
+
+	now := RealClock.Now()
+	if s.Clock != nil {
+		now = s.Clock.Now()
+	}
+	sample := s.bucket.take(now, s.TracesPerSecond)
+	s.decisions.put(span.Trace, sample)
+	return sample
+}
+
+// A tokenBucket limits a rate of events, allowing bursts of up to one
+// second's worth (and at least one event). The zero value is a full bucket.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // when tokens was last updated, or zero if never
+}
+
+// tokenBurst returns the capacity of a token bucket refilled at rate tokens
+// per second.
+func tokenBurst(rate float64) float64 { return math.Max(1, rate) }
+
+// refill adds the tokens accrued since the bucket was last updated.
+func (b *tokenBucket) refill(now time.Time, rate float64) {
+	if b.last.IsZero() {
+		b.tokens = tokenBurst(rate)
+	} else {
+		b.tokens = math.Min(tokenBurst(rate), b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+}
+
+// take refills the bucket and then takes a token from it, reporting whether
+// one was available.
+func (b *tokenBucket) take(now time.Time, rate float64) bool {
+	b.refill(now, rate)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// full reports whether the bucket would be full as of now, such that it is
+// indistinguishable from a new bucket.
+func (b *tokenBucket) full(now time.Time, rate float64) bool {
+	c := *b
+	c.refill(now, rate)
+	return c.tokens >= tokenBurst(rate)
+}
+
+// A KeyedRateLimitSampler is a Sampler that collects at most
+// TracesPerSecond traces per second for each key, such as each route of an
+// HTTP server. Unlike a ProbabilitySampler, this gives every key bounded
+// coverage regardless of its share of the traffic: low-traffic routes are
+// not under-sampled, and a spike on one route does not crowd out others.
+//
+// Each key has its own token bucket, as in RateLimitSampler. Buckets are
+// created when a key is first seen and are dropped once they have refilled,
+// so keys without recent traffic use no memory.
+//
+// A trace is decided by the first of its spans that the sampler sees,
+// normally its root span, which carries the key (e.g. the route) that the
+// trace is rate limited by. A span of a trace whose root is elsewhere is
+// sampled as the decision propagated with it (see SampledKey) says, if
+// any, without taking from a bucket. Lacking one, it takes from the bucket
+// of its own key like a root span does, so that requests claiming a parent
+// cannot bypass the rate limit. Decisions are remembered so that all spans
+// of a trace seen by the sampler are sampled alike; as with
+// RateLimitSampler, only the decisions for the most recent 10000 traces
+// are kept.
+type KeyedRateLimitSampler struct {
+	// TracesPerSecond is the maximum average rate of sampled traces per
+	// key.
+	TracesPerSecond float64
+
+	// Key, if non-nil, returns the key of the trace that the given span
+	// belongs to. Otherwise RouteSamplingKey is used.
+	Key func(span SpanID, as Annotations) string
+
+	// Clock, if non-nil, is used instead of RealClock to measure the rate.
+	Clock Clock
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	decisions traceDecisions
+}
+
+// NewKeyedRateLimitSampler returns a KeyedRateLimitSampler that collects at
+// most tracesPerSecond traces per second for each route.
+func NewKeyedRateLimitSampler(tracesPerSecond float64) *KeyedRateLimitSampler {
+	return &KeyedRateLimitSampler{TracesPerSecond: tracesPerSecond}
+}
+
+// RouteSamplingKey returns the route of an HTTP server span (its
+// "Server.Route" annotation, as recorded by httptrace) or, lacking one, the
+// span's name. It is the default key of a KeyedRateLimitSampler.
+func RouteSamplingKey(span SpanID, as Annotations) string {
+	if route := as.get("Server.Route"); len(route) > 0 {
+		return string(route)
+	}
+	return string(as.get("Name"))
+}
+
+// ShouldSample implements the Sampler interface.
+func (s *KeyedRateLimitSampler) ShouldSample(span SpanID, as Annotations) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sample, ok := s.decisions.get(span.Trace); ok {
+		return sample
+	}
+	if span.Parent != 0 {
+		if sample, ok := propagatedSampled(as); ok {
+			s.decisions.put(span.Trace, sample)
+			return sample
+		}
+	}
+	if s.buckets == nil {
+		s.buckets = make(map[string]*tokenBucket)
+	}
+
+	now := RealClock.Now()
+	if s.Clock != nil {
+		now = s.Clock.Now()
+	}
+	s.sweepNoLock(now)
+
+	keyFunc := s.Key
+	if keyFunc == nil {
+		keyFunc = RouteSamplingKey
+	}
+	key := keyFunc(span, as)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{}
+		s.buckets[key] = b
+	}
+	sample := b.take(now, s.TracesPerSecond)
+	s.decisions.put(span.Trace, sample)
+	return sample
+}
+
+// sweepNoLock drops the buckets that have refilled, at most once per
+// refill period. The s.mu lock must be held while calling sweepNoLock.
+func (s *KeyedRateLimitSampler) sweepNoLock(now time.Time) {
+	if s.TracesPerSecond <= 0 {
+		return
+	}
+	refill := time.Duration(tokenBurst(s.TracesPerSecond) / s.TracesPerSecond * float64(time.Second))
+	if now.Sub(s.lastSweep) < refill {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.full(now, s.TracesPerSecond) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// An AdaptiveSampler is a Sampler that collects about TracesPerSecond
+// traces per second whatever the traffic, by sampling a fraction of traces
+// as ProbabilitySampler does and adjusting that fraction in a feedback
+// loop. Unlike a RateLimitSampler, which keeps the first traces of each
+// second and drops the rest, it spreads the sampled traces evenly over
+// time.
+//
+// Every Interval, the sampler measures the rate of new traces it saw,
+// smooths it into a moving average (see Smoothing), and sets the fraction
+// to TracesPerSecond divided by that average. The smoothing makes the
+// fraction follow changes in traffic gradually rather than oscillating;
+// until the first interval has passed, every trace is sampled.
+//
+// Decisions are remembered per trace so that all spans of a trace seen by
+// the sampler are sampled alike, even if the fraction changes in between;
+// as with RateLimitSampler, only the decisions for the most recent 10000
+// traces are kept.
+type AdaptiveSampler struct {
+	// TracesPerSecond is the target rate of sampled traces.
+	TracesPerSecond float64
+
+	// Interval is how often the fraction is adjusted. If zero, it is
+	// adjusted every second.
+	Interval time.Duration
+
+	// Smoothing is the weight, between 0 and 1, of the latest interval's
+	// rate in the moving average of the rate of traces. Lower values adapt
+	// more slowly but are steadier. If zero, 0.5 is used.
+	Smoothing float64
+
+	// Clock, if non-nil, is used instead of RealClock to measure the rate.
+	Clock Clock
+
+	mu          sync.Mutex
+	fraction    float64   // the fraction of traces sampled
+	rate        float64   // moving average of the rate of new traces, or 0 before the first interval
+	seen        int       // new traces seen since windowStart
+	windowStart time.Time // start of the current interval, or zero if none
+	decisions   traceDecisions
+}
+
+// NewAdaptiveSampler returns an AdaptiveSampler that collects about
+// tracesPerSecond traces per second.
+func NewAdaptiveSampler(tracesPerSecond float64) *AdaptiveSampler {
+	return &AdaptiveSampler{TracesPerSecond: tracesPerSecond}
+}
+
+// ShouldSample implements the Sampler interface.
+func (s *AdaptiveSampler) ShouldSample(span SpanID, as Annotations) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sample, ok := s.decisions.get(span.Trace); ok {
+		return sample
+	}
+
+	now := RealClock.Now()
+	if s.Clock != nil {
+		now = s.Clock.Now()
+	}
+	s.adjustNoLock(now)
+	s.seen++
+	sample := sampleFraction(s.fraction, uint64(span.Trace))
+	s.decisions.put(span.Trace, sample)
+	return sample
+}
+
+// adjustNoLock ends the current interval, if it is over, and adjusts the
+// fraction to the rate of traces seen in it. The s.mu lock must be held
+// while calling adjustNoLock.
+func (s *AdaptiveSampler) adjustNoLock(now time.Time) {
+	if s.windowStart.IsZero() {
+		s.windowStart, s.fraction = now, 1
+		return
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	elapsed := now.Sub(s.windowStart)
+	if elapsed < interval {
+		return
+	}
+
+	observed := float64(s.seen) / elapsed.Seconds()
+	smoothing := s.Smoothing
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = 0.5
+	}
+	if s.rate == 0 {
+		s.rate = observed
+	} else {
+		s.rate += smoothing * (observed - s.rate)
+	}
+	if s.rate > 0 {
+		s.fraction = math.Min(1, s.TracesPerSecond/s.rate)
+	} else {
+		s.fraction = 1
+	}
+	s.windowStart, s.seen = now, 0
+}
+
+// Fraction returns the fraction of traces that the sampler currently
+// samples, its effective sampling probability.
+func (s *AdaptiveSampler) Fraction() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.windowStart.IsZero() {
+		return 1
+	}
+	return s.fraction
+}
+
+// Rate returns the moving average of the rate of new traces per second
+// that the sampler has seen, on which its fraction is based, or 0 before
+// the first interval has passed.
+func (s *AdaptiveSampler) Rate() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rate
+}
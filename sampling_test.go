@@ -0,0 +1,432 @@
+package appdash
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestSpan_SamplingPriority(t *testing.T) {
+	s := Span{Annotations: Annotations{
+		SamplingPriority(2),
+		{Key: SamplingPriorityKey, Value: []byte("bad")},
+		SamplingPriority(1),
+	}}
+	if got, want := s.SamplingPriority(), 2; got != want {
+		t.Errorf("got priority %d, want %d", got, want)
+	}
+	if got := (&Span{}).SamplingPriority(); got != 0 {
+		t.Errorf("got priority %d for unprioritized span, want 0", got)
+	}
+}
+
+func TestTailSamplingCollector(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ms := NewMemoryStore()
+	tc := NewTailSamplingCollector(ms)
+	tc.QuietPeriod = time.Minute
+	tc.MaxAge = 10 * time.Minute
+	tc.Clock = clock
+	tc.Stats = &Stats{}
+	defer tc.Stop()
+
+	// Trace 1 errored in a child span; trace 2 is boring.
+	collect := func(id SpanID, as ...Annotation) {
+		if err := tc.Collect(id, as...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	collect(SpanID{1, 10, 0})
+	collect(SpanID{1, 11, 10}, SamplingPriority(1))
+	collect(SpanID{2, 20, 0})
+	collect(SpanID{2, 21, 20})
+	if traces, _ := ms.Traces(TracesOpts{}); len(traces) != 0 {
+		t.Fatalf("got %d traces stored before they were quiescent, want 0", len(traces))
+	}
+
+	// Once quiescent, both are decided by the next collection.
+	clock.Advance(time.Minute)
+	collect(SpanID{3, 30, 0})
+	if tr, err := ms.Trace(1); err != nil {
+		t.Errorf("errored trace was not kept: %s", err)
+	} else if len(tr.Sub) != 1 {
+		t.Errorf("got %d sub-spans in kept trace, want 1", len(tr.Sub))
+	}
+	if _, err := ms.Trace(2); err != ErrTraceNotFound {
+		t.Errorf("got error %v for boring trace, want it dropped", err)
+	}
+	if got := tc.Stats.Snapshot().Dropped; got != 2 {
+		t.Errorf("got %d dropped spans, want 2", got)
+	}
+
+	// A trace that keeps receiving spans is decided at MaxAge.
+	for i := 0; i < 12; i++ {
+		clock.Advance(59 * time.Second)
+		collect(SpanID{4, ID(40 + i), 0}, SamplingPriority(1))
+	}
+	if _, err := ms.Trace(4); err != nil {
+		t.Errorf("trace exceeding MaxAge was not decided: %s", err)
+	}
+}
+
+func TestTailSamplingCollector_maxTraces(t *testing.T) {
+	ms := NewMemoryStore()
+	tc := NewTailSamplingCollector(ms)
+	tc.QuietPeriod = time.Hour
+	tc.MaxTraces = 2
+	tc.Clock = &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	defer tc.Stop()
+
+	for i := 1; i <= 3; i++ {
+		if err := tc.Collect(SpanID{ID(i), ID(i), 0}, SamplingPriority(1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ms.Trace(1); err != nil {
+		t.Errorf("oldest trace was not decided to make room: %s", err)
+	}
+	if traces, _ := ms.Traces(TracesOpts{}); len(traces) != 1 {
+		t.Errorf("got %d traces decided, want 1", len(traces))
+	}
+
+	if err := tc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if traces, _ := ms.Traces(TracesOpts{}); len(traces) != 3 {
+		t.Errorf("got %d traces after Flush, want 3", len(traces))
+	}
+}
+
+func TestAlwaysNeverSample(t *testing.T) {
+	id := NewRootSpanID()
+	if !AlwaysSample.ShouldSample(id, nil) {
+		t.Error("AlwaysSample did not sample")
+	}
+	if NeverSample.ShouldSample(id, nil) {
+		t.Error("NeverSample sampled")
+	}
+}
+
+func TestProbabilitySampler(t *testing.T) {
+	const n = 10000
+	s := ProbabilitySampler(0.25)
+	var sampled int
+	for i := 0; i < n; i++ {
+		root := NewRootSpanID()
+		sample := s.ShouldSample(root, nil)
+		if sample {
+			sampled++
+		}
+		// Every span of a trace gets the same decision.
+		if s.ShouldSample(NewSpanID(root), nil) != sample {
+			t.Fatalf("trace %v: child span decision differs from root's", root.Trace)
+		}
+	}
+	if sampled < n*20/100 || sampled > n*30/100 {
+		t.Errorf("sampled %d of %d traces, want about 25%%", sampled, n)
+	}
+
+	id := NewRootSpanID()
+	if !ProbabilitySampler(1).ShouldSample(id, nil) || ProbabilitySampler(0).ShouldSample(id, nil) {
+		t.Error("ProbabilitySampler(1) must always and ProbabilitySampler(0) never sample")
+	}
+}
+
+func TestProbabilitySampler_boundaries(t *testing.T) {
+	tests := []struct {
+		trace    ID
+		fraction ProbabilitySampler
+		want     bool
+	}{
+		{0, 0.5, true},
+		{1 << 62, 0.5, true},
+		{1 << 62, 0.25, false},
+		{1<<62 - 1, 0.25, true},
+		{3 << 62, 0.5, false},
+		{math.MaxUint64, 0.999, false},
+		{math.MaxUint64, 1, true},
+		{0, 0, false},
+	}
+	for _, test := range tests {
+		if got := test.fraction.ShouldSample(SpanID{Trace: test.trace, Span: 1}, nil); got != test.want {
+			t.Errorf("trace %d (value %v) at %v: got sampled %v, want %v", test.trace, TraceSampleValue(test.trace), test.fraction, got, test.want)
+		}
+	}
+}
+
+func TestSeededProbabilitySampler(t *testing.T) {
+	s := SeededProbabilitySampler{Fraction: 0.5, Seed: 42}
+
+	// Decisions follow Value, and are the same for every call.
+	var kept, dropped int
+	for id := ID(1); id <= 1000; id++ {
+		v := s.Value(id)
+		if v < 0 || v >= 1 {
+			t.Fatalf("trace %d: got value %v, want in [0, 1)", id, v)
+		}
+		got := s.ShouldSample(SpanID{Trace: id, Span: 1}, nil)
+		if got != (v < s.Fraction) {
+			t.Fatalf("trace %d: got sampled %v with value %v at %v", id, got, v, s.Fraction)
+		}
+		if got != s.ShouldSample(SpanID{Trace: id, Span: 2, Parent: 1}, nil) {
+			t.Fatalf("trace %d: child span decision differs from root's", id)
+		}
+		if got {
+			kept++
+		} else {
+			dropped++
+		}
+	}
+	// Sequential IDs are spread out, rather than all falling below the
+	// fraction as they would with ProbabilitySampler.
+	if kept < 400 || dropped < 400 {
+		t.Errorf("kept %d and dropped %d sequential traces, want about half each", kept, dropped)
+	}
+
+	// Different seeds make different decisions.
+	other := SeededProbabilitySampler{Fraction: 0.5, Seed: 43}
+	var differ int
+	for id := ID(1); id <= 1000; id++ {
+		if s.ShouldSample(SpanID{Trace: id}, nil) != other.ShouldSample(SpanID{Trace: id}, nil) {
+			differ++
+		}
+	}
+	if differ < 400 {
+		t.Errorf("seeds 42 and 43 differ on %d of 1000 traces, want about half", differ)
+	}
+}
+
+func TestRateLimitSampler(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewRateLimitSampler(2)
+	s.Clock = clock
+
+	sampledTraces := func(traces ...ID) (n int) {
+		for _, id := range traces {
+			if s.ShouldSample(SpanID{Trace: id, Span: id}, nil) {
+				n++
+			}
+		}
+		return n
+	}
+	if n := sampledTraces(1, 2, 3, 4); n != 2 {
+		t.Errorf("got %d traces sampled in a burst, want 2", n)
+	}
+	// Later spans of a sampled trace are still sampled.
+	if !s.ShouldSample(SpanID{Trace: 1, Span: 100, Parent: 1}, nil) {
+		t.Error("child span of sampled trace was not sampled")
+	}
+	if s.ShouldSample(SpanID{Trace: 3, Span: 100, Parent: 3}, nil) {
+		t.Error("child span of unsampled trace was sampled")
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if n := sampledTraces(5, 6); n != 1 {
+		t.Errorf("got %d traces sampled after half a second, want 1", n)
+	}
+}
+
+func TestRateLimitSampler_evictsOldestDecisions(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewRateLimitSampler(1)
+	s.Clock = clock
+
+	// Trace 1 is sampled, and a full set of later traces is not. Only the
+	// oldest decision (trace 1's) is forgotten, so trace 2 is still
+	// unsampled after the bucket refills.
+	if !s.ShouldSample(SpanID{Trace: 1, Span: 1}, nil) {
+		t.Fatal("first trace was not sampled")
+	}
+	for id := ID(2); id <= maxRateLimitedTraces+1; id++ {
+		if s.ShouldSample(SpanID{Trace: id, Span: id}, nil) {
+			t.Fatalf("trace %d sampled in a burst, want only the first", id)
+		}
+	}
+	clock.Advance(time.Hour)
+	if s.ShouldSample(SpanID{Trace: 2, Span: 100, Parent: 2}, nil) {
+		t.Error("child span of unsampled trace was sampled after newer traces arrived")
+	}
+	if n := len(s.decisions.decisions); n != maxRateLimitedTraces {
+		t.Errorf("got %d decisions, want %d", n, maxRateLimitedTraces)
+	}
+	if _, ok := s.decisions.get(1); ok {
+		t.Error("oldest decision was not forgotten")
+	}
+}
+
+func TestKeyedRateLimitSampler(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewKeyedRateLimitSampler(2)
+	s.Clock = clock
+
+	route := func(r string) Annotations {
+		return Annotations{{Key: "Server.Route", Value: []byte(r)}}
+	}
+	var next ID
+	sampledTraces := func(r string, n int) (sampled int) {
+		for i := 0; i < n; i++ {
+			next++
+			if s.ShouldSample(SpanID{Trace: next, Span: next}, route(r)) {
+				sampled++
+			}
+		}
+		return sampled
+	}
+
+	// A busy route does not crowd out a quiet one.
+	if n := sampledTraces("busy", 10); n != 2 {
+		t.Errorf("got %d busy traces sampled in a burst, want 2", n)
+	}
+	if n := sampledTraces("quiet", 1); n != 1 {
+		t.Errorf("got %d quiet traces sampled, want 1", n)
+	}
+	if !s.ShouldSample(SpanID{Trace: 1, Span: 100, Parent: 1}, nil) {
+		t.Error("child span of sampled trace was not sampled")
+	}
+	if s.ShouldSample(SpanID{Trace: 3, Span: 100, Parent: 3}, route("quiet")) {
+		t.Error("child span of unsampled trace was sampled")
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if n := sampledTraces("busy", 10); n != 1 {
+		t.Errorf("got %d busy traces sampled after half a second, want 1", n)
+	}
+
+	// Buckets of routes without recent traffic are dropped.
+	if got := len(s.buckets); got != 2 {
+		t.Errorf("got %d buckets, want 2", got)
+	}
+	clock.Advance(time.Second)
+	if n := sampledTraces("other", 1); n != 1 {
+		t.Errorf("got %d traces sampled on a new route, want 1", n)
+	}
+	if got := len(s.buckets); got != 1 {
+		t.Errorf("got %d buckets after the others refilled, want 1", got)
+	}
+}
+
+func TestKeyedRateLimitSampler_childSpans(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewKeyedRateLimitSampler(1)
+	s.Clock = clock
+
+	// The root decides by its route, and the children seen later follow.
+	route := Annotations{{Key: "Server.Route", Value: []byte("r")}}
+	if !s.ShouldSample(SpanID{Trace: 2, Span: 1}, route) {
+		t.Error("root of the first trace on the route was not sampled")
+	}
+	if s.ShouldSample(SpanID{Trace: 1, Span: 1}, route) {
+		t.Error("root of a trace over the route's rate was sampled")
+	}
+	if s.ShouldSample(SpanID{Trace: 1, Span: 2, Parent: 1}, nil) {
+		t.Error("child span of an unsampled root was sampled")
+	}
+
+	// Spans claiming a parent without a propagated decision take from
+	// the bucket of their key, so they cannot bypass the rate limit.
+	for trace := ID(10); trace < 13; trace++ {
+		got := s.ShouldSample(SpanID{Trace: trace, Span: 2, Parent: 1}, route)
+		if got {
+			t.Errorf("trace %v: span claiming a parent was sampled over the route's rate", trace)
+		}
+	}
+	if !s.ShouldSample(SpanID{Trace: 13, Span: 2, Parent: 1}, Annotations{{Key: "Server.Route", Value: []byte("other")}}) {
+		t.Error("span claiming a parent on a new route was not sampled")
+	}
+
+	// A propagated decision is honored for traces whose root is elsewhere.
+	for _, sampled := range []bool{true, false} {
+		v := "0"
+		if sampled {
+			v = "1"
+		}
+		span := SpanID{Trace: 3, Span: 2, Parent: 1}
+		if sampled {
+			span.Trace = 4
+		}
+		if got := s.ShouldSample(span, Annotations{{Key: SampledKey, Value: []byte(v)}}); got != sampled {
+			t.Errorf("propagated decision %v: got sampled %v", sampled, got)
+		}
+		if got := s.ShouldSample(SpanID{Trace: span.Trace, Span: 3, Parent: 2}, nil); got != sampled {
+			t.Errorf("propagated decision %v: got later span sampled %v", sampled, got)
+		}
+	}
+}
+
+func TestRecorder_Sampler(t *testing.T) {
+	ms := NewMemoryStore()
+	rec := NewRecorder(SpanID{Trace: 1, Span: 1}, ms)
+	rec.Sampler = NeverSample
+	rec.Msg("dropped")
+	rec.Finish()
+	child := rec.Child()
+	child.Msg("dropped too")
+	child.Finish()
+	if _, err := ms.Trace(1); err != ErrTraceNotFound {
+		t.Errorf("got error %v, want unsampled trace not to be collected", err)
+	}
+
+	rec = NewRecorder(SpanID{Trace: 2, Span: 1}, ms)
+	rec.Sampler = AlwaysSample
+	rec.Msg("kept")
+	rec.Finish()
+	if _, err := ms.Trace(2); err != nil {
+		t.Errorf("sampled trace was not collected: %s", err)
+	}
+}
+
+func TestAdaptiveSampler(t *testing.T) {
+	clock := &manualClock{t: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewAdaptiveSampler(50)
+	s.Clock = clock
+
+	// Traffic ramps up from 20 to 5000 traces per second, and then drops
+	// to 200. Trace IDs come from a seeded source, so that the test is
+	// deterministic.
+	ids := rand.New(rand.NewSource(1))
+	second := func(traces int) (sampled int) {
+		for i := 0; i < traces; i++ {
+			id := ID(ids.Uint64())
+			sample := s.ShouldSample(SpanID{Trace: id, Span: id}, nil)
+			if sample {
+				sampled++
+			}
+			clock.Advance(time.Second / time.Duration(traces))
+			if s.ShouldSample(SpanID{Trace: id, Span: id + 1, Parent: id}, nil) != sample {
+				t.Fatal("spans of one trace were sampled differently")
+			}
+		}
+		return sampled
+	}
+	for _, phase := range []struct {
+		traces, seconds int
+	}{
+		{20, 8},
+		{500, 15},
+		{5000, 15},
+		{200, 15},
+	} {
+		var sampled []int
+		for i := 0; i < phase.seconds; i++ {
+			sampled = append(sampled, second(phase.traces))
+		}
+
+		// Once the sampler has adapted, it keeps about 50 traces per
+		// second, or all of them if there are fewer.
+		want := math.Min(50, float64(phase.traces))
+		var kept int
+		for _, n := range sampled[len(sampled)-8:] {
+			kept += n
+		}
+		if got := float64(kept) / 8; math.Abs(got-want) > 0.25*want {
+			t.Errorf("%d traces/s: got %.1f sampled traces/s after %ds, want about %.0f (per second: %v)", phase.traces, got, phase.seconds, want, sampled)
+		}
+		if got, want := s.Fraction(), math.Min(1, 50/float64(phase.traces)); math.Abs(got-want) > 0.1*want {
+			t.Errorf("%d traces/s: got fraction %.4f, want about %.4f", phase.traces, got, want)
+		}
+	}
+	if got := s.Rate(); math.Abs(got-200) > 20 {
+		t.Errorf("got rate %.1f, want about 200", got)
+	}
+}
@@ -0,0 +1,108 @@
+package appdash
+
+import (
+	"sync"
+	"time"
+)
+
+// A SamplingDecision is a sampling decision recorded by a SamplingAudit.
+type SamplingDecision struct {
+	Time    time.Time
+	Span    SpanID // the span the sampler was consulted on
+	Sampled bool   // whether the span was kept
+	Sampler string // the name of the sampler that decided (see SamplingAudit.Sampler)
+}
+
+// A SamplingAudit keeps the most recent sampling decisions in memory,
+// overwriting the oldest, so that one can find out why a trace was or was
+// not collected. Decisions are recorded by the samplers that Sampler
+// wraps; none are recorded unless a sampler is wrapped, since auditing
+// costs a lock and a copy on every decision.
+//
+// Samplers are often consulted several times per trace (e.g. once for
+// each Recorder.Annotation call). A decision that repeats the last one
+// recorded, for the same trace by the same sampler, is not recorded again.
+type SamplingAudit struct {
+	// N is the number of decisions to retain.
+	N int
+
+	// Clock, if non-nil, is used instead of RealClock to timestamp
+	// decisions.
+	Clock Clock
+
+	mu   sync.Mutex
+	ring []SamplingDecision // circular list of decisions in order
+	next int                // ring index for the next decision
+}
+
+// NewSamplingAudit returns a SamplingAudit that retains the most recent n
+// decisions.
+func NewSamplingAudit(n int) *SamplingAudit {
+	return &SamplingAudit{N: n}
+}
+
+func (a *SamplingAudit) now() time.Time {
+	if a.Clock != nil {
+		return a.Clock.Now()
+	}
+	return RealClock.Now()
+}
+
+// Sampler returns a Sampler that makes the decisions of s and records them
+// under the given name, such as the name of the rule that s implements.
+// Samplers composed of others may wrap each of them with its own name, so
+// that the audit tells which one decided.
+func (a *SamplingAudit) Sampler(name string, s Sampler) Sampler {
+	return SamplerFunc(func(span SpanID, as Annotations) bool {
+		sampled := s.ShouldSample(span, as)
+		a.Record(SamplingDecision{Span: span, Sampled: sampled, Sampler: name})
+		return sampled
+	})
+}
+
+// Record records a decision, such as one made by a sampling mechanism that
+// is not a Sampler. If d.Time is zero, the current time is used.
+func (a *SamplingAudit) Record(d SamplingDecision) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.N <= 0 {
+		return
+	}
+	if n := len(a.ring); n > 0 {
+		last := a.ring[(a.next+n-1)%n]
+		if last.Span.Trace == d.Span.Trace && last.Sampler == d.Sampler && last.Sampled == d.Sampled {
+			return
+		}
+	}
+	if d.Time.IsZero() {
+		d.Time = a.now()
+	}
+	if len(a.ring) < a.N {
+		a.ring = append(a.ring, d)
+		return
+	}
+	a.ring[a.next] = d
+	a.next = (a.next + 1) % a.N
+}
+
+// Decisions returns the retained decisions, oldest first.
+func (a *SamplingAudit) Decisions() []SamplingDecision {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ds := make([]SamplingDecision, 0, len(a.ring))
+	ds = append(ds, a.ring[a.next:]...)
+	ds = append(ds, a.ring[:a.next]...)
+	return ds
+}
+
+// TraceDecisions returns the retained decisions about the given trace,
+// oldest first.
+func (a *SamplingAudit) TraceDecisions(trace ID) []SamplingDecision {
+	var ds []SamplingDecision
+	for _, d := range a.Decisions() {
+		if d.Span.Trace == trace {
+			ds = append(ds, d)
+		}
+	}
+	return ds
+}
@@ -0,0 +1,39 @@
+package appdash
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSamplingAudit(t *testing.T) {
+	clock := &manualClock{t: time.Unix(1000, 0)}
+	a := NewSamplingAudit(3)
+	a.Clock = clock
+	s := a.Sampler("even traces", SamplerFunc(func(span SpanID, as Annotations) bool {
+		return span.Trace%2 == 0
+	}))
+
+	var want []SamplingDecision
+	for trace := ID(1); trace <= 4; trace++ {
+		clock.Advance(time.Second)
+		span := SpanID{Trace: trace, Span: 1}
+		if got := s.ShouldSample(span, nil); got != (trace%2 == 0) {
+			t.Errorf("trace %v: audited sampler decided %v, want the decision of the sampler it wraps", trace, got)
+		}
+		// A repeated decision about the same trace is not recorded again.
+		s.ShouldSample(SpanID{Trace: trace, Span: 2}, nil)
+		want = append(want, SamplingDecision{Time: clock.Now(), Span: span, Sampled: trace%2 == 0, Sampler: "even traces"})
+	}
+
+	// The oldest decision was evicted.
+	if got := a.Decisions(); !reflect.DeepEqual(got, want[1:]) {
+		t.Errorf("got decisions %+v, want %+v", got, want[1:])
+	}
+	if got := a.TraceDecisions(3); !reflect.DeepEqual(got, want[2:3]) {
+		t.Errorf("got decisions about trace 3 %+v, want %+v", got, want[2:3])
+	}
+	if got := a.TraceDecisions(1); got != nil {
+		t.Errorf("got decisions about evicted trace 1 %+v, want none", got)
+	}
+}
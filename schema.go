@@ -0,0 +1,302 @@
+package appdash
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// An EventSchema describes the annotation keys that events of a schema
+// marshal to. It is used to validate annotations against the schema (see
+// ValidateEvent and SchemaValidation).
+type EventSchema struct {
+	// Keys are the annotation keys that every event of the schema has.
+	Keys []string
+
+	// Optional are annotation keys that events of the schema may omit.
+	Optional []string
+
+	// Prefixes are key prefixes under which events of the schema may have
+	// arbitrary keys, such as the entries of a map or the elements of a
+	// slice. A key matches a prefix p if it is p itself or begins with p
+	// followed by a ".".
+	Prefixes []string
+}
+
+// allows reports whether key is a valid annotation key of the schema.
+func (s *EventSchema) allows(key string) bool {
+	for _, k := range s.Keys {
+		if k == key {
+			return true
+		}
+	}
+	for _, k := range s.Optional {
+		if k == key {
+			return true
+		}
+	}
+	for _, p := range s.Prefixes {
+		if key == p || strings.HasPrefix(key, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// reservesTags reports whether keys of the schema may begin with TagPrefix,
+// and so collide with tags.
+func (s *EventSchema) reservesTags() bool {
+	for _, keys := range [][]string{s.Keys, s.Optional} {
+		for _, k := range keys {
+			if strings.HasPrefix(k, TagPrefix) {
+				return true
+			}
+		}
+	}
+	for _, p := range s.Prefixes {
+		if strings.HasPrefix(p+".", TagPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var eventSchemas = map[string]*EventSchema{} // event schema -> expected keys
+
+// RegisterEventSchema registers the annotation keys expected for events of
+// the given schema, replacing any previously registered keys. The schemas
+// of events passed to RegisterEvent are derived from their struct fields and
+// registered automatically, except for events implementing EventMarshaler,
+// whose keys are only known to their MarshalEvent method. Like RegisterEvent,
+// it should be called during initialization.
+func RegisterEventSchema(schema string, s EventSchema) {
+	eventSchemas[schema] = &s
+}
+
+// LookupEventSchema returns the registered keys of the given schema, or nil
+// if none are registered.
+func LookupEventSchema(schema string) *EventSchema {
+	return eventSchemas[schema]
+}
+
+// A ValidationMode controls whether MarshalEvent and UnmarshalEvent validate
+// annotations against their registered event schema.
+type ValidationMode int
+
+const (
+	// ValidationOff disables validation. It is the default.
+	ValidationOff ValidationMode = iota
+
+	// ValidationWarn logs validation errors using the log package, but
+	// otherwise marshals and unmarshals events as usual.
+	ValidationWarn
+
+	// ValidationError makes MarshalEvent and UnmarshalEvent return
+	// validation errors.
+	ValidationError
+)
+
+// SchemaValidation is the validation mode used by MarshalEvent and
+// UnmarshalEvent. It is useful to turn on in tests and during development to
+// catch misspelled or forgotten annotation keys.
+//
+// MarshalEvent checks for both unknown and missing keys. UnmarshalEvent only
+// checks for missing keys, because the annotations of a span typically hold
+// the keys of several events. Events whose schema has no registered keys are
+// never validated.
+var SchemaValidation = ValidationOff
+
+// An EventSchemaValidationError is returned when annotations do not match
+// the registered keys of their event schema.
+type EventSchemaValidationError struct {
+	Schema  string   // schema of the event
+	Unknown []string // keys not expected by the schema
+	Missing []string // keys required by the schema but not present
+}
+
+func (e *EventSchemaValidationError) Error() string {
+	var problems []string
+	if len(e.Unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("unknown keys %v", e.Unknown))
+	}
+	if len(e.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing keys %v", e.Missing))
+	}
+	return fmt.Sprintf("event: annotations of schema %s have %s", e.Schema, strings.Join(problems, " and "))
+}
+
+// derivers holds the functions registered by RegisterDerivedAnnotations, and
+// derivedKeys the keys that they may return, by event schema.
+var (
+	derivers    = map[string][]func(Annotations) Annotations{}
+	derivedKeys = map[string]map[string]bool{}
+)
+
+// RegisterDerivedAnnotations registers a function that computes annotations
+// derived from those of each event of the given schema, such as the class
+// of a status code. MarshalEvent appends the derived annotations to the
+// event's own, so that a derived value is computed once, when the event is
+// recorded, rather than by everything that reads the trace. keys are the
+// keys that f may return; they are valid keys of the schema in addition to
+// its registered keys. Like RegisterEvent, it should be called during
+// initialization.
+func RegisterDerivedAnnotations(schema string, keys []string, f func(Annotations) Annotations) {
+	derivers[schema] = append(derivers[schema], f)
+	if derivedKeys[schema] == nil {
+		derivedKeys[schema] = map[string]bool{}
+	}
+	for _, k := range keys {
+		derivedKeys[schema][k] = true
+	}
+}
+
+// derive returns as with the annotations derived from it appended.
+func derive(schema string, as Annotations) Annotations {
+	for _, f := range derivers[schema] {
+		as = append(as, f(as)...)
+	}
+	return as
+}
+
+// allowLists holds the keys registered by RegisterEventAllowList, by event
+// schema.
+var allowLists = map[string][]string{}
+
+// RegisterEventAllowList restricts the annotations that MarshalEvent emits
+// for events of the given schema to the given keys, replacing any
+// previously registered list. This trims events whose other annotations
+// are never used, reducing their storage and clutter in the web UI,
+// without modifying the event types. A key in the list also allows the
+// keys nested under it, so "Server.Request" allows
+// "Server.Request.Method" and "Server.Request.Headers.Accept". Derived
+// annotations (see RegisterDerivedAnnotations) are computed before the
+// list is applied, so they may be kept while the annotations they are
+// derived from are dropped.
+//
+// Events unmarshaled from the trimmed annotations have zero values in the
+// fields that were not emitted, and schema validation does not report
+// those keys as missing. A nil list removes the restriction, which is the
+// default. Like RegisterEvent, it should be called during initialization.
+func RegisterEventAllowList(schema string, keys []string) {
+	if keys == nil {
+		delete(allowLists, schema)
+		return
+	}
+	allowLists[schema] = keys
+}
+
+// allowed reports whether key is emitted for events of the given schema
+// according to its allow-list, if any. Reserved keys are always allowed.
+func allowed(schema, key string) bool {
+	list, ok := allowLists[schema]
+	if !ok || strings.HasPrefix(key, "_") {
+		return true
+	}
+	for _, p := range list {
+		if key == p || strings.HasPrefix(key, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// applyAllowList returns the annotations in as that are allowed for the
+// given schema. It does not modify as, which may be shared with the caller.
+func applyAllowList(schema string, as Annotations) Annotations {
+	if _, ok := allowLists[schema]; !ok {
+		return as
+	}
+	kept := make(Annotations, 0, len(as))
+	for _, a := range as {
+		if allowed(schema, a.Key) {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// ValidateEvent checks the annotations of a single event (such as those
+// returned by MarshalEvent) against the registered keys of the given
+// schema. It returns an *EventSchemaValidationError listing any unknown and
+// missing keys, or nil if there are none or the schema has no registered
+// keys. Reserved keys, such as the event's own schema annotation, are
+// ignored.
+func ValidateEvent(schema string, as Annotations) error {
+	return validateEvent(schema, as, true)
+}
+
+func validateEvent(schema string, as Annotations, checkUnknown bool) error {
+	s := eventSchemas[schema]
+	if s == nil {
+		return nil
+	}
+	e := &EventSchemaValidationError{Schema: schema}
+	if checkUnknown {
+		for _, a := range as {
+			if strings.HasPrefix(a.Key, "_") || derivedKeys[schema][a.Key] {
+				continue
+			}
+			if !s.allows(a.Key) {
+				e.Unknown = append(e.Unknown, a.Key)
+			}
+		}
+	}
+	for _, k := range s.Keys {
+		if !as.has(k) && allowed(schema, k) {
+			e.Missing = append(e.Missing, k)
+		}
+	}
+	if len(e.Unknown) == 0 && len(e.Missing) == 0 {
+		return nil
+	}
+	sort.Strings(e.Unknown)
+	return e
+}
+
+// checkSchema validates as according to SchemaValidation, returning the
+// validation error only in ValidationError mode.
+func checkSchema(schema string, as Annotations, checkUnknown bool) error {
+	if SchemaValidation == ValidationOff {
+		return nil
+	}
+	err := validateEvent(schema, as, checkUnknown)
+	if err != nil && SchemaValidation == ValidationWarn {
+		log.Printf("appdash: %s", err)
+		return nil
+	}
+	return err
+}
+
+// deriveEventSchema adds the annotation keys of the fields of t, as they
+// would be flattened by flattenValue under the given prefix, to s.
+func deriveEventSchema(prefix string, t reflect.Type, s *EventSchema) {
+	switch t {
+	case reflect.TypeOf(time.Time{}), reflect.TypeOf(time.Duration(0)):
+		s.Keys = append(s.Keys, prefix)
+		return
+	}
+	if t.Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()) {
+		s.Keys = append(s.Keys, prefix)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		deriveEventSchema(prefix, t.Elem(), s)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			fld := t.Field(i)
+			if fld.PkgPath != "" {
+				continue
+			}
+			deriveEventSchema(nest(prefix, fieldName(fld)), fld.Type, s)
+		}
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Interface:
+		s.Prefixes = append(s.Prefixes, prefix)
+	default:
+		s.Keys = append(s.Keys, prefix)
+	}
+}
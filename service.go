@@ -0,0 +1,47 @@
+package appdash
+
+// ServiceKey is the annotation key under which the name of the service
+// that recorded a span is stored. In a multi-service deployment it lets
+// traces be filtered to those touching a particular service (see
+// TracesOpts.Service).
+const ServiceKey = "Service"
+
+// Service returns an annotation recording that the span it is collected on
+// belongs to the named service.
+func Service(name string) Annotation {
+	return Annotation{Key: ServiceKey, Value: []byte(name)}
+}
+
+// Service returns the name of the service that recorded the span (see
+// ServiceKey), or "" if it has none.
+func (s *Span) Service() string {
+	return string(s.Annotations.get(ServiceKey))
+}
+
+// A ServiceCollector is a Collector that tags the spans collected through
+// it with the name of a service (see ServiceKey) before passing them on.
+// Each process of a multi-service deployment typically wraps its collector
+// in one, so that every span it records names its service.
+type ServiceCollector struct {
+	// Collector is the underlying collector that spans are sent to.
+	Collector
+
+	// Service is the name of the service that spans are tagged with.
+	Service string
+}
+
+// NewServiceCollector is shorthand for:
+//
+// 	c := &ServiceCollector{
+// 		Collector: c,
+// 		Service:   service,
+// 	}
+//
+func NewServiceCollector(c Collector, service string) *ServiceCollector {
+	return &ServiceCollector{Collector: c, Service: service}
+}
+
+// Collect implements the Collector interface.
+func (sc *ServiceCollector) Collect(span SpanID, anns ...Annotation) error {
+	return sc.Collector.Collect(span, withDefault(anns, Service(sc.Service))...)
+}
@@ -0,0 +1,143 @@
+package appdash
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A ShardedMemoryStore is an in-memory Store that spreads traces across
+// several MemoryStores (shards) by trace ID. Each shard has its own lock,
+// so that concurrent collections into different traces rarely contend, as
+// they do on the single lock of a MemoryStore. It is the supported way to
+// scale in-memory collection on multi-core machines: MemoryStore itself
+// keeps one lock, so that its byte budget, eviction order and persistence
+// stay simple.
+//
+// Each trace is held whole by one shard, so Trace and Collect lock only
+// that shard, while Traces and DeleteWhere go through the shards one after
+// another. A byte budget (see NewMemoryStoreBytes) applies to each shard.
+type ShardedMemoryStore struct {
+	// Shards are the underlying stores. Their fields, such as Stats (which
+	// may be shared by all shards) and CompressAfter, should be set before
+	// the store is first used, and Shards must not be changed afterwards.
+	Shards []*MemoryStore
+}
+
+// Compile-time "implements" check.
+var _ interface {
+	Store
+	Queryer
+	DeleteStore
+	SubTraceStore
+} = (*ShardedMemoryStore)(nil)
+
+// NewShardedMemoryStore returns a ShardedMemoryStore with n empty shards.
+// A good number of shards is a small multiple of the number of CPUs that
+// collect concurrently. It panics if n is not positive.
+func NewShardedMemoryStore(n int) *ShardedMemoryStore {
+	if n <= 0 {
+		panic(fmt.Sprintf("NewShardedMemoryStore: invalid number of shards %d", n))
+	}
+	ss := &ShardedMemoryStore{Shards: make([]*MemoryStore, n)}
+	for i := range ss.Shards {
+		ss.Shards[i] = NewMemoryStore()
+	}
+	return ss
+}
+
+// shard returns the shard that holds the given trace.
+func (ss *ShardedMemoryStore) shard(trace ID) *MemoryStore {
+	return ss.Shards[uint64(trace)%uint64(len(ss.Shards))]
+}
+
+// Collect implements the Collector interface by collecting the span into
+// the shard of its trace.
+func (ss *ShardedMemoryStore) Collect(id SpanID, as ...Annotation) error {
+	return ss.shard(id.Trace).Collect(id, as...)
+}
+
+// Trace implements the Store interface.
+func (ss *ShardedMemoryStore) Trace(id ID) (*Trace, error) {
+	return ss.shard(id).Trace(id)
+}
+
+// TraceAnnotations is like MemoryStore.TraceAnnotations.
+func (ss *ShardedMemoryStore) TraceAnnotations(id ID) (Annotations, error) {
+	return ss.shard(id).TraceAnnotations(id)
+}
+
+// Traces implements the Queryer interface by concatenating the traces of
+// each shard, which are snapshots taken one shard after another.
+func (ss *ShardedMemoryStore) Traces(opts TracesOpts) ([]*Trace, error) {
+	sortByRecency := opts.SortByRecency
+	opts.SortByRecency = false
+	var all []*Trace
+	for _, s := range ss.Shards {
+		traces, err := s.Traces(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, traces...)
+	}
+	if sortByRecency {
+		SortTracesByRecency(all)
+	}
+	return all, nil
+}
+
+// IndexedValues is like MemoryStore.IndexedValues, returning the values
+// across all shards.
+func (ss *ShardedMemoryStore) IndexedValues(key string) []string {
+	seen := map[string]struct{}{}
+	var values []string
+	for _, s := range ss.Shards {
+		for _, value := range s.IndexedValues(key) {
+			if _, dup := seen[value]; !dup {
+				seen[value] = struct{}{}
+				values = append(values, value)
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// Bytes returns the approximate size in bytes of the annotations held by
+// all shards (see MemoryStore.Bytes).
+func (ss *ShardedMemoryStore) Bytes() int64 {
+	var n int64
+	for _, s := range ss.Shards {
+		n += s.Bytes()
+	}
+	return n
+}
+
+// Delete implements the DeleteStore interface by deleting each trace from
+// its shard.
+func (ss *ShardedMemoryStore) Delete(traces ...ID) error {
+	byShard := map[*MemoryStore][]ID{}
+	for _, id := range traces {
+		s := ss.shard(id)
+		byShard[s] = append(byShard[s], id)
+	}
+	for s, ids := range byShard {
+		if err := s.Delete(ids...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteWhere implements the DeleteStore interface by deleting the
+// matching traces from each shard in turn.
+func (ss *ShardedMemoryStore) DeleteWhere(filters ...QueryFilter) (int, error) {
+	var total int
+	for _, s := range ss.Shards {
+		n, err := s.DeleteWhere(filters...)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,102 @@
+package appdash
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShardedMemoryStore(t *testing.T) {
+	ss := NewShardedMemoryStore(4)
+	stats := &Stats{}
+	for _, s := range ss.Shards {
+		s.Stats = stats
+	}
+	for trace := ID(1); trace <= 8; trace++ {
+		rec := NewRecorder(SpanID{Trace: trace, Span: 1}, ss)
+		rec.Event(Timespan{S: time.Unix(int64(trace), 0), E: time.Unix(int64(trace)+1, 0)})
+		if trace%2 == 0 {
+			rec.Annotation(Service("even"))
+		} else {
+			rec.Annotation(Service("odd"))
+		}
+		rec.Finish()
+	}
+
+	// Traces are spread across the shards.
+	for i, s := range ss.Shards {
+		if traces, _ := s.Traces(TracesOpts{}); len(traces) != 2 {
+			t.Errorf("shard %d: got %d traces, want 2", i, len(traces))
+		}
+	}
+	if got := stats.Snapshot().Traces; got != 8 {
+		t.Errorf("got %d traces in the shared stats, want 8", got)
+	}
+
+	traces, err := ss.Traces(TracesOpts{SortByRecency: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []ID
+	for _, t := range traces {
+		ids = append(ids, t.ID.Trace)
+	}
+	if want := []ID{8, 7, 6, 5, 4, 3, 2, 1}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got traces %v sorted by recency, want %v", ids, want)
+	}
+	if traces, _ := ss.Traces(TracesOpts{Service: "odd"}); len(traces) != 4 {
+		t.Errorf("got %d traces of service odd, want 4", len(traces))
+	}
+	if got, want := ss.IndexedValues(ServiceKey), []string{"even", "odd"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got services %v, want %v", got, want)
+	}
+
+	if err := ss.Delete(1, 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := ss.DeleteWhere(func(t *Trace) bool { return t.ID.Trace > 6 }); err != nil || n != 2 {
+		t.Errorf("got %d traces deleted by DeleteWhere (error %v), want 2", n, err)
+	}
+	if traces, _ := ss.Traces(TracesOpts{}); len(traces) != 3 {
+		t.Errorf("got %d traces after deleting, want 3", len(traces))
+	}
+}
+
+// benchmarkCollectParallel measures Collect from many goroutines at once,
+// each collecting the spans of its own traces. Lock contention only shows
+// with several CPUs, so compare the stores at several GOMAXPROCS, e.g.:
+//
+// 	go test -run=NONE -bench=CollectParallel -cpu=1,4,16
+//
+func benchmarkCollectParallel(b *testing.B, s Store) {
+	var next int64
+	b.SetParallelism(8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var trace ID
+		span := ID(10)
+		for pb.Next() {
+			if span == 10 {
+				trace, span = ID(atomic.AddInt64(&next, 1)), 1
+			}
+			id := SpanID{Trace: trace, Span: span, Parent: 1}
+			if span == 1 {
+				id.Parent = 0
+			}
+			if err := s.Collect(id, Annotation{Key: "k", Value: []byte("v")}); err != nil {
+				b.Fatal(err)
+			}
+			span++
+		}
+	})
+}
+
+func BenchmarkMemoryStore_CollectParallel(b *testing.B) {
+	benchmarkCollectParallel(b, NewMemoryStore())
+}
+
+func BenchmarkShardedMemoryStore_CollectParallel(b *testing.B) {
+	benchmarkCollectParallel(b, NewShardedMemoryStore(64))
+}
@@ -0,0 +1,75 @@
+package appdash
+
+// SimilarityKeys are the annotation keys, besides span names, that
+// Similarity compares: two spans at the same place in their traces only
+// count as fully alike if they have the same values for these keys.
+var SimilarityKeys = []string{
+	"Server.Route",
+	"Server.Request.Method",
+	"Server.Response.StatusClass",
+	"Client.Response.StatusClass",
+}
+
+// Similarity returns a score between 0 and 1 of how alike the structure of
+// two traces is, e.g. to cluster error traces or to find the traces like a
+// given one. Durations, timestamps and IDs are ignored.
+//
+// Each span of a trace is described by its path, the names of the spans
+// from the root of the trace down to it, and by the path together with
+// each of its SimilarityKeys annotations. The score is the Jaccard index
+// of the multisets of these features of the two traces: the number of
+// features they have in common (counting repeated features as many times
+// as both traces have them) divided by the number of features that either
+// has. Traces with the same tree of names and key annotations score 1,
+// regardless of the order of sibling spans; traces with no span names in
+// common at the same paths score 0. Two empty traces score 1.
+func Similarity(a, b *Trace) float64 {
+	fa, fb := similarityFeatures(a), similarityFeatures(b)
+	var common, total int
+	for f, na := range fa {
+		nb := fb[f]
+		if na < nb {
+			common += na
+			total += nb
+		} else {
+			common += nb
+			total += na
+		}
+	}
+	for f, nb := range fb {
+		if _, inA := fa[f]; !inA {
+			total += nb
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(common) / float64(total)
+}
+
+// similarityFeatures returns the multiset of features of t compared by
+// Similarity, as a map from feature to count.
+func similarityFeatures(t *Trace) map[string]int {
+	features := map[string]int{}
+	if t == nil {
+		return features
+	}
+	var walk func(t *Trace, parent string)
+	walk = func(t *Trace, parent string) {
+		// Names and keys are joined with ASCII unit and record
+		// separators, which they are not expected to contain.
+		path := parent + "\x1f" + t.Span.Name()
+		features[path]++
+		for _, key := range SimilarityKeys {
+			if v, ok := t.Span.Annotations.Get(key); ok {
+				features[path+"\x1e"+key+"="+string(v)]++
+			}
+		}
+		for _, sub := range t.Sub {
+			walk(sub, path)
+		}
+	}
+	walk(t, "")
+	return features
+}
+
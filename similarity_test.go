@@ -0,0 +1,53 @@
+package appdash
+
+import (
+	"math"
+	"testing"
+)
+
+// shapeTrace returns a trace of spans with the given names, where tree
+// maps each name to the names of its children, starting at root. IDs are
+// drawn from next, so that traces of the same shape differ in their IDs.
+func shapeTrace(next *ID, root string, tree map[string][]string, anns map[string]Annotations) *Trace {
+	*next++
+	t := &Trace{Span: Span{ID: SpanID{Trace: 1, Span: *next}, Annotations: append(Annotations{{Key: "Name", Value: []byte(root)}}, anns[root]...)}}
+	for _, child := range tree[root] {
+		t.Sub = append(t.Sub, shapeTrace(next, child, tree, anns))
+	}
+	return t
+}
+
+func TestSimilarity(t *testing.T) {
+	var id ID
+	tree := map[string][]string{"root": {"db", "db", "cache"}, "cache": {"redis"}}
+	reordered := map[string][]string{"root": {"cache", "db", "db"}, "cache": {"redis"}}
+	ok := map[string]Annotations{"root": {{Key: "Server.Response.StatusClass", Value: []byte("2xx")}}}
+	failed := map[string]Annotations{"root": {{Key: "Server.Response.StatusClass", Value: []byte("5xx")}}}
+
+	a := shapeTrace(&id, "root", tree, ok)
+	tests := []struct {
+		name string
+		b    *Trace
+		want float64
+	}{
+		{"same shape", shapeTrace(&id, "root", tree, ok), 1},
+		{"siblings reordered", shapeTrace(&id, "root", reordered, ok), 1},
+		{"disjoint", shapeTrace(&id, "other", map[string][]string{"other": {"x", "y"}}, nil), 0},
+		// Of the 5 spans and 1 key annotation of a, b has 4 spans in
+		// common; its one db span is repeated only once.
+		{"one span fewer", shapeTrace(&id, "root", map[string][]string{"root": {"db", "cache"}, "cache": {"redis"}}, ok), 5.0 / 6},
+		// Same names, but the status class annotation differs.
+		{"different status", shapeTrace(&id, "root", tree, failed), 5.0 / 7},
+	}
+	for _, test := range tests {
+		if got := Similarity(a, test.b); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s: got similarity %v, want %v", test.name, got, test.want)
+		}
+		if got, rev := Similarity(a, test.b), Similarity(test.b, a); got != rev {
+			t.Errorf("%s: got asymmetric similarity %v and %v", test.name, got, rev)
+		}
+	}
+	if got := Similarity(nil, nil); got != 1 {
+		t.Errorf("got similarity %v of empty traces, want 1", got)
+	}
+}
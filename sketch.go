@@ -0,0 +1,206 @@
+package appdash
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// SketchAccuracy is the relative accuracy of the quantiles estimated by a
+// LatencySketch: the estimate of a quantile is within this fraction of its
+// true value.
+const SketchAccuracy = 0.01
+
+// sketchGamma is the ratio between the upper and lower bounds of each bin
+// of a LatencySketch, such that the midpoint of a bin is within
+// SketchAccuracy of every duration in it.
+var (
+	sketchGamma    = (1 + SketchAccuracy) / (1 - SketchAccuracy)
+	sketchLogGamma = math.Log(sketchGamma)
+)
+
+// sketchVersion is the first byte of the binary encoding of a
+// LatencySketch. It changes if the encoding or SketchAccuracy does.
+const sketchVersion = 1
+
+// A LatencySketch summarizes a distribution of durations in a small,
+// bounded amount of memory, so that its quantiles (e.g. the 99th
+// percentile latency) can be estimated to within SketchAccuracy. Unlike
+// averages and percentiles themselves, sketches are mergeable: the merge of
+// the sketches of two sets of durations is the sketch of their union, so
+// the sketches of many processes can be combined into fleet-wide
+// quantiles.
+//
+// Durations are counted in logarithmically sized bins, each spanning a
+// factor of about 1+2*SketchAccuracy, so that a sketch of durations from 1
+// nanosecond to a year needs under 2,000 bins.
+//
+// The zero value is an empty sketch, ready to use. A LatencySketch is not
+// safe for concurrent use.
+type LatencySketch struct {
+	zero  int64           // number of non-positive durations
+	bins  map[int32]int64 // bin index -> number of durations in the bin
+	count int64
+}
+
+// sketchIndex returns the index of the bin that d (which must be positive)
+// falls into, i.e. the i such that gamma^(i-1) < d <= gamma^i.
+func sketchIndex(d time.Duration) int32 {
+	return int32(math.Ceil(math.Log(float64(d)) / sketchLogGamma))
+}
+
+// sketchValue returns the estimate of the durations in bin i: the value
+// with the same relative error to both bounds of the bin.
+func sketchValue(i int32) time.Duration {
+	return time.Duration(2 * math.Pow(sketchGamma, float64(i)) / (sketchGamma + 1))
+}
+
+// Add adds a duration to the sketch.
+func (s *LatencySketch) Add(d time.Duration) {
+	s.count++
+	if d <= 0 {
+		s.zero++
+		return
+	}
+	if s.bins == nil {
+		s.bins = map[int32]int64{}
+	}
+	s.bins[sketchIndex(d)]++
+}
+
+// Merge adds the durations counted by o to s.
+func (s *LatencySketch) Merge(o *LatencySketch) {
+	if o == nil || o.count == 0 {
+		return
+	}
+	s.count += o.count
+	s.zero += o.zero
+	if s.bins == nil {
+		s.bins = make(map[int32]int64, len(o.bins))
+	}
+	for i, n := range o.bins {
+		s.bins[i] += n
+	}
+}
+
+// Count returns the number of durations added to the sketch.
+func (s *LatencySketch) Count() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.count
+}
+
+// Quantile returns an estimate of the q-quantile of the durations, where q
+// is between 0 and 1 (e.g. 0.99 for the 99th percentile). It returns 0 if
+// the sketch is empty.
+func (s *LatencySketch) Quantile(q float64) time.Duration {
+	if s == nil || s.count == 0 {
+		return 0
+	}
+	q = math.Max(0, math.Min(1, q))
+	rank := int64(q * float64(s.count-1))
+	if rank < s.zero {
+		return 0
+	}
+	seen := s.zero
+	for _, i := range s.indexes() {
+		seen += s.bins[i]
+		if seen > rank {
+			return sketchValue(i)
+		}
+	}
+	return 0 // unreachable if the counts are consistent
+}
+
+// indexes returns the indexes of the non-empty bins, in increasing order.
+func (s *LatencySketch) indexes() []int32 {
+	idx := make([]int32, 0, len(s.bins))
+	for i, n := range s.bins {
+		if n > 0 {
+			idx = append(idx, i)
+		}
+	}
+	sort.Slice(idx, func(a, b int) bool { return idx[a] < idx[b] })
+	return idx
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is:
+//
+// 	version    byte (1)
+// 	zero       uvarint, the number of non-positive durations
+// 	bins       uvarint, the number of non-empty bins
+// 	for each non-empty bin, in increasing order of index:
+// 		delta  varint, the index minus that of the previous bin (or 0)
+// 		count  uvarint, the number of durations in the bin
+//
+// The bin with index i holds the durations d, in nanoseconds, such that
+// gamma^(i-1) < d <= gamma^i, where gamma = (1+a)/(1-a) and a is the
+// SketchAccuracy of the version.
+func (s *LatencySketch) MarshalBinary() ([]byte, error) {
+	idx := s.indexes()
+	buf := make([]byte, 1+binary.MaxVarintLen64*(2+2*len(idx)))
+	buf[0] = sketchVersion
+	n := 1
+	n += binary.PutUvarint(buf[n:], uint64(s.zero))
+	n += binary.PutUvarint(buf[n:], uint64(len(idx)))
+	var prev int32
+	for _, i := range idx {
+		n += binary.PutVarint(buf[n:], int64(i-prev))
+		n += binary.PutUvarint(buf[n:], uint64(s.bins[i]))
+		prev = i
+	}
+	return buf[:n], nil
+}
+
+var errSketchEncoding = errors.New("appdash: invalid latency sketch encoding")
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding a sketch
+// encoded by MarshalBinary into s (replacing its contents).
+func (s *LatencySketch) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != sketchVersion {
+		return errors.New("appdash: unknown latency sketch version")
+	}
+	data = data[1:]
+	uvarint := func() (uint64, bool) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, false
+		}
+		data = data[n:]
+		return v, true
+	}
+	zero, ok := uvarint()
+	if !ok {
+		return errSketchEncoding
+	}
+	nbins, ok := uvarint()
+	if !ok || nbins > uint64(len(data)) {
+		return errSketchEncoding
+	}
+	d := LatencySketch{zero: int64(zero), count: int64(zero), bins: make(map[int32]int64, nbins)}
+	var i int64
+	for b := uint64(0); b < nbins; b++ {
+		delta, n := binary.Varint(data)
+		if n <= 0 {
+			return errSketchEncoding
+		}
+		data = data[n:]
+		count, ok := uvarint()
+		if !ok {
+			return errSketchEncoding
+		}
+		if i += delta; i < math.MinInt32 || i > math.MaxInt32 {
+			return errSketchEncoding
+		}
+		d.bins[int32(i)] += int64(count)
+		d.count += int64(count)
+	}
+	if len(data) > 0 {
+		return errSketchEncoding
+	}
+	*s = d
+	return nil
+}
@@ -0,0 +1,84 @@
+package appdash
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// withinAccuracy reports whether got is within SketchAccuracy of want.
+func withinAccuracy(got, want time.Duration) bool {
+	return math.Abs(float64(got-want)) <= SketchAccuracy*float64(want)+1
+}
+
+// exactQuantile returns the q-quantile of the sorted durations, with the
+// same rank as LatencySketch.Quantile.
+func exactQuantile(sorted []time.Duration, q float64) time.Duration {
+	return sorted[int(q*float64(len(sorted)-1))]
+}
+
+func TestLatencySketch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var a, b, all LatencySketch
+	var ds []time.Duration
+	for i := 0; i < 10000; i++ {
+		// A long-tailed distribution, split unevenly between two sketches.
+		d := time.Duration(rnd.ExpFloat64() * float64(20*time.Millisecond))
+		if i%3 == 0 {
+			a.Add(d)
+		} else {
+			b.Add(d)
+		}
+		all.Add(d)
+		ds = append(ds, d)
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+
+	a.Merge(&b)
+	if a.Count() != int64(len(ds)) {
+		t.Fatalf("got count %d after merge, want %d", a.Count(), len(ds))
+	}
+	for _, q := range []float64{0, 0.5, 0.9, 0.99, 0.999, 1} {
+		want := exactQuantile(ds, q)
+		if got := a.Quantile(q); !withinAccuracy(got, want) {
+			t.Errorf("q=%v: got %v from merged sketch, want %v", q, got, want)
+		}
+		if got, wantAll := a.Quantile(q), all.Quantile(q); got != wantAll {
+			t.Errorf("q=%v: got %v from merged sketch, want %v as for a single sketch", q, got, wantAll)
+		}
+	}
+
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded LatencySketch
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Count() != a.Count() || decoded.Quantile(0.99) != a.Quantile(0.99) {
+		t.Errorf("got decoded sketch with count %d and p99 %v, want %d and %v", decoded.Count(), decoded.Quantile(0.99), a.Count(), a.Quantile(0.99))
+	}
+	for _, bad := range [][]byte{nil, {2}, {sketchVersion}, data[:len(data)-1], append(data, 0)} {
+		if err := decoded.UnmarshalBinary(bad); err == nil {
+			t.Errorf("%v: got no error", bad)
+		}
+	}
+
+	// Empty sketches and non-positive durations.
+	var empty, zero LatencySketch
+	if got := empty.Quantile(0.5); got != 0 {
+		t.Errorf("got %v from an empty sketch, want 0", got)
+	}
+	zero.Add(0)
+	zero.Add(-time.Second)
+	zero.Add(time.Second)
+	if got := zero.Quantile(0.5); got != 0 {
+		t.Errorf("got median %v, want 0", got)
+	}
+	if got := zero.Quantile(1); !withinAccuracy(got, time.Second) {
+		t.Errorf("got max %v, want 1s", got)
+	}
+}
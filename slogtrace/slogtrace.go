@@ -0,0 +1,199 @@
+//go:build go1.21
+// +build go1.21
+
+// Package slogtrace links structured logs to appdash traces.
+//
+// A log/slog logger is linked with:
+//
+//  logger := slog.New(slogtrace.NewHandler(slog.NewJSONHandler(os.Stderr, nil)))
+//  ctx := slogtrace.NewContext(ctx, span)
+//  logger.InfoContext(ctx, "cache miss", "key", key)
+//
+// which adds trace_id and span_id fields, holding the hex-encoded IDs of the
+// span in the context, to each record logged with a context. Other logging
+// libraries, such as logrus, can add the same fields with Fields:
+//
+//  logrus.WithFields(slogtrace.Fields(ctx)).Info("cache miss")
+//
+// A Handler with a Collector also records each log record as a Log event
+// on its span, so that the logs are shown along with the trace.
+package slogtrace
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// The keys of the fields that hold a span's IDs. The IDs are formatted as
+// by appdash.ID's String method.
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// contextKey is the type of the context key under which the current span is
+// stored.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the given span, whose IDs
+// are added to records logged with the returned context.
+func NewContext(ctx context.Context, span appdash.SpanID) context.Context {
+	return context.WithValue(ctx, contextKey{}, span)
+}
+
+// FromContext returns the span stored in ctx by NewContext, if any.
+func FromContext(ctx context.Context) (appdash.SpanID, bool) {
+	span, ok := ctx.Value(contextKey{}).(appdash.SpanID)
+	return span, ok
+}
+
+// Attrs returns the fields identifying span, as slog attributes.
+func Attrs(span appdash.SpanID) []slog.Attr {
+	return []slog.Attr{
+		slog.String(TraceIDKey, span.Trace.String()),
+		slog.String(SpanIDKey, span.Span.String()),
+	}
+}
+
+// Fields returns the fields identifying the span in ctx, or nil if it has
+// none. The map may be passed as logrus.Fields.
+func Fields(ctx context.Context) map[string]interface{} {
+	span, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{
+		TraceIDKey: span.Trace.String(),
+		SpanIDKey:  span.Span.String(),
+	}
+}
+
+// Handler is a slog.Handler that adds the IDs of the span in a record's
+// context to the record before passing it to the underlying handler. If the
+// logger has a group (see slog.Logger.WithGroup), the fields are added to
+// that group, like any other attribute of the record.
+type Handler struct {
+	// Handler is the underlying handler that records are passed to.
+	Handler slog.Handler
+
+	// SpanFromContext returns the span of a record's context. If nil,
+	// FromContext is used. Set it to use the spans stored by other
+	// packages, such as grpctrace.FromContext.
+	SpanFromContext func(context.Context) (appdash.SpanID, bool)
+
+	// Collector, if non-nil, receives each record that has a span as a
+	// Log event on that span. The event's message is the record's level,
+	// message and attributes, formatted like slog.TextHandler does.
+	Collector appdash.Collector
+
+	// CollectLevel is the minimum level of the records that are sent to
+	// Collector. If nil, it is slog.LevelInfo.
+	CollectLevel slog.Leveler
+
+	attrs []groupOrAttrs // of WithAttrs and WithGroup, for the Collector
+}
+
+// groupOrAttrs is a call to WithGroup (if group is non-empty) or WithAttrs.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewHandler returns a Handler that passes records to h.
+func NewHandler(h slog.Handler) *Handler {
+	return &Handler{Handler: h}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.Handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	span, ok := h.spanFromContext(ctx)
+	if !ok {
+		return h.Handler.Handle(ctx, r)
+	}
+	if h.Collector != nil && r.Level >= h.collectLevel() {
+		// Errors are not returned, so that a failure to collect never loses
+		// the record itself.
+		h.collect(span, r)
+	}
+	r = r.Clone()
+	r.AddAttrs(Attrs(span)...)
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs}, h.Handler.WithAttrs(attrs))
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name}, h.Handler.WithGroup(name))
+}
+
+func (h *Handler) with(ga groupOrAttrs, next slog.Handler) *Handler {
+	h2 := *h
+	h2.Handler = next
+	h2.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], ga)
+	return &h2
+}
+
+func (h *Handler) spanFromContext(ctx context.Context) (appdash.SpanID, bool) {
+	if ctx == nil {
+		return appdash.SpanID{}, false
+	}
+	if h.SpanFromContext != nil {
+		return h.SpanFromContext(ctx)
+	}
+	return FromContext(ctx)
+}
+
+func (h *Handler) collectLevel() slog.Level {
+	if h.CollectLevel == nil {
+		return slog.LevelInfo
+	}
+	return h.CollectLevel.Level()
+}
+
+// collect sends r to the Collector as a Log event on span.
+func (h *Handler) collect(span appdash.SpanID, r slog.Record) error {
+	// Format the record without its time, which the event records itself.
+	var buf bytes.Buffer
+	var th slog.Handler = slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug - 100,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	for _, ga := range h.attrs {
+		if ga.group != "" {
+			th = th.WithGroup(ga.group)
+		} else {
+			th = th.WithAttrs(ga.attrs)
+		}
+	}
+	if err := th.Handle(context.Background(), r); err != nil {
+		return err
+	}
+	anns, err := appdash.MarshalEvent(appdash.LogWithTimestamp(string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), r.Time))
+	if err != nil {
+		return err
+	}
+	return h.Collector.Collect(span, anns...)
+}
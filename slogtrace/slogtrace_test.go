@@ -0,0 +1,95 @@
+//go:build go1.21
+// +build go1.21
+
+package slogtrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	ms := appdash.NewMemoryStore()
+	h := NewHandler(slog.NewJSONHandler(&buf, nil))
+	h.Collector = appdash.NewLocalCollector(ms)
+	logger := slog.New(h)
+
+	span := appdash.SpanID{Trace: 0xabc, Span: 0x12, Parent: 0x1}
+	ctx := NewContext(context.Background(), span)
+	logger.With("service", "api").InfoContext(ctx, "cache miss", "key", "users/1")
+	logger.DebugContext(ctx, "not collected")
+	logger.Info("no span")
+
+	var records []map[string]interface{}
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r map[string]interface{}
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if got := records[0]; got[TraceIDKey] != "0000000000000abc" || got[SpanIDKey] != "0000000000000012" || got["key"] != "users/1" {
+		t.Errorf("got record %v, want one with the span's IDs", got)
+	}
+	if got := records[1]; got[TraceIDKey] != nil || got[SpanIDKey] != nil {
+		t.Errorf("got record %v without a span, want no span IDs", got)
+	}
+
+	// The record at the collect level was mirrored to the span.
+	trace, err := ms.Trace(span.Trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, a := range trace.Span.Annotations {
+		if a.Key == "Msg" {
+			msgs = append(msgs, string(a.Value))
+		}
+	}
+	if want := []string{`level=INFO msg="cache miss" service=api key=users/1`}; !reflect.DeepEqual(msgs, want) {
+		t.Errorf("got collected log messages %q, want %q", msgs, want)
+	}
+}
+
+func TestHandler_SpanFromContext(t *testing.T) {
+	type key struct{}
+	var buf bytes.Buffer
+	h := NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	h.SpanFromContext = func(ctx context.Context) (appdash.SpanID, bool) {
+		span, ok := ctx.Value(key{}).(appdash.SpanID)
+		return span, ok
+	}
+	ctx := context.WithValue(context.Background(), key{}, appdash.SpanID{Trace: 1, Span: 2})
+	slog.New(h).WithGroup("g").InfoContext(ctx, "hi")
+	if got, want := buf.String(), "level=INFO msg=hi g.trace_id=0000000000000001 g.span_id=0000000000000002\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFields(t *testing.T) {
+	if f := Fields(context.Background()); f != nil {
+		t.Errorf("got fields %v without a span, want nil", f)
+	}
+	want := map[string]interface{}{TraceIDKey: "0000000000000001", SpanIDKey: "0000000000000002"}
+	if f := Fields(NewContext(context.Background(), appdash.SpanID{Trace: 1, Span: 2, Parent: 3})); !reflect.DeepEqual(f, want) {
+		t.Errorf("got fields %v, want %v", f, want)
+	}
+}
@@ -151,6 +151,15 @@ func (s *Span) String() string {
 	return string(b)
 }
 
+// Clone returns a deep copy of the span: its annotations, including their
+// values, are copied, so that changes to the copy never affect s.
+func (s *Span) Clone() *Span {
+	if s == nil {
+		return nil
+	}
+	return &Span{ID: s.ID, Annotations: s.Annotations.Clone()}
+}
+
 // Name returns a span's name if it has a name annotation, and ""
 // otherwise.
 func (s *Span) Name() string {
@@ -162,6 +171,36 @@ func (s *Span) Name() string {
 	return ""
 }
 
+// FollowsFrom returns the IDs of the spans that this span follows from, as
+// recorded by the FollowsFrom event. Malformed IDs are skipped.
+func (s *Span) FollowsFrom() []SpanID {
+	var ids []SpanID
+	for _, ann := range s.Annotations {
+		if ann.Key != followsFromKey {
+			continue
+		}
+		id, err := ParseSpanID(string(ann.Value))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, *id)
+	}
+	return ids
+}
+
+// Attempt returns the span's Attempt event, if it is one of several
+// attempts at a single operation (see Recorder.Attempt).
+func (s *Span) Attempt() (a Attempt, ok bool) {
+	if !s.Annotations.has(attemptKey) {
+		return Attempt{}, false
+	}
+	e, err := a.UnmarshalEvent(s.Annotations)
+	if err != nil {
+		return Attempt{}, false
+	}
+	return e.(Attempt), true
+}
+
 // Annotations is a list of annotations (on a span).
 type Annotations []Annotation
 
@@ -193,6 +232,23 @@ func (a Annotation) Important() bool {
 	return false
 }
 
+// Clone returns a deep copy of the annotations. Each value is copied into a
+// new byte slice, so the copy may be modified (or appended to) without
+// affecting as, and vice versa. Nil values stay nil.
+func (as Annotations) Clone() Annotations {
+	if as == nil {
+		return nil
+	}
+	c := make(Annotations, len(as))
+	for i, a := range as {
+		c[i].Key = a.Key
+		if a.Value != nil {
+			c[i].Value = append(make([]byte, 0, len(a.Value)), a.Value...)
+		}
+	}
+	return c
+}
+
 // String returns a formatted list of annotations.
 func (as Annotations) String() string {
 	var buf bytes.Buffer
@@ -213,16 +269,31 @@ func (as Annotations) schemas() []string {
 	return schemas
 }
 
-// get gets the value of the first annotation with the given key, or
-// nil if none exists. There may be multiple annotations with the key;
-// only the first's value is returned.
-func (as Annotations) get(key string) []byte {
+// Get returns the value of the first annotation with the given key, and
+// whether there is one. There may be multiple annotations with the key;
+// only the first's value is returned. Unlike StringMap, Get does not
+// allocate: the returned slice aliases the stored value, so it must not be
+// modified (use Clone first to get a copy that may be).
+func (as Annotations) Get(key string) ([]byte, bool) {
 	for _, a := range as {
 		if a.Key == key {
-			return a.Value
+			return a.Value, true
 		}
 	}
-	return nil
+	return nil, false
+}
+
+// get is like Get, but returns nil if there is no annotation with the
+// given key.
+func (as Annotations) get(key string) []byte {
+	v, _ := as.Get(key)
+	return v
+}
+
+// has reports whether there is an annotation with the given key.
+func (as Annotations) has(key string) bool {
+	_, ok := as.Get(key)
+	return ok
 }
 
 // StringMap returns the annotations as a key-value map. Only one
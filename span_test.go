@@ -2,7 +2,9 @@ package appdash
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -167,6 +169,45 @@ func TestSpan_Name(t *testing.T) {
 	}
 }
 
+func TestSpan_FollowsFrom(t *testing.T) {
+	a := SpanID{Trace: 1, Span: 2}
+	b := SpanID{Trace: 3, Span: 4, Parent: 5}
+	var anns Annotations
+	for _, id := range []SpanID{a, b} {
+		as, err := MarshalEvent(FollowsFrom(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		anns = append(anns, as...)
+	}
+	anns = append(anns, Annotation{Key: "FollowsFrom", Value: []byte("bad")})
+	want := []SpanID{a, b}
+
+	span := &Span{ID: SpanID{Trace: 1, Span: 6}, Annotations: anns}
+	if got := span.FollowsFrom(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got FollowsFrom %v, want %v", got, want)
+	}
+
+	// The links must survive the wire format.
+	wired := &Span{Annotations: annotationsFromWire(anns.wire())}
+	if got := wired.FollowsFrom(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got FollowsFrom %v after wire round trip, want %v", got, want)
+	}
+
+	// And JSON.
+	b2, err := json.Marshal(span)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unmarshaled Span
+	if err := json.Unmarshal(b2, &unmarshaled); err != nil {
+		t.Fatal(err)
+	}
+	if got := unmarshaled.FollowsFrom(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got FollowsFrom %v after JSON round trip, want %v", got, want)
+	}
+}
+
 type annotations Annotations
 
 func (a annotations) Len() int           { return len(a) }
@@ -205,3 +246,69 @@ func BenchmarkParseSpanID(b *testing.B) {
 		}
 	}
 }
+
+func TestSpan_Clone(t *testing.T) {
+	orig := &Span{ID: SpanID{1, 2, 3}, Annotations: Annotations{
+		{Key: "a", Value: []byte("x")},
+		{Key: "b", Value: []byte{}},
+		{Key: "c"},
+	}}
+	want := &Span{ID: SpanID{1, 2, 3}, Annotations: Annotations{
+		{Key: "a", Value: []byte("x")},
+		{Key: "b", Value: []byte{}},
+		{Key: "c"},
+	}}
+
+	c := orig.Clone()
+	if !reflect.DeepEqual(c, orig) {
+		t.Fatalf("got clone %v, want %v", c, orig)
+	}
+	c.ID.Span = 9
+	c.Annotations[0].Value[0] = 'y'
+	c.Annotations[1].Key = "z"
+	c.Annotations = append(c.Annotations, Annotation{Key: "d"})
+	if !reflect.DeepEqual(orig, want) {
+		t.Errorf("modifying the clone changed the original to %v", orig)
+	}
+
+	// The original's values are not shared either.
+	c = orig.Clone()
+	orig.Annotations[0].Value[0] = 'w'
+	if string(c.Annotations[0].Value) != "x" {
+		t.Errorf("modifying the original changed the clone's value to %q", c.Annotations[0].Value)
+	}
+
+	if (*Span)(nil).Clone() != nil || Annotations(nil).Clone() != nil {
+		t.Error("got non-nil clone of nil")
+	}
+}
+
+func TestAnnotations_Get(t *testing.T) {
+	as := Annotations{
+		{Key: "a", Value: []byte("1")},
+		{Key: "empty"},
+		{Key: "a", Value: []byte("2")},
+	}
+	tests := []struct {
+		key     string
+		want    string
+		present bool
+	}{
+		{"a", "1", true}, // the first of several
+		{"empty", "", true},
+		{"missing", "", false},
+	}
+	for _, test := range tests {
+		got, present := as.Get(test.key)
+		if string(got) != test.want || present != test.present {
+			t.Errorf("%s: got %q (present: %v), want %q (present: %v)", test.key, got, present, test.want, test.present)
+		}
+	}
+
+	// The value aliases the stored one.
+	v, _ := as.Get("a")
+	v[0] = 'x'
+	if string(as[0].Value) != "x" {
+		t.Errorf("got stored value %q after modifying the returned one, want it aliased", as[0].Value)
+	}
+}
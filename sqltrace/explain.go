@@ -0,0 +1,228 @@
+package sqltrace
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// PlanEvent is the execution plan of a slow query, as attached to the
+// query's span by an Explainer.
+type PlanEvent struct {
+	Plan        string `trace:"SQL.Plan.Text"`
+	ContentType string `trace:"SQL.Plan.ContentType"` // media type of Plan, e.g. "text/plain" or "application/json"
+	Truncated   bool   `trace:"SQL.Plan.Truncated"`   // whether Plan was cut short at Explainer.MaxPlanSize
+}
+
+// Schema implements the appdash Event interface by returning this event's
+// constant schema string, "SQLPlan".
+func (PlanEvent) Schema() string { return "SQLPlan" }
+
+func init() { appdash.RegisterEvent(PlanEvent{}) }
+
+// An Explainer attaches the execution plans of slow queries to their
+// spans, so that they can be inspected in the trace view. Explaining
+// queries is off by default: an Explainer does nothing until Register is
+// called.
+//
+// Plans are obtained after Record returns, in a separate goroutine (see
+// OnSlowQuery), and are bounded so as not to add much load to the
+// database: at most MaxConcurrent queries are explained at once, and each
+// distinct query at most once per Interval. Slow queries beyond those
+// bounds are skipped.
+type Explainer struct {
+	// Explain returns the execution plan of query, such as the function
+	// returned by ExplainDB.
+	Explain func(ctx context.Context, query string) (plan string, err error)
+
+	// Collector is the collector that plans are sent to, along with the
+	// span ID of their query. It should be the collector that the query's
+	// span was recorded to.
+	Collector appdash.Collector
+
+	// Threshold is the duration above which a query is explained.
+	Threshold time.Duration
+
+	// ContentType is the media type of the plans returned by Explain. If
+	// empty, "text/plain" is assumed.
+	ContentType string
+
+	// MaxConcurrent is the maximum number of queries explained at once.
+	// If zero, one query is explained at a time.
+	MaxConcurrent int
+
+	// Interval is the minimum time between plans of the same query text.
+	// If zero, a query is explained at most once a minute.
+	Interval time.Duration
+
+	// Timeout bounds the time spent explaining each query. If zero, it is
+	// 5 seconds.
+	Timeout time.Duration
+
+	// MaxPlanSize is the maximum size in bytes of an attached plan; longer
+	// plans are truncated. If zero, it is 64 KiB.
+	MaxPlanSize int
+
+	// Log, if non-nil, is used to log errors from Explain and Collector.
+	Log *log.Logger
+
+	skipped uint64 // accessed atomically
+
+	mu      sync.Mutex
+	running int
+	last    map[string]time.Time // query text -> time it was last explained
+}
+
+// Register starts attaching plans to the spans of the queries recorded
+// with Record that take longer than x.Threshold. Call the returned
+// function to stop.
+func (x *Explainer) Register() (remove func()) {
+	return OnSlowQuery(x.Threshold, x.explain)
+}
+
+// Skipped returns the number of slow queries that were not explained
+// because of the bounds on MaxConcurrent or Interval.
+func (x *Explainer) Skipped() uint64 {
+	return atomic.LoadUint64(&x.skipped)
+}
+
+// acquire reports whether query may be explained now, reserving one of
+// the MaxConcurrent slots if so.
+func (x *Explainer) acquire(query string) bool {
+	maxConcurrent, interval := x.MaxConcurrent, x.Interval
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	now := time.Now()
+	if x.running >= maxConcurrent || now.Sub(x.last[query]) < interval {
+		return false
+	}
+	if x.last == nil {
+		x.last = map[string]time.Time{}
+	}
+	for q, t := range x.last {
+		if now.Sub(t) >= interval {
+			delete(x.last, q)
+		}
+	}
+	x.last[query] = now
+	x.running++
+	return true
+}
+
+func (x *Explainer) release() {
+	x.mu.Lock()
+	x.running--
+	x.mu.Unlock()
+}
+
+// explain attaches the plan of the slow query to span. It is registered
+// as a hook with OnSlowQuery.
+func (x *Explainer) explain(span appdash.SpanID, query string, d time.Duration) {
+	if !x.acquire(query) {
+		atomic.AddUint64(&x.skipped, 1)
+		return
+	}
+	defer x.release()
+
+	timeout := x.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	plan, err := x.Explain(ctx, query)
+	if err != nil {
+		x.logf("Explainer: explaining query: %s", err)
+		return
+	}
+
+	e := PlanEvent{Plan: plan, ContentType: x.ContentType}
+	if e.ContentType == "" {
+		e.ContentType = "text/plain"
+	}
+	maxSize := x.MaxPlanSize
+	if maxSize <= 0 {
+		maxSize = 64 << 10
+	}
+	if len(e.Plan) > maxSize {
+		e.Plan, e.Truncated = e.Plan[:maxSize], true
+	}
+	as, err := appdash.MarshalEvent(e)
+	if err != nil {
+		x.logf("Explainer: %s", err)
+		return
+	}
+	if err := x.Collector.Collect(span, as...); err != nil {
+		x.logf("Explainer: collecting plan: %s", err)
+	}
+}
+
+func (x *Explainer) logf(format string, args ...interface{}) {
+	if x.Log != nil {
+		x.Log.Printf(format, args...)
+	}
+}
+
+// ExplainDB returns a function for Explainer.Explain that explains
+// queries on db by running command followed by the query, and returns the
+// resulting rows as text: one line per row, with the columns separated by
+// tabs and preceded by a line of column names if there are several.
+// The command depends on the database, e.g. "EXPLAIN" for PostgreSQL and
+// MySQL, "EXPLAIN (FORMAT JSON)" for a PostgreSQL plan in JSON (with
+// Explainer.ContentType "application/json"), or "EXPLAIN QUERY PLAN" for
+// SQLite.
+//
+// Commands that execute the query, such as PostgreSQL's EXPLAIN ANALYZE,
+// double the load of each slow query and should be avoided. Queries with
+// placeholders can only be explained by databases that accept them
+// without arguments.
+func ExplainDB(db *sql.DB, command string) func(ctx context.Context, query string) (string, error) {
+	return func(ctx context.Context, query string) (string, error) {
+		rows, err := db.QueryContext(ctx, command+" "+query)
+		if err != nil {
+			return "", err
+		}
+		defer rows.Close()
+		cols, err := rows.Columns()
+		if err != nil {
+			return "", err
+		}
+
+		var lines []string
+		if len(cols) > 1 {
+			lines = append(lines, strings.Join(cols, "\t"))
+		}
+		vals := make([]interface{}, len(cols))
+		for i := range vals {
+			vals[i] = new(sql.RawBytes)
+		}
+		for rows.Next() {
+			if err := rows.Scan(vals...); err != nil {
+				return "", err
+			}
+			fields := make([]string, len(vals))
+			for i, v := range vals {
+				fields[i] = string(*v.(*sql.RawBytes))
+			}
+			lines = append(lines, strings.Join(fields, "\t"))
+		}
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("%s: %s", command, err)
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+}
@@ -0,0 +1,108 @@
+package sqltrace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestExplainer(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	c := appdash.NewLocalCollector(ms)
+
+	// Signal each plan once it has been collected.
+	explained := make(chan string, 10)
+	x := &Explainer{
+		Explain: func(ctx context.Context, query string) (string, error) {
+			return "Seq Scan on " + query, nil
+		},
+		Collector: collectorFunc(func(span appdash.SpanID, as ...appdash.Annotation) error {
+			defer func() { explained <- span.Trace.String() }()
+			return c.Collect(span, as...)
+		}),
+		Threshold: 100 * time.Millisecond,
+	}
+	remove := x.Register()
+	defer remove()
+
+	start := time.Unix(0, 0)
+	fast := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, c)
+	Record(fast, SQLEvent{SQL: "fast", ClientSend: start, ClientRecv: start.Add(10 * time.Millisecond)})
+	fast.Finish()
+	slow := appdash.NewRecorder(appdash.SpanID{Trace: 2, Span: 1}, c)
+	Record(slow, SQLEvent{SQL: "slow", ClientSend: start, ClientRecv: start.Add(time.Second)})
+	slow.Finish()
+
+	select {
+	case trace := <-explained:
+		if trace != appdash.ID(2).String() {
+			t.Errorf("got a plan for trace %s, want one for the slow query's trace", trace)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow query was not explained")
+	}
+
+	trace, err := ms.Trace(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plan PlanEvent
+	if err := appdash.UnmarshalEvent(trace.Span.Annotations, &plan); err != nil {
+		t.Fatal(err)
+	}
+	if want := (PlanEvent{Plan: "Seq Scan on slow", ContentType: "text/plain"}); plan != want {
+		t.Errorf("got plan %+v, want %+v", plan, want)
+	}
+
+	trace, err = ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := trace.Span.Annotations.Get("SQL.Plan.Text"); ok {
+		t.Error("fast query has a plan annotation")
+	}
+
+	// The same query is not explained again within the interval.
+	Record(slow, SQLEvent{SQL: "slow", ClientSend: start, ClientRecv: start.Add(time.Second)})
+	select {
+	case <-explained:
+		t.Error("query was explained again within the interval")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if got := x.Skipped(); got != 1 {
+		t.Errorf("got %d skipped queries, want 1", got)
+	}
+}
+
+func TestExplainer_maxPlanSize(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	x := &Explainer{
+		Explain: func(ctx context.Context, query string) (string, error) {
+			return `{"Plan": "a long plan"}`, nil
+		},
+		Collector:   appdash.NewLocalCollector(ms),
+		ContentType: "application/json",
+		MaxPlanSize: 8,
+	}
+	x.explain(appdash.SpanID{Trace: 1, Span: 1}, "SELECT 1", time.Second)
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plan PlanEvent
+	if err := appdash.UnmarshalEvent(trace.Span.Annotations, &plan); err != nil {
+		t.Fatal(err)
+	}
+	if want := (PlanEvent{Plan: `{"Plan":`, ContentType: "application/json", Truncated: true}); plan != want {
+		t.Errorf("got plan %+v, want %+v", plan, want)
+	}
+}
+
+type collectorFunc func(appdash.SpanID, ...appdash.Annotation) error
+
+func (c collectorFunc) Collect(span appdash.SpanID, anns ...appdash.Annotation) error {
+	return c(span, anns...)
+}
@@ -2,6 +2,7 @@
 package sqltrace
 
 import (
+	"sync"
 	"time"
 
 	"sourcegraph.com/sourcegraph/appdash"
@@ -33,3 +34,63 @@ func (e SQLEvent) Start() time.Time { return e.ClientSend }
 func (e SQLEvent) End() time.Time { return e.ClientRecv }
 
 func init() { appdash.RegisterEvent(SQLEvent{}) }
+
+// SlowQueryThreshold, if non-zero, is the duration above which Record
+// considers a query slow and raises the sampling priority of its span to 1
+// (see appdash.SamplingPriorityKey), so that tail-based samplers keep its
+// trace.
+var SlowQueryThreshold time.Duration
+
+// Record records the SQL event e on rec, raising the span's sampling
+// priority if the query took longer than SlowQueryThreshold, and calling
+// the hooks registered with OnSlowQuery whose threshold it exceeded.
+func Record(rec *appdash.Recorder, e SQLEvent) {
+	rec.Event(e)
+	d := e.End().Sub(e.Start())
+	if SlowQueryThreshold > 0 && d > SlowQueryThreshold {
+		rec.SamplingPriority(1)
+	}
+
+	slowQueryHooksMu.RLock()
+	for _, h := range slowQueryHooks {
+		if d > h.threshold {
+			go h.fn(rec.SpanID, e.SQL, d)
+		}
+	}
+	slowQueryHooksMu.RUnlock()
+}
+
+// slowQueryHook is a hook registered with OnSlowQuery.
+type slowQueryHook struct {
+	threshold time.Duration
+	fn        func(span appdash.SpanID, sql string, d time.Duration)
+}
+
+var (
+	slowQueryHooks   []*slowQueryHook
+	slowQueryHooksMu sync.RWMutex
+)
+
+// OnSlowQuery registers fn to be called for each query recorded with
+// Record that took longer than threshold, e.g. to log it or update a
+// metric immediately rather than polling the store. The span is recorded
+// as usual. fn is called in a new goroutine so as not to delay the caller
+// of Record, and so must be safe for concurrent use.
+//
+// Call the returned function to unregister the hook.
+func OnSlowQuery(threshold time.Duration, fn func(span appdash.SpanID, sql string, d time.Duration)) (remove func()) {
+	h := &slowQueryHook{threshold: threshold, fn: fn}
+	slowQueryHooksMu.Lock()
+	slowQueryHooks = append(slowQueryHooks, h)
+	slowQueryHooksMu.Unlock()
+	return func() {
+		slowQueryHooksMu.Lock()
+		defer slowQueryHooksMu.Unlock()
+		for i, other := range slowQueryHooks {
+			if other == h {
+				slowQueryHooks = append(slowQueryHooks[:i:i], slowQueryHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package sqltrace
+
+import (
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestOnSlowQuery(t *testing.T) {
+	type slowQuery struct {
+		span appdash.SpanID
+		sql  string
+		d    time.Duration
+	}
+	slow := make(chan slowQuery, 10)
+	remove := OnSlowQuery(100*time.Millisecond, func(span appdash.SpanID, sql string, d time.Duration) {
+		slow <- slowQuery{span, sql, d}
+	})
+	defer remove()
+
+	rec := appdash.NewRecorder(appdash.SpanID{1, 2, 3}, appdash.NewLocalCollector(appdash.NewMemoryStore()))
+	start := time.Unix(0, 0)
+	Record(rec, SQLEvent{SQL: "SELECT 1", ClientSend: start, ClientRecv: start.Add(10 * time.Millisecond)})
+	Record(rec, SQLEvent{SQL: "SELECT 2", ClientSend: start, ClientRecv: start.Add(time.Second)})
+
+	want := slowQuery{appdash.SpanID{1, 2, 3}, "SELECT 2", time.Second}
+	select {
+	case got := <-slow:
+		if got != want {
+			t.Errorf("got slow query %+v, want %+v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("hook was not called for the slow query")
+	}
+
+	// The hook runs in a goroutine; give a wrongly fired call a chance to
+	// arrive.
+	select {
+	case got := <-slow:
+		t.Errorf("hook fired for fast query %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	remove()
+	Record(rec, SQLEvent{SQL: "SELECT 3", ClientSend: start, ClientRecv: start.Add(time.Second)})
+	select {
+	case got := <-slow:
+		t.Errorf("removed hook fired for %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
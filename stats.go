@@ -0,0 +1,139 @@
+package appdash
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// Stats holds counters describing the operation of the tracing pipeline
+// itself (as opposed to the application being traced), such that one can
+// alert when trace data is being dropped.
+//
+// A single Stats may be shared by a MemoryStore and the collectors that feed
+// it, by setting their Stats fields. The counters are updated atomically and
+// so do not contend with collection. *Stats implements the expvar.Var
+// interface, so it may be published directly:
+//
+//  stats := &appdash.Stats{}
+//  expvar.Publish("appdash", stats)
+//
+// The zero value is ready to use.
+type Stats struct {
+	// These fields are accessed atomically, and are kept at the start of
+	// the struct so that they are 64-bit aligned.
+	spansCollected   int64
+	bytesCollected   int64
+	traces           int64
+	evictions        int64
+	dropped          int64
+	collectErrors    int64
+	compressedTraces int64
+	compressSaved    int64
+}
+
+// StatsSnapshot is a point-in-time copy of the counters in a Stats.
+type StatsSnapshot struct {
+	// SpansCollected is the number of Collect calls a MemoryStore has
+	// handled.
+	SpansCollected int64
+
+	// BytesCollected is the total size of the annotation keys and values a
+	// MemoryStore has collected.
+	BytesCollected int64
+
+	// Traces is the number of traces currently held by a MemoryStore.
+	Traces int64
+
+	// Evictions is the number of traces deleted from a MemoryStore, as
+	// RecentStore and LimitStore do to evict old traces.
+	Evictions int64
+
+	// Dropped is the number of spans a ChunkedCollector discarded because
+	// its queue grew too large or a flush timed out.
+	Dropped int64
+
+	// CollectErrors is the number of errors encountered sending spans to an
+	// underlying collector or a collector server.
+	CollectErrors int64
+
+	// CompressedTraces is the number of traces whose annotations a
+	// MemoryStore currently holds compressed (see
+	// MemoryStore.CompressAfter).
+	CompressedTraces int64
+
+	// CompressionSavedBytes is the approximate number of bytes of memory
+	// saved by compressing them.
+	CompressionSavedBytes int64
+}
+
+// Snapshot returns the current values of the counters.
+func (s *Stats) Snapshot() StatsSnapshot {
+	return StatsSnapshot{
+		SpansCollected: atomic.LoadInt64(&s.spansCollected),
+		BytesCollected: atomic.LoadInt64(&s.bytesCollected),
+		Traces:         atomic.LoadInt64(&s.traces),
+		Evictions:      atomic.LoadInt64(&s.evictions),
+		Dropped:        atomic.LoadInt64(&s.dropped),
+		CollectErrors:  atomic.LoadInt64(&s.collectErrors),
+
+		CompressedTraces:      atomic.LoadInt64(&s.compressedTraces),
+		CompressionSavedBytes: atomic.LoadInt64(&s.compressSaved),
+	}
+}
+
+// String implements the expvar.Var interface by returning a JSON object of
+// the counters.
+func (s *Stats) String() string {
+	b, err := json.Marshal(s.Snapshot())
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+// The following methods are no-ops on a nil *Stats, so that callers need not
+// check whether stats were requested.
+
+func (s *Stats) collected(as []Annotation) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.spansCollected, 1)
+	atomic.AddInt64(&s.bytesCollected, annotationsSize(as))
+}
+
+func (s *Stats) addTraces(n int64) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.traces, n)
+}
+
+func (s *Stats) evicted(n int64) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.evictions, n)
+}
+
+func (s *Stats) droppedSpans(n int64) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.dropped, n)
+}
+
+func (s *Stats) collectError() {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.collectErrors, 1)
+}
+
+func (s *Stats) compressed(traces, saved int64) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.compressedTraces, traces)
+	atomic.AddInt64(&s.compressSaved, saved)
+}
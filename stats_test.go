@@ -0,0 +1,75 @@
+package appdash
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStats_MemoryStore(t *testing.T) {
+	stats := &Stats{}
+	ms := NewMemoryStore()
+	ms.Stats = stats
+	s := storeT{t, ms}
+
+	s.MustCollect(SpanID{1, 1, 0}, Annotation{Key: "k", Value: []byte("v")})
+	s.MustCollect(SpanID{1, 2, 1})
+	s.MustCollect(SpanID{2, 3, 0}, Annotation{Key: "kk", Value: []byte("vv")})
+	want := StatsSnapshot{SpansCollected: 3, BytesCollected: 6, Traces: 2}
+	if got := stats.Snapshot(); got != want {
+		t.Errorf("after Collect: got %+v, want %+v", got, want)
+	}
+
+	if err := ms.Delete(1, 123); err != nil {
+		t.Fatal(err)
+	}
+	want.Traces, want.Evictions = 1, 1
+	if got := stats.Snapshot(); got != want {
+		t.Errorf("after Delete: got %+v, want %+v", got, want)
+	}
+}
+
+func TestStats_ChunkedCollector(t *testing.T) {
+	stats := &Stats{}
+	cc := &ChunkedCollector{
+		Collector: collectorFunc(func(SpanID, ...Annotation) error {
+			return errors.New("x")
+		}),
+		MinInterval:  time.Hour,
+		MaxQueueSize: 100,
+		Stats:        stats,
+	}
+
+	cc.Collect(SpanID{1, 1, 0})
+	cc.Collect(SpanID{1, 2, 1})
+	if err := cc.Collect(SpanID{1, 3, 1}, Annotation{Key: "k", Value: make([]byte, 100)}); err != ErrQueueDropped {
+		t.Fatalf("got error %v, want %v", err, ErrQueueDropped)
+	}
+	cc.Collect(SpanID{1, 4, 1})
+	cc.Flush()
+	cc.Stop()
+
+	want := StatsSnapshot{Dropped: 2, CollectErrors: 1}
+	if got := stats.Snapshot(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestStats_String(t *testing.T) {
+	stats := &Stats{}
+	stats.collected([]Annotation{{Key: "k"}})
+
+	var got StatsSnapshot
+	if err := json.Unmarshal([]byte(stats.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (StatsSnapshot{SpansCollected: 1, BytesCollected: 1}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// A nil *Stats is a no-op.
+	var nilStats *Stats
+	nilStats.collected(nil)
+	nilStats.collectError()
+}
@@ -1,12 +1,15 @@
 package appdash
 
 import (
+	"bytes"
 	"encoding/gob"
 	"errors"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -15,14 +18,19 @@ import (
 type Store interface {
 	Collector
 
-	// Trace gets a trace (a tree of spans) given its trace ID. If no
-	// such trace exists, ErrTraceNotFound is returned.
+	// Trace gets a trace (a tree of spans) given its trace ID. If no span
+	// of the trace was ever collected (or the trace was deleted),
+	// ErrTraceNotFound is returned. Otherwise the trace is returned, even
+	// if its spans have no annotations, so that callers can tell a missing
+	// trace from an empty one.
 	Trace(ID) (*Trace, error)
 }
 
 var (
-	// ErrTraceNotFound is returned by Store.GetTrace when no trace is
-	// found with the given ID.
+	// ErrTraceNotFound is returned by Store.Trace when no trace is
+	// found with the given ID. Implementations must return this error
+	// value itself, not a wrapped one, so that callers can compare
+	// against it.
 	ErrTraceNotFound = errors.New("trace not found")
 )
 
@@ -33,6 +41,25 @@ type TracesOpts struct {
 
 	// TraceIDs filters the returned traces to just the ones with the given IDs.
 	TraceIDs []ID
+
+	// CorrelationID, if non-empty, filters the returned traces to the ones
+	// linked to the given external ID (see CorrelationID).
+	CorrelationID string
+
+	// Service, if non-empty, filters the returned traces to the ones that
+	// touch the named service, i.e. that have a span tagged with it (see
+	// ServiceKey).
+	Service string
+
+	// Environment, if non-empty, filters the returned traces to the ones
+	// that belong to the named deployment environment, i.e. that have a
+	// span tagged with it (see EnvironmentKey).
+	Environment string
+
+	// SortByRecency, if true, sorts the returned traces so that the most
+	// recent trace comes first (see SortTracesByRecency). Otherwise the
+	// order is implementation-defined, which is cheaper.
+	SortByRecency bool
 }
 
 // A Queryer indexes spans and makes them queryable.
@@ -58,6 +85,15 @@ type AggregatedResult struct {
 	// this result.
 	Samples int64
 
+	// Errors is the number of the sampled spans that failed, if the
+	// aggregator counts them (MetricsOnlyCollector does).
+	Errors int64
+
+	// Latency is the distribution of the trace times, from which
+	// percentiles can be estimated, if the aggregator keeps one
+	// (MetricsOnlyCollector does).
+	Latency *LatencySketch
+
 	// Slowest is the N-slowest trace IDs that were part of this group, such
 	// that these are the most valuable/slowest traces for inspection.
 	Slowest []ID
@@ -78,19 +114,76 @@ type Aggregator interface {
 // NewMemoryStore creates a new in-memory store
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		trace: map[ID]*Trace{},
-		span:  map[ID]map[ID]*Trace{},
+		trace:      map[ID]*Trace{},
+		span:       map[ID]map[ID]*Trace{},
+		traceAnns:  map[ID]Annotations{},
+		traceBytes: map[ID]int64{},
+		correlated: map[string]map[ID]struct{}{},
+		indexed:    map[string]map[string]map[ID]struct{}{},
 	}
 }
 
+// NewMemoryStoreBytes creates a new in-memory store that holds at most
+// approximately maxBytes bytes of annotations (counting their keys and
+// values). When collecting a span takes the store over its budget, the
+// oldest traces are deleted until it is within budget again. Unlike a
+// LimitStore, this bounds memory use no matter how large each trace is.
+//
+// A trace that is larger than maxBytes by itself is deleted as soon as it
+// exceeds the budget.
+func NewMemoryStoreBytes(maxBytes int64) *MemoryStore {
+	ms := NewMemoryStore()
+	ms.maxBytes = maxBytes
+	return ms
+}
+
 // A MemoryStore is an in-memory Store that also implements the PersistentStore
 // interface.
+//
+// A MemoryStore guards all of its traces with a single lock, which
+// concurrent collections contend on. Processes that collect many spans
+// concurrently on multi-core machines should use a ShardedMemoryStore,
+// which spreads traces across several MemoryStores, each with its own lock.
 type MemoryStore struct {
 	trace map[ID]*Trace        // trace ID -> trace tree
 	span  map[ID]map[ID]*Trace // trace ID -> span ID -> trace (sub)tree
 
+	traceAnns  map[ID]Annotations         // trace ID -> trace-level annotations (unprefixed)
+	traceDups  map[ID]struct{}            // set of trace IDs with a trace-level key collected with differing values
+	correlated map[string]map[ID]struct{} // correlation ID -> set of trace IDs linked to it
+	indexed    map[string]map[string]map[ID]struct{} // indexed annotation key (see indexedKeys) -> value -> set of trace IDs with it
+
+	maxBytes   int64             // byte budget (see NewMemoryStoreBytes), or 0 for none
+	bytes      int64             // approximate size of all stored annotations
+	traceBytes map[ID]int64      // trace ID -> approximate size of its annotations
+	traceOrder []traceOrderEntry // traces in creation order, only kept with a byte budget; may include stale entries
+	traceSeq   map[ID]uint64     // trace ID -> sequence number of its current traceOrder entry
+	nextSeq    uint64            // sequence number of the next traceOrder entry
+
+	accessed     map[ID]int64      // trace ID -> UnixNano time last collected into or read, only kept for uncompressed traces with CompressAfter
+	cold         map[ID]*coldTrace // trace ID -> compressed annotations of idle traces
+	lastCompress int64             // UnixNano time idle traces were last looked for
+	compressing  bool              // whether idle traces are being compressed
+
 	sync.Mutex // protects trace
 
+	// Stats, if non-nil, is updated with the number of spans and bytes
+	// collected, traces held, and traces deleted. It should be set before
+	// the store is first used.
+	Stats *Stats
+
+	// CompressAfter, if non-zero, is how long a trace must go without being
+	// collected into or read by Trace before its annotations are compressed
+	// in memory. Compression is transparent to callers: a compressed trace
+	// is decompressed when Trace is called or a span of it is collected,
+	// and Traces and Write decompress copies of compressed traces without
+	// decompressing them in the store. Idle traces are looked for in the
+	// background during Collect, at most every CompressAfter/2. The size
+	// reported by Bytes and counted against a byte budget is that of the
+	// uncompressed annotations. It should be set before the store is first
+	// used.
+	CompressAfter time.Duration
+
 	log bool
 }
 
@@ -98,6 +191,7 @@ type MemoryStore struct {
 var _ interface {
 	Store
 	Queryer
+	SubTraceStore
 } = (*MemoryStore)(nil)
 
 // Collect implements the Collector interface by collecting the events that
@@ -105,7 +199,86 @@ var _ interface {
 func (ms *MemoryStore) Collect(id SpanID, as ...Annotation) error {
 	ms.Lock()
 	defer ms.Unlock()
-	return ms.collectNoLock(id, as...)
+	err := ms.collectNoLock(id, as...)
+	ms.evictNoLock()
+	ms.maybeCompressNoLock()
+	return err
+}
+
+// Bytes returns the approximate size in bytes of the annotations held by
+// the store, counting their keys and values.
+func (ms *MemoryStore) Bytes() int64 {
+	ms.Lock()
+	defer ms.Unlock()
+	return ms.bytes
+}
+
+// addBytesNoLock accounts for annotations of size n being added to (or, if
+// n is negative, removed from) the given trace.
+func (ms *MemoryStore) addBytesNoLock(trace ID, n int64) {
+	if ms.traceBytes == nil {
+		ms.traceBytes = map[ID]int64{}
+	}
+	if _, present := ms.traceBytes[trace]; !present && ms.maxBytes > 0 {
+		if ms.traceSeq == nil {
+			ms.traceSeq = map[ID]uint64{}
+		}
+		ms.nextSeq++
+		ms.traceSeq[trace] = ms.nextSeq
+		ms.traceOrder = append(ms.traceOrder, traceOrderEntry{trace: trace, seq: ms.nextSeq})
+	}
+	ms.traceBytes[trace] += n
+	ms.bytes += n
+}
+
+// A traceOrderEntry records when a trace was created, for eviction. A trace
+// that is deleted and then collected again gets a new entry; the entry of
+// the deleted trace is stale, and is told apart by its sequence number.
+type traceOrderEntry struct {
+	trace ID
+	seq   uint64
+}
+
+// staleNoLock reports whether e is not the current entry of its trace, because
+// the trace was deleted since e was recorded. It does not grab the lock.
+func (ms *MemoryStore) staleNoLock(e traceOrderEntry) bool {
+	seq, present := ms.traceSeq[e.trace]
+	return !present || seq != e.seq
+}
+
+// evictNoLock deletes the oldest traces until the store is within its
+// byte budget, if it has one.
+func (ms *MemoryStore) evictNoLock() {
+	if ms.maxBytes <= 0 {
+		return
+	}
+	for ms.bytes > ms.maxBytes && len(ms.traceOrder) > 0 {
+		oldest := ms.traceOrder[0]
+		ms.traceOrder = ms.traceOrder[1:]
+		if !ms.staleNoLock(oldest) {
+			ms.deleteNoLock(oldest.trace)
+		}
+	}
+
+	// Drop stale entries from the order once they dominate it.
+	if len(ms.traceOrder) > 2*len(ms.traceSeq)+16 {
+		order := make([]traceOrderEntry, 0, len(ms.traceSeq))
+		for _, e := range ms.traceOrder {
+			if !ms.staleNoLock(e) {
+				order = append(order, e)
+			}
+		}
+		ms.traceOrder = order
+	}
+}
+
+// annotationsSize returns the approximate size of as in bytes.
+func annotationsSize(as []Annotation) int64 {
+	var n int64
+	for _, a := range as {
+		n += int64(len(a.Key) + len(a.Value))
+	}
+	return n
 }
 
 // collectNoLock is the same as Collect, but it does not grab the lock.
@@ -113,6 +286,13 @@ func (ms *MemoryStore) collectNoLock(id SpanID, as ...Annotation) error {
 	if ms.log {
 		log.Printf("Collect %v", id)
 	}
+	if err := ms.thawNoLock(id.Trace); err != nil {
+		return err
+	}
+	ms.touchNoLock(id.Trace)
+	ms.Stats.collected(as)
+	ms.addBytesNoLock(id.Trace, annotationsSize(as))
+	ms.indexTraceAnnotationsNoLock(id.Trace, as)
 
 	// Initialize span map if needed.
 	if _, present := ms.span[id.Trace]; !present {
@@ -147,6 +327,7 @@ func (ms *MemoryStore) collectNoLock(id SpanID, as ...Annotation) error {
 			}
 		}
 		ms.trace[id.Trace] = s
+		ms.Stats.addTraces(1)
 		root = s
 	}
 
@@ -181,6 +362,7 @@ func (ms *MemoryStore) collectNoLock(id SpanID, as ...Annotation) error {
 			}
 		}
 		oldRoot.Sub = sub2
+		ms.invalidateIndexNoLock(oldRoot)
 	}
 
 	// Insert into trace tree. (We inserted the trace root span
@@ -198,15 +380,96 @@ func (ms *MemoryStore) collectNoLock(id SpanID, as ...Annotation) error {
 	return nil
 }
 
+// indexTraceAnnotationsNoLock records the trace-level annotations in as (see
+// TraceAnnotationPrefix) in ms.traceAnns, so that they can be looked up
+// without walking the trace tree. Only the first value of each key is kept;
+// traces with keys collected with differing values are recorded in
+// ms.traceDups, since which value wins depends on the tree.
+// It also indexes the trace by the values of the annotations in as whose
+// keys are in indexedKeys.
+func (ms *MemoryStore) indexTraceAnnotationsNoLock(trace ID, as Annotations) {
+	for _, a := range as {
+		if isIndexedKey(a.Key) {
+			if ms.indexed == nil {
+				ms.indexed = map[string]map[string]map[ID]struct{}{}
+			}
+			if ms.indexed[a.Key] == nil {
+				ms.indexed[a.Key] = map[string]map[ID]struct{}{}
+			}
+			if ms.indexed[a.Key][string(a.Value)] == nil {
+				ms.indexed[a.Key][string(a.Value)] = map[ID]struct{}{}
+			}
+			ms.indexed[a.Key][string(a.Value)][trace] = struct{}{}
+			continue
+		}
+		if !strings.HasPrefix(a.Key, TraceAnnotationPrefix) {
+			continue
+		}
+		if ms.traceAnns == nil {
+			ms.traceAnns = map[ID]Annotations{}
+		}
+		key := a.Key[len(TraceAnnotationPrefix):]
+		if !ms.traceAnns[trace].has(key) {
+			ms.traceAnns[trace] = append(ms.traceAnns[trace], Annotation{Key: key, Value: a.Value})
+		} else if !bytes.Equal(ms.traceAnns[trace].get(key), a.Value) {
+			if ms.traceDups == nil {
+				ms.traceDups = map[ID]struct{}{}
+			}
+			ms.traceDups[trace] = struct{}{}
+		}
+		if key == CorrelationIDKey {
+			if ms.correlated == nil {
+				ms.correlated = map[string]map[ID]struct{}{}
+			}
+			cid := string(a.Value)
+			if ms.correlated[cid] == nil {
+				ms.correlated[cid] = map[ID]struct{}{}
+			}
+			ms.correlated[cid][trace] = struct{}{}
+		}
+	}
+}
+
+// TraceAnnotations returns the trace-level annotations of the given trace,
+// as Trace.TraceAnnotations does, without walking its span tree unless a
+// key was collected with differing values. If no such trace exists,
+// ErrTraceNotFound is returned.
+func (ms *MemoryStore) TraceAnnotations(id ID) (Annotations, error) {
+	ms.Lock()
+	defer ms.Unlock()
+	t, present := ms.trace[id]
+	if !present {
+		return nil, ErrTraceNotFound
+	}
+	if _, dup := ms.traceDups[id]; !dup {
+		return ms.traceAnns[id], nil
+	}
+	if c, cold := ms.cold[id]; cold {
+		t = t.copy()
+		if err := c.restore(t); err != nil {
+			return nil, err
+		}
+	}
+	return t.TraceAnnotations(), nil
+}
+
 // insert inserts t into the trace tree whose root (or temp root) is
 // root.
 func (ms *MemoryStore) insert(root, t *Trace) {
 	p, present := ms.span[t.ID.Trace][t.ID.Parent]
-	if present {
+	if present && p == t {
+		if ms.log {
+			log.Printf("Add %v, its own parent, as an orphan of root %v", t.Span.ID, root.Span.ID)
+		}
+		ms.markCycleNoLock(t)
+		root.Sub = append(root.Sub, t)
+		ms.invalidateIndexNoLock(root)
+	} else if present {
 		if ms.log {
 			log.Printf("Add %v as a child of parent %v", t.Span.ID, p.Span.ID)
 		}
 		p.Sub = append(p.Sub, t)
+		ms.invalidateIndexNoLock(p)
 	} else {
 		// Add as temporary child of the root for now. When the
 		// real parent is added, we'll fix it up later.
@@ -214,7 +477,57 @@ func (ms *MemoryStore) insert(root, t *Trace) {
 			log.Printf("Add %v as a temporary child of root %v", t.Span.ID, root.Span.ID)
 		}
 		root.Sub = append(root.Sub, t)
+		ms.invalidateIndexNoLock(root)
+	}
+}
+
+// invalidateIndexNoLock discards the indexes (see Trace.Reindex) of t, of
+// its ancestors and of the root of its trace, whose trees have changed
+// shape. It does not grab the lock.
+func (ms *MemoryStore) invalidateIndexNoLock(t *Trace) {
+	trace := t.ID.Trace
+	// Bound the number of steps, in case the parent IDs form a cycle.
+	spans := ms.span[trace]
+	for i := 0; i <= len(spans) && t != nil; i++ {
+		t.invalidateIndex()
+		if t.ID.IsRoot() {
+			break
+		}
+		t = spans[t.ID.Parent]
+	}
+	if root := ms.trace[trace]; root != nil {
+		root.invalidateIndex()
+	}
+}
+
+// descendsNoLock reports whether the span t is, by the parent IDs of the
+// collected spans, a descendant of (or the same span as) the span anc. If
+// so, making anc a child of t would create a parent cycle, which corrupt
+// span IDs can describe (e.g. spans A and B each naming the other as its
+// parent). It does not grab the lock.
+func (ms *MemoryStore) descendsNoLock(t, anc *Trace) bool {
+	// Bound the number of steps, in case the parent IDs already form a
+	// cycle that does not include anc.
+	spans := ms.span[t.ID.Trace]
+	for i := 0; i <= len(spans) && t != nil; i++ {
+		if t == anc {
+			return true
+		}
+		if t.ID.IsRoot() {
+			break
+		}
+		t = spans[t.ID.Parent]
 	}
+	return false
+}
+
+// markCycleNoLock marks t, which is kept as an orphan (see Trace.Walk)
+// rather than being added to its parent, with a CycleDetectedKey annotation.
+// It does not grab the lock.
+func (ms *MemoryStore) markCycleNoLock(t *Trace) {
+	a := Annotation{Key: CycleDetectedKey, Value: []byte("true")}
+	t.Annotations = append(t.Annotations, a)
+	ms.addBytesNoLock(t.ID.Trace, annotationsSize([]Annotation{a}))
 }
 
 // reattachChildren moves temporary children of src to dst, if dst is
@@ -225,7 +538,15 @@ func (ms *MemoryStore) reattachChildren(dst, src *Trace) {
 	}
 	var sub2 []*Trace
 	for _, c := range src.Sub {
-		if c.Span.ID.Parent == dst.Span.ID.Span {
+		if c.Span.ID.Parent == dst.Span.ID.Span && ms.descendsNoLock(dst, c) {
+			if ms.log {
+				log.Printf("Leave %v under src %v to avoid a parent cycle", c.Span.ID, src.Span.ID)
+			}
+			if !c.Annotations.has(CycleDetectedKey) {
+				ms.markCycleNoLock(c)
+			}
+			sub2 = append(sub2, c)
+		} else if c.Span.ID.Parent == dst.Span.ID.Span {
 			if ms.log {
 				log.Printf("Move %v from src %v to dst %v", c.Span.ID, src.Span.ID, dst.Span.ID)
 			}
@@ -235,6 +556,8 @@ func (ms *MemoryStore) reattachChildren(dst, src *Trace) {
 		}
 	}
 	src.Sub = sub2
+	ms.invalidateIndexNoLock(src)
+	ms.invalidateIndexNoLock(dst)
 }
 
 // Trace implements the Store interface by returning the Trace (a tree of
@@ -244,7 +567,38 @@ func (ms *MemoryStore) Trace(id ID) (*Trace, error) {
 	ms.Lock()
 	defer ms.Unlock()
 
-	return ms.traceNoLock(id)
+	if err := ms.thawNoLock(id); err != nil {
+		return nil, err
+	}
+	t, err := ms.traceNoLock(id)
+	if err == nil {
+		ms.touchNoLock(id)
+	}
+	return t, err
+}
+
+// TracesByID returns the traces with the given IDs, as Trace would, but
+// looks them all up while holding the lock once. Traces that are not found
+// are absent from the returned map.
+func (ms *MemoryStore) TracesByID(ids ...ID) (map[ID]*Trace, error) {
+	ms.Lock()
+	defer ms.Unlock()
+
+	traces := make(map[ID]*Trace, len(ids))
+	for _, id := range ids {
+		if err := ms.thawNoLock(id); err != nil {
+			return nil, err
+		}
+		t, err := ms.traceNoLock(id)
+		if err == ErrTraceNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		ms.touchNoLock(id)
+		traces[id] = t
+	}
+	return traces, nil
 }
 
 func (ms *MemoryStore) traceNoLock(id ID) (*Trace, error) {
@@ -255,22 +609,131 @@ func (ms *MemoryStore) traceNoLock(id ID) (*Trace, error) {
 	return t, nil
 }
 
-// Traces implements the Queryer interface.
+// Traces implements the Queryer interface. It returns snapshots of the
+// traces, which later collections do not modify. Traces linked to
+// opts.CorrelationID, or touching opts.Service or opts.Environment, are
+// looked up in an index rather than by scanning every trace. If
+// opts.Timespan is set, only the traces whose root span started within it
+// are returned; others are skipped without being copied.
+//
+// To avoid stalling concurrent collections, the lock is only held to list
+// the trace IDs and then to copy each trace in turn; sorting happens without
+// holding the lock. A trace collected while Traces runs may or may not be
+// included, but every returned trace is a consistent copy.
 func (ms *MemoryStore) Traces(opts TracesOpts) ([]*Trace, error) {
 	ms.Lock()
-	defer ms.Unlock()
+	var ids []ID
+	if sets := ms.indexedNoLock(opts); len(sets) > 0 {
+		for id := range sets[0] {
+			ids = append(ids, id)
+		}
+		for _, set := range sets[1:] {
+			ids = filterIndexed(ids, set)
+		}
+	} else {
+		ids = make([]ID, 0, len(ms.trace))
+		for id := range ms.trace {
+			ids = append(ids, id)
+		}
+	}
+	ms.Unlock()
+	if len(opts.TraceIDs) > 0 {
+		ids = filterIDs(ids, opts.TraceIDs)
+	}
 
-	var ts []*Trace
-	for id := range ms.trace {
+	ts := make([]*Trace, 0, len(ids))
+	for _, id := range ids {
+		ms.Lock()
 		t, err := ms.traceNoLock(id)
-		if err != nil {
+		cold := ms.cold[id]
+		if err == nil && cold == nil && !t.startedWithin(opts.Timespan) {
+			ms.Unlock()
+			continue
+		}
+		if err == nil {
+			t = t.copy()
+		}
+		ms.Unlock()
+		if err == ErrTraceNotFound {
+			continue // deleted since listing the IDs
+		} else if err != nil {
 			return nil, err
 		}
+		if cold != nil {
+			if err := cold.restore(t); err != nil {
+				return nil, err
+			}
+			if !t.startedWithin(opts.Timespan) {
+				continue
+			}
+		}
 		ts = append(ts, t)
 	}
+	if opts.SortByRecency {
+		SortTracesByRecency(ts)
+	}
 	return ts, nil
 }
 
+// filterIDs returns the IDs in ids that are also in just.
+func filterIDs(ids, just []ID) []ID {
+	want := make(map[ID]struct{}, len(just))
+	for _, id := range just {
+		want[id] = struct{}{}
+	}
+	filtered := ids[:0]
+	for _, id := range ids {
+		if _, ok := want[id]; ok {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
+
+// filterIndexed returns the IDs in ids that are also in the set index.
+func filterIndexed(ids []ID, index map[ID]struct{}) []ID {
+	filtered := ids[:0]
+	for _, id := range ids {
+		if _, ok := index[id]; ok {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
+
+// indexedNoLock returns the sets of traces matching each of the
+// CorrelationID, Service and Environment filters of opts that is set, all
+// of which a trace must be in to match. It does not grab the lock.
+func (ms *MemoryStore) indexedNoLock(opts TracesOpts) []map[ID]struct{} {
+	var sets []map[ID]struct{}
+	if opts.CorrelationID != "" {
+		sets = append(sets, ms.correlated[opts.CorrelationID])
+	}
+	if opts.Service != "" {
+		sets = append(sets, ms.indexed[ServiceKey][opts.Service])
+	}
+	if opts.Environment != "" {
+		sets = append(sets, ms.indexed[EnvironmentKey][opts.Environment])
+	}
+	return sets
+}
+
+// IndexedValues returns the sorted values that the annotations with the
+// given key have on the spans of the stored traces, such as the names of
+// the services they touch for ServiceKey, or of the environments they
+// belong to for EnvironmentKey. Only those keys are indexed; for others,
+// IndexedValues returns nil.
+func (ms *MemoryStore) IndexedValues(key string) []string {
+	ms.Lock()
+	defer ms.Unlock()
+	values := make([]string, 0, len(ms.indexed[key]))
+	for value := range ms.indexed[key] {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
 // Delete implements the DeleteStore interface by deleting the traces given by
 // their span ID's from this in-memory store.
 func (ms *MemoryStore) Delete(traces ...ID) error {
@@ -279,11 +742,98 @@ func (ms *MemoryStore) Delete(traces ...ID) error {
 	return ms.deleteNoLock(traces...)
 }
 
+// DeleteWhere implements the DeleteStore interface. Like Traces, it only
+// holds the lock to list the trace IDs and then to check and delete each
+// trace in turn, so that collection can continue concurrently; a trace
+// collected into while DeleteWhere runs is checked either before or after
+// the collection, and is deleted whole or not at all.
+func (ms *MemoryStore) DeleteWhere(filters ...QueryFilter) (int, error) {
+	ms.Lock()
+	ids := make([]ID, 0, len(ms.trace))
+	for id := range ms.trace {
+		ids = append(ids, id)
+	}
+	ms.Unlock()
+
+	var n int
+	for _, id := range ids {
+		ms.Lock()
+		match, err := ms.matchNoLock(id, filters)
+		if err == nil && match {
+			if err = ms.deleteNoLock(id); err == nil {
+				n++
+			}
+		}
+		ms.Unlock()
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// matchNoLock reports whether the given trace matches all of the filters.
+// A trace that does not exist (e.g. because it was deleted since its ID
+// was listed) does not match. It does not grab the lock.
+func (ms *MemoryStore) matchNoLock(id ID, filters []QueryFilter) (bool, error) {
+	t, present := ms.trace[id]
+	if !present {
+		return false, nil
+	}
+	if c, cold := ms.cold[id]; cold && len(filters) > 0 {
+		t = t.copy()
+		if err := c.restore(t); err != nil {
+			return false, err
+		}
+	}
+	for _, f := range filters {
+		if !f(t) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // deleteNoLock is the same as Delete, but it doesn't grab the lock.
 func (ms *MemoryStore) deleteNoLock(traces ...ID) error {
 	for _, id := range traces {
+		if _, present := ms.trace[id]; present {
+			ms.Stats.addTraces(-1)
+			ms.Stats.evicted(1)
+		}
+		if t, present := ms.trace[id]; present {
+			cids := t.CorrelationIDs()
+			if c, cold := ms.cold[id]; cold {
+				cids = c.cids
+				ms.Stats.compressed(-1, -c.saved)
+			}
+			for _, cid := range cids {
+				delete(ms.correlated[cid], id)
+				if len(ms.correlated[cid]) == 0 {
+					delete(ms.correlated, cid)
+				}
+			}
+			for key, values := range ms.indexed {
+				for value, ids := range values {
+					delete(ids, id)
+					if len(ids) == 0 {
+						delete(values, value)
+					}
+				}
+				if len(values) == 0 {
+					delete(ms.indexed, key)
+				}
+			}
+		}
+		ms.bytes -= ms.traceBytes[id]
 		delete(ms.trace, id)
 		delete(ms.span, id)
+		delete(ms.traceAnns, id)
+		delete(ms.traceDups, id)
+		delete(ms.traceBytes, id)
+		delete(ms.traceSeq, id)
+		delete(ms.accessed, id)
+		delete(ms.cold, id)
 	}
 	return nil
 }
@@ -297,6 +847,12 @@ func (ms *MemoryStore) deleteNoLock(traces ...ID) error {
 func (ms *MemoryStore) deleteSubNoLock(s SpanID, annotationsOnly bool) bool {
 	if sub, ok := ms.span[s.Trace]; ok {
 		if tr, ok := sub[s.Span]; ok {
+			if err := ms.thawNoLock(s.Trace); err != nil {
+				return false
+			}
+			tr = ms.span[s.Trace][s.Span] // the tree may have been replaced
+			ms.touchNoLock(s.Trace)
+			ms.addBytesNoLock(s.Trace, -annotationsSize(tr.Annotations))
 			tr.Annotations = nil
 
 			if !annotationsOnly {
@@ -329,6 +885,21 @@ func (ms *MemoryStore) Write(w io.Writer) error {
 	defer ms.Unlock()
 
 	data := memoryStoreData{ms.trace, ms.span}
+	if len(ms.cold) > 0 {
+		// Write decompressed copies of compressed traces.
+		data = memoryStoreData{make(map[ID]*Trace, len(ms.trace)), make(map[ID]map[ID]*Trace, len(ms.span))}
+		for id, t := range ms.trace {
+			spans := ms.span[id]
+			if c, cold := ms.cold[id]; cold {
+				t, spans = t.copy(), map[ID]*Trace{}
+				if err := c.restore(t); err != nil {
+					return err
+				}
+				spanIndex(t, spans)
+			}
+			data.Trace[id], data.Span[id] = t, spans
+		}
+	}
 	return gob.NewEncoder(w).Encode(data)
 }
 
@@ -342,8 +913,25 @@ func (ms *MemoryStore) ReadFrom(r io.Reader) (int64, error) {
 	if err := gob.NewDecoder(r).Decode(&data); err != nil {
 		return 0, err
 	}
+	ms.Stats.addTraces(int64(len(data.Trace) - len(ms.trace)))
 	ms.trace = data.Trace
 	ms.span = data.Span
+	ms.traceAnns, ms.correlated, ms.indexed = map[ID]Annotations{}, map[string]map[ID]struct{}{}, map[string]map[string]map[ID]struct{}{}
+	ms.traceDups = nil
+	ms.bytes, ms.traceBytes, ms.traceOrder, ms.traceSeq = 0, map[ID]int64{}, nil, nil
+	for _, c := range ms.cold {
+		ms.Stats.compressed(-1, -c.saved)
+	}
+	ms.accessed, ms.cold = nil, nil
+	for id, t := range ms.trace {
+		t.Walk(func(span *Span, depth int) error {
+			ms.indexTraceAnnotationsNoLock(id, span.Annotations)
+			ms.addBytesNoLock(id, annotationsSize(span.Annotations))
+			return nil
+		})
+		ms.touchNoLock(id)
+	}
+	ms.evictNoLock()
 	return int64(len(ms.trace)), nil
 }
 
@@ -383,6 +971,44 @@ type DeleteStore interface {
 
 	// Delete deletes traces given their trace IDs.
 	Delete(...ID) error
+
+	// DeleteWhere deletes the traces that match all of the given filters
+	// (or every trace, if there are none), e.g. to enforce a retention
+	// policy, and returns the number of traces deleted.
+	DeleteWhere(filters ...QueryFilter) (int, error)
+}
+
+// A QueryFilter reports whether a trace matches some criteria. Filters are
+// called with the store's lock held, and so must not call the store.
+type QueryFilter func(t *Trace) bool
+
+// TimeRangeFilter returns a QueryFilter matching the traces that started
+// (per their earliest timespan event) at or after start and before end. A
+// zero start or end leaves the range unbounded on that side, so e.g.
+// TimeRangeFilter(time.Time{}, time.Now().Add(-7*24*time.Hour)) matches
+// the traces older than a week. Traces without timespan events never
+// match.
+func TimeRangeFilter(start, end time.Time) QueryFilter {
+	return func(t *Trace) bool {
+		s, _, ok := t.times()
+		return ok && !s.Before(start) && (end.IsZero() || s.Before(end))
+	}
+}
+
+// AnnotationFilter returns a QueryFilter matching the traces that have a
+// span with an annotation of the given key and value.
+func AnnotationFilter(key, value string) QueryFilter {
+	return func(t *Trace) bool {
+		errFound := errors.New("found")
+		return t.Walk(func(span *Span, depth int) error {
+			for _, a := range span.Annotations {
+				if a.Key == key && string(a.Value) == value {
+					return errFound
+				}
+			}
+			return nil
+		}) == errFound
+	}
 }
 
 // A RecentStore wraps another store and deletes old traces after a
@@ -22,6 +22,34 @@ func TestMemoryStore_Collect_notFound(t *testing.T) {
 	}
 }
 
+func TestMemoryStore_Trace_notFoundVsEmpty(t *testing.T) {
+	ms := storeT{t, NewMemoryStore()}
+
+	// A trace of which only a child span without annotations was collected
+	// is present, with the child as its temporary root.
+	ms.MustCollect(SpanID{1, 2, 1})
+	want := &Trace{Span: Span{ID: SpanID{1, 2, 1}}}
+	if x := ms.MustTrace(1); !reflect.DeepEqual(x, want) {
+		t.Errorf("Trace(1): got trace %+v, want %+v", x, want)
+	}
+
+	for _, s := range []Store{ms.Store, MultiStore(NewMemoryStore(), ms.Store)} {
+		if x, err := s.Trace(2); err != ErrTraceNotFound {
+			t.Errorf("%T: Trace(2): got trace %+v and err %v, want ErrTraceNotFound", s, x, err)
+		}
+		if _, err := s.Trace(1); err != nil {
+			t.Errorf("%T: Trace(1): got err %v, want the present trace", s, err)
+		}
+	}
+
+	if err := ms.Store.(DeleteStore).Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if x, err := ms.Trace(1); err != ErrTraceNotFound {
+		t.Errorf("after Delete: Trace(1): got trace %+v and err %v, want ErrTraceNotFound", x, err)
+	}
+}
+
 func TestMemoryStore_Collect_one(t *testing.T) {
 	ms := storeT{t, NewMemoryStore()}
 
@@ -111,6 +139,39 @@ func TestMemoryStore_Collect_oneChild(t *testing.T) {
 	}
 }
 
+func TestMemoryStore_Collect_parentCycle(t *testing.T) {
+	tests := [][]SpanID{
+		{{1, 1, 0}, {1, 2, 3}, {1, 3, 2}},            // spans 2 and 3 are each other's parent
+		{{1, 1, 0}, {1, 2, 4}, {1, 3, 2}, {1, 4, 3}}, // cycle of three
+		{{1, 1, 0}, {1, 5, 5}},                       // span 5 is its own parent
+	}
+	for _, spans := range tests {
+		ms := storeT{t, NewMemoryStore()}
+		for _, id := range spans {
+			ms.MustCollect(id)
+		}
+
+		// Every span is reachable, and the span closing the cycle is marked.
+		var visited []ID
+		var marked int
+		if err := ms.MustTrace(1).Walk(func(span *Span, depth int) error {
+			visited = append(visited, span.ID.Span)
+			if span.Annotations.has(CycleDetectedKey) {
+				marked++
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if len(visited) != len(spans) {
+			t.Errorf("%v: visited spans %v, want all %d", spans, visited, len(spans))
+		}
+		if marked != 1 {
+			t.Errorf("%v: got %d spans marked with %s, want 1", spans, marked, CycleDetectedKey)
+		}
+	}
+}
+
 func TestMemoryStore_deleteSubNoLock(t *testing.T) {
 	s := NewMemoryStore()
 	ms := storeT{t, s}
@@ -435,6 +496,362 @@ func compareTraces(a, b *Trace) (diff []string) {
 	return diff
 }
 
+func TestMemoryStore_Traces_sortByRecency(t *testing.T) {
+	ms := storeT{t, NewMemoryStore()}
+	base := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	collectSpan := func(id SpanID, end time.Duration) {
+		as, err := MarshalEvent(Timespan{S: base, E: base.Add(end)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ms.MustCollect(id, as...)
+	}
+
+	collectSpan(SpanID{1, 10, 0}, 1*time.Second)
+	collectSpan(SpanID{2, 20, 0}, 2*time.Second)
+	collectSpan(SpanID{3, 30, 0}, 1*time.Second)
+	collectSpan(SpanID{3, 31, 30}, 3*time.Second) // a child makes trace 3 the most recent
+	collectSpan(SpanID{4, 40, 0}, 1*time.Second)  // ties with trace 1
+	ms.MustCollect(SpanID{5, 50, 0})              // no times at all
+
+	want := []ID{3, 2, 1, 4, 5}
+	for i := 0; i < 5; i++ {
+		traces, err := ms.Store.(Queryer).Traces(TracesOpts{SortByRecency: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []ID
+		for _, tr := range traces {
+			got = append(got, tr.ID.Trace)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("call %d: got traces %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestMemoryStore_Traces_timespan(t *testing.T) {
+	ms := storeT{t, NewMemoryStore()}
+	base := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 5; i++ {
+		start := base.Add(time.Duration(i) * time.Minute)
+		as, err := MarshalEvent(Timespan{S: start, E: start.Add(time.Second)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ms.MustCollect(SpanID{ID(i), 1, 0}, as...)
+	}
+	ms.MustCollect(SpanID{6, 1, 0}) // no times at all
+
+	traces, err := ms.Store.(Queryer).Traces(TracesOpts{
+		Timespan: Timespan{S: base.Add(2 * time.Minute), E: base.Add(4 * time.Minute)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(tracesByID(traces))
+	var got []ID
+	for _, tr := range traces {
+		got = append(got, tr.ID.Trace)
+	}
+	if want := []ID{2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got traces %v, want %v", got, want)
+	}
+}
+
+func TestMemoryStore_Traces_snapshot(t *testing.T) {
+	ms := NewMemoryStore()
+	st := storeT{t, ms}
+	st.MustCollect(SpanID{1, 10, 0}, Annotation{Key: "k", Value: []byte("v")})
+	st.MustCollect(SpanID{1, 11, 10})
+
+	traces, err := ms.Traces(TracesOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := traces[0].TreeString()
+
+	// Later collections do not modify the returned traces.
+	st.MustCollect(SpanID{1, 10, 0}, Annotation{Key: "k2", Value: []byte("v2")})
+	st.MustCollect(SpanID{1, 12, 10})
+	if got := traces[0].TreeString(); got != snapshot {
+		t.Errorf("returned trace changed after collecting:\n%s\nwant:\n%s", got, snapshot)
+	}
+	if live := st.MustTrace(1); len(live.Sub) != 2 || len(live.Annotations) != 2 {
+		t.Errorf("got live trace\n%s\nwant new span and annotation", live.TreeString())
+	}
+}
+
+func TestMemoryStore_TraceAnnotations(t *testing.T) {
+	ms := NewMemoryStore()
+	s := storeT{t, ms}
+
+	if _, err := ms.TraceAnnotations(1); err != ErrTraceNotFound {
+		t.Errorf("got error %v, want %v", err, ErrTraceNotFound)
+	}
+
+	// A child collected before the root, both setting the same key. The
+	// first value searching from the root wins, whether the annotations
+	// are looked up in the store or read from the tree.
+	s.MustCollect(SpanID{1, 2, 1}, TraceAnnotation("tenant", []byte("a")), Annotation{Key: "k", Value: []byte("v")})
+	s.MustCollect(SpanID{1, 1, 0}, TraceAnnotation("tenant", []byte("b")), TraceAnnotation("outcome", []byte("ok")))
+	s.MustCollect(SpanID{2, 3, 0}, TraceAnnotation("tenant", []byte("c")), TraceAnnotation("tenant", []byte("c")))
+
+	want := Annotations{{Key: "tenant", Value: []byte("b")}, {Key: "outcome", Value: []byte("ok")}}
+	got, err := ms.TraceAnnotations(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got trace annotations %v, want %v", got, want)
+	}
+	if got := s.MustTrace(1).TraceAnnotations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got tree trace annotations %v, want %v", got, want)
+	}
+	if _, dup := ms.traceDups[2]; dup {
+		t.Error("trace whose key was set twice to the same value was recorded as having differing values")
+	}
+
+	if err := ms.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ms.TraceAnnotations(1); err != ErrTraceNotFound {
+		t.Errorf("after Delete: got error %v, want %v", err, ErrTraceNotFound)
+	}
+	if got, _ := ms.TraceAnnotations(2); len(got) != 1 || string(got[0].Value) != "c" {
+		t.Errorf("got trace 2 annotations %v, want tenant=c", got)
+	}
+}
+
+func TestMemoryStore_Traces_correlationID(t *testing.T) {
+	ms := NewMemoryStore()
+	s := storeT{t, ms}
+
+	s.MustCollect(SpanID{1, 1, 0}, CorrelationID("req-a"))
+	s.MustCollect(SpanID{1, 2, 1}, CorrelationID("req-b"))
+	s.MustCollect(SpanID{2, 3, 0}, CorrelationID("req-a"))
+	s.MustCollect(SpanID{3, 4, 0})
+
+	traceIDs := func(cid string) []ID {
+		traces, err := ms.Traces(TracesOpts{CorrelationID: cid})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(tracesByID(traces))
+		var ids []ID
+		for _, t := range traces {
+			ids = append(ids, t.ID.Trace)
+		}
+		return ids
+	}
+	if got, want := traceIDs("req-a"), []ID{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("req-a: got traces %v, want %v", got, want)
+	}
+	if got, want := traceIDs("req-b"), []ID{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("req-b: got traces %v, want %v", got, want)
+	}
+	if got := traceIDs("req-c"); len(got) != 0 {
+		t.Errorf("req-c: got traces %v, want none", got)
+	}
+	if got, want := s.MustTrace(1).CorrelationIDs(), []string{"req-a", "req-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got correlation IDs %q, want %q", got, want)
+	}
+
+	if err := ms.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := traceIDs("req-a"), []ID{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Delete: req-a: got traces %v, want %v", got, want)
+	}
+	if _, present := ms.correlated["req-b"]; present {
+		t.Error("after Delete: req-b is still indexed")
+	}
+}
+
+func TestMemoryStore_Traces_service(t *testing.T) {
+	ms := NewMemoryStore()
+	s := storeT{t, ms}
+
+	// Trace 1 spans two services; the spans of trace 2 are tagged by a
+	// ServiceCollector.
+	s.MustCollect(SpanID{1, 1, 0}, Service("frontend"))
+	s.MustCollect(SpanID{1, 2, 1}, Service("api"))
+	sc := NewServiceCollector(ms, "api")
+	if err := sc.Collect(SpanID{2, 3, 0}, CorrelationID("req-a")); err != nil {
+		t.Fatal(err)
+	}
+	s.MustCollect(SpanID{3, 4, 0}, CorrelationID("req-a"))
+
+	traceIDs := func(opts TracesOpts) []ID {
+		traces, err := ms.Traces(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(tracesByID(traces))
+		var ids []ID
+		for _, t := range traces {
+			ids = append(ids, t.ID.Trace)
+		}
+		return ids
+	}
+	tests := []struct {
+		opts TracesOpts
+		want []ID
+	}{
+		{TracesOpts{Service: "frontend"}, []ID{1}},
+		{TracesOpts{Service: "api"}, []ID{1, 2}},
+		{TracesOpts{Service: "db"}, nil},
+		{TracesOpts{Service: "api", CorrelationID: "req-a"}, []ID{2}},
+	}
+	for _, test := range tests {
+		if got := traceIDs(test.opts); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%+v: got traces %v, want %v", test.opts, got, test.want)
+		}
+	}
+	if got, want := s.MustTrace(1).AnnotationValues(ServiceKey), []string{"api", "frontend"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got services %q, want %q", got, want)
+	}
+	if got, want := ms.IndexedValues(ServiceKey), []string{"api", "frontend"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got store services %q, want %q", got, want)
+	}
+
+	if err := ms.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ms.IndexedValues(ServiceKey), []string{"api"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Delete: got store services %q, want %q", got, want)
+	}
+}
+
+func TestMemoryStore_Traces_environment(t *testing.T) {
+	ms := NewMemoryStore()
+	s := storeT{t, ms}
+
+	// Traces 1 and 2 come from staging and prod through the same store;
+	// trace 3 mixes both, as when a staging service calls a production
+	// one; trace 4 has no environment.
+	NewEnvironmentCollector(ms, "staging").Collect(SpanID{1, 1, 0}, Service("frontend"))
+	NewEnvironmentCollector(ms, "staging").Collect(SpanID{1, 2, 1})
+	NewEnvironmentCollector(ms, "prod").Collect(SpanID{2, 3, 0}, Service("frontend"))
+	NewEnvironmentCollector(ms, "staging").Collect(SpanID{3, 4, 0})
+	NewEnvironmentCollector(ms, "prod").Collect(SpanID{3, 5, 4})
+	s.MustCollect(SpanID{4, 6, 0})
+
+	// A span that already names an environment keeps it.
+	NewEnvironmentCollector(ms, "prod").Collect(SpanID{1, 7, 1}, Environment("staging"))
+
+	traceIDs := func(opts TracesOpts) []ID {
+		traces, err := ms.Traces(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(tracesByID(traces))
+		var ids []ID
+		for _, t := range traces {
+			ids = append(ids, t.ID.Trace)
+		}
+		return ids
+	}
+	tests := []struct {
+		opts TracesOpts
+		want []ID
+	}{
+		{TracesOpts{}, []ID{1, 2, 3, 4}},
+		{TracesOpts{Environment: "staging"}, []ID{1, 3}},
+		{TracesOpts{Environment: "prod"}, []ID{2, 3}},
+		{TracesOpts{Environment: "dev"}, nil},
+		{TracesOpts{Environment: "prod", Service: "frontend"}, []ID{2}},
+		{TracesOpts{Environment: "staging", TraceIDs: []ID{3, 4}}, []ID{3}},
+	}
+	for _, test := range tests {
+		if got := traceIDs(test.opts); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%+v: got traces %v, want %v", test.opts, got, test.want)
+		}
+	}
+	if got, want := s.MustTrace(1).AnnotationValues(EnvironmentKey), []string{"staging"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("trace 1: got environments %q, want %q", got, want)
+	}
+	if got, want := s.MustTrace(3).AnnotationValues(EnvironmentKey), []string{"prod", "staging"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("trace 3: got environments %q, want %q", got, want)
+	}
+	if got, want := ms.IndexedValues(EnvironmentKey), []string{"prod", "staging"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got store environments %q, want %q", got, want)
+	}
+
+	// Queryers without an index filter the traces they fetch, with the
+	// same results.
+	all, err := ms.Traces(TracesOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered := filterByValue(all, EnvironmentKey, "prod")
+	sort.Sort(tracesByID(filtered))
+	if len(filtered) != 2 || filtered[0].ID.Trace != 2 || filtered[1].ID.Trace != 3 {
+		t.Errorf("filterByValue: got %v, want traces 2 and 3", filtered)
+	}
+
+	if err := ms.Delete(2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ms.IndexedValues(EnvironmentKey), []string{"staging"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Delete: got store environments %q, want %q", got, want)
+	}
+}
+
+func TestMemoryStore_DeleteWhere(t *testing.T) {
+	ms := NewMemoryStore()
+	s := storeT{t, ms}
+	day := func(d int) time.Time { return time.Date(2016, 1, d, 0, 0, 0, 0, time.UTC) }
+	collect := func(span SpanID, start time.Time, route string) {
+		as, err := MarshalEvent(Timespan{S: start, E: start.Add(time.Second)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.MustCollect(span, append(as, Annotation{Key: "Server.Route", Value: []byte(route)})...)
+	}
+	collect(SpanID{1, 1, 0}, day(1), "/old")
+	collect(SpanID{1, 2, 1}, day(1), "/users")
+	collect(SpanID{2, 3, 0}, day(2), "/users")
+	collect(SpanID{3, 4, 0}, day(8), "/old")
+	collect(SpanID{4, 5, 0}, day(9), "/users")
+	s.MustCollect(SpanID{5, 6, 0}) // no times
+
+	remaining := func() []ID {
+		traces, err := ms.Traces(TracesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(tracesByID(traces))
+		var ids []ID
+		for _, t := range traces {
+			ids = append(ids, t.ID.Trace)
+		}
+		return ids
+	}
+	tests := []struct {
+		filters []QueryFilter
+		deleted int
+		want    []ID
+	}{
+		{[]QueryFilter{TimeRangeFilter(time.Time{}, day(3))}, 2, []ID{3, 4, 5}},
+		{[]QueryFilter{TimeRangeFilter(day(8), day(9)), AnnotationFilter("Server.Route", "/users")}, 0, []ID{3, 4, 5}},
+		{[]QueryFilter{AnnotationFilter("Server.Route", "/old")}, 1, []ID{4, 5}},
+		{nil, 2, nil},
+	}
+	for _, test := range tests {
+		n, err := ms.DeleteWhere(test.filters...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != test.deleted {
+			t.Errorf("got %d traces deleted, want %d", n, test.deleted)
+		}
+		if got := remaining(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("got remaining traces %v, want %v", got, test.want)
+		}
+	}
+}
+
 type storeT struct {
 	t *testing.T
 	Store
@@ -484,6 +901,54 @@ func BenchmarkMemoryStore1000(b *testing.B) {
 	benchmarkMemoryStoreN(b, 1000)
 }
 
+// BenchmarkMemoryStore_CollectDuringTraces measures Collect while another
+// goroutine continuously queries all traces, as the web UI does.
+func BenchmarkMemoryStore_CollectDuringTraces(b *testing.B) {
+	ms := NewMemoryStore()
+	for trace := ID(1); trace <= 1000; trace++ {
+		rec := NewRecorder(SpanID{Trace: trace, Span: 1}, ms)
+		rec.Event(Timespan{S: time.Unix(int64(trace), 0), E: time.Unix(int64(trace)+1, 0)})
+		rec.Finish()
+		for span := ID(2); span <= 10; span++ {
+			if err := ms.Collect(SpanID{Trace: trace, Span: span, Parent: 1}, Annotation{Key: "k", Value: []byte("v")}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, err := ms.Traces(TracesOpts{SortByRecency: true}); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Keep the store at a constant size by replacing the oldest trace.
+		trace := ID(1001 + i)
+		if err := ms.Delete(trace - 1000); err != nil {
+			b.Fatal(err)
+		}
+		if err := ms.Collect(SpanID{Trace: trace, Span: 1}, Annotation{Key: "k", Value: []byte("v")}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	close(done)
+	<-stopped
+}
+
 func BenchmarkMemoryStoreWrite1000(b *testing.B) {
 	ms := NewMemoryStore()
 	var x ID
@@ -577,3 +1042,112 @@ func BenchmarkLimitStore500(b *testing.B) {
 		}
 	}
 }
+
+func TestMemoryStoreBytes(t *testing.T) {
+	const budget = 10000
+	ms := NewMemoryStoreBytes(budget)
+	s := storeT{t, ms}
+	big := func(n int) Annotation {
+		return Annotation{Key: "k", Value: make([]byte, n-1)}
+	}
+
+	// Traces of widely varying size, each in several spans.
+	sizes := []int{100, 3000, 50, 4000, 1000, 2500, 10, 3500}
+	for i, size := range sizes {
+		trace := ID(i + 1)
+		s.MustCollect(SpanID{trace, 1, 0}, big(size/2))
+		s.MustCollect(SpanID{trace, 2, 1}, big(size-size/2))
+		if b := ms.Bytes(); b > budget {
+			t.Fatalf("after trace %d: store holds %d bytes, over the budget of %d", trace, b, budget)
+		}
+	}
+
+	// The newest traces that fit are kept whole; older ones are evicted.
+	var (
+		want int64
+		full bool
+	)
+	for i := len(sizes) - 1; i >= 0; i-- {
+		trace := ID(i + 1)
+		if full = full || want+int64(sizes[i]) > budget; full {
+			if _, err := ms.Trace(trace); err != ErrTraceNotFound {
+				t.Errorf("trace %d: got error %v, want it evicted", trace, err)
+			}
+			continue
+		}
+		want += int64(sizes[i])
+		if got := s.MustTrace(trace); len(got.Sub) != 1 {
+			t.Errorf("trace %d: got %d children, want the whole trace kept", trace, len(got.Sub))
+		}
+	}
+	if got := ms.Bytes(); got != want {
+		t.Errorf("got %d bytes used, want %d", got, want)
+	}
+
+	if err := ms.Delete(8); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ms.Bytes(), want-int64(sizes[7]); got != want {
+		t.Errorf("after Delete: got %d bytes used, want %d", got, want)
+	}
+
+	// A trace larger than the budget is not kept at all.
+	s.MustCollect(SpanID{100, 1, 0}, big(budget+1))
+	if _, err := ms.Trace(100); err != ErrTraceNotFound {
+		t.Errorf("got error %v for oversized trace, want it evicted", err)
+	}
+	if b := ms.Bytes(); b > budget {
+		t.Errorf("store holds %d bytes, over the budget of %d", b, budget)
+	}
+}
+
+func TestMemoryStoreBytes_recollectDeleted(t *testing.T) {
+	ms := NewMemoryStoreBytes(3000)
+	s := storeT{t, ms}
+	big := Annotation{Key: "k", Value: make([]byte, 999)}
+
+	// Trace 1 is deleted and collected again, after trace 2, so it is now
+	// newer than trace 2.
+	s.MustCollect(SpanID{1, 1, 0}, big)
+	s.MustCollect(SpanID{2, 1, 0}, big)
+	if err := ms.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	s.MustCollect(SpanID{1, 1, 0}, big)
+	s.MustCollect(SpanID{3, 1, 0}, big)
+
+	// Going over the budget evicts trace 2, the oldest, not trace 1.
+	s.MustCollect(SpanID{4, 1, 0}, big)
+	if _, err := ms.Trace(2); err != ErrTraceNotFound {
+		t.Errorf("got error %v for the oldest trace, want it evicted", err)
+	}
+	for _, trace := range []ID{1, 3, 4} {
+		if _, err := ms.Trace(trace); err != nil {
+			t.Errorf("trace %d: %s", trace, err)
+		}
+	}
+}
+
+func TestMemoryStore_TracesByID(t *testing.T) {
+	ms := NewMemoryStore()
+	s := storeT{t, ms}
+	s.MustCollect(SpanID{1, 1, 0}, Annotation{Key: "Name", Value: []byte("a")})
+	s.MustCollect(SpanID{1, 2, 1})
+	s.MustCollect(SpanID{2, 3, 0})
+	s.MustCollect(SpanID{3, 4, 0})
+
+	traces, err := ms.TracesByID(1, 3, 4, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(traces) != 2 || traces[1] == nil || traces[3] == nil {
+		t.Fatalf("got traces %v, want traces 1 and 3", traces)
+	}
+	if want := s.MustTrace(1); !reflect.DeepEqual(traces[1], want) {
+		t.Errorf("got trace %v, want %v", traces[1], want)
+	}
+
+	if traces, err := ms.TracesByID(); err != nil || len(traces) != 0 {
+		t.Errorf("got traces %v and error %v for no IDs, want none", traces, err)
+	}
+}
@@ -0,0 +1,64 @@
+package appdash
+
+import "errors"
+
+// ErrSpanNotFound is returned by SubTrace when a trace exists but has no
+// span with the given ID.
+var ErrSpanNotFound = errors.New("span not found")
+
+// A SubTraceStore is a Store that can fetch a subtree of a trace, rooted at
+// one of its spans, without materializing the rest of the trace. For very
+// large traces this is much cheaper than Trace followed by Trace.FindSpan,
+// e.g. for backends that can fetch only the spans of the subtree.
+type SubTraceStore interface {
+	Store
+
+	// SubTrace returns the subtree of the given trace rooted at the span
+	// with the given ID: the span and all of its descendants. It returns
+	// ErrTraceNotFound if there is no such trace, and ErrSpanNotFound if
+	// the trace has no such span.
+	SubTrace(trace, span ID) (*Trace, error)
+}
+
+// FindSubTrace returns the subtree of the given trace rooted at the span
+// with the given ID, as SubTraceStore.SubTrace does. It uses s's SubTrace
+// method if s is a SubTraceStore, and otherwise fetches the whole trace and
+// prunes it.
+func FindSubTrace(s Store, trace, span ID) (*Trace, error) {
+	if ss, ok := s.(SubTraceStore); ok {
+		return ss.SubTrace(trace, span)
+	}
+	t, err := s.Trace(trace)
+	if err != nil {
+		return nil, err
+	}
+	if sub := t.FindSpan(span); sub != nil {
+		return sub, nil
+	}
+	return nil, ErrSpanNotFound
+}
+
+// SubTrace implements the SubTraceStore interface by looking the span up in
+// the index of the trace's spans.
+func (ms *MemoryStore) SubTrace(trace, span ID) (*Trace, error) {
+	ms.Lock()
+	defer ms.Unlock()
+
+	if err := ms.thawNoLock(trace); err != nil {
+		return nil, err
+	}
+	if _, err := ms.traceNoLock(trace); err != nil {
+		return nil, err
+	}
+	sub, present := ms.span[trace][span]
+	if !present {
+		return nil, ErrSpanNotFound
+	}
+	ms.touchNoLock(trace)
+	return sub, nil
+}
+
+// SubTrace implements the SubTraceStore interface.
+func (ss *ShardedMemoryStore) SubTrace(trace, span ID) (*Trace, error) {
+	return ss.shard(trace).SubTrace(trace, span)
+}
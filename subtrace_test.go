@@ -0,0 +1,62 @@
+package appdash
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// traceOnlyStore hides the SubTrace method of its Store.
+type traceOnlyStore struct{ Store }
+
+func TestSubTrace(t *testing.T) {
+	// 1
+	// ├── 2
+	// │   ├── 4
+	// │   │   └── 6
+	// │   └── 5
+	// └── 3
+	spans := []SpanID{{1, 1, 0}, {1, 2, 1}, {1, 3, 1}, {1, 4, 2}, {1, 5, 2}, {1, 6, 4}, {2, 1, 0}}
+	stores := map[string]Store{
+		"MemoryStore":        NewMemoryStore(),
+		"ShardedMemoryStore": NewShardedMemoryStore(2),
+		"fallback":           traceOnlyStore{NewMemoryStore()},
+	}
+	for name, s := range stores {
+		for _, id := range spans {
+			if err := s.Collect(id, Annotation{Key: "k", Value: []byte("v")}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tests := map[ID][]ID{
+			1: {1, 2, 3, 4, 5, 6},
+			2: {2, 4, 5, 6},
+			4: {4, 6},
+			3: {3},
+		}
+		for span, want := range tests {
+			sub, err := FindSubTrace(s, 1, span)
+			if err != nil {
+				t.Errorf("%s: span %v: %s", name, span, err)
+				continue
+			}
+			var got []ID
+			sub.Walk(func(s *Span, depth int) error {
+				got = append(got, s.ID.Span)
+				return nil
+			})
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: got subtree of span %v with spans %v, want %v", name, span, got, want)
+			}
+		}
+
+		if _, err := FindSubTrace(s, 1, 7); err != ErrSpanNotFound {
+			t.Errorf("%s: got error %v for a missing span, want ErrSpanNotFound", name, err)
+		}
+		if _, err := FindSubTrace(s, 3, 1); err != ErrTraceNotFound {
+			t.Errorf("%s: got error %v for a missing trace, want ErrTraceNotFound", name, err)
+		}
+	}
+}
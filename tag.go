@@ -0,0 +1,31 @@
+package appdash
+
+import "strings"
+
+// TagPrefix is the reserved key prefix of tags: ad-hoc key-value metadata
+// recorded on a span (see Recorder.Tag) without defining an Event type.
+// RegisterEvent rejects events with fields keyed under it, so a tag cannot
+// collide with the annotations of an event, whatever its key.
+const TagPrefix = "Tag."
+
+// Tag returns an annotation recording a tag with the given key and value.
+func Tag(key, value string) Annotation {
+	return Annotation{Key: TagPrefix + key, Value: []byte(value)}
+}
+
+// Tags returns the tags recorded on the span, keyed without TagPrefix, or
+// nil if it has none. If a tag was recorded more than once, its last value
+// is returned.
+func (s *Span) Tags() map[string]string {
+	var tags map[string]string
+	for _, a := range s.Annotations {
+		if !strings.HasPrefix(a.Key, TagPrefix) {
+			continue
+		}
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tags[a.Key[len(TagPrefix):]] = string(a.Value)
+	}
+	return tags
+}
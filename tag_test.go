@@ -0,0 +1,71 @@
+package appdash
+
+import (
+	"reflect"
+	"testing"
+)
+
+// taggedEvent has fields named like the tags recorded alongside it.
+type taggedEvent struct {
+	Tag  string
+	Tags map[string]string
+}
+
+func (taggedEvent) Schema() string { return "tagged" }
+
+func init() { RegisterEvent(taggedEvent{}) }
+
+func TestRecorder_Tag(t *testing.T) {
+	ms := NewMemoryStore()
+	r := NewRecorder(SpanID{1, 2, 0}, NewLocalCollector(ms))
+	r.Name("op")
+	e := taggedEvent{Tag: "event", Tags: map[string]string{"k": "event"}}
+	r.Event(e)
+	r.Tag("Name", "tag name")
+	r.Tag("Tag", "tag")
+	r.Tag("k", "first")
+	r.Tag("k", "last")
+	r.Finish()
+
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Name": "tag name", "Tag": "tag", "k": "last"}
+	if got := trace.Span.Tags(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got tags %v, want %v", got, want)
+	}
+
+	// Tags leave the span's name and events alone.
+	if got := trace.Span.Name(); got != "op" {
+		t.Errorf("got span name %q, want %q", got, "op")
+	}
+	var got taggedEvent
+	if err := UnmarshalEvent(trace.Span.Annotations, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, e) {
+		t.Errorf("got event %+v, want %+v", got, e)
+	}
+
+	if tags := (&Span{}).Tags(); tags != nil {
+		t.Errorf("got tags %v for an untagged span, want nil", tags)
+	}
+}
+
+// tagMapEvent has a field whose keys would collide with tags.
+type tagMapEvent struct {
+	Tag map[string]string
+}
+
+func (tagMapEvent) Schema() string { return "tagMap" }
+
+func TestRegisterEvent_tagPrefix(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterEvent accepted an event with fields keyed under TagPrefix")
+		}
+		delete(registeredEvents, tagMapEvent{}.Schema())
+	}()
+	RegisterEvent(tagMapEvent{})
+}
@@ -0,0 +1,113 @@
+package appdash
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// A TeeCollector sends every collection to each of several underlying
+// collectors (sinks), such as a live MemoryStore and a RecordingCollector
+// writing to a file.
+//
+// Unlike a MultiStore, which collects into its stores one after another in
+// the caller's goroutine, a TeeCollector queues collections for each sink
+// separately and delivers them from a goroutine per sink. Each sink receives
+// collections in the order in which they were passed to Collect, so the
+// annotations of a span are never reordered, while a slow sink delays
+// neither the caller nor the other sinks. When a sink's queue is full, new
+// collections for that sink are dropped and counted (see Dropped).
+type TeeCollector struct {
+	// Log, if non-nil, is used to log errors returned by the sinks.
+	Log *log.Logger
+
+	// Stats, if non-nil, is updated with the number of spans dropped and
+	// the number of errors returned by the sinks.
+	Stats *Stats
+
+	sinks []*teeSink
+	wg    sync.WaitGroup
+
+	mu      sync.RWMutex // protects stopped and closing the sink queues
+	stopped bool
+}
+
+// teeSink is a sink of a TeeCollector, along with its queue.
+type teeSink struct {
+	Collector
+	queue   chan spanCollection
+	dropped int64 // accessed atomically
+}
+
+// NewTeeCollector returns a TeeCollector that sends collections to each of
+// the given collectors, queueing up to queueSize collections per collector.
+func NewTeeCollector(queueSize int, cs ...Collector) *TeeCollector {
+	tc := &TeeCollector{}
+	for _, c := range cs {
+		s := &teeSink{Collector: c, queue: make(chan spanCollection, queueSize)}
+		tc.sinks = append(tc.sinks, s)
+		tc.wg.Add(1)
+		go tc.deliver(s)
+	}
+	return tc
+}
+
+// Collect implements the Collector interface by queueing the collection
+// for each sink. It does not wait for the sinks, so errors returned by them
+// are only logged (see Log).
+func (tc *TeeCollector) Collect(span SpanID, anns ...Annotation) error {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	if tc.stopped {
+		return errors.New("TeeCollector is stopped")
+	}
+	for _, s := range tc.sinks {
+		select {
+		case s.queue <- spanCollection{span: span, anns: anns}:
+		default:
+			atomic.AddInt64(&s.dropped, 1)
+			tc.Stats.droppedSpans(1)
+		}
+	}
+	return nil
+}
+
+// deliver sends the collections queued for s to it, in order, until its
+// queue is closed.
+func (tc *TeeCollector) deliver(s *teeSink) {
+	defer tc.wg.Done()
+	for c := range s.queue {
+		if err := s.Collect(c.span, c.anns...); err != nil {
+			tc.Stats.collectError()
+			if tc.Log != nil {
+				tc.Log.Printf("TeeCollector: %s", err)
+			}
+		}
+	}
+}
+
+// Dropped returns the number of collections dropped for each sink because
+// its queue was full, in the order the sinks were given to
+// NewTeeCollector.
+func (tc *TeeCollector) Dropped() []int64 {
+	dropped := make([]int64, len(tc.sinks))
+	for i, s := range tc.sinks {
+		dropped[i] = atomic.LoadInt64(&s.dropped)
+	}
+	return dropped
+}
+
+// Stop stops the collector, waiting until every queued collection has been
+// delivered to its sink. After stopping, calls to Collect will fail.
+func (tc *TeeCollector) Stop() {
+	tc.mu.Lock()
+	if !tc.stopped {
+		tc.stopped = true
+		for _, s := range tc.sinks {
+			close(s.queue)
+		}
+	}
+	tc.mu.Unlock()
+	tc.wg.Wait()
+}
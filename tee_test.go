@@ -0,0 +1,107 @@
+package appdash
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink records the values of the annotations it collects, in
+// order, optionally waiting on gate before each collection.
+type recordingSink struct {
+	gate chan struct{}
+
+	mu     sync.Mutex
+	values []string
+}
+
+func (s *recordingSink) Collect(span SpanID, anns ...Annotation) error {
+	if s.gate != nil {
+		<-s.gate
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range anns {
+		s.values = append(s.values, string(a.Value))
+	}
+	return nil
+}
+
+func (s *recordingSink) got() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.values...)
+}
+
+func TestTeeCollector_order(t *testing.T) {
+	fast := &recordingSink{}
+	slow := &recordingSink{gate: make(chan struct{})}
+	tc := NewTeeCollector(100, fast, slow)
+
+	var want []string
+	for i := 0; i < 20; i++ {
+		v := strconv.Itoa(i)
+		want = append(want, v)
+		if err := tc.Collect(SpanID{Trace: 1, Span: ID(i%2 + 1)}, Annotation{Key: "k", Value: []byte(v)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The fast sink receives everything while the slow one is blocked.
+	for len(fast.got()) < len(want) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := slow.got(); len(got) != 0 {
+		t.Errorf("slow sink got %v before being unblocked", got)
+	}
+
+	close(slow.gate)
+	tc.Stop()
+	if got := fast.got(); !reflect.DeepEqual(got, want) {
+		t.Errorf("fast sink got %v, want %v", got, want)
+	}
+	if got := slow.got(); !reflect.DeepEqual(got, want) {
+		t.Errorf("slow sink got %v, want %v", got, want)
+	}
+	if err := tc.Collect(SpanID{1, 2, 3}); err == nil {
+		t.Error("Collect after Stop succeeded")
+	}
+}
+
+func TestTeeCollector_dropped(t *testing.T) {
+	fast := &recordingSink{}
+	slow := &recordingSink{gate: make(chan struct{})}
+	tc := NewTeeCollector(2, fast, slow)
+	tc.Stats = &Stats{}
+
+	const n = 10
+	for i := 0; i < n; i++ {
+		tc.Collect(SpanID{1, 2, 3}, Annotation{Key: "k", Value: []byte(strconv.Itoa(i))})
+	}
+	close(slow.gate)
+	tc.Stop()
+
+	dropped := tc.Dropped()
+	if dropped[1] < n-3 {
+		t.Errorf("got %d collections dropped for the slow sink, want at least %d", dropped[1], n-3)
+	}
+	for i, s := range []*recordingSink{fast, slow} {
+		got := s.got()
+		if int64(len(got))+dropped[i] != n {
+			t.Errorf("sink %d: got %d delivered + %d dropped, want %d", i, len(got), dropped[i], n)
+		}
+		for j := 1; j < len(got); j++ {
+			a, _ := strconv.Atoi(got[j-1])
+			b, _ := strconv.Atoi(got[j])
+			if a >= b {
+				t.Errorf("sink %d: got %v, want collections in order", i, got)
+				break
+			}
+		}
+	}
+	if want := dropped[0] + dropped[1]; tc.Stats.Snapshot().Dropped != want {
+		t.Errorf("got Stats.Dropped %d, want %d", tc.Stats.Snapshot().Dropped, want)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,6 +16,165 @@ import (
 type Trace struct {
 	Span          // Root span
 	Sub  []*Trace // Children
+
+	index atomic.Value // *traceIndex of the tree, built by Parent and Children
+}
+
+// TraceAnnotationPrefix is the reserved key prefix of trace-level
+// annotations: metadata (such as a tenant or the overall outcome) that
+// describes the whole trace rather than a single span. They are physically
+// stored on whichever span they were collected on, by convention the root
+// span, and are retrieved with Trace.TraceAnnotations (or
+// MemoryStore.TraceAnnotations) with the prefix removed.
+const TraceAnnotationPrefix = "_trace:"
+
+// TraceAnnotation returns a trace-level annotation with the given key and
+// value, which may be collected on any span of a trace.
+func TraceAnnotation(key string, value []byte) Annotation {
+	return Annotation{Key: TraceAnnotationPrefix + key, Value: value}
+}
+
+// CorrelationIDKey is the trace annotation key (see TraceAnnotation) under
+// which external IDs of a trace, such as the request IDs that appear in logs
+// and support tickets, are recorded.
+const CorrelationIDKey = "correlationID"
+
+// CorrelationID returns a trace-level annotation linking the trace to the
+// given external ID, so that it may be found by it (see
+// TracesOpts.CorrelationID). A trace may be linked to several IDs, e.g. one
+// per service it passes through.
+func CorrelationID(id string) Annotation {
+	return TraceAnnotation(CorrelationIDKey, []byte(id))
+}
+
+// CorrelationIDs returns the external IDs that t is linked to (see
+// CorrelationID), without duplicates.
+func (t *Trace) CorrelationIDs() []string {
+	var ids []string
+	t.walkTraceAnnotations(func(a Annotation) {
+		if a.Key != CorrelationIDKey {
+			return
+		}
+		for _, id := range ids {
+			if id == string(a.Value) {
+				return
+			}
+		}
+		ids = append(ids, string(a.Value))
+	})
+	return ids
+}
+
+// filterCorrelated returns the traces that are linked to the given
+// external ID. It modifies traces in place.
+func filterCorrelated(traces []*Trace, id string) []*Trace {
+	filtered := traces[:0]
+	for _, t := range traces {
+		for _, cid := range t.CorrelationIDs() {
+			if cid == id {
+				filtered = append(filtered, t)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// TraceAnnotations returns the trace-level annotations found on t and its
+// descendants, with TraceAnnotationPrefix removed from their keys. Trace
+// annotations are set once: if a key was collected more than once, only
+// its first value (searching from the root, depth-first) is returned.
+func (t *Trace) TraceAnnotations() Annotations {
+	var as Annotations
+	t.walkTraceAnnotations(func(a Annotation) {
+		if !as.has(a.Key) {
+			as = append(as, a)
+		}
+	})
+	return as
+}
+
+func (t *Trace) walkTraceAnnotations(f func(Annotation)) {
+	t.Walk(func(span *Span, depth int) error {
+		for _, a := range span.Annotations {
+			if strings.HasPrefix(a.Key, TraceAnnotationPrefix) {
+				f(Annotation{Key: a.Key[len(TraceAnnotationPrefix):], Value: a.Value})
+			}
+		}
+		return nil
+	})
+}
+
+// CycleDetectedKey is the reserved annotation key that MemoryStore records
+// on a span whose parent ID would make it its own ancestor, e.g. when buggy
+// instrumentation records two spans as each other's parent. Such a span is
+// kept as an orphan (see Trace.Walk) instead of being added to its parent,
+// so that the trace remains a tree.
+const CycleDetectedKey = "_cycleDetected"
+
+// Walk calls fn for each span in t in a stable pre-order traversal: a span
+// is visited before its children, and children are visited in the order in
+// which they appear in Sub. The root span has depth 0, its children depth 1,
+// and so on.
+//
+// Orphans, i.e. spans held in a Sub whose parent span was never collected
+// (see MemoryStore), are visited after the rest of the tree, each at depth 1
+// followed by its own descendants.
+//
+// Each node of t is visited at most once, so that Walk terminates even if
+// the tree was assembled by hand with a node in its own Sub.
+//
+// If fn returns an error, the walk stops and Walk returns that error.
+func (t *Trace) Walk(fn func(span *Span, depth int) error) error {
+	var orphans []*Trace
+	seen := map[*Trace]struct{}{}
+	if err := t.walk(fn, 0, &orphans, seen); err != nil {
+		return err
+	}
+	for i := 0; i < len(orphans); i++ {
+		if err := orphans[i].walk(fn, 1, &orphans, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk visits t at the given depth, unless it was already seen, and then
+// descends into its children, appending any orphans it finds to orphans
+// instead of visiting them.
+func (t *Trace) walk(fn func(span *Span, depth int) error, depth int, orphans *[]*Trace, seen map[*Trace]struct{}) error {
+	if _, dup := seen[t]; dup {
+		return nil
+	}
+	seen[t] = struct{}{}
+	if err := fn(&t.Span, depth); err != nil {
+		return err
+	}
+	for _, sub := range t.Sub {
+		if sub.Span.ID.Parent != t.Span.ID.Span {
+			*orphans = append(*orphans, sub)
+			continue
+		}
+		if err := sub.walk(fn, depth+1, orphans, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copy returns a copy of the tree of spans t. Annotation slices are capped
+// at their current length, so that appending to them in either tree does
+// not affect the other; annotations themselves are shared.
+func (t *Trace) copy() *Trace {
+	c := &Trace{Span: t.Span}
+	c.Annotations = t.Annotations[:len(t.Annotations):len(t.Annotations)]
+	if t.Sub != nil {
+		c.Sub = make([]*Trace, len(t.Sub))
+		for i, sub := range t.Sub {
+			c.Sub[i] = sub.copy()
+		}
+	}
+	return c
 }
 
 // String returns the Trace as a formatted string.
@@ -39,11 +200,84 @@ func (t *Trace) FindSpan(spanID ID) *Trace {
 	return nil
 }
 
+// traceIndex indexes the spans of a tree by ID, for Parent and Children.
+type traceIndex struct {
+	parents  map[ID]*Trace   // span ID -> node of its parent
+	children map[ID][]*Trace // span ID -> nodes of its children
+	nodes    map[ID]*Trace   // span ID -> node
+}
+
+// indexed returns the index of the tree t, building it if needed.
+func (t *Trace) indexed() *traceIndex {
+	if idx, _ := t.index.Load().(*traceIndex); idx != nil {
+		return idx
+	}
+	t.Reindex()
+	return t.index.Load().(*traceIndex)
+}
+
+// invalidateIndex discards the index of the tree t, if any, so that it is
+// rebuilt by the next call to Parent or Children.
+func (t *Trace) invalidateIndex() {
+	if t.index.Load() != nil {
+		t.index.Store((*traceIndex)(nil))
+	}
+}
+
+// Reindex rebuilds the index of the tree t that Parent and Children use.
+// The index is built once, by the first call to either, so Reindex must be
+// called if the tree is modified afterwards. The trees returned by
+// MemoryStore.Trace and MemoryStore.SubTrace need not be: the store
+// discards their indexes when collected spans change their shape.
+func (t *Trace) Reindex() {
+	idx := &traceIndex{
+		parents:  map[ID]*Trace{},
+		children: map[ID][]*Trace{},
+		nodes:    map[ID]*Trace{},
+	}
+	var add func(n *Trace)
+	add = func(n *Trace) {
+		if _, dup := idx.nodes[n.ID.Span]; dup {
+			return
+		}
+		idx.nodes[n.ID.Span] = n
+		for _, sub := range n.Sub {
+			// Orphans placed under a temporary root (see Walk) are not
+			// its children.
+			if sub.ID.Parent == n.ID.Span {
+				idx.parents[sub.ID.Span] = n
+				idx.children[n.ID.Span] = append(idx.children[n.ID.Span], sub)
+			}
+			add(sub)
+		}
+	}
+	add(t)
+	t.index.Store(idx)
+}
+
+// Parent returns the node of the parent of the span with the given ID in
+// the tree t, or nil if the span is not in t, or its parent is not (as for
+// the root span). Lookups take constant time, using an index of the tree
+// built by the first call to Parent or Children (see Reindex).
+func (t *Trace) Parent(span ID) *Trace {
+	return t.indexed().parents[span]
+}
+
+// Children returns the nodes of the children of the span with the given
+// ID in the tree t, or nil if it has none or is not in t. Like Parent, it
+// uses an index of the tree.
+func (t *Trace) Children(span ID) []*Trace {
+	return t.indexed().children[span]
+}
+
 // TreeString returns the Trace as a formatted string that visually
 // represents the trace's tree.
 func (t *Trace) TreeString() string {
 	var buf bytes.Buffer
-	t.treeString(&buf, 0)
+	t.Walk(func(span *Span, depth int) error {
+		writeTreeSpan(&buf, span, depth)
+		return nil
+	})
 	return buf.String()
 }
 
@@ -59,25 +293,27 @@ func (t *Trace) TimespanEvent() (TimespanEvent, error) {
 	return timespanEvent{S: start, E: end}, nil
 }
 
-func (t *Trace) treeString(w io.Writer, depth int) {
+// writeTreeSpan writes the lines of TreeString describing span, found at
+// the given depth.
+func writeTreeSpan(w io.Writer, span *Span, depth int) {
 	const indent1 = "    "
 	indent := strings.Repeat(indent1, depth)
 
 	if depth == 0 {
-		fmt.Fprintf(w, "+ Trace %x\n", uint64(t.Span.ID.Trace))
+		fmt.Fprintf(w, "+ Trace %s\n", span.ID.Trace)
 	} else {
 		if depth == 1 {
 			fmt.Fprint(w, "|")
 		} else {
 			fmt.Fprint(w, "|", indent[len(indent1):])
 		}
-		fmt.Fprintf(w, "%s+ Span %x", strings.Repeat("-", len(indent1)), uint64(t.Span.ID.Span))
-		if t.Span.ID.Parent != 0 {
-			fmt.Fprintf(w, " (parent %x)", uint64(t.Span.ID.Parent))
+		fmt.Fprintf(w, "%s+ Span %s", strings.Repeat("-", len(indent1)), span.ID.Span)
+		if span.ID.Parent != 0 {
+			fmt.Fprintf(w, " (parent %s)", span.ID.Parent)
 		}
 		fmt.Fprintln(w)
 	}
-	for _, a := range t.Span.Annotations {
+	for _, a := range span.Annotations {
 		if depth == 0 {
 			fmt.Fprint(w, "| ")
 		} else {
@@ -85,9 +321,6 @@ func (t *Trace) treeString(w io.Writer, depth int) {
 		}
 		fmt.Fprintf(w, "%s = %s\n", a.Key, a.Value)
 	}
-	for _, sub := range t.Sub {
-		sub.treeString(w, depth+1)
-	}
 }
 
 // findTraceTimes finds the minimum and maximum timespan event times for the
@@ -124,6 +357,141 @@ func findTraceTimes(events []Event) (start, end time.Time, ok bool) {
 	return eStart, eEnd, true
 }
 
+// SelfTime returns the self-time of t's root span: its duration minus the
+// time during which any of its direct children were running. Children that
+// overlap are counted once, and the parts of children outside of the span
+// (such as asynchronous work that outlives it) are ignored, so the
+// self-time is never negative. Durations are taken from the spans'
+// timespan events; ok is false if the root span has none.
+func (t *Trace) SelfTime() (self time.Duration, ok bool) {
+	start, end, ok := t.Span.times()
+	if !ok {
+		return 0, false
+	}
+	var children []timespanEvent
+	for _, sub := range t.Sub {
+		if sub.Span.ID.Parent != t.Span.ID.Span {
+			continue // temporary child of the root
+		}
+		s, e, ok := sub.Span.times()
+		if !ok {
+			continue
+		}
+		if s.Before(start) {
+			s = start
+		}
+		if e.After(end) {
+			e = end
+		}
+		if s.Before(e) {
+			children = append(children, timespanEvent{S: s, E: e})
+		}
+	}
+	sort.Sort(timespansByStart(children))
+
+	self = end.Sub(start)
+	var covered timespanEvent // union of the children seen so far that may overlap later ones
+	for _, c := range children {
+		if covered.E.IsZero() || c.S.After(covered.E) {
+			self -= covered.E.Sub(covered.S)
+			covered = c
+		} else if c.E.After(covered.E) {
+			covered.E = c.E
+		}
+	}
+	self -= covered.E.Sub(covered.S)
+	return self, true
+}
+
+// Duration returns the time between the earliest start and the latest end
+// of the span's timespan events (such as the Timespan recorded by
+// ActiveSpan.Finish), or ok == false if it has none.
+func (s *Span) Duration() (d time.Duration, ok bool) {
+	start, end, ok := s.times()
+	if !ok {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// times returns the minimum and maximum times of the span's timespan
+// events, or ok == false if it has none.
+func (s *Span) times() (start, end time.Time, ok bool) {
+	var events []Event
+	if err := UnmarshalEvents(s.Annotations, &events); err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return findTraceTimes(events)
+}
+
+type timespansByStart []timespanEvent
+
+func (v timespansByStart) Len() int           { return len(v) }
+func (v timespansByStart) Less(i, j int) bool { return v[i].S.Before(v[j].S) }
+func (v timespansByStart) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+
+// latestTime returns the latest end time of any TimespanEvent in t or its
+// descendants, or the zero time if there are none.
+func (t *Trace) latestTime() time.Time {
+	var latest time.Time
+	t.Walk(func(span *Span, depth int) error {
+		var events []Event
+		if err := UnmarshalEvents(span.Annotations, &events); err != nil {
+			return nil
+		}
+		if _, end, ok := findTraceTimes(events); ok && end.After(latest) {
+			latest = end
+		}
+		return nil
+	})
+	return latest
+}
+
+// startedWithin reports whether the root span of t started within ts, as
+// filtered by TracesOpts.Timespan. Every trace is within the zero Timespan,
+// and no trace without a timespan event on its root span is within any
+// other.
+func (t *Trace) startedWithin(ts Timespan) bool {
+	if ts == (Timespan{}) {
+		return true
+	}
+	var events []Event
+	if err := UnmarshalEvents(t.Span.Annotations, &events); err != nil {
+		return false
+	}
+	start, _, ok := findTraceTimes(events)
+	return ok && !start.Before(ts.S) && !start.After(ts.E)
+}
+
+// SortTracesByRecency sorts traces such that the trace whose latest span
+// ended most recently comes first. Traces without any timespan events sort
+// last. Ties are broken by trace ID, so the order is deterministic.
+func SortTracesByRecency(traces []*Trace) {
+	byRecency := tracesByRecency{traces: traces, latest: make([]time.Time, len(traces))}
+	for i, t := range traces {
+		byRecency.latest[i] = t.latestTime()
+	}
+	sort.Stable(byRecency)
+}
+
+// tracesByRecency sorts traces by their precomputed latest times.
+type tracesByRecency struct {
+	traces []*Trace
+	latest []time.Time
+}
+
+func (t tracesByRecency) Len() int { return len(t.traces) }
+func (t tracesByRecency) Less(i, j int) bool {
+	if !t.latest[i].Equal(t.latest[j]) {
+		return t.latest[i].After(t.latest[j])
+	}
+	return t.traces[i].ID.Trace < t.traces[j].ID.Trace
+}
+func (t tracesByRecency) Swap(i, j int) {
+	t.traces[i], t.traces[j] = t.traces[j], t.traces[i]
+	t.latest[i], t.latest[j] = t.latest[j], t.latest[i]
+}
+
 type tracesByIDSpan []*Trace
 
 func (t tracesByIDSpan) Len() int           { return len(t) }
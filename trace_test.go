@@ -1,6 +1,13 @@
 package appdash
 
-import "testing"
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestTrace_TreeString(t *testing.T) {
 	t.Skip("TODO")
@@ -90,3 +97,218 @@ func TestTrace_FindSpan(t *testing.T) {
 		}
 	}
 }
+
+func TestTrace_ParentChildren(t *testing.T) {
+	// 1 > (2 > (4, 5), 3 > 6), as collected into a MemoryStore.
+	ms := NewMemoryStore()
+	for _, id := range []SpanID{{1, 1, 0}, {1, 2, 1}, {1, 3, 1}, {1, 4, 2}, {1, 5, 2}, {1, 6, 3}} {
+		if err := ms.Collect(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	x, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(ts []*Trace) []ID {
+		var ids []ID
+		for _, t := range ts {
+			ids = append(ids, t.ID.Span)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+	tests := []struct {
+		span         ID
+		wantParent   ID // 0 for none
+		wantChildren []ID
+	}{
+		{1, 0, []ID{2, 3}},
+		{2, 1, []ID{4, 5}},
+		{3, 1, []ID{6}},
+		{4, 2, nil},
+		{6, 3, nil},
+		{7, 0, nil}, // not in the trace
+	}
+	for _, test := range tests {
+		var parent ID
+		if p := x.Parent(test.span); p != nil {
+			parent = p.ID.Span
+		}
+		if parent != test.wantParent {
+			t.Errorf("%v: got parent %v, want %v", test.span, parent, test.wantParent)
+		}
+		if got := ids(x.Children(test.span)); !reflect.DeepEqual(got, test.wantChildren) {
+			t.Errorf("%v: got children %v, want %v", test.span, got, test.wantChildren)
+		}
+	}
+
+	// The nodes returned are those of the tree itself.
+	if got, want := x.Parent(5), x.FindSpan(2); got != want {
+		t.Errorf("got parent node %p, want the node %p of the tree", got, want)
+	}
+
+	// The index of the store's tree reflects spans collected since, and
+	// so do those of its subtrees.
+	sub, err := ms.SubTrace(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub.Children(2)
+	if err := ms.Collect(SpanID{1, 7, 6}); err != nil {
+		t.Fatal(err)
+	}
+	if p := x.Parent(7); p == nil || p.ID.Span != 6 {
+		t.Errorf("got parent %v of a span collected later, want span 6", p)
+	}
+	if got := ids(x.Children(6)); !reflect.DeepEqual(got, []ID{7}) {
+		t.Errorf("got children %v of span 6 after collecting a child, want [7]", got)
+	}
+	if err := ms.Collect(SpanID{1, 9, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(sub.Children(2)); len(got) == 0 || got[len(got)-1] != 9 {
+		t.Errorf("got children %v of the subtree's root after collecting a child, want them to end with 9", got)
+	}
+}
+
+func TestTrace_Walk(t *testing.T) {
+	x := &Trace{
+		Span: Span{ID: SpanID{1, 1, 0}},
+		Sub: []*Trace{
+			{
+				Span: Span{ID: SpanID{1, 2, 1}},
+				Sub:  []*Trace{{Span: Span{ID: SpanID{1, 3, 2}}}},
+			},
+			{
+				// An orphan: its parent span 6 was never collected.
+				Span: Span{ID: SpanID{1, 7, 6}},
+				Sub:  []*Trace{{Span: Span{ID: SpanID{1, 8, 7}}}},
+			},
+			{Span: Span{ID: SpanID{1, 4, 1}}},
+		},
+	}
+
+	type visit struct {
+		span  ID
+		depth int
+	}
+	var got []visit
+	err := x.Walk(func(span *Span, depth int) error {
+		got = append(got, visit{span.ID.Span, depth})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []visit{{1, 0}, {2, 1}, {3, 2}, {4, 1}, {7, 1}, {8, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got visits %v, want %v", got, want)
+	}
+
+	// Walking stops at the first error.
+	stop := errors.New("stop")
+	got = nil
+	err = x.Walk(func(span *Span, depth int) error {
+		got = append(got, visit{span.ID.Span, depth})
+		if span.ID.Span == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("got error %v, want %v", err, stop)
+	}
+	if want := []visit{{1, 0}, {2, 1}, {3, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got visits %v before stopping, want %v", got, want)
+	}
+}
+
+func TestTrace_Walk_cycle(t *testing.T) {
+	a := &Trace{Span: Span{ID: SpanID{1, 2, 3}}}
+	b := &Trace{Span: Span{ID: SpanID{1, 3, 2}}, Sub: []*Trace{a}}
+	a.Sub = []*Trace{b}
+	x := &Trace{Span: Span{ID: SpanID{1, 1, 0}}, Sub: []*Trace{a}}
+
+	var visited []ID
+	x.Walk(func(span *Span, depth int) error {
+		visited = append(visited, span.ID.Span)
+		return nil
+	})
+	if want := []ID{1, 2, 3}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("got visited spans %v, want %v", visited, want)
+	}
+	if s := x.TreeString(); strings.Count(s, "+ Span") != 2 {
+		t.Errorf("got tree string %q, want 2 spans", s)
+	}
+}
+
+func TestTrace_SelfTime(t *testing.T) {
+	base := time.Unix(1000, 0)
+	span := func(id SpanID, start, end time.Duration) Span {
+		as, err := MarshalEvent(Timespan{S: base.Add(start * time.Millisecond), E: base.Add(end * time.Millisecond)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return Span{ID: id, Annotations: as}
+	}
+	tests := map[string]struct {
+		trace *Trace
+		want  time.Duration
+	}{
+		"no children": {
+			trace: &Trace{Span: span(SpanID{1, 1, 0}, 0, 100)},
+			want:  100 * time.Millisecond,
+		},
+		"sequential": {
+			trace: &Trace{Span: span(SpanID{1, 1, 0}, 0, 100), Sub: []*Trace{
+				{Span: span(SpanID{1, 2, 1}, 10, 30)},
+				{Span: span(SpanID{1, 3, 1}, 50, 60)},
+			}},
+			want: 70 * time.Millisecond,
+		},
+		"overlapping": {
+			trace: &Trace{Span: span(SpanID{1, 1, 0}, 0, 100), Sub: []*Trace{
+				{Span: span(SpanID{1, 3, 1}, 20, 60)},
+				{Span: span(SpanID{1, 2, 1}, 10, 30)},
+				{Span: span(SpanID{1, 4, 1}, 25, 40)},
+				{Span: span(SpanID{1, 5, 1}, 80, 90)},
+			}},
+			want: 40 * time.Millisecond,
+		},
+		"children outliving the span": {
+			trace: &Trace{Span: span(SpanID{1, 1, 0}, 0, 100), Sub: []*Trace{
+				{Span: span(SpanID{1, 2, 1}, -10, 10)},
+				{Span: span(SpanID{1, 3, 1}, 90, 200)},
+				{Span: span(SpanID{1, 4, 1}, 150, 250)},
+			}},
+			want: 80 * time.Millisecond,
+		},
+		"fully covered": {
+			trace: &Trace{Span: span(SpanID{1, 1, 0}, 0, 100), Sub: []*Trace{
+				{Span: span(SpanID{1, 2, 1}, 0, 60)},
+				{Span: span(SpanID{1, 3, 1}, 50, 100)},
+			}},
+			want: 0,
+		},
+		"grandchildren and orphans are ignored": {
+			trace: &Trace{Span: span(SpanID{1, 1, 0}, 0, 100), Sub: []*Trace{
+				{Span: span(SpanID{1, 2, 1}, 0, 10), Sub: []*Trace{{Span: span(SpanID{1, 3, 2}, 20, 30)}}},
+				{Span: span(SpanID{1, 5, 4}, 40, 50)},
+				{Span: Span{ID: SpanID{1, 6, 1}}},
+			}},
+			want: 90 * time.Millisecond,
+		},
+	}
+	for name, test := range tests {
+		got, ok := test.trace.SelfTime()
+		if !ok || got != test.want {
+			t.Errorf("%s: got self-time %v (ok %v), want %v", name, got, ok, test.want)
+		}
+	}
+
+	if _, ok := (&Trace{Span: Span{ID: SpanID{1, 1, 0}}}).SelfTime(); ok {
+		t.Error("got ok for a span without timespan events")
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 
@@ -34,6 +35,8 @@ import (
 	static "sourcegraph.com/sourcegraph/appdash-data"
 )
 
+var errSpanNotFound = errors.New("could not find the specified trace span")
+
 // App is an HTTP application handler that also exposes methods for
 // constructing URL routes.
 type App struct {
@@ -43,6 +46,11 @@ type App struct {
 	Queryer    appdash.Queryer
 	Aggregator appdash.Aggregator
 
+	// SamplingAudit, if non-nil, is the audit of sampling decisions served
+	// as JSON at SamplingAuditRoute, to find out why a trace was or was not
+	// collected.
+	SamplingAudit *appdash.SamplingAudit
+
 	tmplLock sync.Mutex
 	tmpls    map[string]*htmpl.Template
 
@@ -51,7 +59,9 @@ type App struct {
 }
 
 // New creates a new application handler. If r is nil, a new router is
-// created.
+// created. The caller must set the app's Store and Queryer, and its
+// Aggregator to serve the dashboard, before serving requests; see
+// NewWithStorage for setting them from a single StorageProvider.
 //
 // The given base URL is the absolute base URL under which traceapp is being
 // served, e.g., "https://appdash.mysite.com" or "https://mysite.com/appdash".
@@ -83,11 +93,18 @@ func New(r *Router, base *url.URL) (*App, error) {
 	r.r.Get(TraceSpanRoute).Handler(handlerFunc(app.serveTrace))
 	r.r.Get(TraceProfileRoute).Handler(handlerFunc(app.serveTrace))
 	r.r.Get(TraceSpanProfileRoute).Handler(handlerFunc(app.serveTrace))
+	r.r.Get(TraceFlamegraphRoute).Handler(handlerFunc(app.serveTrace))
+	r.r.Get(TraceSpanFlamegraphRoute).Handler(handlerFunc(app.serveTrace))
+	r.r.Get(TraceExportRoute).Handler(handlerFunc(app.serveTraceExport))
 	r.r.Get(TraceUploadRoute).Handler(handlerFunc(app.serveTraceUpload))
+	r.r.Get(IngestRoute).Handler(handlerFunc(app.serveIngest))
 	r.r.Get(TracesRoute).Handler(handlerFunc(app.serveTraces))
+	r.r.Get(TraceListRoute).Handler(handlerFunc(app.serveTraceList))
+	r.r.Get(TraceTimelineRoute).Handler(handlerFunc(app.serveTraceTimeline))
 	r.r.Get(DashboardRoute).Handler(handlerFunc(app.serveDashboard))
 	r.r.Get(DashboardDataRoute).Handler(handlerFunc(app.serveDashboardData))
 	r.r.Get(AggregateRoute).Handler(handlerFunc(app.serveAggregate))
+	r.r.Get(SamplingAuditRoute).Handler(handlerFunc(app.serveSamplingAudit))
 
 	// Static file serving.
 	r.r.Get(StaticRoute).Handler(http.StripPrefix("/static/", http.FileServer(static.Data)))
@@ -131,28 +148,46 @@ func (a *App) serveTrace(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	trace, err := a.Store.Trace(traceID)
-	if err != nil {
-		return err
-	}
-
-	// Get sub-span if the Span route var is present.
+	// Get only the sub-span's subtree if the Span route var is present.
+	// Trace-level annotations describe the whole trace, so they are looked
+	// up in the whole trace either way.
+	var (
+		trace     *appdash.Trace
+		traceAnns appdash.Annotations
+	)
 	if spanIDStr := v["Span"]; spanIDStr != "" {
 		spanID, err := appdash.ParseID(spanIDStr)
 		if err != nil {
 			return err
 		}
-		trace = trace.FindSpan(spanID)
-		if trace == nil {
-			return errors.New("could not find the specified trace span")
+		trace, err = appdash.FindSubTrace(a.Store, traceID, spanID)
+		if err == appdash.ErrSpanNotFound {
+			return errSpanNotFound
+		} else if err != nil {
+			return err
+		}
+		if traceAnns, err = a.traceAnnotations(traceID); err != nil {
+			return err
+		}
+	} else {
+		trace, err = a.Store.Trace(traceID)
+		if err != nil {
+			return err
 		}
+		traceAnns = trace.TraceAnnotations()
 	}
 
 	// We could use a separate handler for this, but as we need the above to
 	// determine the correct trace (or therein sub-trace), we just handle any
-	// JSON profile requests here.
-	if path.Base(r.URL.Path) == "profile" {
+	// JSON profile and flamegraph requests here.
+	switch path.Base(r.URL.Path) {
+	case "profile":
 		return a.profile(trace, w)
+	case "flamegraph":
+		return a.flamegraph(trace, w)
+	}
+	if noJS(r) {
+		return a.serveTraceNoJS(w, trace)
 	}
 
 	// Do not show d3 timeline chart when timeline item fields are invalid.
@@ -168,13 +203,24 @@ func (a *App) serveTrace(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	// Determine the profile URL.
-	var profile *url.URL
+	// Determine the profile and flamegraph URLs.
+	var profile, flamegraph *url.URL
 	if trace.ID.Parent == 0 {
 		profile, err = a.Router.URLToTraceProfile(trace.Span.ID.Trace)
+		if err == nil {
+			flamegraph, err = a.Router.URLToTraceFlamegraph(trace.Span.ID.Trace)
+		}
 	} else {
 		profile, err = a.Router.URLToTraceSpanProfile(trace.Span.ID.Trace, trace.Span.ID.Span)
+		if err == nil {
+			flamegraph, err = a.Router.URLToTraceSpanFlamegraph(trace.Span.ID.Trace, trace.Span.ID.Span)
+		}
+	}
+	if err != nil {
+		return err
 	}
+
+	export, err := a.Router.URLToTraceExport(trace.ID.Trace)
 	if err != nil {
 		return err
 	}
@@ -201,24 +247,55 @@ func (a *App) serveTrace(w http.ResponseWriter, r *http.Request) error {
 	}
 	permalink.RawQuery = "permalink=" + buf.String()
 
+	// Annotation types let the UI compare numeric and time values properly
+	// when filtering.
+	annTypes := make(map[string]string)
+	for k, t := range appdash.AnnotationTypes() {
+		annTypes[k] = t.String()
+	}
+
 	return a.renderTemplate(w, r, "trace.html", http.StatusOK, &struct {
 		TemplateCommon
 		Trace             *appdash.Trace
 		ShowTimelineChart bool
 		VisData           []timelineItem
 		ProfileURL        string
+		FlamegraphURL     string
+		ExportURL         string
 		Permalink         string
 		JSONTrace         string
+		TraceAnnotations  appdash.Annotations
+		AnnotationTypes   map[string]string
 	}{
 		Trace:             trace,
 		ShowTimelineChart: showTimelineChart,
 		VisData:           visData,
 		ProfileURL:        profile.String(),
+		FlamegraphURL:     flamegraph.String(),
+		ExportURL:         export.String(),
 		Permalink:         permalink.String(),
 		JSONTrace:         string(jsonTrace),
+		TraceAnnotations:  traceAnns,
+		AnnotationTypes:   annTypes,
 	})
 }
 
+// traceAnnotations returns the trace-level annotations of the given trace,
+// without fetching the trace if the store can look them up directly (as
+// appdash.MemoryStore can).
+func (a *App) traceAnnotations(id appdash.ID) (appdash.Annotations, error) {
+	if s, ok := a.Store.(interface {
+		TraceAnnotations(appdash.ID) (appdash.Annotations, error)
+	}); ok {
+		return s.TraceAnnotations(id)
+	}
+	trace, err := a.Store.Trace(id)
+	if err != nil {
+		return nil, err
+	}
+	return trace.TraceAnnotations(), nil
+}
+
 func (a *App) serveTraces(w http.ResponseWriter, r *http.Request) error {
 	// Parse the query for a comma-separated list of traces that we should only
 	// show (all others are hidden).
@@ -232,25 +309,113 @@ func (a *App) serveTraces(w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
+	// The correlation query parameter finds the traces linked to an external
+	// ID, such as a request ID from a support ticket, the service one
+	// those touching a service, and the env one those belonging to a
+	// deployment environment.
+	correlation := r.URL.Query().Get("correlation")
+	service := r.URL.Query().Get("service")
+	env := r.URL.Query().Get("env")
 	traces, err := a.Queryer.Traces(appdash.TracesOpts{
-		TraceIDs: showJust,
+		TraceIDs:      showJust,
+		CorrelationID: correlation,
+		Service:       service,
+		Environment:   env,
+		SortByRecency: true,
 	})
 	if err != nil {
 		return err
 	}
+	services, err := a.filterValues(traces, appdash.ServiceKey, service)
+	if err != nil {
+		return err
+	}
+	envs, err := a.filterValues(traces, appdash.EnvironmentKey, env)
+	if err != nil {
+		return err
+	}
+
+	// The q query parameter holds a search query (see ParseQuery). An
+	// invalid query is reported on the page rather than as an error.
+	status, query, queryErr := http.StatusOK, r.URL.Query().Get("q"), ""
+	if filters, err := ParseQuery(query); err != nil {
+		status, queryErr, traces = http.StatusBadRequest, err.Error(), nil
+	} else if len(filters) > 0 {
+		traces = filterTraces(traces, filters)
+	}
+	if noJS(r) {
+		return a.serveTracesNoJS(w, status, traces, queryErr)
+	}
 
-	return a.renderTemplate(w, r, "traces.html", http.StatusOK, &struct {
+	return a.renderTemplate(w, r, "traces.html", status, &struct {
 		TemplateCommon
-		Traces  []*appdash.Trace
-		Visible func(*appdash.Trace) bool
+		Traces      []*appdash.Trace
+		Visible     func(*appdash.Trace) bool
+		Correlation string
+		Service     string
+		Services    []string
+		Env         string
+		Envs        []string
+		Query       string
+		QueryError  string
 	}{
-		Traces: traces,
+		Traces:      traces,
+		Correlation: correlation,
+		Service:     service,
+		Services:    services,
+		Env:         env,
+		Envs:        envs,
+		Query:       query,
+		QueryError:  queryErr,
 		Visible: func(t *appdash.Trace) bool {
 			return true
 		},
 	})
 }
 
+// filterValues returns the values of the annotations with the given key
+// (such as service or environment names) to offer for filtering the traces
+// page. If the queryer cannot list them itself (as MemoryStore can), they
+// are gathered from the unfiltered traces; the selected value is always
+// included.
+func (a *App) filterValues(traces []*appdash.Trace, key, selected string) ([]string, error) {
+	if l, ok := a.Queryer.(interface {
+		IndexedValues(key string) []string
+	}); ok {
+		return withName(l.IndexedValues(key), selected), nil
+	}
+	if selected != "" {
+		// traces were filtered by selected, so query all traces instead.
+		var err error
+		traces, err = a.Queryer.Traces(appdash.TracesOpts{})
+		if err != nil {
+			return nil, err
+		}
+	}
+	seen := map[string]struct{}{}
+	var names []string
+	for _, t := range traces {
+		for _, name := range t.AnnotationValues(key) {
+			if _, dup := seen[name]; !dup {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return withName(names, selected), nil
+}
+
+// withName returns the sorted names with name added, if it is non-empty
+// and not already present.
+func withName(names []string, name string) []string {
+	i := sort.SearchStrings(names, name)
+	if name == "" || (i < len(names) && names[i] == name) {
+		return names
+	}
+	return append(names[:i:i], append([]string{name}, names[i:]...)...)
+}
+
 func (a *App) serveAggregate(w http.ResponseWriter, r *http.Request) error {
 	// By default we display all traces.
 	traces, err := a.Queryer.Traces(appdash.TracesOpts{})
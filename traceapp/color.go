@@ -0,0 +1,23 @@
+package traceapp
+
+import "hash/fnv"
+
+// palette holds the colors that ColorFor picks from: d3's category20
+// palette, which the web UI used before colors were assigned here.
+var palette = []string{
+	"#1f77b4", "#aec7e8", "#ff7f0e", "#ffbb78", "#2ca02c",
+	"#98df8a", "#d62728", "#ff9896", "#9467bd", "#c5b0d5",
+	"#8c564b", "#c49c94", "#e377c2", "#f7b6d2", "#7f7f7f",
+	"#c7c7c7", "#bcbd22", "#dbdb8d", "#17becf", "#9edae5",
+}
+
+// ColorFor returns the CSS color of spans with the given name (or of a
+// service, given its name). It is derived from a hash of the name, so that
+// an operation such as "db.query" has the same color in every trace and in
+// every view: the timeline, the flamegraph and the server-rendered
+// waterfalls.
+func ColorFor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return palette[h.Sum32()%uint32(len(palette))]
+}
@@ -0,0 +1,64 @@
+package traceapp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestColorFor(t *testing.T) {
+	if ColorFor("db.query") != ColorFor("db.query") {
+		t.Error("the same name got different colors")
+	}
+
+	// Distinct names are spread across the whole palette.
+	const names = 2000
+	counts := map[string]int{}
+	for i := 0; i < names; i++ {
+		counts[ColorFor(fmt.Sprintf("op-%d", i))]++
+	}
+	want := names / len(palette)
+	for _, c := range palette {
+		if n := counts[c]; n < want/2 || n > want*3/2 {
+			t.Errorf("color %s was picked for %d of %d names, want about %d", c, n, names, want)
+		}
+	}
+}
+
+func TestColorFor_views(t *testing.T) {
+	// A span named "db.query" is drawn in the same color by every view.
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	trace := &appdash.Trace{Span: appdash.Span{ID: appdash.SpanID{Trace: 1, Span: 1}}}
+	as, err := appdash.MarshalEvent(appdash.Timespan{S: start, E: start.Add(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace.Span.Annotations = append(as, appdash.Annotation{Key: "Name", Value: []byte("db.query")})
+	want := ColorFor("db.query")
+
+	app := &App{Router: NewRouter(nil)}
+	items, err := app.d3timeline(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Times[0].Color != want {
+		t.Errorf("timeline: got items %+v, want color %s", items, want)
+	}
+	frame, err := app.calcFlamegraph(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame.Color != want {
+		t.Errorf("flamegraph: got color %s, want %s", frame.Color, want)
+	}
+	var buf strings.Builder
+	if err := exportTemplate.ExecuteTemplate(&buf, "span", exportTrace(trace, start, time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "background: "+want) {
+		t.Errorf("waterfall: got %s, want color %s", buf.String(), want)
+	}
+}
@@ -57,6 +57,9 @@ func (a *App) serveDashboardData(w http.ResponseWriter, r *http.Request) error {
 		end -= 72 * time.Hour
 	}
 
+	if a.Aggregator == nil {
+		return errNoAggregator
+	}
 	results, err := a.Aggregator.Aggregate(start, end)
 	if err != nil {
 		return err
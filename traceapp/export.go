@@ -0,0 +1,146 @@
+package traceapp
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// exportSpan is a span of a trace export, positioned on the export's
+// waterfall.
+type exportSpan struct {
+	Name        string
+	Color       string // see ColorFor
+	ID          appdash.SpanID
+	Duration    time.Duration
+	HasTime     bool
+	Left, Width float64           // position of the span's bar, in percent of the trace's duration
+	Tags        map[string]string // see appdash.TagPrefix
+	Annotations appdash.Annotations
+	Children    []*exportSpan // in order of start time
+
+	start time.Time
+}
+
+// exportTrace returns the span tree of t, positioned relative to the
+// window from start lasting total.
+func exportTrace(t *appdash.Trace, start time.Time, total time.Duration) *exportSpan {
+	s := &exportSpan{
+		Name:        t.Span.Name(),
+		ID:          t.Span.ID,
+		Tags:        t.Span.Tags(),
+		Annotations: filterAnnotations(t.Span.Annotations),
+	}
+	if s.Name == "" {
+		s.Name = t.Span.ID.Span.String()
+	}
+	s.Color = ColorFor(s.Name)
+	if spanStart, end, ok := spanTimes(&t.Span); ok {
+		s.HasTime, s.start, s.Duration = true, spanStart, end.Sub(spanStart)
+		if total > 0 {
+			s.Left = 100 * float64(spanStart.Sub(start)) / float64(total)
+			s.Width = 100 * float64(s.Duration) / float64(total)
+		}
+	}
+	for _, sub := range t.Sub {
+		s.Children = append(s.Children, exportTrace(sub, start, total))
+	}
+	sort.SliceStable(s.Children, func(i, j int) bool {
+		return s.Children[i].start.Before(s.Children[j].start)
+	})
+	return s
+}
+
+// serveTraceExport serves a trace as a single HTML file that can be viewed
+// without traceapp, e.g. when attached to a bug report. Its styles and
+// scripts are inlined and it loads nothing over the network.
+func (a *App) serveTraceExport(w http.ResponseWriter, r *http.Request) error {
+	traceID, err := appdash.ParseID(mux.Vars(r)["Trace"])
+	if err != nil {
+		return err
+	}
+	trace, err := a.Store.Trace(traceID)
+	if err != nil {
+		return err
+	}
+
+	summary := summarizeTrace(trace)
+	var buf bytes.Buffer
+	err = exportTemplate.Execute(&buf, &struct {
+		Summary *TraceSummary
+		Root    *exportSpan
+	}{
+		Summary: summary,
+		Root:    exportTrace(trace, summary.Start, summary.Duration),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="trace-%s.html"`, traceID))
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+var exportTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"pct": func(f float64) string { return fmt.Sprintf("%.3f%%", f) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Trace {{.Summary.ID}}{{with .Summary.Name}} - {{.}}{{end}}</title>
+<style>
+body { font: 13px/1.4 -apple-system, "Helvetica Neue", Arial, sans-serif; margin: 20px; color: #333; }
+h1 { font-size: 18px; margin: 0 0 4px; }
+.meta { color: #777; margin-bottom: 12px; }
+.controls button { margin-right: 4px; }
+details { margin-left: 16px; }
+details.root { margin-left: 0; }
+summary { cursor: pointer; padding: 2px 0; white-space: nowrap; }
+summary:hover { background: #f4f4f4; }
+.name { display: inline-block; width: 320px; overflow: hidden; text-overflow: ellipsis; vertical-align: middle; }
+.dur { display: inline-block; width: 90px; text-align: right; color: #555; vertical-align: middle; }
+.lane { display: inline-block; position: relative; width: 40%; height: 10px; margin-left: 10px; background: #f0f0f0; vertical-align: middle; }
+.bar { position: absolute; top: 0; bottom: 0; min-width: 1px; background: #4a90d9; }
+table { border-collapse: collapse; margin: 4px 0 8px 16px; }
+td { border: 1px solid #ddd; padding: 2px 6px; font-family: monospace; font-size: 12px; vertical-align: top; white-space: pre-wrap; word-break: break-all; }
+td.key { color: #555; white-space: nowrap; }
+</style>
+</head>
+<body>
+<h1>{{with .Summary.Name}}{{.}}{{else}}Trace {{.Summary.ID}}{{end}}</h1>
+<div class="meta">
+  Trace {{.Summary.ID}} &middot; {{.Summary.Spans}} spans{{if not .Summary.Start.IsZero}} &middot; started {{.Summary.Start.UTC.Format "2006-01-02 15:04:05.000 MST"}} &middot; {{.Summary.Duration}}{{end}}
+</div>
+<div class="controls">
+  <button type="button" onclick="toggleAll(true)">Expand all</button>
+  <button type="button" onclick="toggleAll(false)">Collapse all</button>
+</div>
+{{template "span" .Root}}
+<script>
+function toggleAll(open) {
+  var ds = document.getElementsByTagName("details");
+  for (var i = 0; i < ds.length; i++) {
+    ds[i].open = open;
+  }
+}
+</script>
+</body>
+</html>
+{{define "span"}}<details open{{if eq .ID.Parent 0}} class="root"{{end}}>
+<summary><span class="name" title="{{.ID}}">{{.Name}}</span><span class="dur">{{if .HasTime}}{{.Duration}}{{end}}</span><span class="lane">{{if .HasTime}}<span class="bar" style="left: {{pct .Left}}; width: {{pct .Width}}; background: {{.Color}}"></span>{{end}}</span></summary>
+{{with .Tags}}<table class="tags"><caption>Tags</caption>
+{{range $key, $value := .}}<tr><td class="key">{{$key}}</td><td>{{$value}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Annotations}}<table>
+{{range .Annotations}}<tr><td class="key">{{.Key}}</td><td>{{printf "%s" .Value}}</td></tr>
+{{end}}</table>
+{{end}}{{range .Children}}{{template "span" .}}{{end}}</details>
+{{end}}`))
@@ -0,0 +1,78 @@
+package traceapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestServeTraceExport(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	base := time.Unix(1000, 0)
+	rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, appdash.NewLocalCollector(ms))
+	rec.Name("Serve /users")
+	rec.Event(appdash.Timespan{S: base, E: base.Add(100 * time.Millisecond)})
+	rec.Annotation(appdash.Annotation{Key: "Server.Route", Value: []byte("/users")})
+	child := rec.Child()
+	child.Name("SELECT users")
+	child.Event(appdash.Timespan{S: base.Add(10 * time.Millisecond), E: base.Add(60 * time.Millisecond)})
+	child.Annotation(appdash.Annotation{Key: "SQL", Value: []byte("SELECT * FROM users WHERE name = '<script>'")})
+	child.Finish()
+	rec.Finish()
+
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store, app.Queryer = ms, ms
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/traces/0000000000000001/export.html", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("got Content-Type %q, want text/html", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, `filename="trace-0000000000000001.html"`) {
+		t.Errorf("got Content-Disposition %q", cd)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"Serve /users",
+		"SELECT users",
+		"Server.Route",
+		"100ms",
+		"50ms",
+		"left: 10.000%; width: 50.000%",
+		"SELECT * FROM users WHERE name = &#39;&lt;script&gt;&#39;",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("export does not contain %q", want)
+		}
+	}
+
+	// The export must be a complete document that loads nothing.
+	if !strings.HasPrefix(body, "<!DOCTYPE html>") || !strings.Contains(body, "</html>") {
+		t.Error("export is not a complete HTML document")
+	}
+	if open, closed := strings.Count(body, "<details"), strings.Count(body, "</details>"); open != 2 || closed != 2 {
+		t.Errorf("got %d <details> and %d </details>, want 2 of each", open, closed)
+	}
+	external := regexp.MustCompile(`(?i)(src|href)\s*=\s*"?(https?:)?/|<link|@import|url\(`)
+	if m := external.FindString(body); m != "" {
+		t.Errorf("export references an external resource: %q", m)
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/traces/0000000000000002/export.html", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a missing trace, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,119 @@
+package traceapp
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// flameFrame is a frame of a trace's flamegraph, in which the width of a
+// frame is the duration of its span and frames are stacked on top of their
+// parent span's frame. It is encoded to JSON.
+type flameFrame struct {
+	Name        string
+	Color       string // see ColorFor
+	URL         string
+	Start       float64           // start time in ms, relative to the start of the flamegraph's root span
+	Time        float64           // duration in ms
+	Annotations map[string]string // important annotations, shown when hovering over the frame
+	Children    []*flameFrame     // in order of start time
+}
+
+// calcFlamegraph calculates the flamegraph of the given trace. Spans
+// without timespan events have a zero duration and start along with their
+// parent.
+func (a *App) calcFlamegraph(t *appdash.Trace) (*flameFrame, error) {
+	start, _, _ := spanTimes(&t.Span)
+	return a.calcFlameFrame(t, start)
+}
+
+func (a *App) calcFlameFrame(t *appdash.Trace, origin time.Time) (*flameFrame, error) {
+	var u *url.URL
+	var err error
+	if t.ID.Parent == 0 {
+		u, err = a.URLToTrace(t.ID.Trace)
+	} else {
+		u, err = a.URLToTraceSpan(t.ID.Trace, t.ID.Span)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := &flameFrame{
+		Name:        t.Span.Name(),
+		URL:         u.String(),
+		Annotations: map[string]string{},
+	}
+	if f.Name == "" {
+		f.Name = t.Span.ID.Span.String()
+	}
+	f.Color = ColorFor(f.Name)
+	if start, end, ok := spanTimes(&t.Span); ok {
+		if !origin.IsZero() {
+			f.Start = msSince(origin, start)
+		}
+		f.Time = msSince(start, end)
+	}
+	for _, ann := range t.Span.Annotations {
+		if ann.Important() {
+			f.Annotations[ann.Key] = string(ann.Value)
+		}
+	}
+
+	for _, sub := range t.Sub {
+		child, err := a.calcFlameFrame(sub, origin)
+		if err != nil {
+			return nil, err
+		}
+		if child.Time == 0 {
+			child.Start = f.Start
+		}
+		f.Children = append(f.Children, child)
+	}
+	sort.Stable(flameFramesByStart(f.Children))
+	return f, nil
+}
+
+// spanTimes returns the start and end time of the longest timespan event
+// of the span, as used for its time in profiles, or ok == false if it has
+// none.
+func spanTimes(s *appdash.Span) (start, end time.Time, ok bool) {
+	var events []appdash.Event
+	if err := appdash.UnmarshalEvents(s.Annotations, &events); err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	for _, ev := range events {
+		ts, isTimespan := ev.(appdash.TimespanEvent)
+		if !isTimespan {
+			continue
+		}
+		if !ok || ts.End().Sub(ts.Start()) > end.Sub(start) {
+			start, end, ok = ts.Start(), ts.End(), true
+		}
+	}
+	return start, end, ok
+}
+
+func msSince(from, to time.Time) float64 {
+	return float64(to.Sub(from)) / float64(time.Millisecond)
+}
+
+type flameFramesByStart []*flameFrame
+
+func (v flameFramesByStart) Len() int           { return len(v) }
+func (v flameFramesByStart) Less(i, j int) bool { return v[i].Start < v[j].Start }
+func (v flameFramesByStart) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+
+// flamegraph generates and encodes the flamegraph of the given trace as
+// JSON to the given writer.
+func (a *App) flamegraph(t *appdash.Trace, out io.Writer) error {
+	f, err := a.calcFlamegraph(t)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(out).Encode(f)
+}
@@ -0,0 +1,79 @@
+package traceapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestFlamegraph(t *testing.T) {
+	base := time.Unix(1000, 0)
+	span := func(id appdash.SpanID, name string, start, end time.Duration, extra ...appdash.Annotation) appdash.Span {
+		var as appdash.Annotations
+		for _, e := range []appdash.Event{appdash.SpanName(name), appdash.Timespan{S: base.Add(start), E: base.Add(end)}} {
+			ea, err := appdash.MarshalEvent(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			as = append(as, ea...)
+		}
+		return appdash.Span{ID: id, Annotations: append(as, extra...)}
+	}
+	trace := &appdash.Trace{
+		Span: span(appdash.SpanID{1, 1, 0}, "root", 0, 100*time.Millisecond),
+		Sub: []*appdash.Trace{
+			{Span: span(appdash.SpanID{1, 3, 1}, "second", 60*time.Millisecond, 90*time.Millisecond)},
+			{
+				Span: span(appdash.SpanID{1, 2, 1}, "first", 10*time.Millisecond, 50*time.Millisecond),
+				Sub: []*appdash.Trace{
+					{Span: span(appdash.SpanID{1, 4, 2}, "query", 20*time.Millisecond, 25*time.Millisecond,
+						appdash.Annotation{Key: "Caller.Func", Value: []byte("main.query")})},
+				},
+			},
+		},
+	}
+
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := app.flamegraph(trace, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var got flameFrame
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := flameFrame{
+		Name: "root", Color: ColorFor("root"), URL: "/traces/0000000000000001", Start: 0, Time: 100,
+		Annotations: map[string]string{},
+		Children: []*flameFrame{
+			{
+				Name: "first", Color: ColorFor("first"), URL: "/traces/0000000000000001/0000000000000002", Start: 10, Time: 40,
+				Annotations: map[string]string{},
+				Children: []*flameFrame{
+					{
+						Name: "query", Color: ColorFor("query"), URL: "/traces/0000000000000001/0000000000000004", Start: 20, Time: 5,
+						Annotations: map[string]string{"Caller.Func": "main.query"},
+					},
+				},
+			},
+			{
+				Name: "second", Color: ColorFor("second"), URL: "/traces/0000000000000001/0000000000000003", Start: 60, Time: 30,
+				Annotations: map[string]string{},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		wantJSON, _ := json.MarshalIndent(want, "", "  ")
+		t.Errorf("got flamegraph\n%s\n\nwant\n%s", gotJSON, wantJSON)
+	}
+}
@@ -5,10 +5,21 @@ import (
 	"log"
 	"net/http"
 	"runtime/debug"
+
+	"sourcegraph.com/sourcegraph/appdash"
 )
 
 type handlerFunc func(http.ResponseWriter, *http.Request) error
 
+// A statusError is an error in a request, such as a malformed parameter,
+// which is reported to the client with the given status.
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+
 // ServeHTTP implements http.Handler.
 func (h handlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var rb responseBuffer
@@ -33,5 +44,13 @@ func handleError(w http.ResponseWriter, r *http.Request, err error) {
 	// Never cache error responses.
 	w.Header().Set("cache-control", "no-cache, max-age=0")
 
-	http.Error(w, err.Error(), http.StatusInternalServerError)
+	status := http.StatusInternalServerError
+	if err == appdash.ErrTraceNotFound || err == errSpanNotFound || err == errNoSamplingAudit {
+		status = http.StatusNotFound
+	} else if err == errReadOnlyStorage || err == errNoAggregator {
+		status = http.StatusNotImplemented
+	} else if e, ok := err.(*statusError); ok {
+		status = e.status
+	}
+	http.Error(w, err.Error(), status)
 }
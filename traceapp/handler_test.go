@@ -0,0 +1,150 @@
+package traceapp
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestApp_traceNotFound(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store, app.Queryer = ms, ms
+	if err := ms.Collect(appdash.SpanID{Trace: 1, Span: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]int{
+		"/traces/0000000000000001":                  http.StatusOK,
+		"/traces/0000000000000002":                  http.StatusNotFound,
+		"/traces/0000000000000001/0000000000000003": http.StatusNotFound,
+		"/traces/0000000000000002/profile":          http.StatusNotFound,
+	}
+	for path, want := range tests {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != want {
+			t.Errorf("%s: got status %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestApp_tracesEnvironment(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store, app.Queryer = ms, ms
+	for env, trace := range map[string]appdash.ID{"staging": 1, "prod": 2} {
+		c := appdash.NewEnvironmentCollector(ms, env)
+		if err := c.Collect(appdash.SpanID{Trace: trace, Span: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string][]string{
+		"/traces":             {"0000000000000001", "0000000000000002"},
+		"/traces?env=staging": {"0000000000000001"},
+		"/traces?env=prod":    {"0000000000000002"},
+	}
+	for path, want := range tests {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got status %d", path, w.Code)
+		}
+		body, _ := ioutil.ReadAll(w.Body)
+		var got []string
+		for _, id := range []string{"0000000000000001", "0000000000000002"} {
+			if strings.Contains(string(body), ">"+id+"</a>") {
+				got = append(got, id)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: got traces %v, want %v", path, got, want)
+		}
+		// The selector offers every environment, whichever is selected.
+		for _, env := range []string{"staging", "prod"} {
+			if !strings.Contains(string(body), `<option value="`+env+`"`) {
+				t.Errorf("%s: environment %q is not offered", path, env)
+			}
+		}
+	}
+}
+
+func TestApp_traceTags(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, appdash.NewLocalCollector(ms))
+	rec.Name("op")
+	rec.Tag("customer", "acme")
+	rec.Finish()
+
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store, app.Queryer = ms, ms
+
+	for _, path := range []string{"/traces/0000000000000001", "/traces/0000000000000001/export.html"} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got status %d", path, w.Code)
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, "<caption>Tags</caption>") || !regexp.MustCompile(`<t[hd][^>]*>customer</t[hd]><td>acme</td>`).MatchString(body) {
+			t.Errorf("%s: tag customer=acme is not shown in a Tags section", path)
+		}
+		// The trace page embeds the raw trace JSON for copying, but the
+		// export only shows annotations.
+		if strings.HasSuffix(path, "export.html") && strings.Contains(body, appdash.TagPrefix) {
+			t.Errorf("%s: raw tag annotation key is shown", path)
+		}
+	}
+}
+
+// subTraceOnlyStore is a store whose whole traces cannot be fetched.
+type subTraceOnlyStore struct {
+	*appdash.MemoryStore
+}
+
+func (subTraceOnlyStore) Trace(appdash.ID) (*appdash.Trace, error) {
+	return nil, errors.New("whole trace fetched")
+}
+
+func TestApp_traceSpanFetchesSubTrace(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, appdash.NewLocalCollector(ms))
+	rec.Name("root")
+	child := rec.Child()
+	child.Name("child")
+	child.Finish()
+	rec.Finish()
+
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store, app.Queryer = subTraceOnlyStore{ms}, ms
+
+	path := "/traces/0000000000000001/" + child.SpanID.Span.String()
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	if !strings.Contains(w.Body.String(), "child") {
+		t.Error("sub-span page does not show the sub-span")
+	}
+}
@@ -0,0 +1,39 @@
+package traceapp
+
+import (
+	"strings"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// headerOrderKey is the key under which httptrace records the order that a
+// server received the request headers in (see httptrace.CaptureHeaderOrder).
+const headerOrderKey = "Server.Request.HeaderOrder"
+
+// A receivedHeader is a request header, as listed by receivedHeaders.
+type receivedHeader struct {
+	Name, Value string
+}
+
+// receivedHeaders returns the request headers recorded on the span, in the
+// order the server received them, or nil if the order was not recorded.
+// A header received several times is listed each time, but its values,
+// which httptrace records together, are only shown the first time.
+func receivedHeaders(span appdash.Span) []receivedHeader {
+	order, _ := span.Annotations.Get(headerOrderKey)
+	if len(order) == 0 {
+		return nil
+	}
+	var headers []receivedHeader
+	seen := map[string]bool{}
+	for _, name := range strings.Split(string(order), ", ") {
+		h := receivedHeader{Name: name}
+		if !seen[name] {
+			seen[name] = true
+			v, _ := span.Annotations.Get("Server.Request.Headers." + name)
+			h.Value = string(v)
+		}
+		headers = append(headers, h)
+	}
+	return headers
+}
@@ -0,0 +1,30 @@
+package traceapp
+
+import (
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestReceivedHeaders(t *testing.T) {
+	if hs := receivedHeaders(appdash.Span{}); hs != nil {
+		t.Errorf("got headers %v for a span without a header order, want nil", hs)
+	}
+
+	span := appdash.Span{Annotations: appdash.Annotations{
+		{Key: "Server.Request.Headers.Accept", Value: []byte("*/*")},
+		{Key: "Server.Request.Headers.Host", Value: []byte("example.com")},
+		{Key: "Server.Request.Headers.X-Zeta", Value: []byte("1,2")},
+		{Key: headerOrderKey, Value: []byte("X-Zeta, Host, Accept, X-Zeta")},
+	}}
+	want := []receivedHeader{
+		{Name: "X-Zeta", Value: "1,2"},
+		{Name: "Host", Value: "example.com"},
+		{Name: "Accept", Value: "*/*"},
+		{Name: "X-Zeta"},
+	}
+	if got := receivedHeaders(span); !reflect.DeepEqual(got, want) {
+		t.Errorf("got headers %v, want %v", got, want)
+	}
+}
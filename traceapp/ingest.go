@@ -0,0 +1,79 @@
+package traceapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// maxIngestBytes is the size of the largest batch of spans that the ingest
+// endpoint accepts.
+const maxIngestBytes = 32 << 20
+
+// serveIngest collects a batch of spans POSTed by a system that does not
+// speak appdash's wire protocol. The format of the batch is given by the
+// format parameter of its Content-Type, which must be application/json:
+//
+// 	application/json                 a JSON array of appdash.Spans (the default)
+// 	application/json; format=zipkin  a JSON array of Zipkin v2 spans
+//
+// The batch is validated as a whole before any of its spans are collected,
+// and a malformed batch is rejected with a 400 status. Spans are collected
+// as they are, so a batch may add spans to a trace already in the store.
+// On success, the response has a 202 status.
+func (a *App) serveIngest(w http.ResponseWriter, r *http.Request) error {
+	defer r.Body.Close()
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return &statusError{http.StatusUnsupportedMediaType, fmt.Errorf("ingest: unsupported Content-Type %q (want application/json)", r.Header.Get("Content-Type"))}
+	}
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxIngestBytes))
+	if err != nil {
+		return &statusError{http.StatusRequestEntityTooLarge, fmt.Errorf("ingest: %s", err)}
+	}
+
+	var spans []*appdash.Span
+	switch format := params["format"]; format {
+	case "", "appdash":
+		spans, err = unmarshalIngestedSpans(data)
+	case "zipkin":
+		spans, err = appdash.UnmarshalZipkinJSON(data)
+	default:
+		err = fmt.Errorf("unknown format %q (want appdash or zipkin)", format)
+	}
+	if err != nil {
+		return &statusError{http.StatusBadRequest, fmt.Errorf("ingest: %s", err)}
+	}
+
+	for _, span := range spans {
+		if err := a.Store.Collect(span.ID, span.Annotations...); err != nil {
+			return err
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+	return nil
+}
+
+// unmarshalIngestedSpans parses a JSON array of appdash spans, checking
+// that each has a trace and span ID.
+func unmarshalIngestedSpans(data []byte) ([]*appdash.Span, error) {
+	var spans []*appdash.Span
+	if err := json.Unmarshal(data, &spans); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %s", err)
+	}
+	for i, span := range spans {
+		if span == nil || span.ID.Trace == 0 || span.ID.Span == 0 {
+			return nil, fmt.Errorf("span %d has no trace or span ID", i)
+		}
+		for _, a := range span.Annotations {
+			if a.Key == "" {
+				return nil, fmt.Errorf("span %d (%s) has an annotation without a key", i, span.ID)
+			}
+		}
+	}
+	return spans, nil
+}
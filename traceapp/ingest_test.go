@@ -0,0 +1,119 @@
+package traceapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestApp_ingest(t *testing.T) {
+	tests := map[string]struct {
+		contentType, body string
+		wantStatus        int
+		wantSpans         []appdash.SpanID
+		wantName          string
+	}{
+		"appdash": {
+			contentType: "application/json",
+			body:        `[{"ID":{"Trace":"1","Span":"2","Parent":"0"},"Annotations":[{"Key":"Name","Value":"cm9vdA=="}]},{"ID":{"Trace":"1","Span":"3","Parent":"2"}}]`,
+			wantStatus:  http.StatusAccepted,
+			wantSpans:   []appdash.SpanID{{Trace: 1, Span: 2}, {Trace: 1, Span: 3, Parent: 2}},
+			wantName:    "root",
+		},
+		"zipkin": {
+			contentType: "application/json; format=zipkin",
+			body:        `[{"traceId":"463ac35c9f6413ad48485a3953bb6124","id":"a2fb4a1d1a96d312","name":"get /","timestamp":1461750491274000,"duration":207000,"localEndpoint":{"serviceName":"frontend"}},{"traceId":"463ac35c9f6413ad48485a3953bb6124","id":"b2fb4a1d1a96d312","parentId":"a2fb4a1d1a96d312","name":"query"}]`,
+			wantStatus:  http.StatusAccepted,
+			wantSpans:   []appdash.SpanID{{Trace: 0x48485a3953bb6124, Span: 0xa2fb4a1d1a96d312}, {Trace: 0x48485a3953bb6124, Span: 0xb2fb4a1d1a96d312, Parent: 0xa2fb4a1d1a96d312}},
+			wantName:    "get /",
+		},
+		"malformed JSON": {
+			contentType: "application/json",
+			body:        `[{"ID":`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		"missing span ID": {
+			contentType: "application/json",
+			body:        `[{"ID":{"Trace":"1","Span":"2"}},{"ID":{"Trace":"1"}}]`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		"invalid zipkin ID": {
+			contentType: "application/json; format=zipkin",
+			body:        `[{"traceId":"1","id":"not hex"}]`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		"unknown format": {
+			contentType: "application/json; format=jaeger",
+			body:        `[]`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		"unsupported content type": {
+			contentType: "text/plain",
+			body:        `[]`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+	for label, test := range tests {
+		ms := appdash.NewMemoryStore()
+		app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		app.Store, app.Queryer = ms, ms
+
+		req := httptest.NewRequest("POST", "/ingest", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", test.contentType)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		if w.Code != test.wantStatus {
+			t.Errorf("%s: got status %d, want %d (body %q)", label, w.Code, test.wantStatus, w.Body.String())
+			continue
+		}
+
+		traces, err := ms.Traces(appdash.TracesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.wantSpans == nil {
+			// A rejected batch must not be partly collected.
+			if len(traces) != 0 {
+				t.Errorf("%s: got %d traces collected from a rejected batch", label, len(traces))
+			}
+			continue
+		}
+		trace, err := ms.Trace(test.wantSpans[0].Trace)
+		if err != nil {
+			t.Errorf("%s: %s", label, err)
+			continue
+		}
+		var got []appdash.SpanID
+		trace.Walk(func(span *appdash.Span, depth int) error {
+			got = append(got, span.ID)
+			return nil
+		})
+		if len(got) != len(test.wantSpans) || got[0] != test.wantSpans[0] || got[1] != test.wantSpans[1] {
+			t.Errorf("%s: got spans %v, want %v", label, got, test.wantSpans)
+		}
+		if name := trace.Span.Name(); name != test.wantName {
+			t.Errorf("%s: got root span name %q, want %q", label, name, test.wantName)
+		}
+	}
+}
+
+func TestApp_ingestReadOnlyStorage(t *testing.T) {
+	app, err := NewWithStorage(nil, &url.URL{Scheme: "http", Host: "example.com"}, &minimalStorage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "/ingest", strings.NewReader(`[{"ID":{"Trace":"1","Span":"2"}}]`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+}
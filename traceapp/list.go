@@ -0,0 +1,222 @@
+package traceapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// A TraceSummary summarizes a trace for display in a list, without its
+// spans.
+type TraceSummary struct {
+	ID       appdash.ID
+	Name     string        // name of the root span
+	Route    string        // route of the root span's HTTP server event, if any
+	Start    time.Time     // earliest span start time, if known
+	Duration time.Duration // time between the earliest span start and latest span end
+	Status   int           // HTTP status code of the root span, if any
+	Error    bool          // whether any span failed with a 5xx status or a panic, or is marked failed (see appdash.ErrorKey)
+	Spans    int           // number of spans in the trace
+}
+
+// TraceListOpts specifies which traces ListTraces returns.
+type TraceListOpts struct {
+	// Start and End, if non-zero, restrict the list to traces that started
+	// within the given time range.
+	Start, End time.Time
+
+	// Route, if non-empty, restricts the list to traces whose root span has
+	// the given route or, lacking a route, the given name.
+	Route string
+
+	// MinDuration, if non-zero, restricts the list to traces that took at
+	// least this long.
+	MinDuration time.Duration
+
+	// ErrorsOnly, if true, restricts the list to traces with errors.
+	ErrorsOnly bool
+
+	// Offset is the number of matching traces to skip, and Limit the
+	// maximum number to return. If Limit is zero, all matching traces after
+	// Offset are returned.
+	Offset, Limit int
+}
+
+// A TraceList is a page of trace summaries returned by ListTraces.
+type TraceList struct {
+	Traces []*TraceSummary
+	Total  int // total number of matching traces, across all pages
+}
+
+// ListTraces returns a page of summaries of the traces in q that match
+// opts, most recent first. Summaries are computed from the annotations of
+// each span without unmarshaling events other than timespans.
+func ListTraces(q appdash.Queryer, opts TraceListOpts) (*TraceList, error) {
+	var qopts appdash.TracesOpts
+	if !opts.Start.IsZero() && !opts.End.IsZero() {
+		qopts.Timespan = appdash.Timespan{S: opts.Start, E: opts.End}
+	}
+	traces, err := q.Traces(qopts)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*TraceSummary
+	for _, t := range traces {
+		s := summarizeTrace(t)
+		if opts.matches(s) {
+			matches = append(matches, s)
+		}
+	}
+	sort.Sort(summariesByRecency(matches))
+
+	list := &TraceList{Total: len(matches)}
+	if opts.Offset >= 0 && opts.Offset < len(matches) {
+		list.Traces = matches[opts.Offset:]
+		if opts.Limit > 0 && opts.Limit < len(list.Traces) {
+			list.Traces = list.Traces[:opts.Limit]
+		}
+	}
+	return list, nil
+}
+
+func (opts *TraceListOpts) matches(s *TraceSummary) bool {
+	if !opts.Start.IsZero() && s.Start.Before(opts.Start) {
+		return false
+	}
+	if !opts.End.IsZero() && s.Start.After(opts.End) {
+		return false
+	}
+	if opts.Route != "" {
+		route := s.Route
+		if route == "" {
+			route = s.Name
+		}
+		if route != opts.Route {
+			return false
+		}
+	}
+	if s.Duration < opts.MinDuration {
+		return false
+	}
+	return !opts.ErrorsOnly || s.Error
+}
+
+// summarizeTrace computes the summary of t.
+func summarizeTrace(t *appdash.Trace) *TraceSummary {
+	s := &TraceSummary{ID: t.Span.ID.Trace, Name: t.Span.Name()}
+	var end time.Time
+	t.Walk(func(span *appdash.Span, depth int) error {
+		s.Spans++
+		for _, a := range span.Annotations {
+			switch {
+			case a.Key == "Server.Route" && span == &t.Span:
+				s.Route = string(a.Value)
+			case strings.HasSuffix(a.Key, "Response.StatusCode"):
+				code, _ := strconv.Atoi(string(a.Value))
+				if span == &t.Span && a.Key == "Server.Response.StatusCode" {
+					s.Status = code
+				}
+				if code >= 500 {
+					s.Error = true
+				}
+			case a.Key == "Panic.Value":
+				s.Error = true
+			case a.Key == appdash.ErrorKey && string(a.Value) == "true":
+				s.Error = true
+			}
+		}
+
+		var events []appdash.Event
+		if err := appdash.UnmarshalEvents(span.Annotations, &events); err != nil {
+			return nil
+		}
+		for _, e := range events {
+			e, ok := e.(appdash.TimespanEvent)
+			if !ok {
+				continue
+			}
+			if s.Start.IsZero() || e.Start().Before(s.Start) {
+				s.Start = e.Start()
+			}
+			if e.End().After(end) {
+				end = e.End()
+			}
+		}
+		return nil
+	})
+	if !s.Start.IsZero() {
+		s.Duration = end.Sub(s.Start)
+	}
+	return s
+}
+
+// summariesByRecency sorts summaries by start time, most recent first,
+// breaking ties by trace ID.
+type summariesByRecency []*TraceSummary
+
+func (v summariesByRecency) Len() int { return len(v) }
+func (v summariesByRecency) Less(i, j int) bool {
+	if !v[i].Start.Equal(v[j].Start) {
+		return v[i].Start.After(v[j].Start)
+	}
+	return v[i].ID < v[j].ID
+}
+func (v summariesByRecency) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+
+// serveTraceList serves a page of trace summaries as JSON. The query
+// parameters start and end (RFC 3339 times), route, min (a duration such
+// as "250ms"), errors ("true" for errors only), offset and limit correspond
+// to the fields of TraceListOpts. Malformed parameters, and a negative
+// offset or limit, are rejected with 400 Bad Request.
+func (a *App) serveTraceList(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	var (
+		opts TraceListOpts
+		err  error
+	)
+	if s := q.Get("start"); s != "" {
+		if opts.Start, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid start: %s", err)}
+		}
+	}
+	if s := q.Get("end"); s != "" {
+		if opts.End, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid end: %s", err)}
+		}
+	}
+	opts.Route = q.Get("route")
+	if s := q.Get("min"); s != "" {
+		if opts.MinDuration, err = time.ParseDuration(s); err != nil {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid min: %s", err)}
+		}
+	}
+	if s := q.Get("errors"); s != "" {
+		if opts.ErrorsOnly, err = strconv.ParseBool(s); err != nil {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid errors: %s", err)}
+		}
+	}
+	if s := q.Get("offset"); s != "" {
+		if opts.Offset, err = strconv.Atoi(s); err != nil || opts.Offset < 0 {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid offset %q: must be a non-negative integer", s)}
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		if opts.Limit, err = strconv.Atoi(s); err != nil || opts.Limit < 0 {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid limit %q: must be a non-negative integer", s)}
+		}
+	}
+
+	list, err := ListTraces(a.Queryer, opts)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(list)
+}
@@ -0,0 +1,126 @@
+package traceapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestListTraces(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	base := time.Unix(1000, 0)
+	collect := func(trace appdash.ID, route string, status int, start, d time.Duration) {
+		rec := appdash.NewRecorder(appdash.SpanID{Trace: trace, Span: trace}, appdash.NewLocalCollector(ms))
+		rec.Name("Serve " + route)
+		rec.Event(appdash.Timespan{S: base.Add(start), E: base.Add(start + d)})
+		rec.Annotation(appdash.Annotation{Key: "Server.Route", Value: []byte(route)})
+		rec.Annotation(appdash.Annotation{Key: "Server.Response.StatusCode", Value: []byte(strconv.Itoa(status))})
+		child := rec.Child()
+		child.Event(appdash.Timespan{S: base.Add(start), E: base.Add(start + d/2)})
+		child.Finish()
+		rec.Finish()
+	}
+	for i := 1; i <= 10; i++ {
+		route, status := "a", 200
+		if i%2 == 0 {
+			route = "b"
+		}
+		if i%5 == 0 {
+			status = 500
+		}
+		collect(appdash.ID(i), route, status, time.Duration(i)*time.Second, time.Duration(i)*100*time.Millisecond)
+	}
+
+	ids := func(list *TraceList) []appdash.ID {
+		var ids []appdash.ID
+		for _, s := range list.Traces {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+	tests := []struct {
+		opts      TraceListOpts
+		wantIDs   []appdash.ID
+		wantTotal int
+	}{
+		{TraceListOpts{Limit: 3}, []appdash.ID{10, 9, 8}, 10},
+		{TraceListOpts{Offset: 3, Limit: 3}, []appdash.ID{7, 6, 5}, 10},
+		{TraceListOpts{Offset: 9, Limit: 3}, []appdash.ID{1}, 10},
+		{TraceListOpts{Offset: 20}, nil, 10},
+		{TraceListOpts{Route: "a"}, []appdash.ID{9, 7, 5, 3, 1}, 5},
+		{TraceListOpts{ErrorsOnly: true}, []appdash.ID{10, 5}, 2},
+		{TraceListOpts{MinDuration: 800 * time.Millisecond}, []appdash.ID{10, 9, 8}, 3},
+		{TraceListOpts{Start: base.Add(3 * time.Second), End: base.Add(5 * time.Second)}, []appdash.ID{5, 4, 3}, 3},
+		{TraceListOpts{Route: "b", ErrorsOnly: true}, []appdash.ID{10}, 1},
+	}
+	for _, test := range tests {
+		list, err := ListTraces(ms, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(list); !equalIDs(got, test.wantIDs) || list.Total != test.wantTotal {
+			t.Errorf("%+v: got %v (total %d), want %v (total %d)", test.opts, got, list.Total, test.wantIDs, test.wantTotal)
+		}
+	}
+
+	list, err := ListTraces(ms, TraceListOpts{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TraceSummary{
+		ID:       10,
+		Name:     "Serve b",
+		Route:    "b",
+		Start:    base.Add(10 * time.Second),
+		Duration: time.Second,
+		Status:   500,
+		Error:    true,
+		Spans:    2,
+	}
+	got := *list.Traces[0]
+	if got.Start.Equal(want.Start) {
+		got.Start = want.Start // ignore the time zone
+	}
+	if got != want {
+		t.Errorf("got summary %+v, want %+v", got, want)
+	}
+}
+
+func TestServeTraceList_badRequest(t *testing.T) {
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms := appdash.NewMemoryStore()
+	app.Store, app.Queryer = ms, ms
+
+	for _, q := range []string{"start=yesterday", "end=now", "min=slow", "errors=maybe", "offset=x", "offset=-1", "limit=x", "limit=-5"} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", "/traces/list?"+q, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", q, w.Code, http.StatusBadRequest)
+		}
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/traces/list?offset=0&limit=10", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("got status %d for a valid request, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func equalIDs(a, b []appdash.ID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package traceapp
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// noJS reports whether r asks for the no-JavaScript views of the trace and
+// traces pages, with a nojs query parameter such as ?nojs=1. They are
+// rendered entirely on the server, as plain HTML and CSS, for browsers that
+// cannot run scripts (hardened kiosks, HTML email) and for accessibility.
+func noJS(r *http.Request) bool {
+	v := r.URL.Query().Get("nojs")
+	return v != "" && v != "0"
+}
+
+// noJSTraceRow is a trace of the no-JavaScript traces page.
+type noJSTraceRow struct {
+	*TraceSummary
+	URL   string  // URL of the trace's no-JavaScript page
+	Width float64 // width of the trace's duration bar, in percent of the longest trace's
+}
+
+// serveTracesNoJS serves the no-JavaScript traces page, listing traces with
+// their durations as server-rendered bars, or the error in the search query.
+func (a *App) serveTracesNoJS(w http.ResponseWriter, status int, traces []*appdash.Trace, queryErr string) error {
+	rows := make([]*noJSTraceRow, len(traces))
+	var longest time.Duration
+	for i, t := range traces {
+		u, err := a.URLToTrace(t.Span.ID.Trace)
+		if err != nil {
+			return err
+		}
+		u.RawQuery = "nojs=1"
+		rows[i] = &noJSTraceRow{TraceSummary: summarizeTrace(t), URL: u.String()}
+		if rows[i].Duration > longest {
+			longest = rows[i].Duration
+		}
+	}
+	if longest > 0 {
+		for _, row := range rows {
+			row.Width = 100 * float64(row.Duration) / float64(longest)
+		}
+	}
+	return renderNoJS(w, "nojs-traces", status, &struct {
+		Traces     []*noJSTraceRow
+		QueryError string
+	}{
+		Traces:     rows,
+		QueryError: queryErr,
+	})
+}
+
+// serveTraceNoJS serves the no-JavaScript page of a trace (or sub-trace): its
+// span tree as a waterfall of server-positioned bars, as in trace exports,
+// with every span's annotations.
+func (a *App) serveTraceNoJS(w http.ResponseWriter, trace *appdash.Trace) error {
+	traces, err := a.Router.URLTo(TracesRoute)
+	if err != nil {
+		return err
+	}
+	traces.RawQuery = "nojs=1"
+	summary := summarizeTrace(trace)
+	return renderNoJS(w, "nojs-trace", http.StatusOK, &struct {
+		Summary   *TraceSummary
+		Root      *exportSpan
+		TracesURL string
+	}{
+		Summary:   summary,
+		Root:      exportTrace(trace, summary.Start, summary.Duration),
+		TracesURL: traces.String(),
+	})
+}
+
+// renderNoJS renders the named no-JavaScript template with data.
+func renderNoJS(w http.ResponseWriter, name string, status int, data interface{}) error {
+	var buf bytes.Buffer
+	if err := noJSTemplate.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// noJSTemplate holds the no-JavaScript pages. It extends exportTemplate, to
+// render span trees the same way.
+var noJSTemplate = template.Must(template.Must(exportTemplate.Clone()).Parse(`
+{{define "nojs-style"}}<style>
+body { font: 13px/1.4 -apple-system, "Helvetica Neue", Arial, sans-serif; margin: 20px; color: #333; }
+h1 { font-size: 18px; margin: 0 0 4px; }
+.meta { color: #777; margin-bottom: 12px; }
+details { margin-left: 16px; }
+details.root { margin-left: 0; }
+summary { padding: 2px 0; white-space: nowrap; }
+.name { display: inline-block; width: 320px; overflow: hidden; text-overflow: ellipsis; vertical-align: middle; }
+.dur { display: inline-block; width: 90px; text-align: right; color: #555; vertical-align: middle; }
+.lane { display: inline-block; position: relative; width: 40%; height: 10px; margin-left: 10px; background: #f0f0f0; vertical-align: middle; }
+.bar { position: absolute; top: 0; bottom: 0; min-width: 1px; background: #4a90d9; }
+.error .bar { background: #d9534f; }
+table { border-collapse: collapse; margin: 4px 0 8px 16px; }
+td, th { border: 1px solid #ddd; padding: 2px 6px; font-family: monospace; font-size: 12px; vertical-align: top; white-space: pre-wrap; word-break: break-all; }
+th { text-align: left; font-family: inherit; }
+td.key { color: #555; white-space: nowrap; }
+</style>{{end}}
+
+{{define "nojs-traces"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Traces - appdash</title>
+{{template "nojs-style"}}
+</head>
+<body>
+<h1>Traces</h1>
+{{with .QueryError}}<p class="error"><strong>{{.}}</strong></p>{{end}}
+<div class="meta">{{len .Traces}} traces</div>
+<table>
+<tr><th>Trace</th><th>Name</th><th>Started</th><th>Duration</th><th>Spans</th><th>Status</th></tr>
+{{range .Traces}}<tr{{if .Error}} class="error"{{end}}>
+<td><a href="{{.URL}}">{{.ID}}</a></td>
+<td>{{.Name}}</td>
+<td>{{if not .Start.IsZero}}{{.Start.UTC.Format "2006-01-02 15:04:05.000"}}{{end}}</td>
+<td><span class="dur">{{if not .Start.IsZero}}{{.Duration}}{{end}}</span><span class="lane"><span class="bar" style="left: 0; width: {{pct .Width}}"></span></span></td>
+<td>{{.Spans}}</td>
+<td>{{if .Status}}{{.Status}}{{end}}{{if .Error}} error{{end}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+{{end}}
+
+{{define "nojs-trace"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Trace {{.Summary.ID}}{{with .Summary.Name}} - {{.}}{{end}}</title>
+{{template "nojs-style"}}
+</head>
+<body>
+<p><a href="{{.TracesURL}}">&larr; Traces</a></p>
+<h1>{{with .Summary.Name}}{{.}}{{else}}Trace {{.Summary.ID}}{{end}}</h1>
+<div class="meta">
+  Trace {{.Summary.ID}} &middot; {{.Summary.Spans}} spans{{if not .Summary.Start.IsZero}} &middot; started {{.Summary.Start.UTC.Format "2006-01-02 15:04:05.000 MST"}} &middot; {{.Summary.Duration}}{{end}}
+</div>
+{{template "span" .Root}}
+</body>
+</html>
+{{end}}`))
@@ -0,0 +1,78 @@
+package traceapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestApp_noJS(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store, app.Queryer = ms, ms
+
+	// A root span with two children, one of which has a child of its own.
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := func(rec *appdash.Recorder, name string, from, to time.Duration) {
+		rec.Name(name)
+		rec.Event(appdash.Timespan{S: start.Add(from), E: start.Add(to)})
+	}
+	root := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, ms)
+	record(root, "checkout", 0, time.Second)
+	db := root.Child()
+	record(db, "db query", 0, 500*time.Millisecond)
+	api := root.Child()
+	record(api, "payment api", 500*time.Millisecond, time.Second)
+	retry := api.Child()
+	record(retry, "payment retry", 750*time.Millisecond, time.Second)
+	for _, rec := range []*appdash.Recorder{retry, api, db, root} {
+		rec.Finish()
+	}
+
+	get := func(path string) string {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got status %d: %s", path, w.Code, w.Body)
+		}
+		body := w.Body.String()
+		if strings.Contains(body, "<script") {
+			t.Errorf("%s: page contains a script", path)
+		}
+		return body
+	}
+
+	// The trace page renders the whole span tree, nested, with its bars
+	// positioned on the server.
+	body := get("/traces/0000000000000001?nojs=1")
+	if got := strings.Count(body, "<details"); got != 4 {
+		t.Errorf("got %d spans, want 4", got)
+	}
+	pos := func(s string) int {
+		i := strings.Index(body, s)
+		if i < 0 {
+			t.Fatalf("trace page does not contain %q", s)
+		}
+		return i
+	}
+	if !(pos(">checkout<") < pos(">db query<") && pos(">db query<") < pos(">payment api<") && pos(">payment api<") < pos(">payment retry<")) {
+		t.Error("spans are not in tree order")
+	}
+	if payment := body[pos(">payment api<"):]; !strings.Contains(payment[:strings.Index(payment, "</details>")], ">payment retry<") {
+		t.Error("payment retry is not nested in payment api")
+	}
+	pos(`style="left: 75.000%; width: 25.000%; background: ` + ColorFor("payment retry") + `"`)
+
+	// The traces page links to the trace's no-JS page.
+	if body := get("/traces?nojs=1"); !strings.Contains(body, `href="/traces/0000000000000001?nojs=1"`) {
+		t.Errorf("traces page does not link to the no-JS trace page:\n%s", body)
+	}
+}
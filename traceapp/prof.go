@@ -13,7 +13,7 @@ import (
 type profile struct {
 	Name                        string
 	URL                         string
-	Time, TimeChildren, TimeCum int64
+	Time, TimeSelf, TimeChildren, TimeCum int64
 }
 
 // calcProfile calculates a profile for the given trace and appends it to the
@@ -66,6 +66,11 @@ func (a *App) calcProfile(buf []*profile, t *appdash.Trace) (prof []*profile, ch
 		}
 	}
 
+	// TimeSelf is our time excluding the time our children were running.
+	if self, ok := t.SelfTime(); ok {
+		p.TimeSelf = int64(float64(self)/float64(time.Millisecond) + 0.5)
+	}
+
 	// TimeChildren is our time + the children's time.
 	p.TimeChildren = p.Time
 
@@ -0,0 +1,89 @@
+package traceapp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+var (
+	protoTypes   = map[string]reflect.Type{} // type URL -> message type
+	protoTypesMu sync.RWMutex
+)
+
+// RegisterProtoType registers the message type of m, so that the trace
+// view can decode and pretty-print payloads of that type recorded with
+// appdash.ProtobufAnnotations. Types registered with proto.RegisterType
+// (as code generated by gogo/protobuf does) are decoded without being
+// registered here.
+func RegisterProtoType(m proto.Message) {
+	protoTypesMu.Lock()
+	protoTypes[appdash.ProtobufTypeURL(m)] = reflect.TypeOf(m)
+	protoTypesMu.Unlock()
+}
+
+// protoType returns the registered message type with the given type URL, or
+// nil if there is none.
+func protoType(typeURL string) reflect.Type {
+	protoTypesMu.RLock()
+	t := protoTypes[typeURL]
+	protoTypesMu.RUnlock()
+	if t != nil {
+		return t
+	}
+	if i := strings.LastIndex(typeURL, "/"); i >= 0 {
+		return proto.MessageType(typeURL[i+1:])
+	}
+	return nil
+}
+
+// protobufPayload is a protocol buffer payload of a span, formatted for
+// display.
+type protobufPayload struct {
+	Name, TypeURL string
+	Text          string // the decoded message, or a hexdump
+	Decoded       bool   // whether Text is the decoded message
+}
+
+// protobufPayloads formats the protocol buffer payloads of the span. A
+// payload is decoded if its type is registered (see RegisterProtoType) and
+// it was not truncated; otherwise it is shown as a hexdump.
+func protobufPayloads(span appdash.Span) []protobufPayload {
+	var payloads []protobufPayload
+	for _, p := range span.ProtobufPayloads() {
+		fp := protobufPayload{Name: p.Name, TypeURL: p.TypeURL}
+		if text, err := decodeProtobuf(p); err == nil {
+			fp.Text, fp.Decoded = text, true
+		} else {
+			fp.Text = hex.Dump(p.Data)
+		}
+		payloads = append(payloads, fp)
+	}
+	return payloads
+}
+
+// decodeProtobuf decodes p and returns it in the protocol buffer text
+// format.
+func decodeProtobuf(p appdash.ProtobufPayload) (string, error) {
+	if p.Truncated {
+		return "", fmt.Errorf("payload %s is truncated", p.Name)
+	}
+	t := protoType(p.TypeURL)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return "", fmt.Errorf("unknown message type %s", p.TypeURL)
+	}
+	m, ok := reflect.New(t.Elem()).Interface().(proto.Message)
+	if !ok {
+		return "", fmt.Errorf("type %s is not a message", t)
+	}
+	if err := proto.Unmarshal(p.Data, m); err != nil {
+		return "", err
+	}
+	return proto.MarshalTextString(m), nil
+}
@@ -0,0 +1,76 @@
+package traceapp
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+
+	"sourcegraph.com/sourcegraph/appdash"
+	"sourcegraph.com/sourcegraph/appdash/internal/wire"
+)
+
+func TestProtobufPayloads(t *testing.T) {
+	msg := &wire.CollectPacket{
+		Spanid: &wire.CollectPacket_SpanID{Trace: proto.Uint64(1), Span: proto.Uint64(2), Parent: proto.Uint64(0)},
+		Annotation: []*wire.CollectPacket_Annotation{
+			{Key: proto.String("Name"), Value: []byte("hello")},
+		},
+	}
+	big := &wire.CollectPacket{
+		Spanid:     msg.Spanid,
+		Annotation: []*wire.CollectPacket_Annotation{{Key: proto.String("Big"), Value: make([]byte, 100)}},
+	}
+
+	ms := appdash.NewMemoryStore()
+	rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 1}, appdash.NewLocalCollector(ms))
+	rec.Protobuf("Request", msg)
+	defer func(max int) { appdash.MaxProtobufPayloadSize = max }(appdash.MaxProtobufPayloadSize)
+	appdash.MaxProtobufPayloadSize = 50
+	rec.Protobuf("Response", big)
+	rec.Finish()
+	if errs := rec.Errors(); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unregistered types fall back to a hexdump.
+	data, _ := proto.Marshal(msg)
+	payloads := protobufPayloads(trace.Span)
+	if len(payloads) != 2 {
+		t.Fatalf("got payloads %+v, want 2", payloads)
+	}
+	if got, want := payloads[0], (protobufPayload{Name: "Request", TypeURL: "type.googleapis.com/wire.CollectPacket", Text: hex.Dump(data)}); got != want {
+		t.Errorf("got unregistered payload %+v, want %+v", got, want)
+	}
+
+	RegisterProtoType(&wire.CollectPacket{})
+	defer func() {
+		protoTypesMu.Lock()
+		delete(protoTypes, appdash.ProtobufTypeURL(&wire.CollectPacket{}))
+		protoTypesMu.Unlock()
+	}()
+	payloads = protobufPayloads(trace.Span)
+	if got := payloads[0]; !got.Decoded || got.Text != proto.MarshalTextString(msg) {
+		t.Errorf("got registered payload %+v, want it decoded", got)
+	}
+	if !strings.Contains(payloads[0].Text, `key: "Name"`) {
+		t.Errorf("got decoded payload %q, want it to contain the annotation key", payloads[0].Text)
+	}
+
+	// Truncated payloads cannot be decoded.
+	if got := payloads[1]; got.Decoded || strings.Count(got.Text, "\n") != 4 {
+		t.Errorf("got truncated payload %+v, want a hexdump of 50 bytes", got)
+	}
+
+	// The raw payloads are hidden from the annotation table.
+	for _, a := range filterAnnotations(trace.Span.Annotations) {
+		if strings.HasSuffix(a.Key, ".Payload") {
+			t.Errorf("got raw payload annotation %q, want it hidden", a.Key)
+		}
+	}
+}
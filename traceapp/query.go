@@ -0,0 +1,233 @@
+package traceapp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// queryKeys maps the short keys accepted by ParseQuery to the annotation
+// keys they stand for.
+var queryKeys = map[string]string{
+	"route":      "Server.Route",
+	"status":     "Server.Response.StatusCode",
+	"method":     "Server.Request.Method",
+	"user":       "Server.User",
+	"name":       "Name",
+	"service":    appdash.ServiceKey,
+	"env":        appdash.EnvironmentKey,
+	"revision":   "Build.Revision",
+	"version":    "Build.Version",
+	"entrypoint": appdash.EntrypointKey,
+}
+
+// ParseQuery parses a search query, as typed in the traces page's search
+// box, into the filters that a trace must match to be shown. The grammar
+// is:
+//
+// 	query = { term } .
+// 	term  = key ":" value   // equality, e.g. route:/users
+// 	      | key ">" value   // comparison, e.g. duration>500ms
+// 	      | key "<" value
+// 	      | value .         // free text, e.g. timeout
+//
+// Terms are separated by whitespace, and a trace must match all of them.
+// Values may be double-quoted to include whitespace or the operators
+// ("a b", with \" for a quote), and a quoted term is always free text.
+//
+// A key is either an annotation key (such as Server.Request.URI) or one of
+// the short keys route, status, method, user, name, service, env, revision,
+// version and entrypoint, which stand for the annotations recorded by
+// httptrace, the Recorder, appdash.EnvironmentCollector and
+// appdash.BuildCollector; entrypoint:1 matches the traces started by an
+// external request. A trace matches if any of its spans has a matching
+// annotation. Comparison values are numbers or durations (such as 500ms);
+// durations are compared in nanoseconds, as MarshalEvent records them. The
+// special key duration compares the duration of the whole trace instead.
+// Free text matches traces with an annotation value that contains it,
+// ignoring case.
+func ParseQuery(query string) ([]appdash.QueryFilter, error) {
+	terms, err := splitQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	filters := make([]appdash.QueryFilter, 0, len(terms))
+	for _, term := range terms {
+		f, err := parseQueryTerm(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query term %q: %s", term.text, err)
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// A queryTerm is a term of a query, split into its key, operator and
+// value. Free-text terms have no key or operator.
+type queryTerm struct {
+	text       string // the term as written, for error messages
+	key, value string
+	op         byte // ':', '>', '<' or 0 for free text
+}
+
+// splitQuery splits a query into its terms.
+func splitQuery(query string) ([]queryTerm, error) {
+	var terms []queryTerm
+	rs := []rune(query)
+	for i := 0; i < len(rs); {
+		if unicode.IsSpace(rs[i]) {
+			i++
+			continue
+		}
+		start := i
+		var t queryTerm
+		var buf bytes.Buffer
+		quoted := false
+		for ; i < len(rs) && !unicode.IsSpace(rs[i]); i++ {
+			switch r := rs[i]; {
+			case r == '"':
+				if buf.Len() == 0 && t.op == 0 {
+					quoted = true
+				}
+				end, s, err := unquote(rs, i)
+				if err != nil {
+					return nil, err
+				}
+				buf.WriteString(s)
+				i = end
+			case (r == ':' || r == '>' || r == '<') && t.op == 0 && !quoted:
+				if buf.Len() == 0 {
+					return nil, fmt.Errorf("invalid query term %q: missing key before %q", string(rs[start:]), r)
+				}
+				t.key, t.op = buf.String(), byte(r)
+				buf.Reset()
+			default:
+				buf.WriteRune(r)
+			}
+		}
+		t.text, t.value = string(rs[start:i]), buf.String()
+		if t.op != 0 && t.value == "" {
+			return nil, fmt.Errorf("invalid query term %q: missing value after %q", t.text, t.op)
+		}
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
+
+// unquote reads the double-quoted string starting at rs[i], returning the
+// index of its closing quote and its contents.
+func unquote(rs []rune, i int) (int, string, error) {
+	var buf bytes.Buffer
+	for j := i + 1; j < len(rs); j++ {
+		switch rs[j] {
+		case '\\':
+			if j+1 < len(rs) {
+				j++
+			}
+			buf.WriteRune(rs[j])
+		case '"':
+			return j, buf.String(), nil
+		default:
+			buf.WriteRune(rs[j])
+		}
+	}
+	return 0, "", fmt.Errorf("invalid query: unterminated quote in %q", string(rs[i:]))
+}
+
+// parseQueryTerm returns the filter for a single term.
+func parseQueryTerm(t queryTerm) (appdash.QueryFilter, error) {
+	if t.op == 0 {
+		text := strings.ToLower(t.value)
+		return anySpan(func(span *appdash.Span) bool {
+			for _, a := range span.Annotations {
+				if strings.Contains(strings.ToLower(string(a.Value)), text) {
+					return true
+				}
+			}
+			return false
+		}), nil
+	}
+
+	key := t.key
+	if k, ok := queryKeys[strings.ToLower(key)]; ok {
+		key = k
+	}
+	if t.op == ':' {
+		if strings.EqualFold(t.key, "duration") {
+			return nil, errors.New("duration can only be compared with > or <")
+		}
+		return appdash.AnnotationFilter(key, t.value), nil
+	}
+
+	v, err := parseQueryNumber(t.value)
+	if err != nil {
+		return nil, err
+	}
+	cmp := func(x float64) bool { return x > v }
+	if t.op == '<' {
+		cmp = func(x float64) bool { return x < v }
+	}
+	if strings.EqualFold(t.key, "duration") {
+		return func(tr *appdash.Trace) bool {
+			s := summarizeTrace(tr)
+			return !s.Start.IsZero() && cmp(float64(s.Duration))
+		}, nil
+	}
+	return anySpan(func(span *appdash.Span) bool {
+		for _, a := range span.Annotations {
+			if a.Key != key {
+				continue
+			}
+			if x, err := parseQueryNumber(string(a.Value)); err == nil && cmp(x) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// parseQueryNumber parses a number or, in nanoseconds, a duration.
+func parseQueryNumber(s string) (float64, error) {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return float64(d), nil
+	}
+	return 0, fmt.Errorf("%q is not a number or duration", s)
+}
+
+// anySpan returns a filter matching the traces with a span that matches.
+func anySpan(match func(*appdash.Span) bool) appdash.QueryFilter {
+	return func(t *appdash.Trace) bool {
+		errFound := errors.New("found")
+		return t.Walk(func(span *appdash.Span, depth int) error {
+			if match(span) {
+				return errFound
+			}
+			return nil
+		}) == errFound
+	}
+}
+
+// filterTraces returns the traces that match all of the filters. It
+// modifies traces in place.
+func filterTraces(traces []*appdash.Trace, filters []appdash.QueryFilter) []*appdash.Trace {
+	filtered := traces[:0]
+outer:
+	for _, t := range traces {
+		for _, f := range filters {
+			if !f(t) {
+				continue outer
+			}
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered
+}
@@ -0,0 +1,103 @@
+package traceapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestParseQuery(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	base := time.Unix(1000, 0)
+	collect := func(trace appdash.ID, route string, status int, d time.Duration, msg string) {
+		rec := appdash.NewRecorder(appdash.SpanID{Trace: trace, Span: trace}, appdash.NewLocalCollector(ms))
+		rec.Name("Serve " + route)
+		rec.Event(appdash.Timespan{S: base, E: base.Add(d)})
+		rec.Annotation(appdash.Annotation{Key: "Server.Route", Value: []byte(route)})
+		rec.Annotation(appdash.Annotation{Key: "Server.Response.StatusCode", Value: []byte(strconv.Itoa(status))})
+		child := rec.Child()
+		child.Msg(msg)
+		child.Finish()
+		rec.Finish()
+	}
+	collect(1, "/users", 200, 100*time.Millisecond, "cache hit")
+	collect(2, "/users", 500, 800*time.Millisecond, "Upstream timeout")
+	collect(3, "/repos", 500, 2*time.Second, "database timeout")
+	collect(4, "/repos", 404, 10*time.Millisecond, "not found")
+
+	tests := []struct {
+		query string
+		want  []appdash.ID
+	}{
+		{"", []appdash.ID{1, 2, 3, 4}},
+		{"route:/users", []appdash.ID{1, 2}},
+		{"status:500", []appdash.ID{2, 3}},
+		{"Server.Response.StatusCode:404", []appdash.ID{4}},
+		{"status>499", []appdash.ID{2, 3}},
+		{"status<300", []appdash.ID{1}},
+		{"duration>500ms", []appdash.ID{2, 3}},
+		{"duration<0.5s", []appdash.ID{1, 4}},
+		{"timeout", []appdash.ID{2, 3}},
+		{`"upstream timeout"`, []appdash.ID{2}},
+		{`name:"Serve /repos"`, []appdash.ID{3, 4}},
+		{"  route:/users   status:500 duration>500ms ", []appdash.ID{2}},
+		{"route:/nope", nil},
+	}
+	for _, test := range tests {
+		filters, err := ParseQuery(test.query)
+		if err != nil {
+			t.Errorf("%q: %s", test.query, err)
+			continue
+		}
+		traces, err := ms.Traces(appdash.TracesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []appdash.ID
+		for _, tr := range filterTraces(traces, filters) {
+			got = append(got, tr.ID.Trace)
+		}
+		sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got traces %v, want %v", test.query, got, test.want)
+		}
+	}
+
+	for _, query := range []string{
+		"status>high",
+		"duration:1s",
+		":500",
+		"route:",
+		`route:"/users`,
+	} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("%q: got no error", query)
+		}
+	}
+}
+
+func TestServeTraces_query(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store, app.Queryer = ms, ms
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/traces?q="+url.QueryEscape("duration>fast"), nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "is not a number or duration") {
+		t.Errorf("got page without the query error: %s", body)
+	}
+}
@@ -10,17 +10,24 @@ import (
 
 // Traceapp's route names.
 const (
-	RootRoute             = "traceapp.root"               // route name for root
-	StaticRoute           = "traceapp.static"             // route name for static data files
-	TraceRoute            = "traceapp.trace"              // route name for a single trace page
-	TraceSpanRoute        = "traceapp.trace.span"         // route name for a single trace sub-span page
-	TraceProfileRoute     = "traceapp.trace.profile"      // route name for a JSON trace profile
-	TraceSpanProfileRoute = "traceapp.trace.span.profile" // route name for a JSON trace sub-span profile
-	TraceUploadRoute      = "traceapp.trace.upload"       // route name for a JSON trace upload
-	TracesRoute           = "traceapp.traces"             // route name for traces page
-	DashboardRoute        = "traceapp.dashboard"          // route name for dashboard page
-	DashboardDataRoute    = "traceapp.dashboard.data"     // route name for dashboard JSON data
-	AggregateRoute        = "traceapp.aggregate"          // route name for aggregate trace view
+	RootRoute                = "traceapp.root"                  // route name for root
+	StaticRoute              = "traceapp.static"                // route name for static data files
+	TraceRoute               = "traceapp.trace"                 // route name for a single trace page
+	TraceSpanRoute           = "traceapp.trace.span"            // route name for a single trace sub-span page
+	TraceProfileRoute        = "traceapp.trace.profile"         // route name for a JSON trace profile
+	TraceSpanProfileRoute    = "traceapp.trace.span.profile"    // route name for a JSON trace sub-span profile
+	TraceFlamegraphRoute     = "traceapp.trace.flamegraph"      // route name for a JSON trace flamegraph
+	TraceSpanFlamegraphRoute = "traceapp.trace.span.flamegraph" // route name for a JSON trace sub-span flamegraph
+	TraceExportRoute         = "traceapp.trace.export"          // route name for a standalone HTML trace export
+	TraceUploadRoute         = "traceapp.trace.upload"          // route name for a JSON trace upload
+	IngestRoute              = "traceapp.ingest"                // route name for ingesting a batch of JSON spans
+	TracesRoute              = "traceapp.traces"                // route name for traces page
+	TraceListRoute           = "traceapp.traces.list"           // route name for a JSON page of trace summaries
+	TraceTimelineRoute       = "traceapp.traces.timeline"       // route name for a JSON timeline of trace counts
+	DashboardRoute           = "traceapp.dashboard"             // route name for dashboard page
+	DashboardDataRoute       = "traceapp.dashboard.data"        // route name for dashboard JSON data
+	AggregateRoute           = "traceapp.aggregate"             // route name for aggregate trace view
+	SamplingAuditRoute       = "traceapp.sampling.audit"        // route name for JSON sampling decisions
 )
 
 // Router is a URL router for traceapp applications. It should be created via
@@ -34,15 +41,22 @@ func NewRouter(base *mux.Router) *Router {
 	}
 	base.Path("/").Methods("GET").Name(RootRoute)
 	base.PathPrefix("/static/").Methods("GET").Name(StaticRoute)
+	base.Path("/traces/list").Methods("GET").Name(TraceListRoute)
+	base.Path("/traces/timeline").Methods("GET").Name(TraceTimelineRoute)
 	base.Path("/traces/{Trace}").Methods("GET").Name(TraceRoute)
 	base.Path("/traces/{Trace}/profile").Methods("GET").Name(TraceProfileRoute)
 	base.Path("/traces/{Trace}/{Span}/profile").Methods("GET").Name(TraceSpanProfileRoute)
+	base.Path("/traces/{Trace}/flamegraph").Methods("GET").Name(TraceFlamegraphRoute)
+	base.Path("/traces/{Trace}/{Span}/flamegraph").Methods("GET").Name(TraceSpanFlamegraphRoute)
+	base.Path("/traces/{Trace}/export.html").Methods("GET").Name(TraceExportRoute)
 	base.Path("/traces/upload").Methods("POST").Name(TraceUploadRoute)
+	base.Path("/ingest").Methods("POST").Name(IngestRoute)
 	base.Path("/traces/{Trace}/{Span}").Methods("GET").Name(TraceSpanRoute)
 	base.Path("/traces").Methods("GET").Name(TracesRoute)
 	base.Path("/dashboard").Methods("GET").Name(DashboardRoute)
 	base.Path("/dashboard/data").Methods("GET").Name(DashboardDataRoute)
 	base.Path("/aggregate").Methods("GET").Name(AggregateRoute)
+	base.Path("/sampling/audit").Methods("GET").Name(SamplingAuditRoute)
 	return &Router{base}
 }
 
@@ -75,3 +89,20 @@ func (r *Router) URLToTraceProfile(trace appdash.ID) (*url.URL, error) {
 func (r *Router) URLToTraceSpanProfile(trace, span appdash.ID) (*url.URL, error) {
 	return r.r.Get(TraceSpanProfileRoute).URL("Trace", trace.String(), "Span", span.String())
 }
+
+// URLToTraceExport constructs a URL to a given trace's standalone HTML
+// export.
+func (r *Router) URLToTraceExport(trace appdash.ID) (*url.URL, error) {
+	return r.r.Get(TraceExportRoute).URL("Trace", trace.String())
+}
+
+// URLToTraceFlamegraph constructs a URL to a given trace's JSON flamegraph.
+func (r *Router) URLToTraceFlamegraph(trace appdash.ID) (*url.URL, error) {
+	return r.r.Get(TraceFlamegraphRoute).URL("Trace", trace.String())
+}
+
+// URLToTraceSpanFlamegraph constructs a URL to a sub-span's JSON flamegraph
+// in a trace.
+func (r *Router) URLToTraceSpanFlamegraph(trace, span appdash.ID) (*url.URL, error) {
+	return r.r.Get(TraceSpanFlamegraphRoute).URL("Trace", trace.String(), "Span", span.String())
+}
@@ -0,0 +1,35 @@
+package traceapp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+var errNoSamplingAudit = errors.New("traceapp: sampling decisions are not audited (App.SamplingAudit is nil)")
+
+// serveSamplingAudit serves the sampling decisions retained by
+// a.SamplingAudit as JSON, oldest first, or only those about the trace
+// given by the trace query parameter.
+func (a *App) serveSamplingAudit(w http.ResponseWriter, r *http.Request) error {
+	if a.SamplingAudit == nil {
+		return errNoSamplingAudit
+	}
+	var decisions []appdash.SamplingDecision
+	if s := r.URL.Query().Get("trace"); s != "" {
+		trace, err := appdash.ParseID(s)
+		if err != nil {
+			return err
+		}
+		decisions = a.SamplingAudit.TraceDecisions(trace)
+	} else {
+		decisions = a.SamplingAudit.Decisions()
+	}
+	if decisions == nil {
+		decisions = []appdash.SamplingDecision{} // encode as [], not null
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(decisions)
+}
@@ -0,0 +1,55 @@
+package traceapp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestApp_samplingAudit(t *testing.T) {
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/sampling/audit", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("without an audit: got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	app.SamplingAudit = appdash.NewSamplingAudit(10)
+	s := app.SamplingAudit.Sampler("half", appdash.ProbabilitySampler(0.5))
+	for trace := appdash.ID(1); trace <= 4; trace++ {
+		s.ShouldSample(appdash.SpanID{Trace: trace, Span: 1}, nil)
+	}
+
+	tests := map[string][]appdash.ID{
+		"/sampling/audit":                        {1, 2, 3, 4},
+		"/sampling/audit?trace=0000000000000003": {3},
+		"/sampling/audit?trace=0000000000000009": {},
+	}
+	for path, wantTraces := range tests {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got status %d", path, w.Code)
+		}
+		var got []appdash.SamplingDecision
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if len(got) != len(wantTraces) {
+			t.Fatalf("%s: got %d decisions, want %d", path, len(got), len(wantTraces))
+		}
+		for i, d := range got {
+			want := appdash.ProbabilitySampler(0.5).ShouldSample(d.Span, nil)
+			if d.Span.Trace != wantTraces[i] || d.Sampler != "half" || d.Sampled != want {
+				t.Errorf("%s: got decision %+v, want trace %v sampled=%v by half", path, d, wantTraces[i], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package traceapp
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"sourcegraph.com/sourcegraph/appdash"
+	"sourcegraph.com/sourcegraph/appdash/sqltrace"
+)
+
+// sqlPlanKey is the key of the plan of a sqltrace.PlanEvent, which is shown
+// formatted (see sqlPlan) rather than as a raw annotation.
+const sqlPlanKey = "SQL.Plan.Text"
+
+// sqlPlan returns the SQL execution plan attached to the span (see
+// sqltrace.Explainer), formatted for display according to its content
+// type, or nil if there is none.
+func sqlPlan(span appdash.Span) *sqltrace.PlanEvent {
+	if _, ok := span.Annotations.Get(sqlPlanKey); !ok {
+		return nil
+	}
+	var e sqltrace.PlanEvent
+	if err := appdash.UnmarshalEvent(span.Annotations, &e); err != nil {
+		return nil
+	}
+	if e.ContentType == "application/json" && !e.Truncated {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(e.Plan), "", "  "); err == nil {
+			e.Plan = buf.String()
+		}
+	}
+	return &e
+}
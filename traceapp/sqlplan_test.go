@@ -0,0 +1,38 @@
+package traceapp
+
+import (
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+	"sourcegraph.com/sourcegraph/appdash/sqltrace"
+)
+
+func TestSQLPlan(t *testing.T) {
+	if p := sqlPlan(appdash.Span{}); p != nil {
+		t.Errorf("got plan %+v for a span without one, want nil", p)
+	}
+
+	as, err := appdash.MarshalEvent(sqltrace.PlanEvent{Plan: `[{"Plan":{"Node Type":"Seq Scan"}}]`, ContentType: "application/json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := sqlPlan(appdash.Span{Annotations: as})
+	if p == nil {
+		t.Fatal("got no plan")
+	}
+	want := `[
+  {
+    "Plan": {
+      "Node Type": "Seq Scan"
+    }
+  }
+]`
+	if p.Plan != want {
+		t.Errorf("got plan %q, want it indented as %q", p.Plan, want)
+	}
+	for _, a := range filterAnnotations(as) {
+		if a.Key == sqlPlanKey {
+			t.Errorf("got raw plan annotation %q among the annotations shown", a.Key)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package traceapp
+
+import (
+	"errors"
+	"net/url"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// A StorageProvider is the storage backend that the web UI reads traces
+// from. It is all that the trace and traces pages require, so any store
+// that can look up and list traces, such as an appdash.MemoryStore, an
+// appdash.InfluxDBStore or a client of a remote store, can drive the UI.
+//
+// The UI uses further capabilities if the provider has them:
+//
+// 	appdash.Collector               importing traces (Import JSON, permalinks)
+// 	appdash.Aggregator              the dashboard
+// 	IndexedValues(string) []string  listing the services and environments to filter by
+//
+// Pages that need a capability the provider lacks respond with 501 Not
+// Implemented. Without IndexedValues, the names are gathered from the
+// traces instead.
+type StorageProvider interface {
+	// Trace returns the trace with the given ID, or
+	// appdash.ErrTraceNotFound if there is none.
+	Trace(appdash.ID) (*appdash.Trace, error)
+
+	// Traces lists the traces matching opts. The UI filters by
+	// TraceIDs, Timespan, CorrelationID, Service and Environment, and asks
+	// for SortByRecency; a provider that ignores a filter shows more
+	// traces than asked for, but is otherwise usable.
+	appdash.Queryer
+}
+
+var (
+	errReadOnlyStorage = errors.New("traceapp: storage does not support importing traces (it does not implement appdash.Collector)")
+	errNoAggregator    = errors.New("traceapp: storage does not support the dashboard (it does not implement appdash.Aggregator)")
+)
+
+// NewWithStorage is like New, but also sets the app's Store, Queryer and
+// Aggregator from p, according to the capabilities it has (see
+// StorageProvider).
+func NewWithStorage(r *Router, base *url.URL, p StorageProvider) (*App, error) {
+	app, err := New(r, base)
+	if err != nil {
+		return nil, err
+	}
+	app.Queryer = p
+	if s, ok := p.(appdash.Store); ok {
+		app.Store = s
+	} else {
+		app.Store = readOnlyStore{p}
+	}
+	if agg, ok := p.(appdash.Aggregator); ok {
+		app.Aggregator = agg
+	}
+	return app, nil
+}
+
+// readOnlyStore adapts a StorageProvider that cannot collect spans to the
+// appdash.Store interface.
+type readOnlyStore struct {
+	StorageProvider
+}
+
+// Collect implements the appdash.Collector interface by returning
+// errReadOnlyStorage.
+func (readOnlyStore) Collect(appdash.SpanID, ...appdash.Annotation) error {
+	return errReadOnlyStorage
+}
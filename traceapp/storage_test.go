@@ -0,0 +1,80 @@
+package traceapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// minimalStorage implements only the methods of StorageProvider.
+type minimalStorage struct {
+	traces []*appdash.Trace
+}
+
+func (s *minimalStorage) Trace(id appdash.ID) (*appdash.Trace, error) {
+	for _, t := range s.traces {
+		if t.ID.Trace == id {
+			return t, nil
+		}
+	}
+	return nil, appdash.ErrTraceNotFound
+}
+
+func (s *minimalStorage) Traces(opts appdash.TracesOpts) ([]*appdash.Trace, error) {
+	return s.traces, nil
+}
+
+func TestNewWithStorage(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	rec := appdash.NewRecorder(appdash.SpanID{Trace: 1, Span: 2}, ms)
+	rec.Name("checkout")
+	rec.Finish()
+	trace, err := ms.Trace(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app, err := NewWithStorage(nil, &url.URL{Scheme: "http", Host: "example.com"}, &minimalStorage{traces: []*appdash.Trace{trace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method, path string
+		body         string
+		wantStatus   int
+		wantBody     string
+	}{
+		{"GET", "/traces", "", http.StatusOK, "0000000000000001"},
+		{"GET", "/traces/0000000000000001", "", http.StatusOK, "checkout"},
+		{"GET", "/traces/0000000000000003", "", http.StatusNotFound, ""},
+		{"GET", "/traces/list", "", http.StatusOK, "checkout"},
+
+		// The storage cannot collect or aggregate.
+		{"POST", "/traces/upload", `[{"Span":{"ID":{"Trace":"0000000000000005","Span":"0000000000000006","Parent":"0000000000000000"}}}]`, http.StatusNotImplemented, ""},
+		{"GET", "/dashboard/data", "", http.StatusNotImplemented, ""},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+		if w.Code != test.wantStatus {
+			t.Errorf("%s %s: got status %d, want %d: %s", test.method, test.path, w.Code, test.wantStatus, w.Body)
+			continue
+		}
+		if !strings.Contains(w.Body.String(), test.wantBody) {
+			t.Errorf("%s %s: response does not contain %q", test.method, test.path, test.wantBody)
+		}
+	}
+
+	// A full store provides every capability.
+	app, err = NewWithStorage(nil, &url.URL{Scheme: "http", Host: "example.com"}, ms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.Store != appdash.Store(ms) {
+		t.Errorf("got Store %T, want the MemoryStore itself", app.Store)
+	}
+}
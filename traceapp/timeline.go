@@ -0,0 +1,140 @@
+package traceapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+// maxTimelineBuckets is the maximum number of buckets of a Timeline, so
+// that a tiny bucket size cannot make a request allocate without bound.
+const maxTimelineBuckets = 10000
+
+// TimelineOpts specifies the time window and bucket size of a Timeline.
+type TimelineOpts struct {
+	// Start and End are the time window. Traces that started at or after
+	// Start and before End are counted.
+	Start, End time.Time
+
+	// Bucket is the length of each bucket of the window.
+	Bucket time.Duration
+}
+
+// A TimelineBucket counts the traces that started within one bucket of a
+// Timeline.
+type TimelineBucket struct {
+	Start  time.Time // start of the bucket
+	Count  int       // number of traces that started in the bucket
+	Errors int       // number of those traces with errors (see TraceSummary.Error)
+}
+
+// A Timeline is a time series of the number of traces, and of traces with
+// errors, in a time window, as returned by TraceTimeline.
+type Timeline struct {
+	Start, End time.Time
+	Bucket     time.Duration
+
+	// Buckets are the consecutive buckets of the window, earliest first,
+	// including empty ones. The last bucket may extend past End.
+	Buckets []*TimelineBucket
+}
+
+// buckets returns the number of buckets of the window of opts, or an error
+// if opts are invalid.
+func (opts TimelineOpts) buckets() (int, error) {
+	if opts.Bucket <= 0 {
+		return 0, errors.New("timeline bucket size must be positive")
+	}
+	if !opts.End.After(opts.Start) {
+		return 0, errors.New("timeline must end after it starts")
+	}
+	n := opts.End.Sub(opts.Start) / opts.Bucket
+	if opts.End.Sub(opts.Start)%opts.Bucket != 0 {
+		n++
+	}
+	if n > maxTimelineBuckets {
+		return 0, fmt.Errorf("timeline has %d buckets, more than the maximum of %d", n, maxTimelineBuckets)
+	}
+	return int(n), nil
+}
+
+// TraceTimeline counts the traces in q that started within the window of
+// opts, in buckets of opts.Bucket. Only the traces in the window are
+// queried (see appdash.TracesOpts.Timespan), and they are summarized and
+// counted one at a time, keeping only the counts of each bucket, so that
+// the cost in memory of a timeline doesn't grow with the number of traces
+// stored outside of it.
+func TraceTimeline(q appdash.Queryer, opts TimelineOpts) (*Timeline, error) {
+	n, err := opts.buckets()
+	if err != nil {
+		return nil, err
+	}
+
+	tl := &Timeline{
+		Start:   opts.Start,
+		End:     opts.End,
+		Bucket:  opts.Bucket,
+		Buckets: make([]*TimelineBucket, n),
+	}
+	for i := range tl.Buckets {
+		tl.Buckets[i] = &TimelineBucket{Start: opts.Start.Add(time.Duration(i) * opts.Bucket)}
+	}
+
+	traces, err := q.Traces(appdash.TracesOpts{Timespan: appdash.Timespan{S: opts.Start, E: opts.End}})
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range traces {
+		traces[i] = nil // let the trace be freed once counted
+		s := summarizeTrace(t)
+		if s.Start.IsZero() || s.Start.Before(opts.Start) || !s.Start.Before(opts.End) {
+			continue
+		}
+		b := tl.Buckets[s.Start.Sub(opts.Start)/opts.Bucket]
+		b.Count++
+		if s.Error {
+			b.Errors++
+		}
+	}
+	return tl, nil
+}
+
+// serveTraceTimeline serves a timeline of the number of traces and errors
+// as JSON. The query parameters start and end (RFC 3339 times) and bucket
+// (a duration such as "1m") correspond to the fields of TimelineOpts, and
+// default to the last hour in 1-minute buckets.
+func (a *App) serveTraceTimeline(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	opts := TimelineOpts{End: time.Now(), Bucket: time.Minute}
+	var err error
+	if s := q.Get("end"); s != "" {
+		if opts.End, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid end: %s", err)}
+		}
+	}
+	opts.Start = opts.End.Add(-time.Hour)
+	if s := q.Get("start"); s != "" {
+		if opts.Start, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid start: %s", err)}
+		}
+	}
+	if s := q.Get("bucket"); s != "" {
+		if opts.Bucket, err = time.ParseDuration(s); err != nil {
+			return &statusError{http.StatusBadRequest, fmt.Errorf("invalid bucket: %s", err)}
+		}
+	}
+	if _, err := opts.buckets(); err != nil {
+		return &statusError{http.StatusBadRequest, err}
+	}
+
+	tl, err := TraceTimeline(a.Queryer, opts)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(tl)
+}
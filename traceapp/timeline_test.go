@@ -0,0 +1,110 @@
+package traceapp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/appdash"
+)
+
+func TestTraceTimeline(t *testing.T) {
+	ms := appdash.NewMemoryStore()
+	base := time.Date(2016, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// One trace every 10 seconds for 5 minutes, every third of which
+	// fails.
+	for i := 0; i < 30; i++ {
+		start := base.Add(time.Duration(i) * 10 * time.Second)
+		status := 200
+		if i%3 == 0 {
+			status = 500
+		}
+		rec := appdash.NewRecorder(appdash.SpanID{Trace: appdash.ID(i + 1), Span: 1}, appdash.NewLocalCollector(ms))
+		rec.Event(appdash.Timespan{S: start, E: start.Add(time.Second)})
+		rec.Annotation(appdash.Annotation{Key: "Server.Response.StatusCode", Value: []byte(strconv.Itoa(status))})
+		rec.Finish()
+	}
+
+	// The window starts at 30s, cutting the first minute in half, and
+	// ends with a partial bucket.
+	tl, err := TraceTimeline(ms, TimelineOpts{
+		Start:  base.Add(30 * time.Second),
+		End:    base.Add(4*time.Minute + 45*time.Second),
+		Bucket: time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TimelineBucket{
+		{Start: base.Add(30 * time.Second), Count: 6, Errors: 2},  // 30s-1m20s
+		{Start: base.Add(90 * time.Second), Count: 6, Errors: 2},  // 1m30s-2m20s
+		{Start: base.Add(150 * time.Second), Count: 6, Errors: 2}, // 2m30s-3m20s
+		{Start: base.Add(210 * time.Second), Count: 6, Errors: 2}, // 3m30s-4m20s
+		{Start: base.Add(270 * time.Second), Count: 2, Errors: 1}, // 4m30s-4m40s
+	}
+	if len(tl.Buckets) != len(want) {
+		t.Fatalf("got %d buckets, want %d", len(tl.Buckets), len(want))
+	}
+	for i, b := range tl.Buckets {
+		if *b != want[i] {
+			t.Errorf("bucket %d: got %+v, want %+v", i, *b, want[i])
+		}
+	}
+
+	// Windows outside the traces have empty buckets.
+	tl, err = TraceTimeline(ms, TimelineOpts{Start: base.Add(-time.Hour), End: base, Bucket: 10 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range tl.Buckets {
+		if b.Count != 0 {
+			t.Errorf("bucket %d before the traces: got %d traces, want none", i, b.Count)
+		}
+	}
+	if len(tl.Buckets) != 6 {
+		t.Errorf("got %d buckets, want 6", len(tl.Buckets))
+	}
+
+	for _, opts := range []TimelineOpts{
+		{Start: base, End: base.Add(time.Hour)},
+		{Start: base, End: base, Bucket: time.Minute},
+		{Start: base, End: base.Add(time.Hour), Bucket: time.Millisecond},
+	} {
+		if _, err := TraceTimeline(ms, opts); err == nil {
+			t.Errorf("%+v: got no error for an invalid window", opts)
+		}
+	}
+
+	app, err := New(nil, &url.URL{Scheme: "http", Host: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store, app.Queryer = ms, ms
+	w := httptest.NewRecorder()
+	q := url.Values{
+		"start":  {base.Format(time.RFC3339)},
+		"end":    {base.Add(5 * time.Minute).Format(time.RFC3339)},
+		"bucket": {"5m"},
+	}
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/traces/timeline?"+q.Encode(), nil))
+	var got Timeline
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Buckets) != 1 || got.Buckets[0].Count != 30 || got.Buckets[0].Errors != 10 {
+		t.Errorf("got timeline %+v from the endpoint, want 1 bucket of 30 traces and 10 errors", got.Buckets)
+	}
+
+	for _, q := range []string{"start=yesterday", "end=now", "bucket=often", "bucket=-1m"} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", "/traces/timeline?"+q, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", q, w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -92,6 +92,9 @@ func (a *App) parseHTMLTemplates(sets [][]string) error {
 			"filterAnnotations": filterAnnotations,
 			"descendTraces":     func() bool { return false },
 			"dict":              dict,
+			"protobufPayloads":  protobufPayloads,
+			"sqlPlan":           sqlPlan,
+			"receivedHeaders":   receivedHeaders,
 		})
 		for _, tmp := range set {
 			tmplFile, err := tmpl.Data.Open("/" + tmp)
@@ -145,6 +148,18 @@ func durationClass(usec int64) string {
 func filterAnnotations(anns appdash.Annotations) appdash.Annotations {
 	var anns2 appdash.Annotations
 	for _, ann := range anns {
+		// Protobuf payloads are shown decoded (see protobufPayloads).
+		if strings.HasPrefix(ann.Key, appdash.ProtobufKeyPrefix) && strings.HasSuffix(ann.Key, ".Payload") {
+			continue
+		}
+		// SQL plans are shown formatted (see sqlPlan).
+		if ann.Key == sqlPlanKey {
+			continue
+		}
+		// Tags are shown in their own section (see Span.Tags).
+		if strings.HasPrefix(ann.Key, appdash.TagPrefix) {
+			continue
+		}
 		if ann.Key != "" && !strings.HasPrefix(ann.Key, "_") {
 			anns2 = append(anns2, ann)
 		}
@@ -57,15 +57,15 @@ var Data = func() http.FileSystem {
 		},
 		"/trace.html": &_vfsgen_compressedFileInfo{
 			name:              "trace.html",
-			modTime:           mustUnmarshalTextTime("2016-04-26T09:17:53Z"),
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xec\x3c\xfd\x77\xe3\x36\x72\xbf\xfb\xaf\x40\xb8\xe9\x89\x4a\x24\xda\xde\xcd\xb5\x3d\xd9\x56\x5f\x6e\x3f\x9a\xbd\x4b\xb2\x79\xf1\x66\xaf\xad\xb3\x2f\x0f\x12\x21\x89\x6b\x8a\xe4\x11\x90\x65\x65\x4f\xff\x7b\x67\x06\x1f\x04\x28\xca\x96\x7d\x49\xda\xd7\xeb\xbe\x44\x96\x40\x60\x30\x18\xcc\x37\x06\xfc\xf8\x31\x15\xb3\xac\x10\x2c\x7a\x9b\xa9\x5c\x44\xdb\xed\xc7\x8f\xd9\x8c\x25\x6f\x6b\x3e\x15\xc9\xeb\x17\xc9\x77\xbc\x16\x85\xda\x6e\x65\xc5\x0b\xf6\xf1\x63\xf3\xe0\x12\x1a\xb6\x5b\x36\x84\x46\x51\xa4\xf0\x4d\xe1\x93\xa0\x0b\x7d\xa1\x3e\xbc\xaa\x52\x2e\x17\xa6\xeb\xd1\x51\x33\xed\x37\x3c\x2b\x22\x6c\x3a\x97\xd3\x3a\xab\x14\x93\xf5\xf4\x22\x02\x28\x7f\xe4\x52\xfc\xf0\xfd\xd7\x30\xb3\xe2\x2a\x9b\x1e\x3f\xe7\x73\x91\x1e\xa7\xcf\x86\x2a\xab\x8e\xb3\x22\x15\xb7\xc9\x07\x19\x8d\xcf\x8f\xf5\xb8\xf1\xd1\x79\x9e\x15\xd7\xac\x16\xf9\x45\x24\xd5\x26\x17\x72\x21\x84\x8a\xd8\xa2\x16\xb3\xfb\x01\x8a\x5b\xbe\xac\x72\x31\xd4\x23\x93\xa9\x04\xd0\x88\x13\xfe\x1c\x1f\x31\xf6\x64\x5a\x56\x9b\xe1\x07\x59\x16\xa3\x45\x79\x23\x6a\xf6\x11\x1a\x19\x9b\xae\x6a\x59\xd6\x23\x56\x95\x59\xa1\x44\x7d\x06\x8d\xdb\x23\x40\x49\x0f\x3b\x3a\x5f\x9c\x8e\xdf\xee\x23\x0b\xf4\x25\x5a\x17\xa5\xea\xa0\x37\x81\x3f\x27\xaa\x13\xb4\x8b\x68\x56\x16\x6a\x28\xb3\x9f\xc5\x88\x9d\x3e\xad\x6e\xcf\x18\xa0\x01\xeb\xe0\xf9\x90\xe7\xd9\xbc\x18\xb1\x65\x96\xa6\xb9\x38\x8b\xc6\x34\x96\xb1\xd8\xfc\xd5\x50\xb2\xf4\x22\xa2\x45\x54\xa2\x5e\x72\xa4\xd5\x70\x9a\x67\x95\xeb\x0d\xfd\x78\x47\xa7\x88\xa5\x5c\x71\xea\x3a\x29\x79\x9d\x0e\x95\xb8\x55\x21\x3d\xe1\xfb\x77\xb6\xfb\x76\xdb\x45\xf1\x56\x8f\xb1\xfb\x71\x7e\xcc\xed\xfc\x40\x35\x40\xd3\xfe\xfa\x5b\x37\xee\xb8\x01\x06\x6d\x1f\x5b\x6c\xde\x8f\xe8\x9f\x2e\xdf\x7c\x6b\x68\x1e\x8d\x5f\xde\x56\x65\xad\x18\x97\x0c\x9b\x71\xfe\x70\xe2\xfe\x51\x1b\x19\xcb\xb4\xe7\xc7\xb0\x9b\xb0\xa7\x9f\x0c\x87\xec\x2d\xc0\xfe\xb2\x16\x9c\xc5\x05\x20\xf4\x2a\x07\xde\xee\xb3\x19\xcf\xf3\x09\x9f\x5e\xb3\x59\x59\xb3\xe7\x80\xd7\xe7\xdf\x71\xa9\x04\x2b\x67\x34\x97\x16\x10\xc9\x86\xc3\x31\xf0\xbf\x12\xc0\x6f\x1c\x9e\x46\xaf\x97\x88\x91\xc6\x0b\x16\x91\x4d\x15\xb4\xbd\x88\x98\xb7\x62\x5c\x4a\x64\x45\x94\x45\x3f\x48\xc1\xa6\xaa\xce\x3f\x9f\x32\x98\x69\x5a\x2e\x97\xbc\x48\xe1\x87\x2a\x19\x8e\x61\x6a\x21\xbc\x19\xd9\x44\xe4\xe5\x7a\x04\xe3\xde\xf1\x7c\x05\xe3\xe3\xaa\x06\x6e\x9d\xb1\xe8\xea\x9f\xe4\xfb\xc8\xf2\xde\xa5\x82\xd6\x79\xdf\x17\x45\xb5\xa9\x80\xef\x70\xf2\xe3\x0f\xfc\x86\xeb\x56\x62\x98\x78\xb6\x2a\xa6\x2a\x2b\x8b\xb8\x6f\x24\xe1\x86\x03\x26\x79\x06\xbc\xcb\x2e\x58\x21\xd6\xec\xbf\x44\x5d\x3e\xb7\x9b\x11\xb3\xb4\x9c\xae\x96\xf0\x34\x99\x0b\xf5\x32\x17\xf8\xf5\x8f\x9b\xd7\x69\xec\x6d\x60\x9f\xf5\xcf\x8e\xb4\x58\x11\xa0\x04\xc0\x47\x40\xe4\x74\x13\x0d\x98\x9b\x90\x51\xcb\xcb\x1b\x9c\xc9\x4e\x1e\x8c\xe0\x33\x90\x44\x84\x1a\x8c\x12\xad\x01\x8c\xf1\x1c\xe4\x27\x8e\x88\x50\x5a\x48\x61\x50\x26\x52\x22\xa3\x45\x3c\x89\xfa\x67\x66\xc4\xd6\x7c\xdb\x5a\x2c\x8f\x8f\xd9\x9b\x82\xf1\x62\x13\xae\x95\x89\xba\x86\x6d\x41\x2a\x2f\x79\x9d\xe5\x1b\xb6\x5e\x88\x82\x11\x93\xb0\x4c\x92\xbc\x03\x35\xb3\x9c\x4f\x72\xd1\x67\x6b\x61\x81\x39\xfe\x01\x04\x56\x12\x36\x83\x36\x12\xb4\x55\x91\x22\x58\xdc\x07\x50\x0e\x3c\x69\x93\x88\xe6\xf3\x17\x2b\x76\xe8\x92\x0a\xa9\xea\x72\x13\xf7\x4d\xf3\xa7\x71\xf4\xc4\x23\x7c\x02\xdd\xa6\xd7\xbb\x9b\xba\xd3\x55\xcb\x5e\x3f\x59\x64\xa9\x88\x1d\x65\xda\x9d\x88\x5d\x01\x68\x99\xe7\xbc\x92\x22\x8e\xe4\xa2\x5c\x47\x77\x76\x67\x89\x5d\x1e\x0c\x9c\x01\xaf\xc8\xb8\x9f\x48\x91\x8b\xa9\x8a\xef\xdc\x81\x6f\xcb\x86\x6e\x48\x5c\x21\x52\xd8\x42\x94\x40\x24\x9e\x53\x63\x2c\x9e\x88\x29\x5f\x81\xe0\x60\x33\xb5\x64\x0a\xe0\xcf\x70\x10\x36\x59\x20\xfd\xc4\xb1\xb3\x1b\xfc\xfc\xd1\x7c\xdd\xa8\x51\x62\x6e\x84\xdc\x82\xfa\x10\x26\x77\x64\xf3\xc0\xb6\xb7\x4e\xf8\x7b\x27\x92\xaa\x26\xc6\x7f\x21\x66\x7c\x95\x77\x90\xb2\x1b\x9f\x07\x8a\x90\x53\xe7\x9d\x12\xf4\x63\xf1\x63\xf1\x16\x28\x0c\x96\xc0\xd2\x7c\x0a\xa6\x0c\xec\xbe\xa6\x3c\x00\xcd\x6a\xa1\x75\xd5\x80\x95\x05\x08\x8c\x5c\x00\x27\x40\x67\xb6\xce\xd4\x82\xcd\x6a\x40\x2c\x95\x9f\x74\x8b\x22\x7e\xe2\xba\x1a\x47\xe0\xe8\x3c\xcd\x6e\xc6\xf4\x49\x26\xe2\x09\x81\x1e\x76\x98\xe0\x08\xd0\xe4\x52\x82\x8e\xa3\x1e\x2a\x5b\x0a\x58\x86\x40\xaf\x22\x04\x41\x36\xff\x7b\x21\x49\xf9\x51\xab\x19\x08\x3c\x5e\xd6\x22\x7d\x91\xdd\xb8\x41\xa6\x03\x0e\x2b\xf8\x52\x74\xb5\x03\xa6\x20\x1b\x22\xfd\x09\x4c\x96\x37\x5b\xf0\xe7\xa8\x99\x1d\xc9\x05\xd2\xf1\x8d\x28\x56\x0e\xe3\xb4\x2e\xab\xb4\x5c\x17\xf0\x5b\xf0\x7a\x96\xdd\x6a\xd4\x56\x79\xbb\xc3\x70\x49\xc3\x60\x42\xd0\xe5\xfa\x3b\x68\x25\x3e\x04\xf5\x23\x10\x87\xc9\xa6\xe9\xab\x67\x30\xfe\x46\x9a\x49\xb0\x50\x9b\xd1\x24\x2f\xa7\xd7\x67\x55\x29\x33\x64\x83\x91\xf6\x9e\xce\x40\xb5\xcd\xb3\x62\x38\x29\x95\x2a\x97\xa3\xdf\x83\x3f\x62\x3c\x09\x70\xc3\xcc\x64\xc0\x7b\x12\x36\x97\xe3\x30\x87\x37\x92\x84\x39\xdc\x16\xc0\xe6\xa2\x46\x0a\xe4\xd9\xf8\xc8\x8e\x47\xdb\xae\xf8\x84\x9c\xbc\x8b\x68\x78\x6a\x4c\x3b\x27\x3e\xbc\x20\x6d\x32\x9c\x2e\xb2\x3c\x05\x57\xc9\xba\x1b\x4f\x4c\x27\x55\xce\xe7\x38\xb9\x2a\xcb\x1c\x1c\x3b\xd3\x0a\x0b\x99\x92\x6c\x5e\x44\x75\x36\x5f\x80\x31\x55\x68\x4b\x35\x2c\x60\x63\xa0\x9a\x81\xa7\xad\x25\x30\x26\xe8\x05\xf4\x01\xa2\xf1\x25\x76\x79\x6e\x1e\x6b\x87\x01\x91\x3d\x0c\x57\x54\x94\xbf\x14\xae\x08\xeb\x1e\x5c\xbf\xc2\x2e\x8f\xc5\x75\x96\xe5\x20\xf6\xbf\x00\x41\x8f\x3b\x30\x05\x57\x30\x05\xe1\x66\x9c\x99\x69\xc6\xaf\xe8\x6f\x83\xe4\x7e\x2c\x43\x84\x2c\xba\xd3\xbc\x94\x20\x3c\xcf\xf1\x8f\xbf\xd4\xf3\xe3\x55\x7e\x87\x14\xe9\x69\xff\x4f\xc8\xd2\xae\x18\x91\xb7\xec\x49\x5a\x64\xbd\xdb\x11\xb3\xe4\x0e\x49\x9d\x15\xd5\xca\x77\xf4\x1c\x6c\xbd\x4b\x68\x48\x97\x43\xa4\x1c\xa0\xf1\x38\x86\x40\xd8\xb0\xeb\xd7\x62\x33\xba\x41\xff\x93\x55\x3c\xab\xc1\x6b\x4a\x19\xae\x49\xa2\xfa\x87\x79\xc0\x62\x40\x8c\x98\x6b\xdf\xd5\x32\x22\xc1\x5c\x94\x39\xac\xed\xa2\xe7\x00\x24\x49\xd2\xfb\x0d\x58\xc6\xd0\xe1\x26\x13\xeb\x6f\xca\x54\x68\x96\x98\xac\x60\x8b\x74\x3c\x32\x51\xc5\x25\x78\xec\x97\xe0\xb4\xa8\xb7\x60\x38\x1c\xe5\xe0\x01\x83\xff\x87\xa9\xb6\xb9\xa0\x40\x30\xe0\xf8\xe3\x06\x3d\x39\xf8\x82\x46\xe6\xfc\x58\x03\xda\x03\xf3\x65\x91\x1e\x06\x11\xcc\xe2\x21\xf0\x5e\xac\xea\x90\x71\xf6\x02\x4c\x4d\xcf\x7b\x00\x7e\x8d\xfc\x7e\x3f\x34\x12\x8b\x06\x54\x43\x5f\x92\x0a\x3f\xbc\xd0\xf1\x36\x63\x09\xbf\x05\x5d\x56\x71\xb5\x18\xb8\x5f\x68\x91\x8d\xcf\x01\x8c\x91\x8f\xc0\x81\x2e\x84\xb6\xff\xe8\xd4\x5e\x43\x3c\x3c\x01\x46\xb9\x36\x4d\x0b\x5e\x89\x21\xa8\x1c\x60\x1a\x70\xa1\x47\x0c\x9c\x02\x59\xbd\x4c\xe7\x42\xea\xe8\xdc\x82\x25\xaf\xd3\x80\xc5\xc8\x7a\xc6\x97\xe0\xab\x8f\x98\xe4\x85\x1c\x4a\x18\x3c\x3b\x6b\x1e\x9a\xb0\xfb\x04\x44\xd3\x02\xb1\xce\x82\x16\xfe\x87\x42\x7a\xda\x40\x7a\x62\x21\x3d\x35\x98\x69\x50\xe0\x92\x14\x12\xc5\x6f\xa4\xbf\x62\xb0\x18\x03\x02\x83\x67\xf0\x61\xfc\x9f\xe1\x52\x0e\xef\xe9\xc7\x8e\x3f\x63\xaf\x5f\xb2\x3f\xb0\xcf\x8e\xf5\x90\xb5\x98\x5c\x67\xea\x90\x61\x97\x7c\x06\xda\x8d\x44\xf5\xf9\xa2\x2e\xc1\x6e\x5b\x18\xe5\x21\xc3\xdf\x80\x67\xc9\xdd\x90\x65\xf9\xf3\x21\x83\x5e\x81\x2b\x38\x2b\x6f\xf5\x30\xa2\x8e\x75\xbd\x58\xd2\xf8\x5a\x86\x44\x0b\x81\x9a\x66\xf4\x14\xb7\x05\x5c\xc5\x54\x2d\xcc\xf7\x59\x5e\x72\x35\xca\xc5\x4c\x9d\xed\x80\x79\x42\x1e\x88\x06\x60\xd5\x32\xcb\x0a\xda\x4a\xad\x9e\xe9\x91\xd1\xc9\x08\x63\xc4\x4e\x92\x67\x62\xe9\x40\x79\xee\xd8\xc0\xfd\x6a\xcc\xca\x23\x59\x01\x3c\x71\x6b\x16\x18\x9f\xc8\x32\x5f\x29\xc3\xe5\x0e\xcb\x86\xf1\x7f\x1e\x92\xae\x43\x96\x3c\xe9\xc2\x8b\x25\x81\xc9\x1a\x83\x9a\xd3\x09\xbc\x10\xa0\xb7\xde\x8a\xa7\x29\xc9\xcb\xb3\xea\x96\x3d\x3d\xb1\x38\x91\x45\x84\x8e\xa5\x5a\x78\x98\xaf\x35\xe1\xd9\x17\x7a\x76\x46\x32\x3a\x34\xdb\xc1\x4e\x93\x2f\x9e\xfe\xeb\xef\xff\xe5\xf4\x8b\x67\x06\x06\xee\xdb\x88\x3d\x79\xf6\xcc\x34\xac\x17\x99\x12\x43\x40\x68\x2a\x70\x51\xeb\x9a\x57\x3b\x99\xb3\x47\xa6\x20\x50\xdd\x43\xa0\x06\xbe\xfe\xbb\x4c\xbe\x80\x1f\xdb\xed\x99\x7b\x88\xbe\xc9\x5b\x23\x6c\xcf\x17\xa8\x8c\xa9\xe7\x65\xbb\xd9\x1f\x43\x6c\x05\xfd\x20\xf6\x4a\x4c\xd8\x02\xa6\xa9\x9f\x50\x7b\xec\x05\xa2\x62\x89\x61\x0d\xe6\xe4\x74\x58\xa3\x2d\x6b\x9c\x15\xf8\x64\x55\x40\xe4\xd3\x47\x2b\x57\x65\xb7\x22\x97\xba\x81\x44\xab\x16\x6a\x55\x43\x28\x94\x29\x1d\x79\xda\x65\xc1\xb0\x58\x2c\x7f\xd0\x03\x6d\xc8\x85\x18\xe1\x0e\x5c\x02\xeb\x00\x52\x15\xaf\xa5\x78\x85\xcc\x1e\x7f\x1a\xf7\x26\x65\xba\xe9\xf5\x31\x77\x19\xf7\x1c\x83\xf5\xfa\x2e\x6a\xd2\x33\x35\xe3\x3f\x63\x06\xbe\x09\xa6\xdc\x52\x8a\xd5\xf2\x15\xc8\xfb\x4b\x0f\x3b\x5c\x11\x34\x4f\xd0\x25\x80\x47\x26\x70\x4b\x31\xb7\x45\x71\x76\xa9\x30\x8c\x03\x97\x6f\xc3\xe6\xbc\x9e\xf0\xb9\xcb\x6a\x48\xca\x2b\x0d\x20\x16\x9d\x27\x2c\xb2\xba\xee\xb5\x12\xcb\x9f\x4e\xbf\xf8\xe2\x59\xc4\x86\x63\x86\x5f\xc2\xc5\x37\x28\xc4\x00\xa0\x21\x80\x59\x03\x2d\xfc\x75\xa1\xf0\x61\xb2\xe4\x6a\xba\x88\x8f\xe3\x1f\xd3\xcf\xfb\x9f\x1e\xf7\xaf\x4e\xde\x0f\x40\x2e\xfa\xed\x55\xbd\x86\xa5\x02\x86\xb8\xf2\x49\x59\x2a\x18\xc9\x2b\x66\x9c\x18\xa9\x69\x0f\x44\xbc\xea\xf4\x71\xde\x03\x5d\xcd\x77\x7f\xcf\xa5\x50\xd6\xd9\x06\x76\xcb\x26\x60\xd4\xd6\x3c\xbf\x46\x72\xd5\xe5\x6a\xbe\x20\xda\x10\x4b\xe2\x4e\xcf\x40\x66\x65\xe8\x16\x03\x7b\x4c\xf3\x15\x0a\x9e\x05\x09\xb2\xa9\x38\x84\xd9\x20\xea\xb0\xef\x86\xbc\xf3\x0c\x42\x6f\x72\xf1\x21\x76\x65\xaf\x15\x6a\x27\x98\x45\xf0\xe9\x02\x3b\x62\x36\xf3\xc6\xcc\x1f\xab\x1a\x7c\xac\xb2\xf6\x92\x4a\x52\xf4\x5b\xac\xb5\x8b\x77\xac\x81\x0f\x2c\x1c\x2f\xe9\x90\xe0\x34\x31\xae\xc2\x4b\x06\x64\x10\xa9\x03\x66\xb5\x9f\x0d\xc8\x66\x31\xb5\x25\x15\xe5\xb0\x2f\x09\x22\xfb\xe4\xc2\x20\xee\x77\xb5\x1b\xd9\xa4\x84\xb6\xee\x9b\x86\x61\xd7\x73\x61\x31\x6a\xba\x76\x60\xaf\xc7\xb4\xd7\xb0\x93\x2e\x70\x1b\x07\x8e\x60\x21\xde\x4c\x3e\x7c\x5b\xbe\x00\x4e\xd0\x3f\xa5\x47\xea\x72\xf2\x41\x4c\x41\x7a\x71\xb3\x60\x0f\x32\xd5\x93\xe8\xc1\x6a\x89\x25\x2f\x54\xf6\x71\x23\x2c\x3c\x5f\x4c\x08\xd8\x80\x81\xd7\xa3\xd3\x17\x08\x83\xc6\x1a\xf5\x81\x89\xbd\x14\x67\x8d\x93\x3e\x0c\x24\x27\x37\xa5\xae\x16\xda\x0a\x9d\x17\x39\x05\x63\x27\x13\xf6\x16\xa3\x3b\xf8\x6f\x25\xc5\x0c\x22\x12\x9b\xc6\x7a\x45\xb9\xac\x5a\x70\x65\x30\xa3\xb9\x08\x2e\x70\x03\x9f\x4e\xc1\xb7\x2e\x6b\x69\x41\x66\x05\xe8\x1e\xb9\x9a\x0c\xf5\xca\x24\x26\xae\x15\xa8\x6d\xb0\x59\x24\xb4\x88\x38\x80\x69\x33\x4a\x48\xa7\xb8\x0c\x35\x51\xa1\xa9\x07\x4a\x74\x7b\x16\x72\x4b\xe9\xb1\xca\x35\x6c\x88\xbf\xf7\x7a\xd4\xd5\x75\x62\xd6\x1e\x1f\xff\x98\x1c\xcf\x07\xbd\x9f\x7a\xfd\xf7\xb8\xdd\x3b\x19\x2a\x23\xf3\x7a\x5c\x7b\x27\x75\xac\x60\xf9\xe1\xd5\xea\xe7\x9f\x37\x48\x2a\x69\x08\x54\x02\x1e\xd0\x04\xb6\x97\xd7\xd3\xc5\xae\x5c\xc6\x4e\x94\x2b\x31\xcd\x66\x78\x9c\x92\x6f\x06\x5a\xdd\x81\x9f\xa0\x37\x5c\xf1\x39\xc8\x22\x7e\xc3\xc0\xb6\x25\xc2\x42\x27\xfd\x70\xef\xb9\x82\x0d\x70\x4a\xb4\x44\x31\x05\xcd\xd4\x22\x69\x07\xc2\x4e\xf8\xf4\xb3\x86\x58\x00\x86\x96\xb1\xc0\x2d\x85\xdd\x5a\x66\x3a\x02\x44\x9e\x7c\xf6\x14\x70\xe0\x35\x84\x36\xa0\x93\xcd\xf2\xc0\x71\x86\x5f\x85\xd1\xb9\x72\xc0\x64\xc9\xd6\x82\x7d\x58\x49\xd5\x40\x94\x79\x36\x25\xca\x00\x04\x50\x3f\x10\x98\x43\xb7\xa5\x40\x3d\x42\xb1\x98\x64\x4b\xe0\x3c\x16\x83\x95\x06\x90\xeb\x72\x95\xa7\xcc\xe7\xb9\x92\xd5\x3c\x93\xa2\x01\x08\x7e\x85\xb8\x9d\x8a\x0a\x31\x33\x0c\xc4\xcc\x52\x60\x3f\xf5\x97\x84\x66\x8d\x4f\x06\x30\xab\x55\xa0\x34\xf8\x7b\x81\x67\x68\x20\x74\xc0\x84\x40\xdb\x29\x1a\x15\x62\x56\x52\x6e\xfa\xfc\x0b\xec\x33\x0a\x8d\xd9\x00\xfc\xea\x34\x9f\xcd\x2b\x34\x00\xcb\x95\x23\x07\xc8\x0f\xc4\x23\x32\xf1\x58\xd6\x4e\x71\x01\x16\x26\xcf\x2d\x87\x35\xad\x8e\x6b\x7d\x1d\x16\xa4\xc3\x0f\x56\x87\x84\xcd\xf3\x85\xc0\x84\x3e\xb2\x06\x25\xf3\x71\x3d\x6b\xd1\x03\xfa\xe6\x65\x79\x4d\xab\x52\x28\xdd\x5c\x33\x54\xa8\xf0\x35\x0e\x21\x40\x84\x90\x78\x4d\x7b\x95\xee\xbe\x05\x34\x12\x75\xe6\xb5\x6d\x8f\xc2\x69\xbe\x13\x35\x3a\xea\x98\xae\x21\x91\x32\x14\x05\xd6\x73\xd9\x26\xd9\x23\xc5\x93\xb0\xbf\x80\x2c\x95\xba\x9d\x9b\xe3\x8d\x3c\xdf\xc5\x5a\x02\x1b\xdf\x80\x5f\x94\xa2\xa7\x00\x72\xa6\xd5\x16\xaa\x25\x0b\x7b\x40\x5b\x4c\x5c\xb6\xe6\x28\x52\x56\x28\xa9\x6b\x08\xd1\x1f\xe7\xd3\x03\x37\x19\xd9\xae\xad\xb9\x88\x46\x35\x5f\xa3\x4f\xd8\x3f\x6b\x0d\x98\xe1\x94\x3a\xbd\x8f\xb3\xc7\x57\x35\xb8\x10\x21\xc9\x50\x4e\x2e\x45\x81\x1e\xfa\x0d\x78\xad\x64\x56\x07\x41\x0f\x60\x53\x10\x15\x8c\x7d\x31\xbc\x59\xb5\x9e\x82\xe6\x11\x12\x73\x19\x14\x4d\x0c\x9a\x85\x7c\x09\x9c\xb0\xc6\x0c\x88\xed\x80\xec\x40\x12\x88\x52\x3c\x55\x03\xe0\xf9\x39\x20\x8f\xcd\x39\x28\xf5\x24\x00\x8b\x84\x19\xb1\x37\xa4\xd4\x13\xfc\x11\xd7\xfd\x01\x82\xc5\x75\x82\xec\x89\x3c\x95\x7b\x69\xb5\xdd\x21\x84\x91\x18\x12\x04\x29\x12\x3d\x2a\x36\x6a\xe9\xac\xc5\x23\x2f\x44\x05\x42\x83\x3c\x0c\x6c\xb1\x5e\x08\x24\x31\x1e\x48\x22\x07\x50\x1a\x67\x1f\xe7\x30\xe4\x3e\x10\xdb\x55\x15\x02\xc4\xa3\x34\x83\xc1\xa0\x11\x97\xac\x71\x6e\x00\x38\x28\x00\x60\x20\x7f\x15\x6d\x7f\xc1\x4a\x7d\x2e\x8a\x39\x98\xc4\x31\x3b\xd9\x45\xdc\xd3\x33\x24\x9b\x38\x11\x98\x78\xab\xd4\x7d\xf0\x46\x37\x04\x2e\x86\x47\xb7\x86\x86\xdb\x50\x99\xc4\x41\xd7\x7d\x06\xeb\x37\xf2\x17\xc9\x22\xda\xd4\x2b\xf2\x03\x3a\x90\x5a\x8b\x6a\xbd\x83\x7d\x2d\x48\x1e\x10\x1c\x76\x33\x31\x4f\x8e\x1c\xcb\x6a\xd6\xb4\x7b\x0b\x3b\xa4\xcb\x39\x52\x36\xd9\xe8\x5c\x1f\x44\x19\x39\xf2\xb5\x69\xc1\x10\xb0\xa0\x45\x71\xf6\xd7\x15\x04\x0b\xc6\x8b\x6a\x43\x66\x7f\x16\x9b\x51\x24\x6e\x41\xee\x5d\x9f\xa8\xd5\xe7\x15\x20\x65\xca\x35\x46\xed\xe1\xdf\x82\xcd\x1e\x45\xdf\x8b\xbf\x82\x83\xa6\xda\x03\x5f\xcf\x1a\x12\xa4\xa5\x90\x8d\x89\x26\xa2\xf1\x49\x79\x63\x85\xce\xf8\x0b\xc8\xdb\xc6\xa6\x0e\xf6\xec\x9f\xcc\x80\xcf\x14\x18\x2f\x3c\x40\x94\xcc\x9e\xdf\xa2\xf8\x0c\xb5\x71\xf2\xc5\x00\x00\xde\xe9\x0e\xdc\xe5\x09\xbc\x83\xd8\x05\xcf\x8b\xbc\x14\xa9\x6f\xd9\x20\xa4\xcf\x4c\x16\xc2\xb3\xba\xd8\x18\x47\xa3\xe6\xe8\x0c\xec\x85\xd7\xd3\x0a\x09\xd8\x8b\xa7\xbe\x91\x80\xe9\xbe\xc9\x24\x9d\x41\xeb\xad\xc3\x03\xd5\x60\xd3\x07\xc1\x71\x75\x20\xea\x80\x9f\x27\x41\x07\xf8\x3b\x8d\x34\x85\x86\x69\xeb\x2d\xef\x9a\x5d\xf8\x4b\x84\x20\xef\xcc\x7b\x7a\xd3\x7a\x7a\xfa\xde\x5b\xef\x0d\x74\x86\x15\x5e\xb0\x5e\xd4\x63\x7f\xfb\x1b\xbb\xb9\xba\x31\xeb\x1e\x9e\xba\x07\x7b\x56\xef\x33\xeb\xff\x2c\x11\x00\x29\x2c\xd1\xa8\xc0\x08\xf0\xd4\xba\x43\x10\xc3\x66\xb9\xc3\x53\xea\xd8\x9c\x90\x1d\x59\xea\xa0\x4b\x6d\xbc\xaf\x53\xf0\xc4\xdd\xca\x1b\x75\xfe\x9b\x45\x78\x3e\x81\xb5\x63\x94\xcd\x1a\x3d\xaf\x9d\x5c\xd4\x1d\x2e\xc8\x42\x39\x9f\xa2\x70\x91\x94\x92\xa5\x01\xef\x2e\xe4\x7d\x0f\x2b\x63\xde\xaf\xae\x21\x8e\xb8\x08\x83\x8e\x5d\x33\x81\x26\xda\x43\x0e\xfc\x78\x29\xee\x1c\x40\x26\xbf\x2b\x60\x6d\x89\x70\x18\x8b\xb6\x76\x77\x37\x14\xfd\x0b\x16\x87\x20\x11\x40\x63\xd4\xfa\x4c\xc4\x84\xa2\x74\x4c\xc1\x6c\xf6\x5d\x77\x32\x39\x3e\xb6\xa4\xe4\x23\xf8\xf6\x74\xd4\x96\x29\xf4\xc2\x9c\x49\x10\xd3\x1c\xcf\xcf\xad\x47\xc6\x81\x27\x61\xc3\x50\x75\x34\x19\x00\x63\xf8\x08\xd9\x00\x2a\x00\x13\x4b\x30\x85\x8d\x3d\xf8\xeb\x2a\x9b\x5e\xc3\x26\xd0\x54\x6d\x24\x70\x82\xb5\x00\x1b\x15\x4b\x61\x4a\x8d\x76\x82\x48\x75\x8b\x39\xc9\x2f\xe9\x17\x2d\xca\xaf\x52\xd8\x5f\xa3\xa0\xcb\x1d\x9a\xa3\xef\xb0\xec\x64\x6b\x33\x36\x41\xde\x93\x5f\x75\x1c\xf8\x60\xf6\x06\xcb\x1a\xa8\x54\x22\x1a\x74\x20\xe4\xe5\x74\x82\x87\x98\x1a\xa4\x33\x55\x53\x25\x92\xa1\xd5\x59\xda\x83\x38\x57\x66\xe2\x13\x04\xdc\x08\x1c\x75\xe4\xf8\xdc\x18\x0a\x64\xea\xe0\x78\xd6\xec\xac\xbc\x8b\x5a\x76\xfe\x58\x74\x64\x66\x3a\xe9\x7a\x16\x98\x04\x92\xcf\x8b\x0e\x4a\x22\x95\xe2\x08\x3f\xb5\xef\x08\x4d\xba\xb7\x1d\x7f\x40\x8a\xc8\xf4\xb4\x29\xbd\xaf\x30\xc3\x1e\xef\xf0\xb7\x2e\x62\x59\xc0\xf2\x73\x8c\x2d\x91\x64\xda\xef\xf0\x99\x08\xd7\x79\x4c\xd4\xd1\x44\x49\x0e\xd9\xdc\xb0\x0e\xa0\xbd\xc9\x41\x45\xcc\x7e\xaa\xa2\x1a\xe8\x3b\xb1\xbc\x67\xc6\xf0\x34\xff\x91\x33\xea\x8c\x5c\x50\xc4\x14\xd0\xc8\x71\x95\x71\x55\xe4\x6a\x82\x34\x3a\x88\x24\xe6\xe4\xf4\x4e\xcc\x1a\x7b\xa2\x15\x0c\xe5\x3a\x4a\xac\xe0\x09\xf6\x24\xb9\x9b\xcb\x1a\x28\x2f\xf4\x69\x82\x56\xe4\x3e\xae\x81\x04\x7b\xe7\x23\x09\x9d\x4c\x83\x34\xab\x65\x1e\xb7\x58\x33\x7c\xd8\xef\xd2\x05\xde\x01\xbe\x4e\x76\x37\x4a\xdb\x1d\x6c\x44\x74\xb2\x11\x35\x21\x98\x3e\xc7\xd9\x95\x03\x1c\x1f\xe1\xc3\xa8\xdf\x74\x56\x65\xb5\xb7\x2f\x3c\x8b\xfa\x3b\x29\x2a\x6f\x5b\xfc\x85\xea\xed\xe8\xb5\x2b\xd9\xfc\xad\xff\xca\x2a\x55\xb3\xdb\x06\xca\xd0\x50\x52\xd7\x0e\x76\x9a\x87\xa9\x67\x1e\x76\x98\xc3\xc3\xe2\x20\x95\xd8\xc5\x21\x07\x69\xe6\x60\x37\x02\xfd\xec\x2d\x32\xb4\x71\x78\xa6\x23\x29\xe7\xa4\xc8\xa6\x9b\x30\xcc\x91\x80\x58\x50\x1f\x9f\xb8\x32\x01\x61\x0a\x05\x9c\x1b\x0e\xa6\xaf\x5d\x30\x40\x45\x04\x5d\xe5\x31\x40\x60\x5e\xcf\x85\xf2\x92\x27\xf7\x6d\x18\xb8\x21\xab\xaa\xb3\xaa\x0e\xbc\x0d\x81\x8f\x9f\x97\xb0\x71\xe0\xfa\x9c\x3e\xf3\xfd\x0c\xe3\xf4\xe8\xca\x44\xa5\x71\x4e\x76\x3d\xb9\xaf\xba\x4c\xe9\x80\xcd\x6b\x3e\x69\xe3\xcb\x50\xe5\xea\x70\x50\x2f\x12\x47\xb5\xe2\xd4\xbf\x57\xd9\xef\x09\x42\x3e\x8d\xd1\x85\xe8\x27\xe0\x8b\x81\x28\x3e\x60\xef\xf7\x19\x05\xcb\x12\x6d\x63\xf7\xa6\x82\x59\x41\x35\x02\x8a\xab\xe5\x00\xb3\xef\xed\xa2\xc7\xfb\xe6\x3b\x60\xd1\x1a\xee\x9e\x01\xa1\xde\x21\x3c\x12\x3a\xd8\xbf\x63\x86\x87\xe9\x1e\x90\x26\x3e\x17\xff\xd1\xd2\x32\xba\xf5\x3f\x77\x14\x8a\x3d\xab\x6b\x7c\xce\x6d\x8b\x74\x2d\x0a\xfb\x7a\x9d\xc4\xad\x16\x93\x15\xec\xa7\x2d\x23\xb6\xdd\x49\x48\xa6\xd3\x72\x05\x06\x07\x0d\xcd\x14\xac\xce\x5c\x48\xf2\x25\x97\x2b\x09\x8d\x59\x0d\x9f\x62\x59\xa9\x4d\x03\x31\x53\x58\x66\x0e\xbe\x8f\x12\xf9\xc6\xd3\xee\x49\xab\x70\xb2\x9f\xd0\xc0\x38\x30\x10\x58\x0a\x4f\x39\x68\x42\xc4\xa5\x16\xcc\x41\x84\x49\x59\x50\xd6\x03\x11\xaa\xb8\x8e\xbc\x48\x2b\xd0\xed\x0c\x0d\xdb\xe7\x75\x03\xe3\x85\x3e\xec\xbd\x7a\x7f\x76\x6f\x24\xe3\x73\x14\xc9\xf0\x27\xd0\x94\xec\xb8\x54\x9e\x08\x77\x38\xfa\xde\xb4\x49\xb5\x92\x8b\xd8\x67\xa8\xad\x1f\x62\xfb\x3d\x4d\x88\x0d\x11\xc9\x49\x87\xa6\x70\xba\xc1\x5b\x1e\xd5\x2a\xbc\xd5\xc7\x8d\x2e\x53\xed\x3d\x47\x92\xa0\x8c\xd2\xd6\xfb\x49\x6b\x3c\x03\xca\x8a\x01\x1d\x0c\xa8\x01\xa3\x1a\x81\xd6\xba\x75\x97\x70\xc5\x94\x18\xcf\x6e\x48\x77\xf4\x5c\xa5\x44\x6f\x27\x3b\x48\x07\xf9\x98\x1c\x24\xf8\xba\x1e\x43\xc6\x41\x37\x00\x93\x60\xde\x2a\xee\x79\xe5\x1a\xf6\x50\x1a\x03\xe5\x79\x0d\xac\x97\x0e\xe9\x61\x6f\x60\x40\xc6\x1a\xd3\x3d\x90\xa8\x62\x03\x0f\x60\x41\xf4\x7c\xca\x5e\xd1\xa8\xf7\xc9\x0c\xe8\xf3\x75\x20\xab\xdd\xe3\xb9\x52\x35\x18\x70\xba\x44\x31\x60\x1d\x80\xac\xc0\x7b\x50\x60\x07\xb4\x4a\x38\x78\x5e\x1c\x81\x9e\x29\xe9\xce\x01\xa9\x8d\xe0\xd0\x3b\xfa\x5c\x2f\xf6\xea\xe4\x7d\xff\xce\xf8\x93\xa6\x6e\xd5\xd9\x6f\xdb\xec\x12\x9e\x6b\x07\x82\xae\x37\xc9\x63\x9b\xa9\x29\x79\x48\x9f\xb9\xe2\x25\x77\x21\x20\xfc\x67\xaa\x1b\xe8\x73\x4f\x0f\xa9\x60\x33\xf7\x0d\xd7\xc5\x33\xf1\x47\xd2\x7c\x62\x19\xff\x73\x7f\xc0\xa8\x2a\x70\x74\x32\x20\xbd\x07\x7f\x4c\xb5\xe3\xc9\x76\x0f\x0c\x62\x43\x67\x81\x59\x9c\x02\x3f\x1b\x0b\x81\xee\x75\x20\x03\x74\xe8\xdd\xb0\x3d\x3a\xd7\x7b\x10\x2b\xc1\x03\x29\x57\xea\x50\xb8\x3a\xcd\x7f\x00\xe0\xb0\x0a\xbf\x0d\xb5\x73\x0c\x63\x6b\xe0\x84\x72\x9d\x80\xb9\xa0\x70\x32\xc1\xa2\x45\xdc\x1f\xb2\x3e\xab\x3a\x3f\xdb\x33\xee\xf8\x58\x17\xde\xe3\xd5\x95\x44\x9f\xf5\x65\xb3\x8d\xb1\x5a\x26\x09\x32\x20\xb5\x31\x60\x4f\x43\xa9\x6a\xfe\x6d\x03\x1d\xed\x33\x91\x56\x3c\x81\xbe\xa9\x2c\xdb\x54\xb1\x91\xa3\x1e\x15\xff\x81\x04\xf7\xf4\x0d\xba\x9e\x67\xfa\xab\xa4\x9c\xcd\x20\x80\x8c\xaf\x86\xa7\xb0\xd3\xc4\xe8\xbe\xa7\x72\x33\xd7\xe0\x8c\x57\xdc\x61\x45\xc0\xb5\xc3\x14\x7a\x04\x5d\x23\x2b\xb8\xc4\x8d\x20\xb8\x7b\xb9\x32\x21\x02\xf8\x92\x0a\x7a\x07\x1c\xc2\x98\xb6\xaf\x73\x04\x95\x1b\xc5\x11\xee\xf5\x0c\x7c\x45\x80\x1e\x99\xe1\x51\x67\x7f\x02\x07\x0b\x0c\x17\xd4\x9c\x75\x5a\x45\x8c\xaa\xaa\xef\xeb\x5d\x46\x4d\xd6\x16\x9c\xb3\xd3\x2f\x90\xd9\x8c\x95\xc7\x47\x67\x9e\x9d\xf1\x9a\x13\x88\x03\x60\x87\xf1\xe0\x14\x1d\xcd\xcf\x59\x94\x24\x49\xe4\xac\x86\x1f\xed\x7f\x4a\xea\x4b\x9a\x5a\xa5\x90\xa4\x1a\x56\x58\xb2\x18\x05\x0c\xf0\x0d\xbf\xd6\xbd\xf0\xa4\x86\x02\x74\x37\xd6\x9c\x70\x33\xe2\xf1\x21\xde\x5a\x4a\x02\xc3\xfc\x41\x52\x3a\xbd\xe8\xf9\x87\xcc\x42\x2c\xd1\xd5\xa0\x23\x3f\xce\xd6\x18\x1f\x62\x01\x42\x45\xb7\xef\x28\x33\x25\xb8\xcc\x1a\x67\xc2\xa4\xe9\xb5\xff\xd2\x1c\x28\x4e\x30\xa1\x8a\x5c\xe2\xfc\x18\x44\xd1\x60\x34\xa0\x23\x12\xfb\x04\x83\x15\x87\x2b\x68\x5f\xef\x84\xfa\xf2\xdd\xbf\x03\x51\xa7\xaa\xaf\x3d\x69\xcc\xcd\x52\x09\x91\x1d\x0a\x7e\xb3\x39\xee\xc6\x53\x59\x2c\x39\xc5\xaa\xd2\x56\xb1\x52\xd4\xef\xc2\x15\x6f\xb6\x80\x24\x28\x5b\x1d\x45\xee\x8c\x3e\xd3\xd5\x55\x60\xa0\xeb\xb5\x2f\x83\xa9\x4b\xdf\x2f\xd8\xeb\x45\xb1\xf9\xd8\xdc\xa0\x22\x6f\xa6\xf3\x56\x16\x6e\xb8\x86\x7d\xe1\xd7\x4a\x59\x8f\x1d\x69\xe1\x24\x35\x4b\x7b\xbe\x12\xc0\xa1\xba\x66\xf5\xc2\xf2\x8c\xb1\x68\x9e\xe5\x6b\xa4\x93\x00\x7a\xb2\x40\x61\xa3\xd6\xa3\x20\x35\x7e\xe8\xd8\x56\x74\x77\xa9\x68\x32\x81\xc1\x01\xf4\x9e\x39\x56\xaa\x35\xc5\xdd\x1a\x5a\xc3\xed\x80\xb6\x13\xe8\xb6\xb1\xdd\xa3\x8c\x3b\xec\x7e\x4b\x33\x6f\xfb\x9d\x74\xd3\xce\xc4\xa1\x84\x3b\x80\x58\xbf\x2a\x89\xc8\xb7\xd2\x7a\x4c\x63\x9e\x64\x45\x21\xea\xaf\xde\x7e\xf3\x35\x44\x7e\xae\xa7\x17\xcb\xd7\xc2\xd4\x2d\xe8\x98\x88\x92\x15\x31\x15\xf9\x91\xa5\xd7\xda\xa2\x6f\x2e\x8d\x41\x70\x51\x56\x7a\x9c\x0f\x2b\xc8\x01\x96\x85\xf3\x5f\x28\xee\x45\x81\x85\xd8\x24\x17\x49\xc0\xba\xa4\xe4\x03\xfb\x11\x32\x3d\xfa\x55\x3a\xf8\xeb\x7b\x87\x44\x28\x67\x57\xda\x23\xa3\xe5\xbd\x37\xe9\x8f\x06\xf9\x9d\x04\x9e\xd1\xc2\xdd\x21\xea\x2e\x5b\xf4\x83\xe3\xf4\x96\x20\xfe\x8a\x73\xb5\x4e\x14\xc0\xc5\xa7\x50\xea\x13\x1d\x37\xb0\xdf\xfd\x6e\xb7\xec\xb5\x61\xfd\x7b\x72\xb7\x92\xd3\x89\x28\x9d\x1c\x94\xb5\xd6\x73\x12\x14\xf8\x91\xdd\x0c\x6c\xa1\x6a\xff\x0b\x07\x12\x9d\xed\x11\xeb\xf5\x6c\xa0\x6b\x8e\xc3\x41\xd5\xbe\xa9\x53\x51\xb7\x4a\x27\x74\xa1\xa5\x7d\x62\x69\x82\x30\xda\xe6\x13\xc8\x49\x31\x3a\x1d\xd8\x51\x87\xd0\x5b\x76\xcf\xf5\xd3\xb3\xf6\xb3\x16\x1e\xbb\x07\x3a\xce\xee\x9e\x76\x9e\x59\xed\x01\xf2\x49\x57\xfb\xd9\x2e\xea\xad\x1e\x5d\x21\x27\x1b\x9e\xde\x19\x10\x74\xa1\xe7\xff\xdd\x7a\x85\xa9\xb8\x27\x13\x73\xe5\x04\xe6\xfc\x09\x37\xba\x95\x3f\x20\xca\x07\x57\x58\xe2\xb0\xbc\x0f\x81\xd8\x65\xda\x8d\x4e\xbc\x0d\x8b\x7b\x04\x9e\x60\x37\xee\x1f\x72\x5f\x82\x43\x1b\xc3\x05\xce\xe8\xa4\x75\xbe\x7a\xa3\x0f\xb3\x33\x57\xb9\x65\x48\xb5\xa9\x04\x58\x50\x4e\xae\x8a\xd4\x67\xb3\x3a\x51\x40\x27\xb7\xe6\xf1\xa4\xe3\x71\xbf\x8b\x88\x08\xd2\xc0\x6a\xc2\x70\x88\xc3\x11\xd6\xa4\xa3\x3d\x00\xe2\xa3\xeb\x98\xbe\x05\x15\x34\x4c\x12\xd0\x18\x5c\xbb\xc9\x9e\x47\x9d\x5b\xde\xd0\xf8\xb3\xae\xed\xbf\x73\xaa\xf1\x23\xa7\x3a\x84\xc9\x4e\x3a\x98\xec\xc0\x03\x1f\xcb\x7b\x9a\xdb\xef\xe4\x3c\x73\xd1\xe9\xc1\x7c\x07\xa0\xff\xd1\xb9\xce\xa3\x6e\xc8\x73\xde\x83\x5f\x80\xe3\xfc\x69\xc6\x8f\x9a\xe6\x37\xe2\x36\x7b\x73\x6d\x1f\xab\xd9\x3b\x70\x0f\xe6\x35\x0b\xf8\x1f\x98\xd7\x2c\x09\x42\x46\xb3\xad\xbf\x00\x97\xb9\x09\xc6\x0f\x9f\xe0\x37\xe2\x2f\x1d\x31\xf1\xbc\x5a\xc0\x17\xa5\xeb\xc4\x9d\x1b\xd4\xb0\xd9\xd7\x26\xb0\x6a\xdc\xf1\x87\x71\x1b\x4d\xf3\x4b\xb3\x9a\xc6\x9d\x78\x49\x67\x8b\x42\x56\xdb\x7d\xfc\x10\x2e\xd1\xd1\x82\x2a\xbf\xc6\x2a\xd6\xe7\x5c\xa2\x36\x3f\xb7\x50\xc3\xf6\xc7\x73\x4a\xd7\x24\xe3\xc7\x4c\xf2\x6b\x73\x8b\xd0\x0e\x32\x86\x3d\x0a\x33\x1e\xa6\xc6\x83\x9e\x63\x2a\x78\xe7\xd6\xb0\x7d\x81\x47\x87\x3b\xe6\x6a\x12\xdc\x30\x7b\x31\x78\x77\x90\x79\xb2\x3b\xc4\xdd\xfd\xdd\x1d\x63\x1f\xed\x0e\xd2\xf7\x7b\x77\x47\x34\xd9\xee\x9d\x77\x6e\x98\xf7\x22\xd1\x7b\x74\xde\x62\x8e\x88\xde\x73\x74\xc7\x4d\x5f\x7b\xb1\xda\xec\x80\xb9\x7f\x38\xa4\x53\xb1\x53\x7d\xdb\xb2\x69\x35\xc9\x62\xfb\x80\xae\x3b\x56\x75\x09\x01\xab\x78\x07\x70\x06\xec\x09\x6c\xc7\xa4\x94\x14\x24\x61\x4b\xfb\xa6\xa3\xbd\x3a\x49\x57\x77\x67\xd9\xad\x48\x87\x0a\xb1\x1c\xba\x3b\x7d\x66\xc4\xa4\xd4\xb1\x48\x30\x80\xba\x42\x04\xe0\x5f\xed\xb4\x77\x20\x75\xe9\x44\xbb\x6b\xea\x18\x6b\x0d\x00\x87\x93\x5a\xf0\xeb\x11\xa3\x3f\x43\x9e\x9b\xee\xde\x75\x47\x24\xde\x9f\x56\x52\x65\x33\x7c\x7f\x4a\xcd\xd3\xac\x1c\x1a\xde\xd1\x65\x87\xeb\xcc\x54\xc0\x81\xf6\x59\x0b\x08\xa5\x5d\x99\xb0\xa1\x03\x43\x82\xea\xb7\x4b\x75\xdd\x5f\xa7\x1b\xda\x78\xf8\x52\x35\xdf\x86\x1f\xdc\x8c\x4d\xdb\xad\x6c\xdd\xf3\x37\x68\x44\x74\x01\x9c\x30\x2b\xcd\x15\xcc\x73\xad\x39\x5a\xd7\xc0\xf5\x7b\x8f\x36\x0c\x0b\x0e\x6e\x84\x7d\x93\x81\xff\xa2\x01\x02\x12\x51\x98\xa6\x11\x8c\xec\xe5\x72\xbb\x7d\x91\xae\xff\xbb\x88\xe8\x88\x8f\x5a\xc6\xee\xeb\xf9\x31\x01\xd3\x17\xf7\x09\x85\x7b\x91\x79\x18\x16\xef\x42\x5e\x72\xc8\x98\x76\xe6\x21\xb5\xd3\xf4\xab\x23\xf7\x5d\xc3\xf6\x0e\x31\xd3\x66\x70\xf2\x7f\x75\xa1\x63\xef\xe1\x23\xd3\x1d\xb1\x3f\xf1\x1b\x7e\xa9\x2f\xdb\x4e\x91\x4f\x30\x69\x4b\xaf\xef\x00\xd6\xa2\xb7\xa3\xb8\xd3\xd9\xe3\x16\xab\xa5\x61\xfd\x7f\x36\x5d\x1c\x69\xce\x75\x35\x8b\xd2\x24\x6f\x45\x7a\xa4\xb5\xc1\x7d\x97\x7a\x31\x19\xea\x38\x96\x30\x1f\x69\x4a\x80\x2e\xa2\x83\xea\xb8\xdb\xb0\x66\x29\xa5\xbd\x75\xce\x45\x1f\x17\x64\x69\x50\xf4\x8c\x3d\x2e\x58\xc0\x63\xed\xb7\x5c\xa5\xee\x81\x3e\xc0\x6b\x52\x2c\x6d\x53\xd1\xea\x1d\x9e\xd2\xb9\x9c\x41\x30\x6b\x9b\xa7\xda\x93\xdf\xb4\x9f\x1f\x82\xc3\xee\xa0\x43\x50\xf1\x39\xa8\x8d\x46\xe5\x3f\x3b\x04\x85\x70\x40\x7b\x7a\x9d\x9e\xf2\x5f\xcd\x44\x56\x42\x57\x52\x82\x41\xc1\x9b\x0b\xc4\x5b\xb8\xe9\xe0\x12\x6d\x4a\x90\x00\x62\x95\x55\x4e\x0c\xef\xa8\x1c\xbc\xa9\xc9\xbc\x87\x29\xcf\x82\x56\x2d\x22\x98\x3b\x6c\xde\xf5\x84\x25\xca\xcd\xdb\x2a\x23\xfb\x46\xbf\xe6\x15\x97\x78\x63\xc0\xbd\x6d\x51\xd5\x25\x9e\x08\xe8\x17\x97\x78\xef\x8b\xc2\x91\xee\xa7\x1e\x81\xba\x1b\x7b\xbb\x77\x04\x02\x69\x1e\x06\xc7\x61\xb5\x03\x4a\xbf\x23\xb3\x8d\x29\x2d\xe5\xcb\xa2\x28\x75\xf1\xa9\xb4\xb3\x69\x8b\x63\x09\xa1\xcd\x8f\x35\x6d\xa9\x28\xf0\x0a\x82\xfe\x8d\xce\x5d\x25\x52\xf7\xc6\xc7\x8f\x1f\x6b\x14\x29\x66\xf2\xbe\x1e\xe8\x7d\x53\xf6\xb7\xcd\x21\x95\x46\xed\xb5\xdd\x46\xef\x09\xe2\x54\x8f\xcf\xd5\x02\xd7\xfa\x67\xb1\xc1\x15\xc2\x8f\x73\x95\x42\x03\x20\xc1\x12\x7a\x11\x21\x35\xa7\x63\xf8\xa8\xc7\x1e\x54\xbd\xfa\xdd\x5f\xd0\x0f\x57\x11\x12\x89\xb4\x5a\x86\x2a\x0d\x5f\xe0\xd2\x70\x97\x11\x8c\xbb\x79\xab\x2d\x3d\xff\xcf\x62\xff\xcb\x58\xec\xb1\x6c\xf4\x68\xb6\xf1\xed\x5b\xc0\x31\xf6\x1d\x52\xbe\xb6\x23\xfe\xd0\xc4\x08\xdf\x19\x80\x4d\xc6\x87\x5a\xd5\x39\xed\x8e\x51\xb9\xf4\x32\xd4\xe8\x4e\x42\x9a\x81\xfa\x5d\x1a\x17\xd1\xd3\x3f\xfc\xc1\xba\x07\x0a\xdf\xf2\xe4\xde\x96\xea\x0b\xcd\x39\xde\xfc\xc6\x51\x18\x7a\x20\x38\xe4\xd6\x95\xc5\x81\x2e\x01\x5e\x44\xdf\xd2\xeb\xa0\xf0\x93\xc8\xf8\xb0\xc1\x14\x75\x8c\xf1\x93\xc5\x4b\xd9\x7f\x24\x04\x5b\x60\x68\x20\x7d\xde\x94\xc2\xff\x3d\x40\x57\xcb\x68\x0c\x1f\xab\x9c\xa3\xbf\xc9\x3a\x91\x6c\xb8\x03\x5b\x1d\x1d\xcf\x15\xbe\x33\xc3\x75\x42\x36\x78\xa9\x6f\x96\x69\xcd\x61\xee\xdc\x53\x59\x5a\x2d\x90\x2b\xdc\x31\x34\xed\xdd\x0f\xaf\xbb\xb7\x23\x1d\x1f\xab\x65\xf5\x6f\xb3\xb2\xbc\x40\xa4\x89\x41\x83\xc7\xa7\x27\xbf\x3f\xd9\x6d\x7d\x76\x72\xd2\xd1\xfa\xb4\xdd\xec\xb3\x3a\x72\xa7\x69\xb3\x4b\x71\x1c\x1f\xfa\x79\x74\x6e\x47\x01\x9d\xf1\xd8\xb8\x65\xf7\x21\xb1\xbb\x5e\x51\x5d\xae\xa9\x14\x10\xef\xdf\x62\xd5\x1f\xde\xf6\x16\x69\x86\x67\x71\x40\x0d\xa6\x0b\x75\x8f\x70\x64\xa5\x4b\xd3\x87\x74\x56\x87\x45\x8c\xc9\xe1\x3e\x9e\xef\x35\x98\x90\x29\xa2\x43\xb2\x9e\xae\x31\x00\x2c\x92\x89\xd4\x0f\x7a\xcd\x59\x19\xc3\x43\x31\xc2\xf0\x53\x73\xce\x6f\xdd\x17\x2a\x6c\x0b\xcf\x6f\xf1\x25\x52\xe6\xbc\x08\xc7\x24\x20\x7b\x8d\xb3\xb3\xe7\xb0\xd7\xf4\xb3\x51\xfd\x8e\xf7\x62\xf5\xc7\x7f\x07\x00\x00\xff\xff\x94\x79\x14\x00\x36\x5b\x00\x00"),
-			uncompressedSize:  23350,
+			modTime:           mustUnmarshalTextTime("2026-10-15T10:49:52Z"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x7d\x69\x97\xe3\x36\x92\xe0\x77\xfd\x8a\x30\xab\xc6\x49\x76\x4a\x54\x1e\xf6\xf6\xb4\x52\x52\x3f\xbb\x8e\x75\xf5\xf8\xda\xaa\xb2\x7b\x77\xd3\xb9\x7e\x10\x09\x4a\xa8\xa4\x08\x36\x00\x5d\xce\xd2\x7f\xdf\x17\x38\x48\x90\xa2\xf2\xa8\xb6\x67\xde\x9b\x19\x97\x5f\x95\x84\x23\x10\x08\x44\x04\x22\x02\x01\xe8\xee\x2e\xa5\x19\x2b\x28\x04\xef\x99\xca\x69\xb0\xdf\xdf\xdd\xb1\x0c\xe2\xf7\x82\x24\x34\x7e\xf3\x32\xfe\x91\x08\x5a\xa8\xfd\x5e\x96\xa4\x80\xbb\xbb\xba\xe2\x5d\x49\x8a\xfd\x1e\x06\x70\x77\x47\x8b\x74\xbf\x07\x85\x35\x8d\x26\xfa\x83\x6e\x43\xca\x32\x25\x72\x61\x9b\xf6\x7a\xf5\xb0\xdf\x11\x56\x04\xfb\x7d\xaf\x37\x96\x89\x60\xa5\x02\x29\x92\x49\x70\x77\x17\x7f\x4d\x24\xfd\xe9\xed\xb7\xfb\xbd\x54\x44\xb1\x64\xf8\x82\xcc\x69\x3a\x4c\x2f\x07\x8a\x95\x43\x56\xa4\x74\x1b\x7f\x90\xc1\x74\x3c\x34\xfd\xa6\xbd\x71\xce\x8a\x5b\x10\x34\x9f\x04\x52\xed\x72\x2a\x17\x94\xaa\x00\x16\x82\x66\x0f\x03\xa4\x5b\xb2\x2c\x73\x3a\x30\x3d\xe3\x44\xca\x60\x8a\x38\xe1\xd7\x69\x0f\xe0\x59\xc2\xcb\xdd\xe0\x83\xe4\xc5\x68\xc1\xd7\x54\xc0\x5d\x0f\x00\x20\x59\x09\xc9\xc5\x08\x4a\xce\x0a\x45\xc5\x55\x0f\x60\xdf\x1b\x0f\x6d\xb7\xde\x78\x71\x3e\x7d\x7f\x8c\x2c\x3d\x00\x4d\xeb\x82\xab\x0e\x7a\x6b\xf0\x63\x4d\x75\x0d\x6d\x12\x64\xbc\x50\x03\xc9\x7e\xa3\x23\x38\xbf\x28\xb7\x57\xb0\xa6\x42\xb1\x84\xe4\x03\x92\xb3\x79\x31\x82\x25\x4b\xd3\x9c\x5e\x05\x88\x2f\xfe\x09\xed\xbf\x06\x0a\x4b\x27\x81\x9e\x44\x49\xc5\x92\x20\xad\x06\x49\xce\xca\xaa\x35\xc0\x98\x74\x34\x0a\x20\x25\x8a\xe8\xa6\x33\x4e\x44\x3a\x50\x74\xab\x34\x3d\x7f\x74\x4d\xf6\x7b\x8f\xca\x7e\xe9\xb4\xfa\x32\x1e\x12\x37\xce\x78\x88\xe8\xb8\x6f\x1f\xbb\x71\x44\x42\x5b\xf4\x7c\xac\xb0\xf8\x38\x42\x7f\x7b\xf7\xc3\xf7\x96\xb6\xc1\xf4\xd5\xb6\xe4\x42\x01\x91\x80\xc5\x38\xfe\x91\x81\x49\x8d\xbb\xe9\xa3\x99\xce\x07\xf0\xcd\xfb\xef\xbe\xf5\x26\x10\xf5\xda\xd3\x70\x6c\x3d\x1e\x2e\xce\xa7\xc8\xdc\x1b\xa6\x16\x76\x4d\xbf\x2a\x0a\x8e\x0c\xcc\x0b\xb9\xdf\xf7\xc6\x8a\xcc\x72\x0a\x49\x4e\xa4\x9c\x04\xe6\x8b\xfe\x7b\x90\xf0\x22\xa5\x85\xa4\xa9\x91\xa3\x01\xa9\xfb\xe9\x25\xba\xbb\x13\xa4\x98\x53\x88\xf7\xfb\x1e\xc0\x58\x89\xe9\x58\x2d\xa6\x77\x77\xf1\xbf\xd1\xdd\x7e\x3f\x1e\xaa\xc5\x74\xac\xd2\xe9\xdd\x5d\x29\x58\xa1\x32\x08\xfe\x45\x06\x10\xff\x4c\xf2\x15\xd5\xd5\xe9\x74\x3c\x54\x62\xda\xf3\xb1\xd5\x23\x4f\x7b\xae\xa0\x37\xfe\x6c\x30\x80\xf7\x74\xab\xbe\x12\x94\x40\x58\xf0\x62\xf0\x3a\x27\x72\x11\x41\x46\xf2\x7c\x46\x92\x5b\xc8\xb8\x80\x17\xbc\xdc\x9d\xfe\x48\xa4\xa2\xc0\x33\x4d\x5e\x83\xb3\x84\xc1\x00\xa1\x29\xba\x2c\x73\xa2\x28\x04\x6f\x96\x48\x43\x43\xc9\x00\x52\x96\x28\x08\xde\xbc\x0c\xc0\x5b\x64\x64\xa7\xc0\x69\x1f\x08\x7e\x92\x14\x12\x25\xf2\xd3\x04\xb8\x80\x84\x2f\x97\xa4\x48\x4f\x13\x50\x1c\xb0\x0f\xa8\x05\xf5\x46\x84\x19\xcd\xf9\x66\x14\x40\xa0\x27\x1a\x40\xe8\x66\x7f\xfd\x2f\xf2\x26\x70\x62\xf5\x4e\x09\x56\xcc\x23\x5f\xcb\xa8\x5d\x49\x27\x01\x0e\x3e\xfc\x40\xd6\xc4\xe8\x10\x4d\xe8\x30\x5b\x15\x09\xae\x57\x18\x59\x21\x5f\x13\x01\x49\xce\x68\xa1\x60\x02\x05\xdd\xc0\xff\xa5\x82\xbf\x70\xfc\x17\x42\xca\x93\xd5\x92\x16\x2a\x9e\x53\xf5\x2a\xa7\xf8\xf1\xeb\xdd\x9b\x34\xf4\x78\x36\x82\xe8\xaa\xa7\x81\x19\x40\x31\x2f\xc2\x40\x50\x92\xee\x82\x3e\x54\x03\x82\x2e\x79\xb5\xc6\x91\xdc\xe0\x8d\x1e\x24\x53\x54\x20\xd4\x46\x2f\xda\xea\x00\x40\x72\x2a\x54\x18\x68\x42\x69\x12\x20\xf1\x18\xf2\x16\x87\x4a\x70\xe2\x20\xba\xb2\x3d\xf6\xf6\xd3\xde\x61\x39\x1c\xc2\x0f\x05\x90\x62\xd7\x9c\x2b\x50\x21\xb8\xd0\x54\x5e\x12\xc1\xf2\x1d\x6c\x16\xb4\x00\xcd\x24\xc0\xa4\x56\x65\x64\x4d\x58\x8e\x8c\x15\xc1\x86\x3a\x60\x15\xff\x28\x0e\x2b\xc9\x8a\xb9\x5e\x48\xa9\x48\x91\x12\x91\x02\xae\x03\x11\x94\xc4\x6d\x12\xe9\xf1\xfc\xc9\xd2\x03\xba\xa4\x54\x2a\xc1\x77\x61\x64\x8b\x9f\x87\x41\xad\xac\x83\x28\x4e\x72\x96\xdc\x1e\x2e\xea\x41\x53\xad\x51\x82\x28\x5e\xb0\x94\x86\xd1\xd5\x91\x46\x88\x29\x02\xe5\x79\x4e\x4a\x49\xc3\x40\x2e\xf8\x26\xb8\xb7\x39\xc4\x6e\x7a\x41\x14\x67\x3c\x59\xc9\x30\x8a\x25\xcd\x69\xa2\xc2\x7b\x57\xe0\x7b\x5e\xd3\x0d\x89\x4b\x69\x4a\x53\x2d\x81\x48\xbc\x4a\x43\x43\x38\xa3\x09\x59\x49\xaa\x69\x8a\x0a\x19\x98\x92\x34\xcf\x70\x45\xb0\xc8\x01\x89\xe2\x8a\x9d\xab\xce\x2f\x3e\x99\xaf\x2b\x10\x86\xb9\x11\x72\x0b\xea\x53\x98\xbc\x22\x9b\x07\xb6\xbd\x74\xde\xda\x03\xd0\xb8\x14\x9a\xf1\x5f\xd2\x8c\xac\xf2\x0e\x52\x76\xe3\xf3\x44\x11\xaa\x76\xb0\x4e\x09\xfa\xa5\xf8\xa5\x78\xbf\xa0\xf0\xd3\xdb\x6f\x1d\xcd\x13\x5e\x28\xc2\x0a\x43\x79\x5a\x28\x26\xa8\xd1\x8e\x7d\xe0\x45\xbe\x03\xb9\x20\x82\x02\x53\xa0\xf7\x88\x4c\x30\x5a\xa4\xf2\xb3\x6e\x51\xc4\xbf\x71\x5e\xb5\x8d\xd3\x1b\xa7\x6c\x3d\xd5\x7f\xeb\x5d\xf1\x99\x06\x3d\xe8\xb0\x2e\x82\x6a\x93\xc1\x8a\x81\x62\x4b\x9a\xb3\x82\xa2\xc1\xd4\x04\xa1\xcd\x99\xb7\x14\xed\x1d\x00\x0d\xd8\x76\x4c\x78\xce\x05\x4d\x5f\xb2\x75\xd5\xc9\x36\xc0\x6e\x05\x59\xd2\xae\x72\x99\x08\x9e\xe7\x34\xfd\x35\x25\xca\x1b\xad\xf1\x4f\xaf\x1e\x1d\xc9\x45\xb7\xea\x3b\x5a\xac\x2a\x8c\x53\xc1\xcb\x94\x6f\x0a\x48\x72\x4a\x44\xc6\xb6\x06\xb5\x55\xde\x6e\x30\x58\xea\x6e\x82\xe7\x74\x12\x98\xcf\x44\x30\x32\xc8\xc9\x8c\x22\x0e\xb3\x5d\xdd\xd6\x8c\x60\x4d\xa9\x94\xc9\x32\x27\xbb\xd1\x2c\xe7\xc9\xed\x55\xc9\x25\x43\x36\x18\x19\xc3\xf0\x6a\x49\xc4\x9c\x15\x83\x19\x57\x8a\x2f\x47\x5f\x96\x5b\x67\x52\x8d\x73\x66\x07\x2b\x05\x95\xb4\xc0\xe6\xbc\xa8\xf0\x46\x92\x40\x85\xdb\x82\x92\x94\x0a\xa4\x40\xce\xa6\x3d\xd7\x7f\x3a\x26\xa0\xc8\x4c\xdb\xaf\x93\x60\x70\x6e\xad\x19\xa2\x39\x7c\xa2\xb5\xc9\x20\x59\xb0\x3c\x15\xb4\x70\x56\xd5\x33\xdb\x48\xf1\xf9\x1c\x07\x57\x9c\xe7\x8a\x95\xb6\xb4\xcc\x49\xa2\xf7\x9c\x49\x20\xd8\x7c\xa1\x02\x50\x68\xc9\x1b\x58\x40\xf2\x1c\x1c\x3c\xb3\x5b\x82\x5a\x30\x09\x68\x0a\x05\xd3\x77\x0b\xbe\x81\x17\xb6\x1a\x4d\x1c\x83\xec\xe3\x70\x45\x45\xf9\x7b\xe1\x8a\xb0\x1e\xc0\xf5\x1b\x6c\xf2\xa9\xb8\x66\x2c\x57\x54\xfc\x0e\x04\x1d\x76\x60\x4a\xd0\x6a\xe3\x05\x10\xb0\xc3\x4c\x5f\xeb\x7f\x6b\x24\x8f\x63\xd9\x44\xc8\xa1\x9b\xe4\x5c\xd2\x60\xfa\x02\xff\xf1\xa7\x3a\x1e\xae\xf2\x7b\xa4\xc8\x0c\xfb\x9f\x42\x96\x0e\xc5\x08\x39\xd6\xd5\x3a\xe5\x83\x65\xd3\x11\x38\x72\x37\x49\xcd\x8a\x72\xe5\x1b\x7a\x15\x6c\xb3\x4a\xb8\x91\x2e\xd1\xec\x56\x82\xe7\x9f\xc6\x10\x08\x1b\x08\xdc\xd2\xdd\x68\x8d\xf6\x27\x94\x84\x09\x20\x45\x0a\x38\x27\x09\x14\x7d\x42\xb4\xb9\x48\x59\xe6\x3b\xbd\x23\x38\x46\xd4\x4c\xb6\xe0\x79\x4a\xc5\xe4\xa4\x02\x10\xc7\xf1\xc9\xbf\x03\xcb\x58\x3a\xac\x19\xdd\x7c\xc7\x53\x6a\x58\x62\xb6\x52\x8a\x1b\x37\x71\xa6\x8a\x77\x5c\xa8\x77\x8a\x08\xf5\x9e\x2d\x69\x45\xb9\x99\x2a\x60\xa6\x8a\x41\x6a\xf6\xdc\x60\x8a\xcd\xe0\xeb\x1d\x48\x6c\x0a\xb8\xc9\x8c\x87\x06\xd0\x11\x98\xaf\x8a\xf4\x71\x10\x69\x91\x3e\x06\xde\xcb\x95\x68\x32\xce\x51\x80\xa9\x6d\xf9\x00\xc0\x6f\x71\xef\x78\x18\x9a\x16\x8b\x1a\x54\x4d\x5f\x2d\x15\xbe\x7b\x61\x42\x09\x00\x31\xd9\x32\x09\x25\x51\x8b\x7e\xf5\x0d\x77\x64\x6b\x73\x64\x2c\xcf\x47\x50\xf0\x82\xe2\xbe\x0f\x80\x46\xed\x2d\x1d\xc1\x2c\x27\xc9\xad\x2d\x5a\x90\x92\x0e\x04\x2d\x52\x8a\xfe\xcc\x08\x12\xc1\x64\xf9\x2a\x9d\x53\x89\x0d\xf6\x15\x58\xe4\x76\x07\x16\x83\x06\x19\x59\xb2\x7c\x37\x02\x49\x0a\x39\x90\x54\xb0\xec\xaa\xae\xb4\x11\x85\xb3\x72\x5b\x01\x71\xc6\x82\xd9\x48\x9f\x0a\xe9\xa2\x86\xf4\xcc\x41\xba\xb0\x98\x19\x50\x4a\x90\x42\xa2\xf8\x8d\xd0\x34\x2a\x24\x3a\x8b\xe1\x59\xb9\xed\x5f\x9e\x95\x5b\x6b\xff\x0c\x96\x72\xf0\x40\x3b\x18\xfe\x09\xde\xbc\x82\xbf\xc0\x9f\x86\xa6\xcb\x86\xce\x6e\x99\x7a\x4c\xb7\x77\x24\x23\x82\x69\x51\x7d\xb1\x10\x7c\x49\x2b\x18\xfc\x31\xdd\x7f\x28\xa9\x20\x55\x97\x25\xff\xed\x31\x9d\x5e\x33\x41\x33\xbe\x35\xdd\x90\xce\xcf\x9c\xe9\x05\x71\x6d\x6b\x59\x6a\x2f\x28\x6e\x3d\xa3\x0b\x5c\x16\xd8\xb0\x54\x2d\xec\xe7\x2c\xe7\x44\x8d\x72\x9a\xa9\xab\x03\x30\xcf\x50\x2f\x5a\x00\x4e\x2d\x03\x2b\x70\x01\x06\xc6\xd4\xd1\x55\x56\x27\x23\x8c\x11\x9c\xc5\x97\x74\xe9\x40\xc5\x19\xcf\x73\xbe\x91\x83\x4c\xf0\xe5\x40\xbb\x12\xf7\x73\xe7\xb3\x3f\xff\xf9\xcf\x7e\xc9\xc0\xa0\x0a\xe7\xe5\xb6\x51\x8c\xc1\x3f\x22\x04\xd9\x8d\xe0\x8b\xfe\x65\x85\xb9\x67\xfd\xf5\xe1\xd9\xc1\x2e\xf6\x89\x9c\x07\x50\xed\x42\x40\x66\x92\xe7\x2b\x45\xaf\x9a\x44\xa9\x67\xf2\xdb\x40\xab\x56\x94\x80\xb3\x2e\xbc\x20\xae\xb6\x22\xb4\x30\xa7\x39\x9b\xe2\xae\xd3\xa6\xb2\x47\xde\x92\xa4\xa9\x16\xcf\xcb\x72\x0b\x17\x56\xae\xd0\x5d\xa5\x44\x8c\x60\xc6\xd5\xc2\xc3\x7c\x63\xd6\x19\xbe\x30\xa3\x03\xe8\xc5\xb2\xab\x0f\xe7\xf1\x17\x17\xff\xfa\xe5\x9f\xcf\xbf\xb8\xb4\x30\x90\x4d\x46\xf0\xec\xf2\xd2\x16\x6c\x16\x4c\xd1\x81\x2c\x49\x42\x71\x52\x1b\x41\xca\x83\x18\xe4\x27\x46\x3c\x70\x77\x81\x09\xc6\x73\x7f\x66\xf2\x25\x51\x64\xbf\xbf\xaa\x2a\xd1\xb6\x7c\x6f\x65\xfb\xc5\x02\x75\xbf\x6e\xf9\xae\x5d\xec\xf7\xa9\x23\x5a\xef\x77\x25\x95\x06\x76\x1d\x1e\xd3\x85\x7e\x7b\xcd\x4a\x30\x41\x07\x3c\xb6\x5e\x15\x15\x41\x14\xeb\xf2\xd0\xf3\x93\xe9\x12\x12\x5e\x60\x34\xd4\x78\x5d\x66\xe3\x0f\x59\x01\x74\x09\xab\x82\x29\x19\xe1\x26\x5c\xb2\x2d\xcd\xa5\x29\xd0\x92\x2f\xa8\x5a\x89\x42\x02\x53\xc6\x31\x76\x64\x00\xba\x0c\xe9\xf2\x27\x6c\x57\x7b\x84\x88\x11\xae\xd8\x3b\xf6\x1b\x85\x09\x94\x44\x48\xfa\x1a\x65\x31\x7c\x1e\x9e\xcc\x78\xba\x3b\x89\xe2\x44\xca\xf0\xa4\x62\xc8\x93\xc8\xaa\x32\xb0\x23\xd5\xfd\xff\x04\x16\xbe\xf5\xf5\xaa\xa9\x14\xab\xe5\x6b\xc1\x97\xaf\x3c\xec\x70\x46\xc5\x6a\x39\x43\x8b\x45\xf0\xa5\xf5\x2b\x53\x0c\xbd\xe1\xc7\x92\x2b\xf4\x32\x49\x9e\xef\x60\x4e\xc4\x8c\xcc\xab\xa0\x8b\x54\xb8\x4d\xf4\x81\xc6\xf3\x18\x02\xa7\x8a\xdf\x28\xba\xfc\xf5\xfc\x8b\x2f\x2e\x03\x18\x4c\x01\x3f\x34\x27\x5f\xa3\x10\x4a\x25\x6a\x02\xd8\x39\xe8\x89\xbf\x29\x14\x56\xc6\x4b\xa2\x92\x45\x38\x0c\x7f\x49\x4f\xa3\xe7\xc3\xe8\xfa\xec\xa6\x0f\xe7\x67\x76\xda\xf5\xac\xde\x14\x0c\x31\xc4\x99\xcf\x38\x57\x52\x09\x52\x82\xb5\xb1\xa4\xa1\xfd\xf3\xf0\xe4\xba\xd3\x04\xbb\x39\x89\x62\xfb\xd9\x5f\x73\x49\x95\xf3\x05\x7e\x66\x92\x61\x1c\x75\x43\xf2\x5b\x64\x00\xc1\x57\xf3\x85\x26\x13\x02\xd4\x2b\x9d\xb1\x22\x95\x4d\xab\x3d\x64\x45\x92\xaf\x50\x50\x1d\xc8\x94\x61\x3c\x4a\x01\x2f\xa8\x8c\x1c\x79\xe7\x6c\x4d\x0b\xed\x81\xbc\x79\x19\xc3\x1b\x05\x4b\x22\x6e\x25\x50\x92\x2c\xb0\x21\x86\x87\xd7\x76\xfc\x50\x89\x15\x05\x2e\x1c\xbc\x8c\xe4\x92\x46\x71\x93\xba\x87\x78\x87\x06\x78\xdf\xc1\xa9\x29\xfe\x3c\xc6\x61\x42\x9c\x85\x17\xab\x60\x7d\xe0\x6a\x41\xbd\x95\x01\x60\x59\xa8\xcb\xe2\x52\x9f\x1e\xe0\xd1\xcc\x9b\x97\xf0\xd9\xc4\x22\xee\x37\x75\x0b\xe9\x58\x13\xb9\xcf\x7d\x32\x30\xdc\x7c\x26\x0e\xa3\xba\x69\x07\xf6\xa6\x4f\x7b\x0e\x07\xd1\x8c\x6a\xe1\x92\x9c\x17\xf4\x87\xd9\x87\xef\xf9\x4b\xae\xa4\xf9\x2a\x3d\x52\xf3\xd9\x07\x9a\x28\x08\x71\xb1\x78\x06\x4c\x9d\x48\x34\xb0\xa5\x5e\x47\x6d\x24\xcb\x08\x17\xc2\xc1\xf3\xc5\x44\x03\xeb\xc3\x6c\x65\xa3\x2b\x08\x43\xf7\xb5\xea\x03\xe3\x8e\x29\x8e\x1a\xc6\x11\x08\xaa\x6d\xf0\x54\x37\x75\xd0\x56\x68\x5b\xc9\x84\x0b\x2a\x63\x78\x8f\x8e\x32\x93\xb0\x92\x34\x5b\xe5\xe0\xa2\x6c\xaf\xf1\x2f\x25\x28\x51\x16\x33\x04\x60\xe0\x12\x09\x24\x49\xa8\x94\x5c\x48\x07\x92\x15\x8a\x83\x5c\xcd\x06\x66\x66\x12\xe3\xea\x0a\x72\xa6\xa8\xd0\x42\x8b\x88\xdf\xd2\x5d\x9b\x51\x9a\x74\x0a\x79\xbd\x86\xa8\x89\x0a\x43\xbd\x09\xdc\xed\xaf\x9a\xdc\xc2\x3d\x56\xb9\xed\xc3\xda\x5f\x7b\xd3\xeb\xfa\x36\xb6\x73\x0f\x87\xbf\xc4\xc3\x79\xff\xe4\xd7\x93\xe8\x06\x26\xb0\x6e\x2d\x5a\x25\xf3\xa6\x5f\x7b\x25\x8d\x2b\xe3\xf8\xe1\xf5\xea\xb7\xdf\x76\x48\x2a\x69\x09\xc4\x21\xc3\xa2\x81\xa4\x44\x24\x8b\x43\xb9\x0c\x1d\x1c\x59\xd2\x84\x65\x78\x90\x95\xef\xfa\x9a\x13\xd0\x8c\x31\x0b\xae\xc8\x5c\x46\xfa\x13\xfa\xdd\x2d\x11\xa6\x26\x26\x89\x6b\x4f\x14\xa4\xdc\x01\x44\xfa\x6a\xcd\xd4\x22\x69\x07\xc2\x95\xf0\x99\xba\x9a\x58\xc3\xa1\x99\xc6\x02\x97\x14\x72\xb6\x64\x66\x97\x42\xbd\x70\x79\x01\xc9\x82\x08\x92\xa0\x77\x67\xa7\x57\x12\xa5\xa8\x28\xd0\x6c\x67\xc5\x5c\xf6\x41\x72\xd8\x50\xf8\xb0\x92\xaa\x86\x28\x73\x96\x68\xca\x5c\x5e\x00\x2b\x12\x22\x29\x48\xbe\xa4\xa8\x47\xb4\xab\x28\x61\xc9\x05\x85\x70\xb3\x60\xc9\x02\x36\x7c\x95\xa7\xe0\xf3\x1c\x07\x41\x98\xa4\x35\x40\x52\x00\xdd\x26\xb4\x44\xcc\x2c\x03\x81\x5d\x17\x98\xd8\x0f\xb1\x1e\x35\x3c\xeb\xc3\xe5\x85\x53\xa0\xba\xf3\x5b\x8a\xa7\x97\x6c\x4d\xf3\x1d\xa4\x54\x26\xe8\x71\x69\x66\x45\xad\xa3\x35\x87\xde\xe6\x51\x68\xec\x02\xe0\xc7\x4a\xf3\xb9\xb0\x47\x0d\x90\xaf\x2a\x72\x08\x2a\x57\xb9\xb2\xba\xdd\xda\x13\x76\x88\x09\x14\xab\x3c\x77\x1c\xe6\x06\x9e\xd4\x5c\xeb\xeb\x30\x9f\x7b\x1f\xaf\x0e\xf5\xf4\x5e\x2c\x28\x9e\x37\x2c\x88\xd2\x3c\xa5\xe7\xb3\xa1\x27\x82\x42\xce\xf9\x2d\x4e\x85\x28\x8c\x90\x13\xb3\x27\x34\x15\xbe\xc1\xa1\x09\x10\x21\xb8\x09\xdd\xab\x74\x8f\x4d\xa0\x4b\xf9\x56\x02\x55\x0d\xf3\x23\x15\xe8\x47\x60\x34\x09\xe5\xc7\x51\x94\x17\x75\x30\x4c\x9e\x68\xc5\x13\xc3\xdf\x29\xa4\xdc\x94\x13\x7b\xfa\x92\xe7\x4d\x70\xba\x3d\x2c\xc8\x9a\x02\x4b\xd1\x52\x48\x88\x55\x8a\x8a\xd7\xb0\xfb\x5a\xc6\x34\x97\x6d\x08\x8a\x94\x13\x4a\xdd\xb4\x09\xd1\xef\xe7\xd3\x03\x17\x59\xc0\xe4\x40\x73\x69\x1a\x09\xb2\x41\x1b\x32\xba\x6a\x75\xc8\x70\x48\x73\xfa\x80\xa3\x87\xd7\xe2\xa6\xdf\x22\x19\xca\xc9\x3b\x5a\xa0\x45\xbf\xa6\x23\x3c\x12\x91\xb4\xdf\x68\x21\x17\x28\x2a\xe8\x9a\xa3\xf7\xb5\x6a\xd5\xaa\x85\xa0\x12\x43\x2d\xda\xd9\xe9\xdb\xd2\xe1\x10\xbe\x82\x9c\x6f\xa8\xa8\x1b\x20\x3b\x68\x09\x44\x29\x4e\x54\x1f\x16\x6c\xbe\xa0\x02\x8b\x73\x2a\x2b\x6e\x36\xff\x23\x61\x46\xf0\x83\x56\xea\x31\x7e\x09\x45\xd4\x47\xfa\xe0\x3c\x21\x63\x34\x4f\xe5\x51\x5a\xed\x0f\x08\x61\x25\x06\xc5\x76\x25\x69\x6c\x56\x3d\xb4\x6a\xe9\xaa\xd7\x5c\x82\x97\xb4\xa4\x05\xda\x2e\xc0\x0b\xd8\x2c\x28\x92\x18\xcf\x4b\x91\x03\x90\x89\x8f\x72\x0e\x20\xf7\xd1\x14\x56\x65\x13\x20\x9e\xf4\x59\x0c\xfa\xb5\xb8\xb0\xda\xb8\xe1\x02\x16\x2c\x4d\x69\x63\x16\x6d\x7b\xc1\x42\x88\x73\x5a\xcc\xd5\x02\xa6\x70\x76\x88\xb8\xa7\x67\xb4\xda\xc6\x81\x4e\x64\xa5\xd4\x7d\xf0\x56\x37\x58\x0e\xb2\xa6\xcc\x55\xef\x90\x86\xfb\x5e\xb3\x43\xa3\x69\xbd\x61\x25\x7c\x89\xa2\xa9\x8f\x8a\xa5\xfb\x26\x41\x6d\xb8\x35\x2c\x9c\x0e\xa8\x3d\x15\x64\x7f\xb8\xc5\x4d\x9d\x0b\x4d\x6f\x55\xed\x32\x4c\x49\x10\x74\xce\xa4\xa2\x02\x4f\x56\x31\x16\x18\x4a\x4a\x5d\xae\x4b\xcb\xb5\x89\xfa\x56\xf6\x11\x0a\x81\x82\xce\x09\xf2\xb3\x83\x66\x2c\xfc\x3e\xfc\x46\x05\xc7\x95\x24\xd6\x87\x5d\x3b\xe3\x3f\x06\x8b\x37\xcf\x60\x55\xdc\x16\x18\xd3\xbd\xa5\x3b\xd9\xc7\xd6\x16\x7d\x24\xa8\x03\x98\xe8\x49\xc0\x8c\x1a\x57\x25\x45\x4b\x55\x2d\x28\x13\x38\xa5\x13\xa9\xf1\xed\x03\x9e\x45\x59\x42\xe8\x16\x76\xfb\x6a\xdb\x22\x3e\xe1\xc2\xdb\x3e\x90\x3e\xcc\x6a\xcd\x86\xec\xbb\x85\x09\x96\xee\x60\x02\x33\xb7\x2c\x72\xc3\xd0\x3d\x68\xf9\x7d\xd7\xb7\x37\x75\x57\x94\x6d\x08\xcc\x0c\x83\x91\x2d\x04\xd8\x36\x3d\x2c\x5f\x6d\xec\x9a\x55\x33\xaf\x6a\x26\x28\xb9\xbd\x6a\x40\x46\xa7\xa7\x05\xf7\x25\x51\x14\x55\xb6\xa4\x07\x70\xbd\xaa\xa3\x70\x1d\xaf\xb1\x2c\x64\xf2\x7b\xf2\x7d\xb8\x8d\xe0\xe3\x47\x30\x9f\x77\x51\x3d\x35\x33\x0b\x52\x83\x69\xd0\x66\xdf\xb4\xb0\xb6\x30\x86\x1d\xfc\x15\x06\xe7\x30\x82\x70\x0b\x53\xfd\x0d\xbf\x1c\x7a\x53\x3a\x0f\xe4\x87\x52\xc2\x92\x94\xd6\x13\xd1\x45\x6e\xe3\xe7\x18\x9c\x52\x78\x4a\xcc\x81\x80\xa2\x52\x21\x5f\x93\xe6\x2a\x56\xc0\xb4\xc8\xd6\x07\xc3\x15\xf0\x49\x35\x91\x60\x3c\x09\x46\xf5\x86\x9b\x44\x70\xe7\xd0\x4e\x60\x3c\x81\xb3\x2b\xd8\x3b\x8d\x1b\x4c\xef\x69\x3b\x6d\xb5\x1d\x07\x23\x38\xd6\x76\xdc\x02\x7b\x4f\xd3\xa9\xd7\x74\x6f\x15\xce\x70\x68\xd8\xfe\x2d\x4e\xc7\x7c\xc4\x9d\xbe\x41\x27\x2d\x34\x20\x57\xc9\x02\x39\x3f\x98\x4e\xbe\x3c\x3b\x0b\x8c\x66\x42\xd9\x76\x64\x74\xf0\x70\x83\xd4\x65\x45\xea\x8b\x32\x1a\x33\xc0\x32\x58\x57\xf9\x0f\x66\x94\x96\x08\xd5\xd8\x84\x9e\x49\x8e\x14\x5f\xa2\xe5\xed\x3c\xe9\xff\x17\x8e\x27\x1f\xa7\x93\x8f\xe3\x8f\xd3\x28\x8c\xb5\x53\xed\x38\x86\x65\xe1\x67\x4b\x9f\xbd\x2c\x01\x7c\x6b\xaa\xc5\x55\x77\xbc\x1c\x55\x2b\x7a\xbd\xbc\x3e\xbf\xb9\xe9\xbb\x39\x8c\x60\x79\x7d\x71\xb3\x6f\x33\x57\xd3\x46\xfe\x77\xf2\xa9\xb5\xd7\xe0\x4e\xcf\x90\x6d\xd1\xc9\x36\x9c\xad\x61\xeb\xad\xcb\x81\xf4\x3c\x6e\xb3\xe3\xc5\xb6\xc6\x35\xf8\x4a\x1b\xe1\x89\x72\x3b\x2f\x93\x60\x92\x0d\x53\x98\xed\xcc\x71\x0d\x98\x38\xa7\x2b\xc1\xe8\x2b\x66\xbb\xa4\x40\xe0\x1f\x2b\xae\xa8\xf5\x34\xdb\x90\xe1\xdf\xe8\x6e\x14\xd0\x6d\x49\x93\xaa\x4d\xd0\x6a\xf3\x9a\x0b\xb0\xc9\x84\xa3\x56\x15\x7c\x4f\x96\x74\x14\xbc\xa5\xff\x58\x51\xa9\xda\x1d\xbf\xb2\xdc\xf9\x34\xac\xfb\x95\x60\x33\x69\x6d\xf1\xe1\xb0\x56\x01\xe1\xb8\x0f\xe3\x49\x1f\xa6\xb8\x4b\x4c\x27\x91\x9d\xa4\xc6\x3c\x86\xef\x57\x4b\x2a\x58\xa2\x0b\x51\x53\x7a\x1b\x9f\xc4\xad\xc1\x81\xb3\x9a\xc3\xec\x10\xab\x64\xd1\x87\xec\x9e\x59\xbe\xa3\x62\x4d\x45\xfc\x96\xca\x92\x17\x12\xb3\xaf\x88\x5a\xc9\x17\x3c\xa5\xa3\xe9\xe4\x8b\xb3\xb3\x56\xfb\x37\x59\x75\x70\x0a\x29\xa7\xb2\x76\xdf\x80\x32\xdc\xf9\xab\x5d\x79\xc6\xd7\xb8\x9b\x69\x47\x4b\xf6\x5b\xac\xea\xc0\x49\x96\xd3\x42\xe5\x3b\xb4\x13\x73\x09\x2e\xe9\x08\xed\xcc\x81\x71\x59\x7c\xe3\x88\x15\xf3\x96\xa0\x36\xa1\xde\xe7\x1f\xfe\x4c\x72\x86\x49\x0e\xde\xb9\x9e\xb3\x5e\x50\xae\x65\x99\x33\xf5\xba\xed\x8b\x61\x61\x18\x8c\xea\x7c\x0f\x96\x85\x5e\x4b\x67\x3a\x7d\x36\x81\x0b\x5f\xd6\x87\x43\xf8\x8e\x49\x9d\x38\x65\x98\x15\x17\xa0\xc1\xe6\xfd\x3a\x57\x48\xf1\xc6\x1c\x11\x3f\xcf\x6c\x7b\x84\x17\x7c\xd5\xd2\x31\x6d\xf5\x82\xd3\xbb\x85\x89\x3f\xc5\xeb\xb3\x1b\xd7\x0a\x6b\xd7\xad\xda\xf3\x46\xad\x61\xf4\x49\x53\x29\xba\x06\xa8\xe7\x4c\x83\xcf\x3f\x87\x70\x7d\x7d\x76\x03\x9f\x4d\x26\x70\x12\x9c\xe0\x3e\xbb\xbe\x5e\x5b\x1a\x0d\xce\xab\x8a\xe8\x08\xa9\x7c\x59\xfe\x8f\xa5\x58\x35\xa9\x16\xa6\x98\x99\x58\x42\x4e\x49\xea\xdc\x6c\x25\x08\xcb\x2b\xe4\xa5\x89\xf9\x6a\x79\xad\xcd\x18\xa4\xee\xda\xfa\xf5\xe7\x7d\xa8\x29\x52\xe1\xb1\xef\x35\xa3\x42\x7f\x7c\x0c\xb1\x77\xe0\x7a\xb3\xac\xf6\x24\x4c\x18\x05\x8d\xe9\x2a\x8c\x67\x64\x1c\x67\x8a\x71\x83\xa6\xfc\x18\x26\x9a\x73\x3c\x56\x68\xf8\x8f\xd7\xb7\x15\x23\x69\xa2\x1e\xd0\xf4\xd0\x29\x41\x28\xc8\x27\x18\xda\x33\xfa\xf4\xf3\xcf\xed\x16\xcd\xcb\xb0\x61\x14\xa1\x69\x3b\xe7\xaa\x5f\x55\xeb\xdd\x3e\xf2\x56\x77\x0f\x34\x97\xf4\xc1\xf1\x26\x13\x58\x7b\x9d\x8e\x70\x52\xc3\xb1\x39\x60\x25\xe7\xde\x58\xd2\x0e\x87\xf0\x77\xcc\xb5\x44\x92\xae\x24\x15\x26\xc5\x40\x1b\xfd\x14\xf4\xa9\x3f\xb8\xc3\x6c\xd3\xc8\x9e\x61\x01\x9e\x5a\xf5\x31\x16\x85\x11\x34\x3c\xeb\x80\xbf\x57\x8a\x3d\xa5\x49\x8e\x2e\x80\x8b\x20\x10\x90\xb4\x24\x02\x95\x5a\xa5\x10\xa5\x75\xd4\x34\xb2\x0d\xa8\xc0\x14\x5d\x4a\x48\xea\xbd\xf9\x1f\x2b\x96\xdc\xe6\x3b\x74\x15\xe9\x01\x12\x38\xc0\x86\xe6\xb9\xf1\x92\x74\x2e\xd2\x41\xd0\x53\x6d\xf1\xcc\xed\x2b\xfd\x4d\x4f\xca\x4f\xfa\x3b\x9e\xf2\x67\xb2\x07\xab\x33\xbb\x56\x16\xe7\xde\x9d\x30\x34\xce\xf5\xc8\x75\x47\xfe\x04\x9e\x36\x60\x96\xa0\xce\x3c\x0c\xfa\x1d\x08\x79\x67\x10\x8d\x4a\x3c\xfa\xd2\x29\x4a\x36\xe9\x92\xe1\xde\xb8\x74\x79\x2d\x55\xd6\xa6\xc5\x40\xd3\xef\x44\x02\xf6\x72\xe0\x1c\x5b\x68\x35\xd0\xc8\x76\xb2\x2b\x2b\xef\xa3\x96\x1b\x3f\xa4\x1d\x27\x09\x9d\x74\x75\xc4\x43\x51\x33\x32\x0e\x93\x0e\x4a\x22\x95\xc2\x00\xff\x36\xb1\x8e\x20\xb2\x1c\x7b\xd5\x3b\x7a\x28\xe0\x58\xda\x21\x62\x5b\xba\x23\xa8\x6f\xf0\xc0\xba\x5e\x1d\x47\x00\x93\x13\xba\x20\x45\x9a\x53\x21\x35\xc9\x8c\x0d\xe8\x33\x11\xce\x73\x88\x13\xb5\x44\x89\x1f\xb3\xb8\xcd\xb4\xba\xf6\x22\x3b\x82\x6a\x5e\x3b\x4e\x55\x8c\x2c\x45\x55\xd4\xe1\x81\x11\x9b\xc9\x71\x9f\x38\xa2\x0e\x75\x45\x8d\x9c\xe0\x06\x8d\x2a\xae\xb2\xe6\x93\x5c\xcd\x90\xaf\x1e\x45\x12\x9b\x88\x74\x2f\x66\x76\xd9\x30\x98\x8a\x3c\xa3\x87\x2a\x38\x26\xc4\x36\xd6\x24\xb6\xed\x8e\x70\x59\x0d\xe5\xa5\x39\x2d\xd7\x70\x1a\xb8\x36\x24\xd8\x3b\xff\x8f\xf1\x24\x00\xa5\x59\x2d\xf3\xb0\xc5\x9a\xcd\xca\x5a\x49\x77\x42\xc2\x94\x6d\x29\x43\x27\x0f\xde\xc1\x7d\xa0\x4f\xee\x03\xe7\x69\x02\x98\xb4\x88\xd6\x60\xb6\x7f\x80\x95\x41\x54\x37\x56\xbc\x3c\xda\x56\xf1\x32\x88\x5a\xca\xbc\xb1\x2c\xfe\x44\xcd\x72\x9c\xb4\x13\xc3\xfd\xa5\xff\xc6\x29\x55\xbb\xda\x16\xca\xc0\x52\x12\x36\x47\xb7\x87\xc4\xdb\x1e\xe2\xde\x71\x2c\x1e\xa5\x12\xbb\x38\xe4\x51\x9a\xb9\x1e\xa8\xad\x9f\xa3\xab\x23\x7b\x1c\x06\xa9\xa4\x3e\x23\x51\xda\x52\xb0\x61\xc3\x8a\x04\x9a\x05\xcd\x71\x7f\x95\x75\x47\x6d\xde\x5d\xdf\x81\xdc\xd0\x83\xfc\x3b\x34\xf8\x3a\xb3\x4d\xf1\x36\x8e\x98\x53\xe5\x05\xfb\x1f\x5a\xb0\x5b\xba\x5b\x95\x9d\x49\xea\x2c\x0b\x29\x46\x86\xd1\xf5\x41\x43\xea\xfc\xb2\xae\xab\x4c\x28\x5c\xd9\xef\xb9\x32\x38\xc7\x2d\xb3\xd1\x5f\x75\x8b\x83\x96\xb8\x3e\xcc\x05\x99\xb5\xf1\x05\x54\xb9\x48\x07\x37\xc9\x05\xad\x66\x18\xff\x4e\xca\xbe\x65\xc1\x38\x45\xff\x3c\x44\x13\x22\x8a\xd7\x24\x0f\xa3\xe8\x09\x6b\x7f\x6c\x53\x70\x2c\xe1\xe8\xea\x94\xcb\x0f\x25\x2d\x50\x19\xa7\x44\xad\x96\x7d\xe0\xb3\x0f\x35\x4d\x1f\x37\x9e\xd7\xea\xd8\xa4\x0d\xdc\x23\x1d\x9a\x7a\x47\xe3\x11\xeb\x3c\xb9\x7b\x46\x78\x9a\xee\xc1\xf0\xe4\x9c\xfe\xef\x96\x96\x31\xa5\xff\xe7\x40\xa1\xd8\x58\x8f\xde\x2b\x2c\xf1\x5a\xa4\x6b\x51\xb8\xa2\x97\x13\x37\x41\x67\x2b\x96\xa7\xee\x56\x8e\x6b\xae\x85\x24\x49\xf8\xaa\x50\x7a\xa3\x49\x16\x68\x15\x4b\x6d\x4b\x2e\x57\x52\x41\xc6\x84\x54\x40\x97\xa5\xda\xd5\x10\x99\xd2\xf1\x88\x9c\x2a\x9a\xef\x1c\xd7\x61\x0a\x4f\xeb\x1e\x42\x14\xeb\x8e\x55\x4e\x87\x66\x76\xbc\x59\xa6\xcf\x4c\x35\x22\xd6\x7a\xb0\x29\x01\x55\x74\x1e\x75\x94\x46\xa8\x24\xc6\xcd\xd3\x5a\x21\xbd\xac\x60\xfb\xbc\x6e\x61\xbc\xc4\x3e\x13\xb8\xbe\xb9\x7a\xd0\x2f\xf2\x39\x4a\xfb\x18\x9f\xf1\xd9\x07\x67\xdf\xfb\x55\x95\x08\x57\x25\x4e\x6c\xc1\x1f\x36\x2e\x57\x72\x11\xfa\x0c\x55\xaf\x1d\xcb\x42\xbf\xa5\x75\xfe\x27\x13\x38\xeb\xd0\x14\xf6\xbb\xb5\x97\xcc\xf4\x74\x06\xe1\x7b\x93\x1e\x53\x9d\xac\x7a\xf5\x48\x12\x94\x51\xbd\xf4\xfe\x21\x2b\xe6\x2c\xb0\xa2\xaf\x0f\xb2\x55\x1f\x74\x0e\x9c\x3f\x26\xcb\x6c\x13\xbf\xd0\x1e\xe4\x32\xf4\x3f\x51\x2d\xba\xc4\xc3\x93\x6a\x42\xf8\x7f\xca\xd6\x31\x06\x07\xc3\x13\x2f\xad\xd1\x65\x47\xa1\xbb\x3d\x17\x7c\x55\xa4\x03\x5d\x79\x52\x19\x89\x48\xab\x6b\x8d\xc7\x4d\xac\xd1\xbe\x3e\xbb\x31\x00\x8e\x40\xd7\xd9\x8e\x98\x1d\x44\xb7\x2a\xec\x00\x92\xad\xf2\xfc\xdb\x86\x60\x76\xf7\x27\x4a\x89\x30\xd0\x39\xfe\x41\x27\x36\x4e\xba\x3d\x28\x8a\x95\x46\xfe\x1f\x3d\x2e\xf6\x40\x33\x54\x2b\x4a\x54\x98\x41\x95\x1c\xab\x33\xb2\x82\x53\xdd\x1d\x73\xa8\xee\xf7\x37\x11\x50\x53\xa3\xd5\x8c\x57\xf1\x46\x33\xe9\xaa\x21\xd5\x7a\xc5\x7d\x11\xd1\x05\x30\x81\xf4\x32\x76\x8d\xaa\xcb\x74\xcd\x3f\x36\xf5\x4e\xff\x7d\xa4\x85\x54\x24\xb9\x3d\xd6\xdd\x24\x9e\x86\x77\x5a\xcd\xd1\x65\xf8\x3f\xf0\x50\x4c\x67\x3a\x9e\xf5\xb5\x92\x3b\xeb\x83\xbd\x29\x70\xb6\x3f\x02\x43\xf3\x5c\xb5\xdd\x42\x98\xf6\x81\xd9\xed\x00\x6d\xe9\x06\xc3\xeb\x8c\xac\x9a\xc7\x23\x38\x06\x74\xc9\x57\x92\xf2\x95\x7a\x2c\x5c\xad\x6c\x1f\x03\xb8\x79\x83\xad\x0d\xb5\xb3\x0f\xc0\x86\x15\x29\xdf\xc4\x39\x4f\xb4\xef\x18\xe3\x1d\x11\x5c\x1f\xc4\x25\x5e\x89\x2a\xda\xdf\xfe\x33\x1c\x9a\x4b\x6b\x78\xed\x33\xc6\xe0\x60\x31\x67\xd9\xce\x6e\x51\x36\x80\xd2\xd7\x3a\xa2\x0f\x17\x7e\x54\xc3\xff\xaf\xda\x79\x0f\x98\xc8\x68\x19\x5b\x87\x8c\x63\x74\x8e\x66\x9b\x32\xb4\x72\x74\xa2\x13\xe7\x4f\xfa\x70\xa2\x15\x72\x59\xab\x06\xe4\x5b\x9e\x65\x92\xaa\xf0\x7a\x70\x7e\xd6\x07\xcd\xe8\x1e\x38\xb9\x9e\x1b\x70\xd6\x04\xee\xd8\x32\x48\x59\xe2\xf9\x6e\x20\xd7\xf3\xc0\x09\xae\xe6\xc6\xa0\x0f\x47\xb9\x12\xf7\xf7\xd5\xd2\x97\xd4\x28\xc6\x64\xa3\x50\x2f\x5f\x67\x0f\x9d\x3b\x1b\x06\xc8\x6a\x59\xce\x37\x41\x1f\x02\xdb\xbd\xb2\xe8\xfd\x3f\x06\x9c\x62\x65\x73\x42\xd6\x0c\xf3\xb4\x2e\x9a\x04\x51\xbd\xec\x2c\x03\x5d\xe4\x14\xff\x18\xce\xbf\xf0\x8e\xb6\xb0\xea\x0a\xf6\xad\x7d\x40\x5f\x96\x8d\xe5\x6a\x26\x95\x08\xcf\xfa\xda\xaa\x3c\x85\x20\x8e\xe3\xc0\x91\x7a\xef\x3e\x20\x16\xcf\xb5\xfa\x92\x30\xe9\xd8\x85\x0d\x2c\xf7\xcd\xa4\xfb\x07\xf5\x24\x30\xea\x4c\x6e\x4d\x2b\x4c\x23\xd0\xde\x78\xd5\xd7\xa6\x5f\xe1\x75\xc8\xe4\x76\x80\x37\x7e\xe3\xc6\x2e\xfc\x41\xea\x78\x7e\x71\xe2\x67\x40\x51\xba\x44\xbb\x42\xe7\xa3\x10\xd8\xa0\x33\x88\xd9\x71\x25\xde\x10\x37\x89\x2c\x94\x48\x56\x5b\x0e\xf6\x9c\x00\x3f\xf8\xd9\x2e\x33\x0c\xd5\x22\x97\x54\x46\x0b\xa2\x68\x31\xc2\x50\x5a\x51\x99\x33\xe8\x99\xb8\x1a\x08\xd5\xc2\x4b\x9f\x7a\xf7\xf3\xff\x04\x41\x13\x15\x19\xb3\x19\xe3\xe4\x3a\x26\xed\xba\xbe\x79\xe9\x72\xb1\x30\x65\x48\x42\xce\x30\xe7\xbd\x95\x49\x1b\x44\x5d\xb8\xe2\xad\xd0\x9c\x48\x65\x4f\xef\x8d\xed\x62\x12\x8e\x10\xb2\xd6\xf5\xe6\xac\x11\xa3\x9f\x1e\x6f\x1e\x37\x99\x60\x3e\xb5\xb7\x8f\x71\x1d\x0e\x93\xb6\xdd\x82\x1b\xd8\x13\x3f\x91\xd7\x99\xe7\x48\x8b\x4a\x52\x59\xea\x65\x28\x9b\xae\x9a\x01\x90\x53\xf4\x07\x69\x77\xb4\x8a\x1f\xc0\x93\x4e\x0d\xb0\x2a\x07\xd0\x3e\xa2\xd1\xa3\x6b\x2a\x7c\x3f\xb1\xad\xe8\xee\x53\xd1\x38\x9e\x87\x13\xc0\xfe\xc8\x18\x2b\xd5\x1a\xe2\x7e\x0d\x6d\xe0\x76\x40\x3b\xf0\x6a\xdb\xd8\x1e\x51\xc6\x1d\xfb\x7e\x4b\x33\xef\xa3\x4e\xba\x69\xca\x3e\x9a\x70\x8f\x20\xd6\x1f\x4a\x22\x64\x38\x9b\x84\x64\x30\x8f\x59\x51\x50\x81\x2f\x62\x44\x51\x3d\x3d\xcf\x71\xc7\xcb\xcd\x18\x48\xb6\x0e\x10\x7a\xab\x10\xea\x0c\x74\x7d\x77\xce\x68\x8b\xc8\x5e\xb8\xde\x50\x3c\xaa\xd4\xfd\x7c\x58\xb6\xaf\x76\x75\x51\xef\x38\xfb\x05\xb9\x46\x0b\x2c\x29\xe6\x79\x65\xe6\x5b\xab\x14\x95\x7c\x63\xff\x68\x32\x3d\xda\x55\xb8\x13\x10\xfd\xb1\x5e\xa8\xe7\xe1\x35\x36\xeb\x83\x9e\xde\x8d\x8d\x75\xd4\xc8\xfb\x34\xa4\x7e\xde\x41\xa7\x3f\x7a\xc8\x16\x75\xc4\x10\xff\xb4\x04\xf1\x0f\x1c\xcb\x63\xbf\x54\x90\xcd\x6b\x7d\x74\x2c\xf1\x72\x41\x28\xd7\xf3\x46\x6f\xdb\xc7\x1a\x8f\xc3\x61\xbb\x83\xfe\x8e\x6b\x8a\xb7\x78\x68\x8a\x57\x54\x6e\x0f\xee\x22\xd4\x49\xa3\x26\x44\xe3\x60\x99\x9b\x81\xd6\x75\xc3\x05\x94\x60\x54\xaa\x39\x82\x37\x70\xf0\x54\xe2\xa7\x02\xd5\x6b\x15\xb0\xd0\x69\x68\xd2\x76\x71\xc0\xf0\xac\x02\x8f\x87\x0b\x2a\xf1\x00\x7e\x46\x0b\x4a\xd4\xa2\x3e\x66\x52\x8b\xea\x94\x5c\x03\x6e\x05\xcc\x1f\x24\x44\x25\xfb\xc8\x51\xc8\x68\x5f\xef\xec\x39\x58\x47\xa6\xb5\xd7\xf1\x5e\x17\xd2\xc1\xb2\x01\x97\xc6\xee\x11\x9c\xb2\x26\x3b\xe2\x99\x16\xee\x48\x5e\x7f\x68\x60\x72\x8d\xde\x28\x4e\xf6\xcd\x4b\xcc\xd9\xc6\xaa\x0e\x3f\x20\xfa\x27\x70\x55\x1c\x26\x47\x87\xac\xc7\x42\xcf\x58\x71\x3c\x93\xd5\x0e\xb2\xbd\x39\x86\xfc\xf2\x58\x27\xd9\xe2\xd6\xea\xed\xe1\xf7\x6b\xdf\xc6\xaa\x9a\x10\x11\x49\x5c\xdd\x36\x9a\x07\x28\x1a\x24\xb3\x03\x94\x0e\x91\xf2\xd1\xaa\x07\xf8\x9a\xe3\x3e\x8a\x9f\xf0\x69\x97\xaf\xbf\xe6\xdb\x30\x42\x47\xc5\x94\x2b\x5e\x97\xfa\x90\xb0\xf7\xf6\xdc\x76\xfc\x9a\x6f\xe3\x2d\x9c\x56\x9f\xb5\x95\xda\x87\x9d\x5f\xbf\xf3\xea\xcd\xcd\xaf\xe1\xc5\x01\xc0\x0b\x3d\x22\x36\xdf\xf6\x61\x57\x7f\xc3\xce\x8a\x1f\xeb\x2a\xd7\xf3\xca\x68\xc6\xab\xa7\x2d\xf3\xd5\x9a\xd0\xda\x66\x47\x23\xf7\xe0\xfa\x5f\x77\xfb\x14\xdb\x7e\x17\x9c\x6e\xcf\x4f\x83\x7e\x70\xba\x3b\x3f\x0d\xe0\x45\x70\x1a\x6e\xcf\x4f\xb7\x17\xd1\xf0\xa2\x2e\x3d\x28\xbc\xd0\x85\x5b\xf7\xcd\x23\x5c\x53\x75\x79\x0a\x89\x65\xe8\xc2\x10\x8c\xa0\xa2\xef\x02\x9f\x7f\x7e\x78\xfd\xac\x5e\xdf\x56\xb0\xab\xad\xda\x24\x1a\x9e\x78\x37\x9d\xea\x10\x92\x36\xd1\x24\x17\xaa\x4a\x80\xc3\x12\xcc\x24\xf6\x12\xe0\x30\xce\x30\x82\x93\x93\x7e\x33\xcd\x94\x15\xf3\x1f\x44\x4a\x45\x2b\x25\xd9\x3c\x2a\xe0\x6a\x1c\x2b\x23\x8c\xb6\xe5\xbf\x60\x52\xc7\x12\x75\x1a\x03\x7e\x68\x72\x69\x5d\x6f\x6a\xaf\xda\x75\x2d\x3c\x60\xe2\x07\x01\x7d\x3e\x87\xf3\xba\xcc\x92\xe2\x1e\x20\x9f\x75\x95\x5f\x1d\xa2\xde\x6a\xd1\x44\xde\x0e\x3c\x38\xbf\x37\x96\xd1\x85\x9e\xff\xaf\x97\xce\x87\x8b\x84\xd9\x5a\x7a\x3f\x61\xc5\xfc\x57\x5c\xe8\x56\x9c\x53\x53\xbe\x71\x73\xdd\x33\x3e\x71\x71\x11\x88\x9b\xa6\x5b\xe8\xd8\x5b\xb0\xf0\x44\x83\xd7\xb0\x6b\xcf\x15\xb9\x2f\xc6\xae\xb5\xcd\xdd\x4c\x80\xd5\x08\xae\x4d\x3a\x10\xe3\x45\x93\x54\xbb\x92\x62\xfa\xa5\x8e\xad\x48\xbd\xd4\x27\x26\xa0\xa9\xf3\x59\x6c\xf5\xac\xa3\x3a\xea\x22\x22\x52\xdf\xc2\xaa\xc3\x85\x13\x38\x43\x58\xb3\x8e\xf2\x06\x90\x0a\x8a\xa5\x67\x17\x54\x8c\xba\x35\x68\x0c\x63\x98\x1d\xa9\x8a\xba\x16\xb3\xa6\xf1\x9f\xba\x96\xff\xde\xa1\xa6\x9f\x38\xd4\xc1\x28\x1d\x8d\xcf\x3a\x98\x2c\x7a\xa4\xd2\xb0\xbc\x67\xb8\xfd\x5e\xce\xb3\xef\x1b\x3c\x99\xef\x68\x91\xfe\x57\xe7\x3a\x8f\xba\x4d\x9e\xf3\x2a\xa2\xae\x95\x7d\x1a\xc7\xf9\xc3\x4c\x3f\x69\x98\x83\x11\xfe\x18\x6e\x73\x0f\x56\x1c\x63\x35\xf7\xf4\xc5\x93\x79\xcd\x01\xfe\x2f\xcc\x6b\x8e\x04\x4d\x46\x73\xa5\x51\xd7\x8a\x3e\x8d\xcb\xaa\x01\xa6\x4f\x1f\xe0\x00\xf6\x1f\xc3\x5f\xda\xe1\x05\x92\x97\x0b\x32\xa3\xfa\x5e\x58\xbe\xab\xcc\xa0\x9a\xcd\xbe\xb5\x31\xa1\x8a\x33\xa2\xa7\x71\x9b\x1e\xe6\xf7\x66\x35\x0d\xd4\xf0\x92\x09\x74\x37\x59\xed\xb0\xfa\x29\x5c\xa2\x7b\xc7\x8a\x7f\x8b\xb7\xc3\x5e\x10\x49\xc3\x48\xf3\x49\x47\xf9\xa7\x73\x4a\xd7\x20\xd3\x4f\x19\xe4\x00\xfe\xef\xcc\x2d\x98\x0c\x81\xfb\x1f\x5d\x53\x85\xc1\x5a\x9b\x8b\x66\x73\x23\x82\x67\x07\x8f\x05\xb9\x27\x17\x3b\xcc\xb1\xe8\xaa\xdd\xcd\xbd\x07\x74\xd8\xc9\xd6\x1c\x76\xa9\x9e\xfc\x39\xec\xe3\xaa\x0e\x3b\x69\x2e\xee\x18\xa5\x3e\xa8\x3b\x78\x6a\xcf\x3e\x87\x8a\xc7\xd6\xf0\x1e\xc3\xdb\xfa\x79\xd3\x7b\x1e\xf8\x71\xef\x29\xc1\x9d\xff\x0e\xc8\x00\x0f\xb6\xe0\x9c\x2e\x1b\xaf\x83\xb8\x17\xb1\x5c\x05\x2e\xcb\xb3\x52\xf0\x8c\xe5\xf4\x67\x46\x37\x7d\x78\xb6\xa6\x62\xc6\xa5\xf6\xd9\x6d\x49\x96\x93\x25\x9d\x0b\x52\x2e\xb0\xc0\x0e\x73\xf0\xa6\x89\x06\xd5\x6a\x8a\x61\x02\xb8\x6b\x3e\xdd\x92\x65\xd9\xd5\xf1\x47\x8c\x0f\x61\xb4\x5f\x15\xb2\x8f\xae\x54\x4f\xbd\xd8\xee\x03\x1d\xda\x93\x4d\x7c\xe2\x8c\x6d\x69\x3a\xd0\x0f\xce\x0e\xaa\xc7\x3c\x2c\xb4\x19\x47\x61\x69\x75\xb0\x6f\xe3\x2e\xe0\xee\xf0\xb1\x14\x93\x83\xd6\x6e\x9a\xda\xa6\x00\x1b\x2e\xd2\x81\xbe\xb5\x35\x02\xfd\xcf\x80\xe4\xf9\xc1\xbb\x28\xb8\xba\x7f\x5b\x49\xc5\x32\x46\x53\x10\x24\x65\x7c\x60\x99\x5b\xfb\x86\xe6\xfa\x1a\x9e\x05\xcc\xa8\xda\x50\x5a\xd4\x77\x5f\xec\x42\x01\xae\xb8\x79\xf5\xb6\xeb\x5d\x2d\xfd\x72\x14\x1e\x76\x97\xf5\xa7\xc1\x87\x6a\xc4\xba\x6c\x2b\x03\x68\x3c\x7e\x61\xd1\x08\xf4\x4b\x57\x1a\x33\x6e\xdf\x6a\x19\x6b\xfd\xd0\x7e\x9e\xaa\x14\x6c\x49\xc4\x0e\x30\x99\x75\x6d\xde\xf3\x02\x68\x3c\x80\xa6\x81\x04\xda\x8f\x34\x08\x06\xee\xd1\x2b\xc7\x5f\x01\xda\x92\x2b\x3a\x09\xb0\x00\x74\xc9\xb4\xfa\x38\x1e\x6a\x60\x08\x78\x3c\xd4\x28\x3c\x88\xcc\xd3\xb0\xf8\xb9\xc9\xec\x15\x32\xb6\x1c\x3c\xa4\x0e\x8a\xfe\x70\xe4\x7e\xac\xe5\xb2\x42\xcc\x96\x59\x9c\xfc\x6f\x7f\x38\x3a\xaf\x1b\x72\x59\x61\x54\x17\x5b\xa4\x5a\x05\x5d\x78\xb9\x77\xcb\x50\xd5\xf5\xe0\x6f\x64\x4d\xde\x99\xd7\x82\x12\xcc\x59\xc3\x83\x3a\x4c\x3f\x43\x96\xc7\x90\x4b\x9d\x7e\x33\x6c\x89\x40\xda\xbc\x90\xcc\x92\x45\xcf\x48\x94\xdd\x2e\xf0\x4c\xc0\x84\xe5\x69\xda\xd3\xf2\xf2\xe0\xab\x44\x78\x00\x56\x49\x92\xc6\x7c\xa4\x21\xa2\x12\xd7\x99\x48\x61\xb7\x45\xc2\xf0\x31\x01\x17\x67\x37\xf1\x2a\x96\x36\xee\xdb\x60\x8b\x09\x34\x78\xdf\xdf\x62\x31\x35\x23\xad\x2a\x62\x9c\xb8\xdb\x16\x3b\xf6\xd8\x56\xeb\x66\x66\xc6\xbe\xd7\x35\x6a\x9b\xd7\xdb\x83\xb7\x14\xff\xe3\x70\x38\xec\xf4\x18\x54\x2c\xdf\x76\xa2\x61\x57\xf8\xf1\x28\x34\x3b\x3c\x66\xf8\x9a\x43\x3b\x31\xc8\x5a\xd5\x2d\x24\xd0\xbc\xc1\xf7\x5a\x6a\x28\x0f\x20\x78\x00\xaf\x8d\x23\x5a\x01\xcd\xe7\x76\x71\x93\xc0\xb3\x5c\xb6\xc4\x13\x6a\x7c\x1e\x08\x17\x5b\x73\x3d\xe4\x64\xc7\x57\xca\xa8\xff\x55\xae\x35\x59\xc5\x09\x4e\xd0\xf5\x11\xae\x7d\x5b\x37\x67\x8d\x52\x23\xcf\x18\xb4\xae\xdf\xef\xc5\x18\x7f\xfd\xe3\x0a\x56\x2d\xf8\xbf\xc8\x80\x57\x08\xf5\x0b\xf0\x00\x63\x7c\x5b\x0d\x4f\xaa\x31\x73\x69\x12\x78\x6f\x00\x63\xcf\xea\xab\xe9\x81\xfb\x1e\xb6\xae\x1e\xac\xcf\xe5\x13\xe1\x54\x58\x1d\x80\xc2\x5f\x7f\xe8\xd9\x2f\xde\xcb\xf7\xba\x79\xfc\x9e\xcc\xa5\x1b\xe8\x11\x8f\xdf\x6b\x72\xe0\xab\x2f\x76\xee\x00\xe3\x84\xe8\xf7\x4d\xa6\x08\x69\x3c\x74\xdf\x6c\xad\x7b\x16\xff\xf9\x2d\xdd\xf5\xe1\xb9\x56\x85\x30\x9a\xd8\x67\xf2\x2d\x84\xea\xb1\xfc\xe7\xb7\xad\xc7\xf2\x9f\xaf\x3b\x1e\xc8\xf7\xa7\x85\x9f\xab\x87\xf2\x3b\xe6\x5b\xaf\x8c\x9e\x6d\x7d\xe7\xff\x29\x93\x36\xdf\xd1\x91\x29\x69\x1a\xb4\xa7\x66\xcf\x5f\x3d\xd0\xc7\x86\x8c\xec\x98\x35\x6a\x6f\x1c\xdb\x7a\x35\xf7\xfe\x7a\x80\x54\xa2\xfb\x57\x03\x0e\xc9\xf2\x08\x22\x61\x31\x3e\xca\x6a\xde\xad\xad\xa5\xc9\x2a\xab\xfb\x65\xa9\xad\xd1\xfe\x5b\xa4\xfe\x5b\xa4\xfe\x43\x45\xea\x53\xc5\xc6\x9f\xb8\x87\x43\x29\xb8\xe2\xb3\x55\xf6\x23\xd9\xe5\x9c\xa4\x0d\x14\xba\x86\xfd\xd1\xb6\x8f\xef\xee\x2a\x0e\xb4\x08\x8c\x4b\x41\x7d\x66\xdb\x95\xd4\xfe\x8a\x09\x7e\xa3\x5b\x85\xc8\x96\x82\x4e\x1f\xc4\x4d\x33\x5b\x28\xff\x91\xff\x98\x93\xe2\x21\x8c\xde\xfd\xaf\x6f\x63\x6c\x77\x0c\x8f\x17\x98\x46\x51\x28\x7c\xf6\xc3\xe2\x82\xad\x9f\x88\x8b\xa0\x09\x65\x6b\x9a\x7e\xa3\x1f\x82\x7e\x90\x4a\xd5\xed\x73\x7d\xc9\x3e\x76\xbd\xf0\x35\x48\x07\x09\xb4\xbf\x19\x35\xb0\x9e\xba\x65\x89\xf7\xfb\x8a\xbe\x23\x7c\xa1\xd2\xae\xaa\xfb\x95\x94\x47\xe0\xfe\x24\x55\x68\x8d\xa6\x43\x2d\xe8\xde\xf6\xf6\xad\xaa\x69\xaf\xe2\xfe\xe6\x63\x89\x58\x64\x7d\xc8\x95\xc8\x35\x13\x58\xd3\xce\xf0\xc1\xbd\xc2\x62\x3b\x9a\xf3\xe3\x49\x70\xf1\x97\xbf\x58\x81\x19\x2b\x7c\xc4\xde\x4d\x71\x5c\xcf\x16\xbf\x2c\x4c\x2f\x8c\x43\xe1\xe8\xa8\x81\x57\x0e\x07\xfd\xfa\xd1\x24\x40\x2e\x0d\xa6\xf8\xb7\xa6\xf5\xd3\x3a\xe3\x3b\xa2\xc1\x14\xff\x86\x70\x29\xa3\x4f\x84\xf0\x8e\xe6\x59\xf5\x5e\x37\x16\xe0\x83\x69\xe6\xdd\xc7\x2a\xad\x08\x7f\xba\xc1\x7b\x68\x02\x6f\xa9\x0a\x0a\x62\x55\x14\xac\x98\x07\x53\x04\x01\xff\x2c\x1e\xee\xc6\x8c\x9d\xd1\x69\x7d\xb7\xf3\x9f\x01\xba\x5a\x06\xd3\x17\xab\xe5\x2a\xd7\x6f\x09\x75\x23\x59\x73\xe9\x78\xe8\xad\xe7\x58\xe1\xa3\xa5\x55\x23\xdc\x99\x5f\x99\x07\x1d\xf4\x30\xee\xd1\x43\x0c\x84\x60\xb2\x12\xa3\x9b\x8a\x64\x38\x4f\xf8\xe9\x4d\x37\x5b\xa4\xd3\xa1\x5a\x96\x7f\xcd\x38\x9f\x20\x25\xb4\xa0\x34\xaa\xcf\xcf\xbe\x3c\x3b\x2c\xfd\xb2\xab\xf0\xf2\xec\xac\xa3\xf4\xa2\x5d\xec\xcb\xe1\x60\x50\xcd\xd5\xcd\xaf\x12\x47\xdf\xd9\xd5\xb2\x57\xfb\x03\xc7\xc5\xaf\xe5\x33\x4c\x9b\x1e\x73\x0b\x0a\x93\xd6\x25\xc1\xdf\x69\xc1\x0c\x15\xe4\x2c\x4c\xc7\xb6\x77\xcf\xf0\xbe\x0c\x1e\x06\xd8\xeb\x2d\x74\xa3\xfd\xe2\x05\xdf\xe0\x5d\xb3\x57\x98\x41\x95\x09\x3c\xf0\x37\xb7\xb1\x37\xda\xf7\xc6\xf7\x69\xf1\x3d\x36\xfd\x4c\x56\x75\xac\xa0\x5d\x72\x45\x92\x5b\x54\x67\x05\x26\xcf\x63\x8a\x29\x53\xb2\x67\xdf\xae\x73\x3d\x34\xc0\x2b\x58\x60\x74\x17\x57\x50\x2e\xf8\x46\x56\x19\x53\x27\xd2\xf3\x6c\x1a\xaf\x7f\x14\x69\xcf\xe4\xea\x61\x27\x62\xf1\xc2\x64\x3d\x8b\xcf\x6a\x86\xcf\x0e\x17\x80\x97\x92\xe2\x47\xba\xf4\x78\xb0\x50\x53\xeb\xad\xa3\x94\x97\xb7\xe0\x5c\xfa\x0e\xcf\xce\xfa\x72\x2c\x0b\x0f\x41\xd4\x8e\xa3\x9f\xd8\x63\x36\xac\xce\x01\x91\x35\xed\x93\xd0\x97\x31\xfe\xb0\x4f\x88\x2a\xb3\x1e\xce\x68\xcd\x7e\x7d\xe8\xa1\x7f\x88\xa8\x0f\x82\x73\x2f\xd7\x04\x2f\xf8\x61\x79\xf4\x90\x9f\x89\xa1\xd3\x30\x78\x4d\x58\x8e\xa6\x09\x07\xdc\xef\x3d\xc4\x46\x10\xc0\xa9\xf9\x6d\x25\x3c\x10\x57\x2b\x89\x3f\xc2\x55\xf9\xa5\xcd\x59\xd5\x21\x7c\x4d\x4e\x5c\x17\x4c\xee\xbe\xbe\xe9\xc3\x92\x6c\x5f\xd2\x12\x0f\xc6\xea\x88\x7f\x15\x25\xc1\xf1\x94\xa2\x45\x98\xf5\x21\xc5\x56\x3e\xd6\x59\x9c\xda\x8e\xfa\x5f\xd7\x19\x7c\x90\xdf\x11\xb5\x88\x97\x64\x1b\xba\x32\x07\xa7\x6e\xad\xb9\x44\x9a\x1b\x4f\x99\x57\x1e\x66\x71\xa5\xef\x3e\x7e\x84\xeb\x1b\xfc\x21\x24\xf1\xaa\x91\xc6\xac\xdf\x95\x72\x38\x26\x16\x36\x9c\xc2\xb9\x4e\x4a\x75\xb0\xf6\x51\x88\x6b\xd0\x87\xb3\x3a\xbf\x11\xe9\x20\xf8\xe6\x1b\xbd\x7f\xc1\x04\xce\xff\xd5\xe5\xe2\xe0\x1f\xef\x19\xe9\xc3\x85\xb1\x6f\x49\xfb\xed\x17\x0e\x4c\x35\x4f\x8d\x04\xfc\xa9\x1e\xc3\x6f\xbe\xb5\x39\xa7\x09\xc9\x69\x8c\x39\x04\x44\x84\x51\x9c\xf2\x25\x61\x45\x78\x8d\xb8\xe2\x03\x38\x98\xaf\x5b\x7f\x86\x53\xd0\xb3\x88\xb5\xc2\xfe\xf8\x11\xce\xa3\x9b\x28\xd6\xf6\x47\x78\x7d\x66\xaf\x32\xdc\xe0\x61\x05\x59\x96\x3a\xe9\xf7\x81\x9b\x12\x87\x13\x7b\xf8\xa6\x84\x2d\x34\xd3\x0d\xfa\x76\xde\xd5\xa2\xe1\x38\x38\x0a\x66\x90\x99\xc8\xdd\x57\x79\x1e\x06\x73\x77\x5f\xd2\x2c\x75\x14\xeb\x7b\xab\x61\x3d\xe0\xdc\x4b\x19\xb3\x43\x54\x6f\xe3\xfb\x02\x95\x79\xf9\xad\x41\xfd\x64\x3e\x0a\xc2\x36\xcc\x0c\x9d\xf4\xc5\x86\x3e\x16\xd5\x6b\x31\x70\xbc\xda\x58\x11\x6c\x18\x05\x8d\xec\xe5\xee\xf4\xee\xcc\xe7\x7a\x7b\xcd\xad\x99\xe0\x8d\x69\x72\x36\x2b\x0b\xb2\xf8\xa7\xb7\xdf\x3e\x32\x23\x5c\xb7\x75\xd4\xf3\xc5\xd4\x4f\x6d\xab\x44\xf6\xef\x96\x2b\x3b\xe9\x51\x49\x5a\x45\x09\xcc\x10\xd4\xdc\x12\xc1\xc0\xa3\x4f\xdf\x48\x87\x83\x5e\xe7\xfa\xe1\x79\xcf\xe1\x42\x38\x06\xa8\x31\x38\x68\x52\xb1\x43\x45\xda\x83\x26\xf8\x5b\x00\xc7\x56\x32\x8b\x5f\xe0\x8d\x3d\x5f\x62\x6b\xa4\x50\x0b\x1e\x22\xb5\x0d\xfa\x70\x79\x50\xba\xf3\x51\x80\x01\x7c\xe9\xb5\x40\x38\x61\xf7\xf0\xd5\xc4\x90\x9e\x53\xf8\xe2\x0c\xfe\x0a\x99\xb6\xe6\x61\x04\x41\x70\x04\x2f\x74\x9c\x82\xa8\x03\x6e\x35\x26\xae\x1b\x0a\xb7\xd6\xb4\x16\xe0\x29\x04\x10\x06\xd5\xca\x20\x0b\xc2\x52\x46\x81\x97\x80\x9a\x71\x11\x62\xd7\x5b\x7c\x94\x25\x6b\xf8\x95\x0d\xa6\xd2\xa0\x8d\xda\xbc\xd5\x70\x26\x7a\x47\x68\xf4\xc0\x77\x13\xaf\x7a\x87\xcc\x65\xa7\x6e\x40\x7c\xe0\xac\x08\x83\x5f\x8a\x3a\xde\xdd\xfa\x3d\xb1\x76\x70\xb3\x67\x12\xf7\xab\x9d\x1e\x4d\x0b\x17\xdb\x1f\x68\xa3\x48\x1b\x4f\x28\x69\xfa\xe2\x2f\xbe\x0e\x8b\xbf\x5e\xa6\x38\x08\x9a\x32\xe4\x33\x58\xc9\x2a\xe7\xbb\x14\xf8\xf4\xd8\xa7\x59\x07\xad\x10\xb2\x3d\xd7\x0b\x74\x46\xfe\x89\x46\x70\x20\xf8\x26\x9e\x49\x53\x71\x52\xb3\x20\x60\x56\xbc\xc6\xf0\xb9\xbd\xe8\xe3\xd6\xee\x01\xf9\x46\x78\x0d\x09\x3f\x22\xdb\xb6\xdd\x55\xef\x48\x98\xf8\xee\x8e\x16\xe9\x7e\xdf\xfb\xff\x03\x00\x7f\x3b\x6a\x3c\x4e\x77\x00\x00"),
+			uncompressedSize:  30542,
 		},
 		"/traces.html": &_vfsgen_compressedFileInfo{
 			name:              "traces.html",
-			modTime:           mustUnmarshalTextTime("2015-10-04T03:50:12Z"),
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xa4\x58\x5b\x73\xdb\xb8\x15\x7e\xcf\xaf\x40\xb0\x99\x86\x9a\x58\x54\xbb\x33\x7d\x71\x24\x77\xbc\x9b\x6d\xc7\x6d\x37\xc9\xac\x9d\x74\xa6\x9d\x3e\x40\x24\x24\xc1\x81\x08\x16\x00\x65\xab\x5a\xfd\xf7\x7e\x07\xe0\x5d\x72\xe2\xd9\x3e\xc4\x11\x41\xe0\x5c\xbe\xf3\x9d\x0b\x78\x38\xe4\x72\xa5\x0a\xc9\xf8\x9d\xf2\x5a\xf2\xe3\xf1\xce\x8a\x4c\x3a\x36\x65\xa2\x2c\x73\xe1\x36\x87\x83\x2c\xf2\xe3\xf1\xc5\x8b\x6e\xeb\xcf\x42\x15\x9c\x96\xe6\x2f\xa7\x53\x76\xeb\xf7\x5a\x15\x6b\xb6\x32\x96\xf9\x8d\x64\x6a\x5b\x1a\xeb\xa7\xf7\xce\x14\x6c\x59\x79\x8f\xff\x7e\xc7\xb6\xb2\xa8\xd8\x74\x7a\xf5\x62\xee\xb0\x5d\x5e\xbd\x60\xec\x3b\x6f\xca\xa9\x55\xeb\x8d\x9f\x2e\x7d\xe1\xd8\x01\x6b\x8c\x6d\x85\x5d\xab\x62\x8a\x77\x97\xec\xfb\x3f\x96\x8f\x6f\xb1\x7a\xc4\xbf\xd9\x8c\x7d\x58\xad\x9c\xf4\xad\x9e\x6c\x23\xb3\x2f\x4b\xf3\xc8\x96\x32\x13\x95\x83\x62\xff\xda\xb1\xc2\x78\x26\x32\x5f\x09\xad\xf7\x6c\x27\xad\x57\x59\xf8\x29\xb4\x5a\x17\x32\x67\x0f\xca\x6f\xa2\x38\x92\xe1\xe5\xa3\x4f\xf1\x98\x7a\xf2\x7a\xda\x8a\x8c\xb6\x60\xd3\xdd\x46\x39\x96\x1b\xe9\x8a\xd7\xd0\xac\x1e\xa3\x87\xce\x55\xf2\xb2\xde\xd2\xe8\x98\x06\x0d\x97\x6c\xab\xf2\x5c\xcb\xb7\xe1\x6d\x69\x9c\xf2\xca\x60\xd5\x4a\x2d\xbc\xda\xd5\xeb\xd1\xbb\xc6\xb9\xf9\xac\xc6\x24\xe2\x79\x07\x58\x7e\x21\x58\xd8\xcf\x2d\x68\xb9\xda\xb1\x4c\x0b\xe7\x16\x1c\x58\x4d\xd7\xd6\x54\x25\x2b\x2b\xad\x23\x80\x9c\x59\xa3\xe5\x82\x87\x75\xce\x84\x55\x62\xaa\xc5\x52\xea\x05\x4f\xd3\x94\x33\x95\x2f\xf8\x10\x6d\x4e\x11\x08\xea\x6e\x42\xb8\xd8\x5f\x6f\x3f\xbc\x6f\xc2\x45\x2a\xf1\xb6\x7e\xea\xf4\x32\xd2\x0d\x12\x88\x4a\x43\xa5\xdf\x97\x50\x19\x37\x45\x15\xbd\xc8\xf3\xe0\x67\x2e\xbc\x40\x28\xd7\x6b\x32\x2e\x33\x5a\x8b\xd2\x49\x5e\x2f\x23\xd0\xd2\x2f\xf8\x77\xbd\x53\x53\xa2\x49\x3c\xea\x89\x8e\x8d\xc8\x68\x9d\x8f\xcc\xcc\x95\x95\x99\x47\x48\x55\xe1\x0d\xbb\x8e\x2c\xe5\x57\x3d\x3f\xe6\xb3\x68\x15\x10\xad\x9d\xac\x49\x6d\x4a\x8a\x86\xeb\xd8\xd8\x79\x39\xf4\xe6\xbc\xcf\x2c\xb7\xa6\xcc\xcd\x43\x51\xfb\xc4\x87\x0e\x36\x6f\xeb\x00\xc8\xc7\x52\x14\xb9\x04\x2e\x2b\xa1\xc9\xed\xda\xa5\x9d\x92\x0f\xad\x25\x44\xe6\x2d\x44\xab\x52\x4b\xe6\xa4\x86\x67\x20\x69\xf4\x34\xc4\x88\x35\xb6\xcf\x1d\xc4\x35\x86\x65\xc2\x4a\xcf\xaf\xc0\x1c\x2c\x06\x37\x5a\x97\xf1\xbb\xd2\xcd\xbe\xd6\xe0\x00\x6c\xcd\x92\xf0\x3b\xca\x9e\x6b\x75\x35\x17\x6c\x63\xe5\x0a\x91\x68\x88\x42\xee\x4c\xa3\x31\x8a\xd0\xa8\x0d\x8f\x2b\xb3\x5c\xc6\x1f\x48\x29\xdd\x5a\x7a\x17\x0e\xb1\xdb\xe6\xd0\x7c\x26\x60\x1d\xa4\xf7\xd5\x90\x74\xa0\x42\xd1\xf6\x26\xd2\xa4\x91\x9d\x99\x72\x1f\x72\x6b\x84\x01\x52\x25\x26\xbb\x56\xe5\xd2\x08\x9b\x33\xe1\x22\x1b\x08\x7a\x7e\xf5\x53\x10\x57\xeb\x95\xf9\x59\xb5\x03\xef\xc4\x7a\x6d\xe5\x5a\x78\x39\xa5\x38\x0c\x83\x42\x8a\xda\xf7\x79\xd0\xc0\xcc\xea\x9c\x59\xfc\xea\xba\xd9\xc7\x3e\xe3\x68\x5f\xef\x7c\x56\x69\xa4\xec\x0c\x39\xdb\xa4\x74\x29\xd6\x32\x6a\x8a\xe9\xbc\xf9\xc3\x55\x8c\xea\x7c\x86\x9f\x54\x5a\xbd\xdc\x96\x9a\xa4\xf1\xc8\xe3\xe8\x17\x08\xa6\x00\x34\xbf\x79\xc7\x19\x3f\xc9\x13\xc6\xaf\xeb\x00\xf1\x1e\xf9\x79\x53\xca\xdb\x55\xd1\x4b\x1f\xb6\xdc\xc3\x1a\xe7\xa9\x60\x2b\x8f\xd2\xa9\xcd\xc3\x65\x57\xcb\xef\x50\x0f\xaf\xad\x14\x2c\x29\xa0\xe5\xcf\x20\xd1\x66\xc2\x40\x5f\xbd\x14\xd9\x97\x40\xd6\x1f\x11\xa8\x37\x1f\x21\x41\x12\x34\xfd\xbc\x24\xcf\x9e\xe5\x48\x4d\x81\xbe\x23\x8d\xc5\x9f\x50\xc6\x33\x6f\xf5\x9b\x8c\x41\x57\x66\xb6\x5b\xa4\x10\x1e\xc0\x82\x96\x21\x7d\x9d\x7d\xfb\x3b\xd6\x6b\xe5\xfc\xb4\x2a\x42\x55\xcd\x03\xd3\x0f\x07\x2b\x0a\xc4\x20\x8d\xb0\x1f\x8f\x81\x1f\x87\x83\x5a\xb1\x84\xfa\x03\x7b\x95\x7e\x56\x4e\x2d\x11\xa0\x74\x52\xbf\x8d\xfc\xa9\x7f\xe2\x41\x15\x65\xe5\xeb\x3a\xd1\x34\x8a\xb6\x52\x0c\xfb\x07\x8f\xcd\x89\x72\x7f\x0f\xb2\xb4\x32\x42\xb9\x08\x7e\x87\xfd\x0b\x7e\x38\xa4\xb7\xde\x22\x18\xc7\x23\xef\xa9\x6a\x38\x7b\x38\x54\x56\xdf\x99\x60\x34\x4b\x6f\x91\xeb\xe9\xcd\xbb\xe8\x03\x1d\xa0\xd3\xc3\x35\xe2\xe1\x8b\x4e\x4e\x07\xc9\xa0\xa2\xb4\xde\x0d\xde\xc6\xfc\xa0\x82\x32\xed\x09\xa6\xff\x07\xc6\xb5\xc0\xa5\xef\xc5\x56\xb6\x58\xd5\x32\x9d\xb7\x06\xd4\xaa\xb3\x0a\x72\x6e\xde\xd5\x96\xc6\xdd\xd4\xeb\x68\xc7\x58\x9e\x44\x85\x7c\xbe\xac\xd6\xae\x27\xc5\xc5\x91\xe5\xd4\xe6\xe0\xd6\x75\x81\x29\x41\x84\x02\x3c\xd6\xe9\x05\x71\xa0\x81\x25\x3c\x84\xbf\xd3\xcc\xa0\x96\x17\x8e\x2a\x40\x78\x86\x5e\x55\xd6\xec\x1a\xaa\x89\x4c\x4b\x56\x4a\x7b\x69\x7b\xaa\x4e\x95\x4f\x46\xda\x3b\x33\x63\xee\x88\xc2\x9f\xd9\x41\x56\xda\xab\xb9\xdf\x10\x12\x7f\x93\x7b\x42\x01\x0f\x73\x9f\x63\x01\x66\xb1\xf4\xb3\xd0\x55\xc0\x1a\x4b\xf8\x63\xc7\x36\x76\x08\x7d\x7b\x15\xe7\xc9\xdf\xf3\x00\xf7\xb7\x0d\x72\x25\x94\xc0\xf1\x9b\xee\x54\xf3\x2b\xee\xc3\x4c\x98\x01\xcc\x26\xb7\x68\x26\x9b\xdd\x8b\x9d\x88\xab\x01\x61\x4c\x62\x3f\xa8\x22\x47\xa2\xb8\xb3\x73\x26\x95\x11\x9a\xe3\x92\x55\x55\x84\x9a\x98\x4c\xba\x19\xee\xa6\xc0\x10\x86\xe9\xec\xbf\x92\xea\x88\xd8\x19\x95\x33\xb7\x31\x0f\x54\x03\x71\x76\xa5\xac\xf3\x2c\x6d\xc6\x93\x84\x6f\x54\x2e\xf9\x84\x51\x5d\x48\x83\x8c\x57\x09\xff\xee\xa4\x68\x4d\xba\x13\x87\xd8\x32\x2f\x59\x68\xf4\xc7\xc9\xdb\xf6\xd4\x49\xcd\xfe\xea\xa9\xc6\xe0\x7f\x6c\x64\x11\x5c\x1c\x2b\xc5\xe4\x19\x2c\x2f\xd8\x83\x64\x0f\xa0\x07\x39\x44\xe6\xf6\x00\x61\x2d\x20\x8d\x38\x67\xa8\xd4\x7b\xf1\x05\x05\x53\x79\xc7\x50\x9f\x33\xf9\x55\xcf\x80\xdf\x6b\xd2\x93\x2e\x5d\x6b\xef\xeb\x0b\xd6\x80\xcb\x5a\x74\x9f\xe3\x67\x8d\x67\x04\xe5\x38\x69\xac\xba\x2e\x72\xb6\x53\x28\x9a\x18\xa0\x9d\x68\xa3\x6a\xf0\xc7\xd6\x83\xe8\xe5\x39\x1c\x49\xb4\x56\xd9\x97\xd3\x50\x3f\x27\x54\x23\x63\x3a\xcc\x3f\x95\x34\xea\x1a\x34\x2f\x19\x5c\xac\xfb\x7e\x8d\x29\xac\xdb\x5e\x10\xe8\x1f\x3f\xdc\xde\x8d\xba\x50\x9c\x13\x30\x8f\x7b\xd3\x08\xa3\x0d\x7c\x16\x6b\xee\xac\x2a\xb5\x11\x39\x67\x9f\x7e\xf9\x3b\x43\x37\xa3\xab\x00\x9e\xc3\x9e\x38\x17\x18\x34\x47\x87\xa0\xc4\xf6\x56\xd0\x20\x62\x07\x11\x1a\xa3\xcb\x52\x11\x1b\xff\x57\xa0\xa0\xbb\x8b\x55\x5b\xf6\xb0\x51\x5e\xa2\xa8\x67\x41\x11\x2a\x58\x65\x23\x5b\x70\x63\xb2\x61\x14\x40\x49\x73\x66\x2b\xfd\x86\xf2\x21\x29\x75\xe5\x2e\xea\x91\xc7\x22\x34\x9d\xb8\xe6\x16\x44\xb3\x27\x13\x4b\x83\x66\xd8\x09\x9f\xa4\xf5\xc6\x9d\xb0\x11\x90\xc5\x13\xa6\x53\x7a\x43\x82\x80\xf1\x3b\xa1\x93\x3a\x14\x8c\xa9\x55\xf2\x32\x1c\xfc\xf5\xd7\x20\x00\x57\x32\xb5\x4d\x26\xa9\x96\xc5\xda\x6f\xd8\x62\xc1\x7e\xdf\x0f\xb4\xd0\xb8\x77\x25\xfc\xa3\x96\x82\xae\x7e\xa1\x37\x0b\x68\xd7\x48\xee\x10\x9b\xd0\x11\x5f\xf2\x56\x3e\x03\xf0\xbe\xb2\x45\xf3\xdc\xb6\x87\x10\xfc\x36\x24\x01\xfa\x0b\xec\x5d\x59\xe9\x02\x24\x21\x48\xd5\x90\x1e\x8d\xb7\xaf\x52\x5c\xf1\x60\xc6\x28\xd6\x17\xc1\x83\x49\xab\x39\xcd\x4d\x21\x07\x51\x62\xda\x64\xa1\x09\xa4\x91\x0e\x58\x3b\xf6\xf6\xaf\x84\xd2\xdd\xfe\xc7\x8d\xbd\x08\x57\xd5\x5b\x34\x0e\x0a\x8f\xb4\xd6\xd8\xbb\x8d\x45\x25\xe8\x63\xd2\xa2\x12\xde\x5f\x32\xce\xde\x30\x9c\x85\x0a\x07\xf3\x9d\xa4\xe9\xae\x87\x47\xab\xf0\xd8\xe6\x43\x12\x33\xe2\x5c\xb9\xf5\xa7\x57\xa8\x27\x2b\x6e\x3b\x2d\x47\xc8\x1d\x78\x8f\x3b\x91\x05\xc3\xeb\xb4\x2a\x85\xa5\x56\x3a\x4e\x22\xd2\x25\x45\xb6\x69\x05\xb4\x09\xd5\x25\x04\x11\xac\x95\xbf\x60\x67\xa8\x4f\x3b\x6a\x6b\x17\xec\x5f\xff\x6e\x1c\x06\x1d\x47\xd7\x7c\x50\x90\xb4\x75\x2e\x28\x40\xdb\x07\x14\x9c\x7c\x95\x20\x31\xdc\x24\x2d\x71\x93\x4a\x78\x3d\xd6\xf1\xc9\x10\x76\xd2\x78\x1f\x18\x1f\x37\x0b\xef\x6d\xc2\x47\xd3\x5e\x9f\x8a\xac\x36\x30\x2d\x2b\xb7\x49\xc0\x22\xc2\x83\xd0\x48\xee\x27\xfd\x08\x8d\x02\xd4\x70\xb8\x3e\x5d\x47\xad\xad\x61\xa3\xcb\x50\x5d\x45\x3b\xd8\x62\x61\xbc\x33\x01\xf6\x45\xa8\x34\xff\x94\xd6\xfc\xd8\xdc\xad\x92\x5e\xf5\x6c\x2e\x68\x8d\x39\xfd\xb3\xd4\x1f\xc2\x8d\x8d\x77\x3d\x21\x91\xc3\x00\x20\x44\x50\xd1\x04\x6a\x90\xe6\x58\x7c\x2a\xab\xc7\x29\xda\x66\xe8\x7b\xe3\xd1\x29\xbf\xa7\x06\x18\xca\x32\x0d\x63\xa1\x4e\x6b\xb9\x93\xba\x49\xc7\x81\x91\x4e\x7a\x22\x7c\x12\x1f\xc2\x94\xad\x56\x7b\xd2\x7e\xc1\x8a\x4a\xe3\xef\xf7\x1d\xd6\x31\x71\x7a\x96\xbd\x41\xf6\xf4\x6f\x1a\x70\x57\xd1\xf0\x67\xba\xab\x68\xca\x27\x27\x6d\xe4\x03\x88\x5e\xec\x87\xb0\xc6\x74\x45\x69\x45\x41\x13\x56\xe9\x3d\xea\x26\x1a\x7c\xb8\x5d\x91\x43\xe1\x93\xd5\x0e\x19\x4f\x83\xd6\x04\x5d\xa6\x11\xd6\x5e\xbc\xa0\xb5\x72\x54\x8b\x42\x5d\xc6\x5c\x98\x93\xd8\xa6\x92\xa6\xe7\x03\x14\xb4\x3e\x11\xa1\xc1\x66\x5c\xe8\x31\x44\xef\x93\x49\x97\x28\x27\x2c\xf8\xbf\x7a\x2e\x8d\x12\xbc\x01\xe9\x5b\x04\xf9\x16\x45\xc6\x24\xe9\x68\x72\xde\x92\x93\x8e\xf3\x2c\x3e\x3c\x43\xd6\xca\x64\x95\x43\x83\x8a\x2e\x74\x0e\x1c\xcf\x4c\x17\xe3\xcf\x23\x27\xa9\x59\x17\x16\x20\xe2\x6d\x25\xbb\x01\xf2\xe4\x63\xcc\x49\x24\xfa\x51\x45\x95\x42\x3a\x14\xfe\x5d\xfc\x5e\xd5\xd9\xd4\x89\x7f\x59\xff\xfc\x6a\x55\x1c\x16\xbb\x8b\xe6\xf8\x19\xc7\x86\x9f\x41\x06\x6e\x91\xf9\xa3\xaf\x2d\xbf\xcd\xf8\xf3\x6c\xe9\x6a\xc3\xcd\x0a\x39\xf3\x7a\xd7\xfb\x48\x03\x39\x76\x1f\x06\x9a\x8b\x66\xde\x97\xa1\x9d\xd1\x88\x80\x57\xe8\xbe\xf8\x4d\x03\xd9\x7f\x2a\x3c\x76\xa2\x50\x87\x71\x4d\xf5\x34\x81\xee\xd9\x7d\x85\x7b\xc1\xda\xd0\x31\xd0\x45\x84\x4f\xb2\xc8\xc4\x59\xeb\x14\xcd\x3f\xd9\xe6\x82\xf6\xd6\x5f\x08\x2f\xc2\x78\xee\x3a\x81\xe3\xcf\x49\xd4\xe1\xba\xef\x66\xe9\x53\x45\xf1\x6c\x54\xe2\xeb\x7e\x2e\xe0\x0a\x93\x63\x4a\x6f\x67\x09\xfa\x6a\x00\x98\x70\x2f\xfc\x01\x53\x11\xfc\x6b\xbf\x2e\x50\x3d\x6b\x6d\xe1\xcf\x99\x89\x6e\x81\x58\xec\xd5\x56\x66\x32\x80\x17\x86\x5f\x2b\x81\x19\x80\xa1\x0f\xe9\xe1\xfd\xcd\x3b\x47\x93\x31\x4d\x85\xaa\x00\x74\x32\x8c\x94\xaa\xe8\x44\x51\xec\x63\x2c\xa2\xc8\x82\xfd\xe5\xa7\x38\x45\xf7\xb0\xa4\x31\xab\x3f\x44\xaa\x7c\xd4\xbe\x63\xaf\x0e\xe9\xda\x6f\xd8\x01\xa6\x41\xd3\xce\xeb\xb6\x1a\x87\x86\xe6\xe3\xc8\x49\x7e\xfe\x66\xf8\xfe\xd4\x66\xe3\x82\x26\x2c\xd2\x77\x0f\x96\x24\xbd\xf4\x68\x66\xa9\xf9\x2c\x5e\x62\xc3\x37\xbe\x78\xe7\xfd\x5f\x00\x00\x00\xff\xff\x29\x3a\x44\x0c\x72\x19\x00\x00"),
-			uncompressedSize:  6514,
+			modTime:           mustUnmarshalTextTime("2026-10-15T10:32:13Z"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x59\x6f\x8f\xdb\x36\xd2\x7f\xef\x4f\x31\x61\x82\x27\x32\x62\xc9\x69\x1e\xf4\xcd\x46\xf6\x21\x6d\xd2\x22\x77\x6d\xd3\xcb\x6e\x7a\xc0\x1d\xee\x05\x2d\x8d\x2d\x26\x34\xa9\x90\x94\xbd\xae\xea\xef\x7e\x18\x52\x94\x64\x7b\x37\x49\x73\x97\x05\x02\x89\x22\xe7\xcf\x6f\x86\x33\x3f\xd2\x6d\x5b\xe2\x5a\x28\x04\x76\x23\x9c\x44\x76\x3c\xde\x18\x5e\xa0\x85\x14\x78\x5d\x97\xdc\x56\x6d\x8b\xaa\x3c\x1e\x27\x93\x61\xea\xcf\x5c\x28\x46\x43\xf9\x83\x34\x85\x6b\x77\x90\x42\x6d\x60\xad\x0d\xb8\x0a\x41\x6c\x6b\x6d\x5c\xfa\xde\x6a\x05\xab\xc6\x39\xad\xe0\xff\x60\x8b\xaa\x81\x34\x5d\x4e\x72\xeb\x0e\x12\x97\x13\x80\x87\x4e\xd7\xa9\x11\x9b\xca\xa5\x2b\xa7\x2c\xb4\x13\x00\x80\x2d\x37\x1b\xa1\x52\xa7\xeb\x2b\x78\xf6\x6d\x7d\xfb\x7c\x02\x70\x9c\x00\xcc\xe7\xf0\x66\xbd\xb6\xe8\x7a\x3d\x45\x85\xc5\x87\x95\xbe\x85\x15\x16\xbc\xb1\x08\xc2\x3d\xb6\xa0\xb4\x03\x5e\xb8\x86\x4b\x79\x80\x1d\x1a\x27\x0a\xff\xc8\xa5\xd8\x28\x2c\x61\x2f\x5c\x15\xc4\x91\xad\x0e\x6f\x5d\x36\x01\xc8\x1c\x79\x9d\xf6\x22\x83\x2d\xf3\x39\xdc\x54\xc2\x42\xa9\xd1\xaa\xc7\x0e\xd6\xe2\xd6\x6b\x16\xd6\x36\x78\xd5\x4d\x89\x3a\x52\xaf\xe1\x0a\xb6\xa2\x2c\x25\x92\xd9\x00\xb5\xb6\xc2\x09\xad\xae\xc0\xa0\xe4\x4e\xec\xba\xf1\xe0\x5d\x74\x2e\x9f\x77\x98\x04\x3c\x6f\x74\x9d\xbe\x25\x58\xe0\xe7\x1e\xb4\x52\xec\xa0\x90\xdc\xda\x05\x5b\x39\x95\x6e\x8c\x6e\x6a\xa8\x1b\x29\x03\x80\x0c\x8c\x96\xb8\x60\x7e\x9c\x01\x37\x82\xa7\x92\xaf\x50\x2e\x58\x96\x65\x0c\x44\xb9\x60\xa7\x68\x33\x8a\x80\x57\xf7\xda\x87\x0b\xfe\x7a\xfd\xe6\x97\x18\x2e\x52\x09\x90\x77\x6f\x83\x5e\x20\xdd\x25\xae\x79\x23\x1d\x03\x77\xa8\x71\xc1\xc2\xa4\xa0\x62\x14\x79\xe6\xfd\x2c\xb9\xe3\xa9\xd3\x9b\x0d\x19\x57\x68\x29\x79\x6d\x91\x75\xc3\xdc\x6c\xd0\x2d\xd8\xc3\xd1\xaa\x94\xd2\x24\x2c\x75\x94\x8e\x51\x64\xb0\xce\xc7\xc8\x42\x29\x0c\x16\x4e\x1e\x40\x28\xa7\xe1\x45\xc8\x52\xb6\x1c\xf9\x91\xcf\x83\x55\xcb\x49\x74\xb2\x4b\x6a\x5d\x53\x34\xec\x90\x8d\x83\x97\xa7\xde\xdc\xed\x33\x94\x46\xd7\xa5\xde\xab\xce\x27\x76\xea\x60\xfc\xda\x05\x00\x6f\x6b\xae\x4a\x2c\x17\x6c\xcd\x25\xb9\xdd\xb9\xb4\x13\xb8\xef\x2d\xa1\x64\xde\x36\xd2\x89\x5a\x22\x58\x94\x58\x38\x2c\x3b\x4f\x7d\x8c\x20\xda\x9e\xdb\x9a\xf7\xc1\x28\xb8\x41\xc7\x96\xf9\x9c\x06\x69\xda\xe0\x32\x40\xde\xc8\x38\xaf\x37\x98\x3c\x8e\x59\xe2\x9f\x69\x22\x40\x2e\xc5\x32\xe7\x50\x19\x5c\x2f\xd8\xc3\x98\x28\xe4\x5b\x1a\x8c\x11\x5a\xf5\x86\x87\x91\x79\x89\xe1\x01\xb8\x94\xbd\xa5\x37\x1e\x03\xb8\x8e\x8b\xf2\x39\x5f\xe6\x73\x29\x4e\xd4\x90\x74\xbc\xa5\x30\xa5\x4e\xfb\x80\xf7\xb2\x0b\x5d\x1f\xfc\xde\x3a\xc3\x00\x9c\xf6\xc3\x85\x14\xf5\x4a\x73\x53\x02\xb7\x3e\xc6\x1e\x7a\xb6\x7c\xe5\xc5\x75\x7a\xb1\xbc\x53\xed\x89\x77\x7c\xb3\x31\xb8\xe1\x0e\x53\x8a\x43\xaf\x9f\x5e\xbc\xa2\xfe\x7b\xe9\x35\x80\x5e\xdf\x65\x16\x5b\xbe\x88\xf3\xe0\x37\x81\xfb\xb1\xde\x7c\xde\xc8\xe5\x24\x9f\x97\x62\x17\xb7\xf4\x0f\x42\xf5\x0e\xad\x0e\xc0\x15\xe0\xad\x43\xa3\xb8\x84\xd7\x2f\x67\x80\xd9\x26\x03\x0e\x06\x3f\x36\x68\x9d\x1f\x5a\x1d\x00\xd5\x4e\x18\xad\xb6\xa8\x1c\x68\x03\xab\x03\x58\x34\x3b\x51\x60\xa8\xa3\x6b\x6d\xb6\x31\xcc\xf4\x9c\x0a\x25\xa9\x90\x8f\xab\x02\x01\xbe\x16\xaa\x4c\x0b\x6d\x42\x05\xa2\x70\x6e\xd1\x55\xba\x5c\xb0\x1f\x5f\xdd\x30\xf0\xa5\x67\xc1\x42\xd9\x0d\x25\x17\xbe\xc1\x2d\x3c\x85\xa7\xcf\x7d\x02\xb6\xad\x58\x43\xf6\x4a\xed\xec\x91\xea\x70\xde\x05\x7f\xac\xb9\xd0\xca\x19\x2d\x41\xa8\xba\x71\xa9\xdd\x32\x50\x7c\x8b\x0b\x86\x6a\xd7\x03\x6c\x2b\xbd\x07\xad\x64\x88\x72\x87\xc5\xda\xe8\x2d\x70\x28\xb1\x96\xfa\xe0\x1d\x1d\x39\xcd\x40\xab\xa2\xe2\x6a\x83\x0b\xe6\x2a\x61\x33\xf2\x32\xb3\xcd\x6a\x2b\x5c\x32\xed\x36\x47\x1e\x36\x12\xec\xb8\x6c\x70\xc1\xd8\xf2\x85\x94\x63\xe4\x6c\x3e\x0f\x33\xc2\xf4\xb6\x35\x24\x30\xfa\x73\xb6\xba\x6d\xb3\xe3\x91\x79\x87\xf1\x23\x64\xf0\x88\xa6\x1d\x8f\x7d\xf0\xbb\x56\xb8\xf4\xf3\x7a\xc1\xb1\x41\x52\xe4\xc3\x4c\xd2\x35\x8c\x7a\x79\xd9\x75\x88\xdd\x9f\x07\xb1\x0b\xfa\xa7\x81\x74\xba\x29\x2a\x6a\xc2\x3c\x26\xc9\xd7\xa3\xd7\x09\xb8\x0f\xb9\xc1\x91\xcf\xa2\xd7\x4d\xfd\xaf\x11\xcc\x3d\x26\x5d\x89\xa6\x96\xcd\xbe\x08\xb9\x93\xa4\xaf\x25\x2f\xb0\xd2\xb2\x44\xb3\x60\x6f\xfb\x9d\xd6\xc3\x4a\xdb\x64\x8c\xa8\x14\xea\x03\x55\x21\x3d\xda\x98\xda\xc0\x48\xa6\x5f\x3d\xf8\xfe\xfd\xf0\xe5\x78\x64\xcb\xaf\x37\xfb\xe3\x99\xb1\x46\x37\x0e\xaf\xe6\x8d\x45\x63\xc1\x3a\xee\x1a\x7b\xf5\xed\xd3\xa7\x50\x36\xc6\xdb\xb1\xfc\xf6\xe9\xd3\xad\x1d\xf2\x03\xb9\x29\x2a\xcf\x73\xe0\x03\x1e\xae\xbc\x85\x33\x7a\x5c\xfa\x47\xe0\xaa\xa4\xb7\x3c\xbc\x39\x34\x5b\xeb\xc7\xd6\x06\xe9\xf5\xd6\x8d\xbd\xfa\x7b\x83\xe6\x70\x3c\x32\xb0\xe2\x77\x5c\xb0\xff\x7f\xc6\x2e\x9b\x66\x48\xaa\x7b\x9b\x26\x91\x06\xbb\x65\x4b\x2a\x82\x43\x9b\xca\xe7\xb4\x9d\x63\x81\xac\xf9\x06\x83\x03\xa1\xb8\x55\xdf\x2c\x43\xdb\xcb\xe7\xd5\x37\x4b\xe2\x9e\xb4\x87\xbc\x31\xaf\x8c\xd1\xe6\x78\x3c\xe1\x44\x5c\xa2\x71\xe0\xff\x4f\x4b\x4a\x53\x13\x7b\x9d\x1f\x63\xcb\xb6\x3d\x59\xdc\x15\xe7\x98\x61\x93\xb6\x75\xb8\xad\x25\x77\x08\x2c\xf0\x88\xd0\x57\x18\x94\xa2\x70\xc0\x28\xd2\x63\x76\x13\x78\x0a\xb0\x17\x5d\x83\xec\x16\xf9\xc6\xc4\x22\x95\x8e\xa2\x80\x8f\xe8\x0b\x95\xf1\x9a\x5b\x47\x7b\x55\x38\x58\xa1\xd4\xfb\xab\x81\x4b\xdf\xe0\xad\x7b\x61\x90\x43\xa2\xb4\x4a\x7f\x90\xdc\x56\x53\x58\x73\x29\x57\xbc\xf8\xe0\x99\xef\xf7\xba\x3e\x3c\xf9\x95\x5b\x87\xd4\x9a\xc6\xbc\x88\x80\xfb\x22\x47\xf0\xf6\xc2\x91\x68\xf1\x3b\x8b\x50\x38\x23\x9f\x14\xd4\x72\x0a\xbd\xdd\x72\x55\x3e\x29\x68\x1b\xf4\x1d\x7a\xac\x73\x6c\xff\xc0\x3a\xa4\xb0\x2e\x6d\x94\x6f\x2d\x65\xd7\x44\xba\xea\x11\xa2\xea\x77\x75\x57\x19\x13\xe2\xe7\xf0\x28\xfb\x4d\x58\xb1\x92\x08\xd9\xb4\xfb\x1a\xfa\x77\xf7\x78\xb6\x9b\x22\x51\xef\x93\xee\x94\xbf\x33\xf0\x4f\xc4\xbd\x0e\x68\x59\x2f\x83\x3a\x7a\xf0\xdb\xcf\xf7\x19\x7e\xed\x8c\x50\x9b\x6e\xcb\x76\xaa\x22\x67\x68\xdb\xc6\xc8\x1b\xed\x8d\x86\xec\xba\xe6\x2a\x7b\xfd\x32\xf3\xaf\xb4\xa0\x6d\xcf\xc7\x88\x07\x4c\x06\x39\x03\x24\x91\x36\xf4\xdf\xbc\x77\x27\x5f\x43\xbb\x26\x42\x97\x8e\x04\x93\xd2\x13\xe3\x7a\xe0\xb2\x5f\xf8\x16\x7b\xac\x3a\x99\xd6\x19\xad\x36\xb1\x16\xb4\x6d\xf6\xfa\x65\x67\x69\x98\x4d\x67\x0d\x9a\x71\x2e\x0f\xa5\xfd\x13\xb2\x7a\xbb\xee\x15\xe7\x2b\xf7\xd9\x20\xd9\x4c\xee\x64\x2f\x94\xd2\xce\xd7\xae\x98\x09\xf1\x5f\xee\x38\xe5\x40\x84\xc5\xbf\xf8\xa1\xb4\xd0\xaa\x44\x65\xa9\x24\xfb\x77\xeb\x8c\xa8\xb1\x3c\x03\x66\xc8\xb4\x64\x2d\xa4\x43\x33\x52\x75\xa9\x7c\xc8\xb4\xe1\x5f\x80\x36\xec\x1d\xae\xdc\x1d\x33\xc8\x4a\xb3\xcc\x5d\x45\x55\xe5\x6f\x78\x20\x50\x5d\xb5\xcc\x5d\xb9\x6c\x5b\xeb\x0c\x64\xbf\x51\x65\xf5\xc3\xe5\x32\x9f\x3b\x73\x6e\xe3\xb8\xb7\x7d\x6e\x34\x9f\x7b\xff\x97\x93\x4f\x4f\x1c\x48\x2f\xfd\x05\x0a\x7a\xfe\x65\x58\x15\x9f\xc2\xbc\x49\x6e\x0b\x23\xea\x71\xa7\x9a\xbf\xe7\x3b\x1e\x46\x3d\xc2\xf3\x39\x7c\x27\x54\x29\xd4\xc6\xde\x79\xce\xa7\x32\x42\xe7\xe8\x64\xdd\x28\x5f\x13\x93\x69\x77\x9e\x9f\xcf\xe1\xb5\x12\x4e\x70\x29\x7e\x47\xaa\x23\x7c\xa7\x45\x09\x44\x07\xa9\x06\x6a\x05\x6b\x61\xac\x83\x2c\x1e\x0f\x13\x56\x89\x12\xd9\x14\xa8\x2e\x90\x4c\x80\x47\x09\x7b\x78\x51\xb4\xa6\xc3\x8a\x36\x1c\x59\xae\xa8\x52\x5a\x3c\x4e\x9f\xf7\xab\xc4\xf6\xcf\xac\x8a\x06\xff\xa3\x42\xe5\x4b\xdd\xb9\x52\x10\xd6\x5b\xae\x60\x8f\xb0\xe7\xca\x91\x43\x64\xee\x08\x10\xe8\x01\x89\xe2\xac\x06\xe1\xc0\xf1\x0f\x68\x41\x38\x1b\xba\xfb\x27\x3d\xd3\x2a\x79\x4c\x7a\xb2\x95\xed\xed\x7d\x3c\x83\x08\x2e\xf4\xe8\x7e\x89\x9f\x1d\x9e\x01\x94\xe3\x34\x5a\xf5\x42\x95\x40\x44\x31\xdd\xa1\xb1\xbc\x8f\xaa\x76\x15\x9a\xee\x22\xe0\xea\x2e\x1c\x49\xb4\x14\xc5\x87\xcb\x50\x7f\xc2\xa1\xfb\x8c\x19\x30\x7f\x57\xd3\x55\x83\xde\xd6\x12\xbd\x8b\x7a\x3d\xc6\x94\xb8\xc2\x8c\x40\xff\xf5\xcd\xf5\xcd\x59\x17\x0a\xe7\xb4\xa6\x06\xa7\xa3\x30\x9a\xc0\xe6\xfe\xab\x9d\x37\xb5\xd4\xbc\x64\xf0\xee\xed\x4f\x9e\xe7\x18\xa4\x77\x2f\x24\xd0\x0e\x0d\xa5\xb0\xb5\xe4\xe1\x68\xa2\xe8\x20\x68\x4e\x22\x74\x8e\x2e\x64\xdc\x7b\xfe\x29\x28\xe8\xee\xc8\x88\x2d\xec\x2b\xe1\xd0\xd6\x64\xa7\xd3\x80\xca\x36\x06\xbd\x1e\xe2\x73\x9e\x0a\x60\x09\x56\xd3\x99\x8c\xf6\x43\x52\xcb\xc6\xce\xba\x23\xa7\xd9\xa1\x19\xc4\xc5\x5b\x28\x3a\xfb\x03\x5f\xe9\xc6\x8d\x84\x4f\xb3\x6e\xe2\x8e\x9b\x00\xc8\xe2\x1e\xd3\x69\x7b\x73\x83\x9c\x4d\xb3\x1d\x97\x49\x17\x0a\x00\xb1\x4e\x1e\xf8\x85\x7f\xfc\xe1\x05\x64\xce\x88\x6d\x32\xcd\x24\xaa\x8d\xab\x60\xb1\x80\xa7\xe3\x40\x7b\x62\x95\xb0\x5f\x25\x72\x8b\xe1\xe4\x07\x9c\x0e\x12\xa2\x0c\xb1\xf1\x5d\xf2\x41\x0c\x35\xfd\x19\x74\x8d\x51\xf1\xbd\x6f\x0f\x3e\xf8\x7d\x48\x7c\xd0\x66\x60\x70\x6d\xd0\xfa\x23\x8d\x0f\x52\x73\x9a\x1e\xd1\xdb\x47\x59\xad\xad\x4b\xce\x63\x3d\xf3\x1e\x4c\xbb\x49\x00\x59\xa9\x15\x9e\x44\x09\xa4\x2e\x7c\x13\xc8\x42\x3a\x24\xd3\xb8\x35\xe8\x2f\x5b\x73\x21\x87\xf9\xb7\x95\x99\x79\x66\x7c\xed\xd9\xf7\x0c\x90\x68\xe4\x4d\x65\xf4\x5e\x8d\x31\xe9\x51\xf1\xdf\xaf\x80\xc1\x13\xb8\xad\x4c\x66\xd0\xd6\x5a\x59\x24\x76\x37\xc2\xa3\x57\x18\x2b\xd6\x71\x4a\xe1\xb8\xa7\xdc\xba\xcb\x2b\xac\x7b\x2b\x6e\x3c\x6e\x75\x90\x13\xbf\x07\x6e\x0c\x3f\xc4\xeb\x8c\x9a\x1b\x6a\xa5\xe7\x9b\x88\x8a\x00\xf2\xa2\xea\xcf\x6b\xfd\x86\x1a\x36\x04\x25\x58\x2f\x7f\x01\x17\xea\xc3\x8c\xce\xda\x05\xfc\xeb\xdf\xd1\xe1\x47\x09\x3b\xbb\x66\x65\xd3\x8c\xb4\x0d\x2e\x88\x19\xe0\x20\xc7\xe7\xe4\xa3\x84\x4e\xaf\xd3\xac\x36\xba\x4e\x58\x47\xeb\xd8\x74\x3c\x2b\x68\x7c\xef\x33\x3e\x4c\xe6\xce\x99\x84\x9d\xb1\xbd\x71\x2a\x42\x67\x60\x56\x37\xb6\x4a\x1e\x65\x1e\x0f\x42\x23\x79\x3f\x1d\x4d\x3b\x9e\x05\x28\xe6\x70\xb7\xba\x8b\x5a\x5f\xc3\xce\x2e\xa3\xba\x2a\x3a\xc0\x16\x0a\xe3\x8d\x26\x45\xb0\xf0\x95\xe6\x9f\x68\xf4\xf7\xf1\x6e\x2b\x19\x55\xcf\x78\x41\x16\xcd\x19\xaf\xcd\xb4\x4a\x18\xf1\x71\x36\xf4\x84\x64\x04\x5c\x17\x22\x58\xf4\x81\x3a\xd9\xe6\x16\xe5\x7d\xbb\xfa\x7c\x8b\xf6\x3b\xf4\x17\xed\xf0\x0a\x9e\x51\x03\xa4\xfc\x11\x44\xc6\x48\x2d\x48\xdc\x61\xd7\xa6\xcf\x8c\xb4\xe8\x28\xe1\x93\xf0\xe2\x59\xb6\x58\x1f\x12\x8b\x72\x06\xaa\x91\x72\x06\xcf\x06\xac\xc3\xc6\x19\x59\xf6\x04\xd8\x28\x3d\x2d\x14\xba\x16\x44\xfe\xf4\x70\x15\x98\xb1\xe9\x45\x1b\x79\xa3\x80\xab\xc3\x29\xac\x61\xbb\x42\x52\x1b\xb1\xe5\x46\xc8\x03\xec\xa9\xc1\xfb\xd3\x15\x39\xe4\x7f\x32\xd8\x71\x21\x89\x68\x4d\x61\x8f\x51\x58\x7f\xf0\x72\x1a\x1a\x4b\xb5\x88\x7c\xb7\x8e\xab\x92\x6e\x22\x63\x25\xcd\xee\x0e\x90\xd7\x7a\x4f\x84\x4e\x26\x97\x48\x24\xfa\x90\x4c\x27\x17\x3d\xd4\xe9\xff\x45\xcf\x25\x2a\xc1\x22\x48\x9f\x4b\x90\xcf\xa5\xc8\x79\x92\x0c\x69\x72\xb7\x25\x17\x1d\xe7\x8b\xf2\xe1\x0b\x64\xad\x75\xd1\xd8\x64\x9a\x05\x17\x06\x07\x86\x6a\x3a\xa4\xc5\xf9\xf5\xf4\xc5\xd6\xec\x0a\x0b\x2c\xc0\x99\xa6\xfb\x95\x86\x2c\xb8\xb8\x0c\xbf\x88\xc4\x38\xaa\x59\x6d\x70\x87\xca\xbd\x0c\x57\x1f\x83\x4d\x83\xf8\x07\xdd\xe3\x27\xab\xe2\x69\xb1\x9b\xc5\xe5\x77\x38\x76\x7a\x0d\x7d\xe2\x16\x99\x7f\x76\xdb\xfd\x75\xc6\xdf\x9d\x2d\xdd\x47\xe2\xf7\x6b\xd8\xe3\xe3\xdd\xe8\x92\x1c\x77\x68\x0e\x9e\xd0\xcc\x22\xdf\x47\xdf\xce\x88\x22\xa0\x39\x80\xa4\x83\x25\x11\xb2\x8f\x0d\x9a\xc3\x20\xaa\xe6\x86\x6f\xd1\xa1\xbf\xee\x7e\xdf\x58\x07\x1b\x4d\xcb\xac\x33\x9c\xae\xb4\x69\xff\xcf\x7b\xa7\x88\xff\x14\xd5\x8c\xe6\x76\x97\x4d\x33\x4f\xcf\xed\x20\xf0\xfc\x3a\x9f\x3a\xdc\xf0\xbb\x45\x36\xb9\x27\xe3\xef\x8c\x4a\xa8\x4c\x03\x62\x00\x7b\xa1\x4a\xbd\xcf\x7a\x2e\x41\xb7\x06\xb0\x80\xb6\xcd\xbe\xe3\x16\xdf\xbd\xfd\xa9\xbf\x5d\x80\x27\xc0\x7a\x5b\xd8\xf3\xc9\xdd\x7b\x69\xcc\x89\xae\xb1\xbb\x90\xac\x0d\x16\xe8\xc1\xf3\xe4\x37\x5e\x46\xfa\x0b\x3e\xaa\x47\xaf\x5f\x5a\x62\xc6\xc4\x0a\x85\x72\x68\xd0\x53\x4a\xa1\x06\x51\x14\xfb\x10\x8b\x20\x52\xc1\x8f\xaf\x02\x8b\x1e\x61\x49\x34\x2b\xe2\x41\x11\x17\xe5\x59\xfb\x0e\xbd\xda\x97\xef\x3e\x7f\xc4\x2c\xb4\xc2\x31\x28\xa2\xec\xda\xaa\xff\xd2\x5f\x8e\x5c\xec\xcf\xaf\x86\xef\x2f\xfd\x6e\x5c\x10\xc3\x22\x7d\xef\xb5\x50\x31\x61\x69\xdf\x47\x2e\x95\xcf\xc3\x21\x76\x39\x99\xb4\x2d\xaa\xf2\x78\x9c\xfc\x67\x00\xc4\x0a\x86\x62\xf2\x1e\x00\x00"),
+			uncompressedSize:  7922,
 		},
 	}
 
@@ -25,6 +25,8 @@ type timelineItem struct {
 	Data         map[string]string       `json:"rawData"`
 	SpanID       string                  `json:"spanID"`
 	ParentSpanID string                  `json:"parentSpanID"`
+	FollowsFrom  []string                `json:"followsFrom"`
+	RetryOf      string                  `json:"retryOf"` // span of the first attempt, for retried operations
 	URL          string                  `json:"url"`
 	Visible      bool                    `json:"visible"`
 }
@@ -40,6 +42,7 @@ func (tl *timelineItem) Valid() bool {
 
 type timelineItemTimespan struct {
 	Label    string `json:"label"`
+	Color    string `json:"color"`         // see ColorFor
 	Start    int64  `json:"starting_time"` // msec since epoch
 	End      int64  `json:"ending_time"`   // msec since epoch
 	Duration int64  `json:"duration"`
@@ -83,10 +86,20 @@ func (a *App) d3timelineInner(t *appdash.Trace, depth int) ([]timelineItem, erro
 	if !item.Valid() {
 		return nil, errTimelineItemValidation
 	}
+	if attempt, ok := t.Span.Attempt(); ok {
+		item.Label += fmt.Sprintf(" (attempt %d)", attempt.N)
+		item.FullLabel = item.Label
+		if attempt.N > 1 {
+			item.RetryOf = attempt.RetryOf.Span.String()
+		}
+	}
 
 	if t.Span.ID.Parent != 0 {
 		item.ParentSpanID = t.Span.ID.Parent.String()
 	}
+	for _, id := range t.Span.FollowsFrom() {
+		item.FollowsFrom = append(item.FollowsFrom, id.Span.String())
+	}
 	if depth <= 1 {
 		item.Visible = true
 	}
@@ -106,6 +119,7 @@ func (a *App) d3timelineInner(t *appdash.Trace, depth int) ([]timelineItem, erro
 			ts := timelineItemTimespan{
 				Start: start,
 				End:   end,
+				Color: ColorFor(t.Span.Name()),
 			}
 			if t.Span.ID.Parent == 0 {
 				ts.Label = e.Schema()
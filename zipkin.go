@@ -0,0 +1,155 @@
+package appdash
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// zipkinSpan is a span in the JSON format of the Zipkin v2 API.
+type zipkinSpan struct {
+	TraceID        string            `json:"traceId"`
+	ID             string            `json:"id"`
+	ParentID       string            `json:"parentId"`
+	Name           string            `json:"name"`
+	Kind           string            `json:"kind"`
+	Timestamp      int64             `json:"timestamp"` // microseconds since the epoch
+	Duration       int64             `json:"duration"`  // microseconds
+	LocalEndpoint  *zipkinEndpoint   `json:"localEndpoint"`
+	RemoteEndpoint *zipkinEndpoint   `json:"remoteEndpoint"`
+	Annotations    []zipkinEvent     `json:"annotations"`
+	Tags           map[string]string `json:"tags"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type zipkinEvent struct {
+	Timestamp int64  `json:"timestamp"` // microseconds since the epoch
+	Value     string `json:"value"`
+}
+
+// Keys of the annotations that record the parts of a Zipkin span that
+// appdash has no counterpart for.
+const (
+	zipkinKindKey          = "Zipkin.Kind"
+	zipkinRemoteServiceKey = "Zipkin.RemoteService"
+)
+
+// UnmarshalZipkinJSON parses a batch of spans in the JSON format of the
+// Zipkin v2 API (a JSON array, as POSTed to /api/v2/spans) and returns
+// them as appdash spans, ready to be collected. The batch is validated as
+// a whole: if any span is malformed, an error naming it is returned and no
+// spans are.
+//
+// Zipkin's trace IDs may have 128 bits, while appdash's have 64; only the
+// low 64 bits are kept. A span's name, local service, start and duration,
+// and timestamped annotations become its Name, Service, Timespan and log
+// events, and its tags become annotations with the same keys. A span with
+// an "error" tag is also marked as failed (see ErrorKey).
+func UnmarshalZipkinJSON(data []byte) ([]*Span, error) {
+	var zspans []zipkinSpan
+	if err := json.Unmarshal(data, &zspans); err != nil {
+		return nil, fmt.Errorf("invalid Zipkin JSON: %s", err)
+	}
+	spans := make([]*Span, len(zspans))
+	for i, zs := range zspans {
+		span, err := zs.span()
+		if err != nil {
+			return nil, fmt.Errorf("invalid Zipkin span %d: %s", i, err)
+		}
+		spans[i] = span
+	}
+	return spans, nil
+}
+
+// span converts zs to an appdash span.
+func (zs *zipkinSpan) span() (*Span, error) {
+	traceID := zs.TraceID
+	if len(traceID) > 16 {
+		traceID = traceID[len(traceID)-16:] // the low 64 bits
+	}
+	var (
+		id  SpanID
+		err error
+	)
+	if id.Trace, err = parseZipkinID("traceId", traceID); err != nil {
+		return nil, err
+	}
+	if id.Span, err = parseZipkinID("id", zs.ID); err != nil {
+		return nil, err
+	}
+	if zs.ParentID != "" {
+		if id.Parent, err = parseZipkinID("parentId", zs.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if zs.Timestamp < 0 || zs.Duration < 0 {
+		return nil, fmt.Errorf("negative timestamp or duration")
+	}
+
+	var events []Event
+	if zs.Name != "" {
+		events = append(events, SpanName(zs.Name))
+	}
+	if zs.Timestamp != 0 {
+		start := zipkinTime(zs.Timestamp)
+		events = append(events, Timespan{S: start, E: start.Add(time.Duration(zs.Duration) * time.Microsecond)})
+	}
+	for _, e := range zs.Annotations {
+		events = append(events, LogWithTimestamp(e.Value, zipkinTime(e.Timestamp)))
+	}
+	var as Annotations
+	for _, e := range events {
+		eas, err := MarshalEvent(e)
+		if err != nil {
+			return nil, err
+		}
+		as = append(as, eas...)
+	}
+
+	if zs.LocalEndpoint != nil && zs.LocalEndpoint.ServiceName != "" {
+		as = append(as, Service(zs.LocalEndpoint.ServiceName))
+	}
+	if zs.RemoteEndpoint != nil && zs.RemoteEndpoint.ServiceName != "" {
+		as = append(as, Annotation{Key: zipkinRemoteServiceKey, Value: []byte(zs.RemoteEndpoint.ServiceName)})
+	}
+	if zs.Kind != "" {
+		as = append(as, Annotation{Key: zipkinKindKey, Value: []byte(zs.Kind)})
+	}
+	keys := make([]string, 0, len(zs.Tags))
+	for k := range zs.Tags {
+		if k == "" {
+			return nil, fmt.Errorf("empty tag key")
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		as = append(as, Annotation{Key: k, Value: []byte(zs.Tags[k])})
+	}
+	if _, isError := zs.Tags["error"]; isError {
+		as = append(as, Failed())
+	}
+	return &Span{ID: id, Annotations: as}, nil
+}
+
+// parseZipkinID parses the hex-encoded ID of the given field, which must
+// be non-zero.
+func parseZipkinID(field, s string) (ID, error) {
+	if s == "" || len(s) > 16 {
+		return 0, fmt.Errorf("invalid %s %q", field, s)
+	}
+	id, err := ParseID(s)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid %s %q", field, s)
+	}
+	return id, nil
+}
+
+// zipkinTime returns the time of a Zipkin timestamp.
+func zipkinTime(usec int64) time.Time {
+	return time.Unix(0, usec*int64(time.Microsecond)).UTC()
+}
@@ -0,0 +1,74 @@
+package appdash
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnmarshalZipkinJSON(t *testing.T) {
+	spans, err := UnmarshalZipkinJSON([]byte(`[{
+		"traceId": "463ac35c9f6413ad48485a3953bb6124",
+		"id": "b2fb4a1d1a96d312",
+		"parentId": "a2fb4a1d1a96d312",
+		"name": "get /api",
+		"kind": "CLIENT",
+		"timestamp": 1461750491274000,
+		"duration": 207000,
+		"localEndpoint": {"serviceName": "frontend"},
+		"remoteEndpoint": {"serviceName": "backend"},
+		"annotations": [{"timestamp": 1461750491300000, "value": "retrying"}],
+		"tags": {"http.method": "GET", "error": "timeout"}
+	}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if want := (SpanID{Trace: 0x48485a3953bb6124, Span: 0xb2fb4a1d1a96d312, Parent: 0xa2fb4a1d1a96d312}); span.ID != want {
+		t.Errorf("got span ID %v, want %v", span.ID, want)
+	}
+	for key, want := range map[string]string{
+		"Name":                 "get /api",
+		ServiceKey:             "frontend",
+		zipkinRemoteServiceKey: "backend",
+		zipkinKindKey:          "CLIENT",
+		"http.method":          "GET",
+		"error":                "timeout",
+		"Msg":                  "retrying",
+	} {
+		if got := string(span.Annotations.get(key)); got != want {
+			t.Errorf("got %s %q, want %q", key, got, want)
+		}
+	}
+	if !span.Failed() {
+		t.Error("span with an error tag is not marked as failed")
+	}
+
+	var ts Timespan
+	if err := UnmarshalEvent(span.Annotations, &ts); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2016, 4, 27, 9, 48, 11, 274000000, time.UTC)
+	if !ts.S.Equal(start) || !ts.E.Equal(start.Add(207*time.Millisecond)) {
+		t.Errorf("got timespan %v to %v, want %v lasting 207ms", ts.S, ts.E, start)
+	}
+}
+
+func TestUnmarshalZipkinJSON_invalid(t *testing.T) {
+	tests := map[string]string{
+		"not an array":      `{"traceId": "1", "id": "2"}`,
+		"missing traceId":   `[{"id": "2"}]`,
+		"missing id":        `[{"traceId": "1"}]`,
+		"zero id":           `[{"traceId": "1", "id": "0000000000000000"}]`,
+		"non-hex parentId":  `[{"traceId": "1", "id": "2", "parentId": "xyz"}]`,
+		"negative duration": `[{"traceId": "1", "id": "2", "timestamp": 1, "duration": -1}]`,
+		"second span bad":   `[{"traceId": "1", "id": "2"}, {"traceId": "1", "id": "too long for an id"}]`,
+	}
+	for label, data := range tests {
+		if spans, err := UnmarshalZipkinJSON([]byte(data)); err == nil {
+			t.Errorf("%s: got spans %v, want an error", label, spans)
+		}
+	}
+}